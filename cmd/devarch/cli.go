@@ -6,21 +6,31 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 	"time"
 
 	"github.com/prospect-ogujiuba/devarch/internal/apply"
 	"github.com/prospect-ogujiuba/devarch/internal/appsvc"
+	cachepkg "github.com/prospect-ogujiuba/devarch/internal/cache"
 	planpkg "github.com/prospect-ogujiuba/devarch/internal/plan"
 	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+	"github.com/prospect-ogujiuba/devarch/internal/spec"
+	workspacepkg "github.com/prospect-ogujiuba/devarch/internal/workspace"
 )
 
 type cliConfig struct {
 	workspaceRoots []string
 	catalogRoots   []string
 	json           bool
+	readOnly       bool
+	readOnlyReason string
+	team           string
+	stateDir       string
 }
 
 type stringSliceFlag []string
@@ -45,6 +55,7 @@ type serviceAPI interface {
 	Doctor(context.Context) (*appsvc.DoctorReport, error)
 	RuntimeStatus(context.Context) (*appsvc.RuntimeStatusReport, error)
 	SocketStatus(context.Context) (*appsvc.SocketStatusReport, error)
+	SocketDiagnose(context.Context, bool) (*appsvc.SocketDiagnosisReport, error)
 	SocketStart(context.Context) (*appsvc.WorkflowCommandResult, error)
 	SocketStop(context.Context) (*appsvc.WorkflowCommandResult, error)
 	CatalogTemplates(context.Context) ([]appsvc.TemplateSummary, error)
@@ -52,12 +63,64 @@ type serviceAPI interface {
 	Workspaces(context.Context) ([]appsvc.WorkspaceSummary, error)
 	Workspace(context.Context, string) (*appsvc.WorkspaceDetail, error)
 	WorkspacePlan(context.Context, string) (*planpkg.Result, error)
-	ApplyWorkspace(context.Context, string) (*apply.Result, error)
+	ApplyWorkspace(context.Context, string, appsvc.ApplyRequest) (*apply.Result, error)
+	WorkspaceRender(context.Context, string) (*appsvc.WorkspaceRenderView, error)
 	WorkspaceStatus(context.Context, string) (*appsvc.WorkspaceStatusView, error)
+	WorkspaceTopology(context.Context, string) (*appsvc.WorkspaceTopologyView, error)
+	WorkspaceActivity(context.Context, string, appsvc.ActivityRequest) (*appsvc.WorkspaceActivityView, error)
+	WorkspaceBrief(context.Context, string, string) (*appsvc.WorkspaceBriefView, error)
+	WorkspacePortForward(context.Context, string, string) (*appsvc.PortForwardView, error)
+	WorkspaceNameConflicts(context.Context, string) (*appsvc.NameConflictsView, error)
+	UnmanagedContainers(context.Context, string) ([]runtimepkg.UnmanagedContainer, error)
+	AdoptContainer(context.Context, string, string, appsvc.AdoptRequest) (*appsvc.AdoptView, error)
+	RunArtisan(context.Context, string, string, []string) (*runtimepkg.ExecResult, error)
+	InspectDatabase(context.Context, string, string) (*appsvc.DatabaseInspectionView, error)
+	QueryDatabase(context.Context, string, string, appsvc.DatabaseQueryRequest) (*appsvc.DatabaseQueryView, error)
+	QueryHistory(context.Context, string, string) (*appsvc.QueryHistoryView, error)
+	ResourceConnections(context.Context, string, string) (*appsvc.ConnectionView, error)
+	URLCatalog(context.Context, appsvc.URLCatalogRequest) (*appsvc.URLCatalogView, error)
+	WorkspaceBadge(context.Context, string, string) (*appsvc.BadgeView, error)
+	MaintenanceCalendar(context.Context) (string, error)
+	StatusPage(context.Context, appsvc.StatusPageRequest) (*appsvc.StatusPageView, error)
+	WorkspaceSchedules(context.Context) (*appsvc.ScheduleView, error)
+	RunSchedules(context.Context, appsvc.ScheduleRunRequest) (*appsvc.ScheduleRunView, error)
+	LaravelWorkers(context.Context, string, string, appsvc.LaravelWorkersRequest) (*appsvc.LaravelWorkersView, error)
+	RunLoadTest(context.Context, string, string, appsvc.LoadTestRequest) (*appsvc.LoadTestReport, error)
+	PauseResource(context.Context, string, string) (*appsvc.ChaosView, error)
+	UnpauseResource(context.Context, string, string) (*appsvc.ChaosView, error)
+	KillResource(context.Context, string, string, string) (*appsvc.ChaosView, error)
+	DegradeResourceNetwork(context.Context, string, string, runtimepkg.NetworkFault) (*appsvc.ChaosView, error)
+	ResourceRequests(context.Context, string, string, int) (*appsvc.InspectorRequestsView, error)
+	CaptureResourceTraffic(context.Context, string, string, appsvc.CaptureRequest) (*appsvc.CaptureView, error)
+	WorkspaceNotes(context.Context, string, string) (*appsvc.NotesView, error)
+	WorkspaceExpiry(context.Context, appsvc.ExpiryRequest) ([]appsvc.WorkspaceExpiryView, error)
+	HealthSuggestions(context.Context, string, string, appsvc.HealthSuggestionRequest) (*appsvc.HealthSuggestionsView, error)
+	ExtendWorkspace(context.Context, string, string) (*appsvc.WorkspaceDetail, error)
+	ExportResource(context.Context, string, string, appsvc.ExportRequest) (*appsvc.ResourceExportView, error)
 	WorkspaceLogs(context.Context, string, string, runtimepkg.LogsRequest) ([]runtimepkg.LogChunk, error)
 	ExecWorkspace(context.Context, string, string, runtimepkg.ExecRequest) (*runtimepkg.ExecResult, error)
-	RestartWorkspaceResource(context.Context, string, string) error
+	RunWorkspaceTask(context.Context, string, string, []string) (*runtimepkg.ExecResult, error)
+	RestartWorkspaceResource(context.Context, string, string, runtimepkg.RestartRequest) error
+	RollingRestartWorkspace(context.Context, string, appsvc.RollingRestartRequest) (*appsvc.RollingRestartView, error)
+	StopWorkspace(context.Context, string, *int) (*appsvc.WorkspaceLifecycleView, error)
+	StartWorkspace(context.Context, string) (*appsvc.WorkspaceLifecycleView, error)
+	IdleWorkspace(context.Context, string, appsvc.IdleRequest) (*appsvc.WorkspaceIdleView, error)
+	SaveWorkspaceImages(context.Context, string, string) (*appsvc.ImageBundleView, error)
+	LoadWorkspaceImages(context.Context, string, string) (*appsvc.ImageBundleView, error)
+	UpdateWorkspaceImages(context.Context, string, appsvc.ImageUpdateRequest) (*appsvc.ImageUpdateView, error)
+	WorkspaceStats(context.Context, string, string) (*appsvc.WorkspaceStatsView, error)
+	ResourceTop(context.Context, string, string) (*runtimepkg.ProcessList, error)
+	ResourceInspect(context.Context, string, string) (*appsvc.ResourceInspectView, error)
 	ScanProject(context.Context, string) (*appsvc.ProjectScanView, error)
+	ScanProjects(context.Context, string, appsvc.ProjectScanRequest) (*appsvc.ProjectInventoryView, error)
+	RunProjectScript(context.Context, appsvc.ProjectScriptRequest) (*appsvc.ProjectScriptRunView, error)
+	RunWPCLI(context.Context, appsvc.ProjectScriptRequest) (*appsvc.ProjectScriptRunView, error)
+	ProjectSuggestions(context.Context, string, appsvc.ProjectSuggestionRequest) (*appsvc.ProjectSuggestionView, error)
+	ProjectScriptHistory(context.Context, string, string) (*appsvc.ProjectScriptHistoryView, error)
+	ProjectDevcontainer(context.Context, string, string, bool) (*appsvc.DevcontainerView, error)
+	BackupDefinitions(context.Context, appsvc.BackupRequest) (*appsvc.BackupView, error)
+	ListBackups(context.Context, string) ([]appsvc.BackupInfo, error)
+	RestoreBackup(context.Context, string, string) (*appsvc.BackupView, error)
 }
 
 type serviceFactory func(cliConfig) (serviceAPI, error)
@@ -71,10 +134,42 @@ func (e *exitStatusError) ExitCode() int { return e.code }
 func (e *exitStatusError) Silent() bool  { return true }
 
 func defaultServiceFactory(cfg cliConfig) (serviceAPI, error) {
-	return appsvc.New(appsvc.Config{
+	return appsvc.New(serviceConfig(cfg))
+}
+
+// serviceConfig builds the service configuration for cfg, with history kept
+// in a file store under cfg.stateDir or else the user state directory. The
+// store creates its directory on the first record, and never under
+// --read-only. Without a state directory to use, history is not kept.
+func serviceConfig(cfg cliConfig) appsvc.Config {
+	return appsvc.Config{
 		WorkspaceRoots: cfg.workspaceRoots,
 		CatalogRoots:   cfg.catalogRoots,
-	})
+		ReadOnly:       cfg.readOnly,
+		ReadOnlyReason: cfg.readOnlyReason,
+		Team:           cfg.team,
+		Actor:          strings.TrimSpace(os.Getenv("DEVARCH_ACTOR")),
+		Cache:          historyStore(cfg),
+	}
+}
+
+func historyStore(cfg cliConfig) cachepkg.Store {
+	dir := cfg.stateDir
+	if dir == "" {
+		var err error
+		if dir, err = cachepkg.DefaultDir(); err != nil {
+			return nil
+		}
+	}
+	open := cachepkg.NewFileStore
+	if cfg.readOnly {
+		open = cachepkg.OpenFileStore
+	}
+	store, err := open(dir)
+	if err != nil {
+		return nil
+	}
+	return store
 }
 
 func run(ctx context.Context, args []string, stdout, stderr io.Writer, factory serviceFactory) error {
@@ -93,6 +188,14 @@ func run(ctx context.Context, args []string, stdout, stderr io.Writer, factory s
 		factory = defaultServiceFactory
 	}
 
+	err = dispatch(ctx, cfg, rest, stdout, stderr, factory)
+	if err != nil && cfg.json {
+		return writeJSONError(stderr, err)
+	}
+	return err
+}
+
+func dispatch(ctx context.Context, cfg cliConfig, rest []string, stdout, stderr io.Writer, factory serviceFactory) error {
 	switch rest[0] {
 	case "doctor":
 		return runDoctor(ctx, cfg, rest[1:], stdout, stderr, factory)
@@ -106,6 +209,12 @@ func run(ctx context.Context, args []string, stdout, stderr io.Writer, factory s
 		return runCatalog(ctx, cfg, rest[1:], stdout, stderr, factory)
 	case "scan":
 		return runScan(ctx, cfg, rest[1:], stdout, stderr, factory)
+	case "names":
+		return runNames(ctx, cfg, rest[1:], stdout, stderr, factory)
+	case "schema":
+		return runSchema(cfg, rest[1:], stdout, stderr)
+	case "backup":
+		return runBackup(ctx, cfg, rest[1:], stdout, stderr, factory)
 	case "help", "-h", "--help":
 		writeRootUsage(stdout)
 		return nil
@@ -117,11 +226,15 @@ func run(ctx context.Context, args []string, stdout, stderr io.Writer, factory s
 
 func parseRootFlags(args []string, stderr io.Writer) (cliConfig, []string, error) {
 	cfg := cliConfig{}
+	cfg.readOnly, cfg.readOnlyReason = readOnlyFromEnv(os.Getenv("DEVARCH_READ_ONLY"))
 	fs := flag.NewFlagSet("devarch", flag.ContinueOnError)
 	fs.SetOutput(stderr)
 	fs.Var((*stringSliceFlag)(&cfg.workspaceRoots), "workspace-root", "Repeatable workspace root scanned recursively for devarch.workspace.yaml")
 	fs.Var((*stringSliceFlag)(&cfg.catalogRoots), "catalog-root", "Repeatable catalog root scanned for template.yaml")
 	fs.BoolVar(&cfg.json, "json", false, "Emit stable JSON output (place before the command)")
+	fs.BoolVar(&cfg.readOnly, "read-only", cfg.readOnly, "Reject commands that change runtime state or write files (default from DEVARCH_READ_ONLY)")
+	fs.StringVar(&cfg.team, "team", strings.TrimSpace(os.Getenv("DEVARCH_TEAM")), "Only see workspaces whose metadata.team matches (default from DEVARCH_TEAM)")
+	fs.StringVar(&cfg.stateDir, "state-dir", strings.TrimSpace(os.Getenv("DEVARCH_STATE_DIR")), "Directory for apply, query, and script history (default from DEVARCH_STATE_DIR, else $XDG_STATE_HOME/devarch)")
 	fs.Usage = func() { writeRootUsage(stderr) }
 	if err := fs.Parse(args); err != nil {
 		return cliConfig{}, nil, err
@@ -129,6 +242,20 @@ func parseRootFlags(args []string, stderr io.Writer) (cliConfig, []string, error
 	return cfg, fs.Args(), nil
 }
 
+// readOnlyFromEnv reads DEVARCH_READ_ONLY. A boolean value switches read-only
+// mode on or off; any other non-empty value switches it on and is shown as
+// the reason, for example "host maintenance until 14:00".
+func readOnlyFromEnv(value string) (bool, string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return false, ""
+	}
+	if enabled, err := strconv.ParseBool(value); err == nil {
+		return enabled, ""
+	}
+	return true, value
+}
+
 func runDoctor(ctx context.Context, cfg cliConfig, args []string, stdout, stderr io.Writer, factory serviceFactory) error {
 	if len(args) != 0 {
 		fmt.Fprintln(stderr, "Usage: devarch [global flags] doctor")
@@ -170,7 +297,7 @@ func runRuntime(ctx context.Context, cfg cliConfig, args []string, stdout, stder
 }
 
 func runSocket(ctx context.Context, cfg cliConfig, args []string, stdout, stderr io.Writer, factory serviceFactory) error {
-	if len(args) != 1 {
+	if len(args) == 0 || (len(args) > 1 && args[0] != "diagnose") {
 		writeSocketUsage(stderr)
 		return fmt.Errorf("socket subcommand is required")
 	}
@@ -179,6 +306,28 @@ func runSocket(ctx context.Context, cfg cliConfig, args []string, stdout, stderr
 		return err
 	}
 	switch args[0] {
+	case "diagnose":
+		fs := flag.NewFlagSet("devarch socket diagnose", flag.ContinueOnError)
+		fs.SetOutput(stderr)
+		var repair bool
+		fs.BoolVar(&repair, "repair", false, "Start or restart podman.socket and retry the connection")
+		fs.Usage = func() { fmt.Fprintln(stderr, "Usage: devarch [global flags] socket diagnose [--repair]") }
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if len(fs.Args()) != 0 {
+			fs.Usage()
+			return fmt.Errorf("socket diagnose does not accept positional arguments")
+		}
+		report, err := svc.SocketDiagnose(ctx, repair)
+		if err != nil {
+			return err
+		}
+		if cfg.json {
+			return writeJSON(stdout, report)
+		}
+		printSocketDiagnosis(stdout, report)
+		return nil
 	case "status":
 		report, err := svc.SocketStatus(ctx)
 		if err != nil {
@@ -226,6 +375,11 @@ func runWorkspace(ctx context.Context, cfg cliConfig, args []string, stdout, std
 		writeWorkspaceUsage(stderr)
 		return fmt.Errorf("workspace subcommand is required")
 	}
+	if args[0] == "validate" {
+		// Validation reports broken manifests, which would stop the service
+		// from being built, so it reads the roots directly.
+		return runWorkspaceValidate(cfg, args[1:], stdout, stderr)
+	}
 	svc, err := factory(cfg)
 	if err != nil {
 		return err
@@ -275,50 +429,244 @@ func runWorkspace(ctx context.Context, cfg cliConfig, args []string, stdout, std
 		printPlan(stdout, plan)
 		return nil
 	case "apply":
+		return runWorkspaceApply(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "status":
 		if len(args) != 2 {
-			fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace apply <name>")
-			return fmt.Errorf("workspace apply requires <name>")
+			fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace status <name>")
+			return fmt.Errorf("workspace status requires <name>")
 		}
-		result, err := svc.ApplyWorkspace(ctx, args[1])
+		status, err := svc.WorkspaceStatus(ctx, args[1])
 		if err != nil {
 			return err
 		}
 		if cfg.json {
-			return writeJSON(stdout, result)
+			return writeJSON(stdout, status)
 		}
-		printApply(stdout, result)
+		printStatus(stdout, status)
 		return nil
-	case "status":
+	case "topology":
 		if len(args) != 2 {
-			fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace status <name>")
-			return fmt.Errorf("workspace status requires <name>")
+			fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace topology <name>")
+			return fmt.Errorf("workspace topology requires <name>")
 		}
-		status, err := svc.WorkspaceStatus(ctx, args[1])
+		topology, err := svc.WorkspaceTopology(ctx, args[1])
 		if err != nil {
 			return err
 		}
 		if cfg.json {
-			return writeJSON(stdout, status)
+			return writeJSON(stdout, topology)
 		}
-		printStatus(stdout, status)
+		printTopology(stdout, topology)
+		return nil
+	case "stats":
+		if len(args) != 2 && len(args) != 3 {
+			fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace stats <name> [resource]")
+			return fmt.Errorf("workspace stats requires <name>")
+		}
+		resource := ""
+		if len(args) == 3 {
+			resource = args[2]
+		}
+		stats, err := svc.WorkspaceStats(ctx, args[1], resource)
+		if err != nil {
+			return err
+		}
+		if cfg.json {
+			return writeJSON(stdout, stats)
+		}
+		printStats(stdout, stats)
+		return nil
+	case "top":
+		if len(args) != 3 {
+			fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace top <name> <resource>")
+			return fmt.Errorf("workspace top requires <name> and <resource>")
+		}
+		processes, err := svc.ResourceTop(ctx, args[1], args[2])
+		if err != nil {
+			return err
+		}
+		if cfg.json {
+			return writeJSON(stdout, processes)
+		}
+		printProcesses(stdout, processes)
+		return nil
+	case "inspect":
+		if len(args) != 3 {
+			fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace inspect <name> <resource>")
+			return fmt.Errorf("workspace inspect requires <name> and <resource>")
+		}
+		inspect, err := svc.ResourceInspect(ctx, args[1], args[2])
+		if err != nil {
+			return err
+		}
+		if cfg.json {
+			return writeJSON(stdout, inspect)
+		}
+		printResourceInspect(stdout, inspect)
+		return nil
+	case "render":
+		return runWorkspaceRender(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "save-images", "load-images":
+		if len(args) != 3 {
+			fmt.Fprintf(stderr, "Usage: devarch [global flags] workspace %s <name> <archive>\n", args[0])
+			return fmt.Errorf("workspace %s requires <name> and <archive>", args[0])
+		}
+		var bundle *appsvc.ImageBundleView
+		var err error
+		if args[0] == "save-images" {
+			bundle, err = svc.SaveWorkspaceImages(ctx, args[1], args[2])
+		} else {
+			bundle, err = svc.LoadWorkspaceImages(ctx, args[1], args[2])
+		}
+		if err != nil {
+			return err
+		}
+		if cfg.json {
+			return writeJSON(stdout, bundle)
+		}
+		printImageBundle(stdout, args[0], bundle)
 		return nil
+	case "update-images":
+		return runWorkspaceUpdateImages(ctx, cfg, svc, args[1:], stdout, stderr)
 	case "logs":
 		return runWorkspaceLogs(ctx, cfg, svc, args[1:], stdout, stderr)
 	case "exec":
 		return runWorkspaceExec(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "run":
+		return runWorkspaceTask(ctx, cfg, svc, args[1:], stdout, stderr)
 	case "restart":
+		return runWorkspaceRestart(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "rolling-restart":
+		return runWorkspaceRollingRestart(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "stop", "start":
+		return runWorkspaceLifecycle(ctx, cfg, svc, args[0], args[1:], stdout, stderr)
+	case "idle":
+		return runWorkspaceIdle(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "activity":
+		return runWorkspaceActivity(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "brief":
+		return runWorkspaceBrief(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "loadtest":
+		return runWorkspaceLoadTest(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "chaos":
+		return runWorkspaceChaos(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "requests":
+		return runWorkspaceRequests(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "capture":
+		return runWorkspaceCapture(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "port-forward":
 		if len(args) != 3 {
-			fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace restart <name> <resource>")
-			return fmt.Errorf("workspace restart requires <name> and <resource>")
+			fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace port-forward <name> <user@host|ssh://user@host[:port]>")
+			return fmt.Errorf("workspace port-forward requires <name> and <host>")
 		}
-		if err := svc.RestartWorkspaceResource(ctx, args[1], args[2]); err != nil {
+		forward, err := svc.WorkspacePortForward(ctx, args[1], args[2])
+		if err != nil {
 			return err
 		}
-		result := map[string]string{"workspace": args[1], "resource": args[2], "status": "restarted"}
 		if cfg.json {
-			return writeJSON(stdout, result)
+			return writeJSON(stdout, forward)
+		}
+		printPortForward(stdout, forward)
+		return nil
+	case "adopt":
+		return runWorkspaceAdopt(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "artisan":
+		return runWorkspaceArtisan(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "laravel-workers":
+		return runWorkspaceLaravelWorkers(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "db":
+		if len(args) != 3 {
+			fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace db <name> <resource>")
+			return fmt.Errorf("workspace db requires <name> and <resource>")
+		}
+		inspection, err := svc.InspectDatabase(ctx, args[1], args[2])
+		if err != nil {
+			return err
+		}
+		if cfg.json {
+			return writeJSON(stdout, inspection)
+		}
+		printDatabaseInspection(stdout, inspection)
+		return nil
+	case "badge":
+		return runWorkspaceBadge(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "calendar":
+		return runWorkspaceCalendar(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "status-page":
+		return runWorkspaceStatusPage(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "schedules":
+		return runWorkspaceSchedules(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "urls":
+		return runWorkspaceURLs(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "connect":
+		if len(args) != 3 {
+			fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace connect <name> <resource>")
+			return fmt.Errorf("workspace connect requires <name> and <resource>")
+		}
+		connections, err := svc.ResourceConnections(ctx, args[1], args[2])
+		if err != nil {
+			return err
+		}
+		if cfg.json {
+			return writeJSON(stdout, connections)
+		}
+		printConnections(stdout, connections)
+		return nil
+	case "query":
+		return runWorkspaceQuery(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "query-log":
+		return runWorkspaceQueryLog(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "conflicts":
+		if len(args) != 2 {
+			fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace conflicts <name>")
+			return fmt.Errorf("workspace conflicts requires <name>")
 		}
-		fmt.Fprintf(stdout, "Restarted %s/%s\n", args[1], args[2])
+		conflicts, err := svc.WorkspaceNameConflicts(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		if cfg.json {
+			return writeJSON(stdout, conflicts)
+		}
+		printNameConflicts(stdout, conflicts)
+		return nil
+	case "export":
+		return runWorkspaceExport(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "expiry":
+		return runWorkspaceExpiry(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "health-suggest":
+		return runWorkspaceHealthSuggest(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "extend":
+		if len(args) != 3 {
+			fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace extend <name> <YYYY-MM-DD|+Nd>")
+			return fmt.Errorf("workspace extend requires <name> and <YYYY-MM-DD|+Nd>")
+		}
+		workspace, err := svc.ExtendWorkspace(ctx, args[1], args[2])
+		if err != nil {
+			return err
+		}
+		if cfg.json {
+			return writeJSON(stdout, workspace)
+		}
+		printWorkspaceDetail(stdout, workspace)
+		return nil
+	case "notes":
+		if len(args) != 2 && len(args) != 3 {
+			fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace notes <name> [resource]")
+			return fmt.Errorf("workspace notes requires <name> and optional [resource]")
+		}
+		resource := ""
+		if len(args) == 3 {
+			resource = args[2]
+		}
+		notes, err := svc.WorkspaceNotes(ctx, args[1], resource)
+		if err != nil {
+			return err
+		}
+		if cfg.json {
+			return writeJSON(stdout, notes)
+		}
+		printNotes(stdout, notes)
 		return nil
 	case "help", "-h", "--help":
 		writeWorkspaceUsage(stdout)
@@ -367,281 +715,2221 @@ func runWorkspaceLogs(ctx context.Context, cfg cliConfig, svc serviceAPI, args [
 	return nil
 }
 
-func runWorkspaceExec(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
-	if len(args) < 3 {
-		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace exec <name> <resource> [--] <command...>")
-		return fmt.Errorf("workspace exec requires <name> <resource> and <command...>")
+func runWorkspaceRestart(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch workspace restart", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	timeout := -1
+	fs.IntVar(&timeout, "timeout", -1, "Seconds to wait for a graceful stop before killing (default: container stop timeout)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace restart [--timeout SECONDS] <name> <resource>")
 	}
-	name := args[0]
-	resource := args[1]
-	command := append([]string(nil), args[2:]...)
-	if len(command) > 0 && command[0] == "--" {
-		command = command[1:]
+	if err := fs.Parse(args); err != nil {
+		return err
 	}
-	if len(command) == 0 {
-		return fmt.Errorf("workspace exec requires <command...>")
+	if len(fs.Args()) != 2 {
+		fs.Usage()
+		return fmt.Errorf("workspace restart requires <name> and <resource>")
 	}
-	result, err := svc.ExecWorkspace(ctx, name, resource, runtimepkg.ExecRequest{Command: command})
-	if err != nil {
+	request := runtimepkg.RestartRequest{}
+	if timeout >= 0 {
+		request.Timeout = &timeout
+	}
+	if err := svc.RestartWorkspaceResource(ctx, fs.Arg(0), fs.Arg(1), request); err != nil {
 		return err
 	}
+	result := map[string]string{"workspace": fs.Arg(0), "resource": fs.Arg(1), "status": "restarted"}
 	if cfg.json {
-		if err := writeJSON(stdout, result); err != nil {
-			return err
-		}
-	} else {
-		printExecResult(stdout, stderr, result)
-	}
-	if result != nil && result.ExitCode != 0 {
-		return &exitStatusError{code: result.ExitCode}
+		return writeJSON(stdout, result)
 	}
+	fmt.Fprintf(stdout, "Restarted %s/%s\n", fs.Arg(0), fs.Arg(1))
 	return nil
 }
 
-func runCatalog(ctx context.Context, cfg cliConfig, args []string, stdout, stderr io.Writer, factory serviceFactory) error {
-	if len(cfg.catalogRoots) == 0 {
-		return fmt.Errorf("catalog commands require at least one --catalog-root")
+func runWorkspaceRender(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch workspace render", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var canonical bool
+	fs.BoolVar(&canonical, "canonical", false, "Print only the canonical payload document")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace render [--canonical] <name>")
 	}
-	if len(args) == 0 {
-		writeCatalogUsage(stderr)
-		return fmt.Errorf("catalog subcommand is required")
+	if err := fs.Parse(args); err != nil {
+		return err
 	}
-	svc, err := factory(cfg)
+	if len(fs.Args()) != 1 {
+		fs.Usage()
+		return fmt.Errorf("workspace render requires <name>")
+	}
+	render, err := svc.WorkspaceRender(ctx, fs.Arg(0))
 	if err != nil {
 		return err
 	}
-
-	switch args[0] {
-	case "list":
-		if len(args) != 1 {
-			fmt.Fprintln(stderr, "Usage: devarch [global flags] catalog list")
-			return fmt.Errorf("catalog list does not accept positional arguments")
-		}
-		templates, err := svc.CatalogTemplates(ctx)
+	if canonical {
+		encoded, err := apply.Canonical(render.Payload)
 		if err != nil {
 			return err
 		}
+		_, err = stdout.Write(encoded)
+		return err
+	}
+	if cfg.json {
+		return writeJSON(stdout, render)
+	}
+	printRender(stdout, render)
+	return nil
+}
+
+func runWorkspaceRollingRestart(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch workspace rolling-restart", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	timeout := -1
+	var readyTimeout time.Duration
+	fs.IntVar(&timeout, "timeout", -1, "Seconds to wait for each graceful stop before killing (default: container stop timeout)")
+	fs.DurationVar(&readyTimeout, "ready-timeout", 0, "How long to wait for each resource to become ready (default 60s)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace rolling-restart [--timeout SECONDS] [--ready-timeout DURATION] <name>")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) != 1 {
+		fs.Usage()
+		return fmt.Errorf("workspace rolling-restart requires <name>")
+	}
+	request := appsvc.RollingRestartRequest{ReadyTimeout: readyTimeout}
+	if timeout >= 0 {
+		request.Timeout = &timeout
+	}
+	result, err := svc.RollingRestartWorkspace(ctx, fs.Arg(0), request)
+	if result != nil {
 		if cfg.json {
-			return writeJSON(stdout, templates)
+			if writeErr := writeJSON(stdout, result); writeErr != nil && err == nil {
+				return writeErr
+			}
+		} else {
+			printRollingRestart(stdout, result)
 		}
-		printCatalogList(stdout, templates)
-		return nil
-	case "show":
-		if len(args) != 2 {
-			fmt.Fprintln(stderr, "Usage: devarch [global flags] catalog show <template>")
-			return fmt.Errorf("catalog show requires <template>")
+	}
+	return err
+}
+
+func runWorkspaceLifecycle(ctx context.Context, cfg cliConfig, svc serviceAPI, action string, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch workspace "+action, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	timeout := -1
+	if action == "stop" {
+		fs.IntVar(&timeout, "timeout", -1, "Seconds to wait for each graceful stop before killing (default: container stop timeout)")
+	}
+	fs.Usage = func() {
+		if action == "stop" {
+			fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace stop [--timeout SECONDS] <name>")
+			return
 		}
-		template, err := svc.CatalogTemplate(ctx, args[1])
-		if err != nil {
-			return err
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace start <name>")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) != 1 {
+		fs.Usage()
+		return fmt.Errorf("workspace %s requires <name>", action)
+	}
+	var result *appsvc.WorkspaceLifecycleView
+	var err error
+	if action == "stop" {
+		var stopTimeout *int
+		if timeout >= 0 {
+			stopTimeout = &timeout
 		}
+		result, err = svc.StopWorkspace(ctx, fs.Arg(0), stopTimeout)
+	} else {
+		result, err = svc.StartWorkspace(ctx, fs.Arg(0))
+	}
+	if result != nil {
 		if cfg.json {
-			return writeJSON(stdout, template)
+			if writeErr := writeJSON(stdout, result); writeErr != nil && err == nil {
+				return writeErr
+			}
+		} else {
+			printWorkspaceLifecycle(stdout, result)
 		}
-		printCatalogDetail(stdout, template)
-		return nil
-	case "help", "-h", "--help":
-		writeCatalogUsage(stdout)
-		return nil
-	default:
-		writeCatalogUsage(stderr)
-		return fmt.Errorf("unknown catalog subcommand %q", args[0])
 	}
+	return err
 }
 
-func runScan(ctx context.Context, cfg cliConfig, args []string, stdout, stderr io.Writer, factory serviceFactory) error {
-	if len(args) == 0 {
-		writeScanUsage(stderr)
-		return fmt.Errorf("scan subcommand is required")
+func runWorkspaceIdle(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch workspace idle", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var request appsvc.IdleRequest
+	timeout := -1
+	fs.Float64Var(&request.CPUPercent, "cpu", 0, "Total CPU percent below which a sample counts as idle (default 2)")
+	fs.Uint64Var(&request.NetworkBytes, "net-bytes", 0, "Network bytes allowed across the sampling window (default 65536)")
+	fs.IntVar(&request.Samples, "samples", 0, "Number of stats samples to take (default 3)")
+	fs.DurationVar(&request.Interval, "interval", 0, "Time between samples (default 20s)")
+	fs.BoolVar(&request.Stop, "stop", false, "Stop the workspace when it is idle")
+	fs.IntVar(&timeout, "timeout", -1, "Seconds to wait for each graceful stop before killing (default: container stop timeout)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace idle [--cpu PERCENT] [--net-bytes N] [--samples N] [--interval DURATION] [--stop] [--timeout SECONDS] <name>")
 	}
-	svc, err := factory(cfg)
-	if err != nil {
+	if err := fs.Parse(args); err != nil {
 		return err
 	}
-
-	switch args[0] {
-	case "project":
-		if len(args) != 2 {
-			fmt.Fprintln(stderr, "Usage: devarch [global flags] scan project <path>")
-			return fmt.Errorf("scan project requires <path>")
-		}
-		result, err := svc.ScanProject(ctx, args[1])
-		if err != nil {
-			return err
-		}
+	if len(fs.Args()) != 1 {
+		fs.Usage()
+		return fmt.Errorf("workspace idle requires <name>")
+	}
+	if timeout >= 0 {
+		request.StopTimeout = &timeout
+	}
+	result, err := svc.IdleWorkspace(ctx, fs.Arg(0), request)
+	if result != nil {
 		if cfg.json {
-			return writeJSON(stdout, result)
+			if writeErr := writeJSON(stdout, result); writeErr != nil && err == nil {
+				return writeErr
+			}
+		} else {
+			printWorkspaceIdle(stdout, result)
 		}
-		printScanResult(stdout, result)
-		return nil
-	case "help", "-h", "--help":
-		writeScanUsage(stdout)
-		return nil
-	default:
-		writeScanUsage(stderr)
-		return fmt.Errorf("unknown scan subcommand %q", args[0])
 	}
+	return err
 }
 
-func writeJSON(w io.Writer, value any) error {
-	encoder := json.NewEncoder(w)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(value)
-}
+func runWorkspaceExpiry(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch workspace expiry", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var request appsvc.ExpiryRequest
+	timeout := -1
+	fs.IntVar(&request.WarnDays, "warn-days", 0, "Report workspaces expiring within N days (default 7)")
+	fs.BoolVar(&request.Stop, "stop", false, "Stop workspaces whose expiry date has passed")
+	fs.IntVar(&timeout, "timeout", -1, "Seconds to wait for each graceful stop before killing (default: container stop timeout)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace expiry [--warn-days N] [--stop] [--timeout SECONDS]")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) != 0 {
+		fs.Usage()
+		return fmt.Errorf("workspace expiry does not accept positional arguments")
+	}
+	if timeout >= 0 {
+		request.StopTimeout = &timeout
+	}
+	expiring, err := svc.WorkspaceExpiry(ctx, request)
+	if err != nil {
+		return err
+	}
+	if cfg.json {
+		return writeJSON(stdout, expiring)
+	}
+	printWorkspaceExpiry(stdout, expiring)
+	return nil
+}
+
+func runWorkspaceValidate(cfg cliConfig, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch workspace validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var request appsvc.ValidationRequest
+	fs.IntVar(&request.Recent, "recent", 0, "List up to N recently broken workspaces (default 5)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace validate [--recent N]")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) != 0 {
+		fs.Usage()
+		return fmt.Errorf("workspace validate does not accept positional arguments")
+	}
+	summary, err := appsvc.ValidateWorkspaces(cfg.workspaceRoots, cfg.team, request)
+	if err != nil {
+		return err
+	}
+	if cfg.json {
+		return writeJSON(stdout, summary)
+	}
+	printValidationSummary(stdout, summary)
+	return nil
+}
+
+func runWorkspaceHealthSuggest(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch workspace health-suggest", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var request appsvc.HealthSuggestionRequest
+	fs.StringVar(&request.Apply, "apply", "", "Write the named suggestion into the workspace manifest")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace health-suggest [--apply NAME] <name> <resource>")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) != 2 {
+		fs.Usage()
+		return fmt.Errorf("workspace health-suggest requires <name> and <resource>")
+	}
+	suggestions, err := svc.HealthSuggestions(ctx, fs.Arg(0), fs.Arg(1), request)
+	if err != nil {
+		return err
+	}
+	if cfg.json {
+		return writeJSON(stdout, suggestions)
+	}
+	printHealthSuggestions(stdout, suggestions)
+	return nil
+}
+
+func runWorkspaceApply(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch workspace apply", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var request appsvc.ApplyRequest
+	fs.DurationVar(&request.HealthTimeout, "wait-healthy", 0, "Wait up to this long for started resources to become healthy and time it")
+	fs.IntVar(&request.Parallelism, "parallel", 1, "Apply up to N independent resources at a time")
+	fs.BoolVar(&request.ChangedOnly, "changed-only", false, "Only recreate resources whose configuration or image changed; do not restart stopped or unhealthy ones")
+	fs.BoolVar(&request.IgnoreCapacity, "ignore-capacity", false, "Apply even when the new resources' memory limits exceed the memory available")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace apply [--wait-healthy D] [--parallel N] [--changed-only] [--ignore-capacity] <name>")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) != 1 {
+		fs.Usage()
+		return fmt.Errorf("workspace apply requires <name>")
+	}
+	result, err := svc.ApplyWorkspace(ctx, fs.Arg(0), request)
+	if err != nil {
+		return err
+	}
+	if cfg.json {
+		return writeJSON(stdout, result)
+	}
+	printApply(stdout, result)
+	return nil
+}
+
+func runWorkspaceUpdateImages(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch workspace update-images", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var request appsvc.ImageUpdateRequest
+	fs.BoolVar(&request.Apply, "apply", false, "Recreate resources whose image has a newer digest")
+	fs.BoolVar(&request.IgnoreWindow, "ignore-window", false, "Apply even outside the workspace's maintenance windows")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace update-images [--apply] [--ignore-window] <name>")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) != 1 {
+		fs.Usage()
+		return fmt.Errorf("workspace update-images requires <name>")
+	}
+	update, err := svc.UpdateWorkspaceImages(ctx, fs.Arg(0), request)
+	if err != nil {
+		return err
+	}
+	if cfg.json {
+		return writeJSON(stdout, update)
+	}
+	printImageUpdate(stdout, update)
+	return nil
+}
+
+func runWorkspaceActivity(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch workspace activity", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var request appsvc.ActivityRequest
+	var beforeRaw string
+	fs.IntVar(&request.Limit, "limit", 0, "Maximum entries to show (default 50)")
+	fs.StringVar(&beforeRaw, "before", "", "Show entries older than this RFC3339 timestamp")
+	fs.StringVar(&request.Actor, "actor", "", "Show only the applies run by this user")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace activity [--limit N] [--before RFC3339] [--actor USER] <name>")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) != 1 {
+		fs.Usage()
+		return fmt.Errorf("workspace activity requires <name>")
+	}
+	if beforeRaw != "" {
+		before, err := time.Parse(time.RFC3339, beforeRaw)
+		if err != nil {
+			return fmt.Errorf("parse --before: %w", err)
+		}
+		request.Before = &before
+	}
+	activity, err := svc.WorkspaceActivity(ctx, fs.Arg(0), request)
+	if err != nil {
+		return err
+	}
+	if cfg.json {
+		return writeJSON(stdout, activity)
+	}
+	printActivity(stdout, activity)
+	return nil
+}
+
+func runWorkspaceQueryLog(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch workspace query-log", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	actor := fs.String("actor", "", "Show only the queries run by this user")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace query-log [--actor USER] <name>")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) != 1 {
+		fs.Usage()
+		return fmt.Errorf("workspace query-log requires <name>")
+	}
+	history, err := svc.QueryHistory(ctx, fs.Arg(0), *actor)
+	if err != nil {
+		return err
+	}
+	if cfg.json {
+		return writeJSON(stdout, history)
+	}
+	printQueryHistory(stdout, history)
+	return nil
+}
+
+func runWorkspaceLoadTest(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch workspace loadtest", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var request appsvc.LoadTestRequest
+	fs.IntVar(&request.Port, "port", 0, "Container port to target (default the resource's first port)")
+	fs.DurationVar(&request.SampleInterval, "interval", 0, "How often to sample resource usage (default 2s)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace loadtest [--port N] [--interval D] <name> <resource> <script.js>")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) != 3 {
+		fs.Usage()
+		return fmt.Errorf("workspace loadtest requires <name>, <resource>, and <script.js>")
+	}
+	request.Script = fs.Arg(2)
+	report, err := svc.RunLoadTest(ctx, fs.Arg(0), fs.Arg(1), request)
+	if err != nil {
+		return err
+	}
+	if cfg.json {
+		return writeJSON(stdout, report)
+	}
+	printLoadTest(stdout, report)
+	return nil
+}
+
+func runWorkspaceChaos(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	usage := func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace chaos pause|unpause <name> <resource>")
+		fmt.Fprintln(stderr, "       devarch [global flags] workspace chaos kill [--signal SIG] <name> <resource>")
+		fmt.Fprintln(stderr, "       devarch [global flags] workspace chaos network --duration D [--latency D] [--jitter D] [--loss PCT] <name> <resource>")
+	}
+	if len(args) == 0 {
+		usage()
+		return fmt.Errorf("workspace chaos requires an action")
+	}
+	action := args[0]
+	fs := flag.NewFlagSet("devarch workspace chaos "+action, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = usage
+	var signal string
+	var fault runtimepkg.NetworkFault
+	switch action {
+	case "pause", "unpause":
+	case "kill":
+		fs.StringVar(&signal, "signal", "KILL", "Signal to send, such as TERM or SIGHUP")
+	case "network":
+		fs.DurationVar(&fault.Duration, "duration", 0, "How long the fault lasts (at most 10m)")
+		fs.DurationVar(&fault.Latency, "latency", 0, "Added delay per packet, such as 200ms")
+		fs.DurationVar(&fault.Jitter, "jitter", 0, "Random variation of the added delay")
+		fs.Float64Var(&fault.Loss, "loss", 0, "Packet loss in percent")
+	default:
+		usage()
+		return fmt.Errorf("unknown workspace chaos action %q", action)
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if len(fs.Args()) != 2 {
+		usage()
+		return fmt.Errorf("workspace chaos %s requires <name> and <resource>", action)
+	}
+
+	var result *appsvc.ChaosView
+	var err error
+	switch action {
+	case "pause":
+		result, err = svc.PauseResource(ctx, fs.Arg(0), fs.Arg(1))
+	case "unpause":
+		result, err = svc.UnpauseResource(ctx, fs.Arg(0), fs.Arg(1))
+	case "kill":
+		result, err = svc.KillResource(ctx, fs.Arg(0), fs.Arg(1), signal)
+	case "network":
+		result, err = svc.DegradeResourceNetwork(ctx, fs.Arg(0), fs.Arg(1), fault)
+	}
+	if err != nil {
+		return err
+	}
+	if cfg.json {
+		return writeJSON(stdout, result)
+	}
+	line := fmt.Sprintf("%s %s (%s)", result.Action, result.Resource, result.RuntimeName)
+	if result.Detail != "" {
+		line += ": " + result.Detail
+	}
+	fmt.Fprintln(stdout, line)
+	return nil
+}
+
+func runWorkspaceRequests(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch workspace requests", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var tail int
+	fs.IntVar(&tail, "tail", 0, "Inspector log lines to read (default 200)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace requests [--tail N] <name> <resource>")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) != 2 {
+		fs.Usage()
+		return fmt.Errorf("workspace requests requires <name> and <resource>")
+	}
+	requests, err := svc.ResourceRequests(ctx, fs.Arg(0), fs.Arg(1), tail)
+	if err != nil {
+		return err
+	}
+	if cfg.json {
+		return writeJSON(stdout, requests)
+	}
+	printInspectedRequests(stdout, requests)
+	return nil
+}
+
+func runWorkspaceAdopt(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch workspace adopt", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var request appsvc.AdoptRequest
+	fs.StringVar(&request.Key, "as", "", "Resource key for the adopted container (default: container name)")
+	fs.StringVar(&request.Template, "template", "", "Catalog template to base the resource on (default: match by image)")
+	fs.BoolVar(&request.Write, "write", false, "Append the resource to the workspace manifest")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace adopt [--as KEY] [--template NAME] [--write] <name> [container]")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	switch len(fs.Args()) {
+	case 1:
+		containers, err := svc.UnmanagedContainers(ctx, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		if cfg.json {
+			return writeJSON(stdout, containers)
+		}
+		printUnmanagedContainers(stdout, containers)
+		return nil
+	case 2:
+		adopted, err := svc.AdoptContainer(ctx, fs.Arg(0), fs.Arg(1), request)
+		if err != nil {
+			return err
+		}
+		if cfg.json {
+			return writeJSON(stdout, adopted)
+		}
+		printAdoption(stdout, adopted)
+		return nil
+	default:
+		fs.Usage()
+		return fmt.Errorf("workspace adopt requires <name> and an optional <container>")
+	}
+}
+
+func runWorkspaceArtisan(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	if len(args) < 3 {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace artisan <name> <resource> [--] <args...>")
+		return fmt.Errorf("workspace artisan requires <name> <resource> and <args...>")
+	}
+	artisanArgs := append([]string(nil), args[2:]...)
+	if artisanArgs[0] == "--" {
+		artisanArgs = artisanArgs[1:]
+	}
+	if len(artisanArgs) == 0 {
+		return fmt.Errorf("workspace artisan requires <args...>")
+	}
+	result, err := svc.RunArtisan(ctx, args[0], args[1], artisanArgs)
+	if err != nil {
+		return err
+	}
+	if cfg.json {
+		if err := writeJSON(stdout, result); err != nil {
+			return err
+		}
+	} else {
+		printExecResult(stdout, stderr, result)
+	}
+	if result != nil && result.ExitCode != 0 {
+		return &exitStatusError{code: result.ExitCode}
+	}
+	return nil
+}
+
+func runWorkspaceBadge(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch workspace badge", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	resource := fs.String("resource", "", "Badge one resource instead of the workspace")
+	output := fs.String("output", "", "Write the SVG to this file instead of stdout")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace badge [--resource KEY] [--output FILE] <name>")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) != 1 {
+		fs.Usage()
+		return fmt.Errorf("workspace badge requires <name>")
+	}
+	if *output != "" && cfg.readOnly {
+		return &appsvc.ReadOnlyError{Operation: "badge --output", Reason: cfg.readOnlyReason}
+	}
+	badge, err := svc.WorkspaceBadge(ctx, fs.Arg(0), *resource)
+	if err != nil {
+		return err
+	}
+	if *output != "" {
+		if err := os.WriteFile(*output, []byte(badge.SVG), 0o644); err != nil {
+			return fmt.Errorf("write badge: %w", err)
+		}
+	}
+	switch {
+	case cfg.json:
+		return writeJSON(stdout, badge)
+	case *output != "":
+		fmt.Fprintf(stdout, "Wrote %s badge (%s) to %s\n", badge.Label, badge.Message, *output)
+	default:
+		_, _ = io.WriteString(stdout, badge.SVG)
+	}
+	return nil
+}
+
+func runWorkspaceCalendar(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch workspace calendar", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	output := fs.String("output", "", "Write the iCalendar feed to this file instead of stdout")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace calendar [--output FILE]")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) != 0 {
+		fs.Usage()
+		return fmt.Errorf("workspace calendar does not accept positional arguments")
+	}
+	if *output != "" && cfg.readOnly {
+		return &appsvc.ReadOnlyError{Operation: "calendar --output", Reason: cfg.readOnlyReason}
+	}
+	calendar, err := svc.MaintenanceCalendar(ctx)
+	if err != nil {
+		return err
+	}
+	if *output != "" {
+		if err := os.WriteFile(*output, []byte(calendar), 0o644); err != nil {
+			return fmt.Errorf("write calendar: %w", err)
+		}
+	}
+	switch {
+	case cfg.json:
+		return writeJSON(stdout, map[string]string{"calendar": calendar})
+	case *output != "":
+		fmt.Fprintf(stdout, "Wrote calendar to %s\n", *output)
+	default:
+		_, _ = io.WriteString(stdout, calendar)
+	}
+	return nil
+}
+
+func runWorkspaceStatusPage(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch workspace status-page", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var request appsvc.StatusPageRequest
+	fs.IntVar(&request.Days, "days", 0, "List maintenance starting within this many days (default 14)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace status-page [--days N]")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) != 0 {
+		fs.Usage()
+		return fmt.Errorf("workspace status-page does not accept positional arguments")
+	}
+	page, err := svc.StatusPage(ctx, request)
+	if err != nil {
+		return err
+	}
+	if cfg.json {
+		return writeJSON(stdout, page)
+	}
+	printStatusPage(stdout, page)
+	return nil
+}
+
+func runWorkspaceSchedules(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch workspace schedules", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	run := fs.Bool("run", false, "Stop or start the workspaces whose schedule is due")
+	var request appsvc.ScheduleRunRequest
+	fs.DurationVar(&request.Window, "window", 0, "With --run, treat schedules from this far back as due (default 15m)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace schedules [--run] [--window D]")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) != 0 {
+		fs.Usage()
+		return fmt.Errorf("workspace schedules does not accept positional arguments")
+	}
+	if !*run {
+		schedules, err := svc.WorkspaceSchedules(ctx)
+		if err != nil {
+			return err
+		}
+		if cfg.json {
+			return writeJSON(stdout, schedules)
+		}
+		printSchedules(stdout, schedules)
+		return nil
+	}
+	result, err := svc.RunSchedules(ctx, request)
+	if err != nil {
+		return err
+	}
+	if cfg.json {
+		if err := writeJSON(stdout, result); err != nil {
+			return err
+		}
+	} else {
+		printScheduleRuns(stdout, result)
+	}
+	failed := 0
+	for _, item := range result.Runs {
+		if item.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d scheduled run(s) failed", failed)
+	}
+	return nil
+}
+
+func runWorkspaceURLs(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch workspace urls", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var request appsvc.URLCatalogRequest
+	fs.BoolVar(&request.All, "all", false, "Include resources that are not running")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace urls [--all]")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) != 0 {
+		fs.Usage()
+		return fmt.Errorf("workspace urls does not accept positional arguments")
+	}
+	catalog, err := svc.URLCatalog(ctx, request)
+	if err != nil {
+		return err
+	}
+	if cfg.json {
+		return writeJSON(stdout, catalog)
+	}
+	printURLCatalog(stdout, catalog)
+	return nil
+}
+
+func runWorkspaceQuery(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch workspace query", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var request appsvc.DatabaseQueryRequest
+	fs.IntVar(&request.Limit, "limit", 0, "Maximum rows to return (default 500)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace query [--limit N] <name> <resource> <sql>")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) != 3 {
+		fs.Usage()
+		return fmt.Errorf("workspace query requires <name> <resource> and <sql>")
+	}
+	request.Query = fs.Arg(2)
+	result, err := svc.QueryDatabase(ctx, fs.Arg(0), fs.Arg(1), request)
+	if err != nil {
+		return err
+	}
+	if cfg.json {
+		return writeJSON(stdout, result)
+	}
+	printQueryResult(stdout, result)
+	return nil
+}
+
+func runWorkspaceLaravelWorkers(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch workspace laravel-workers", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var request appsvc.LaravelWorkersRequest
+	fs.BoolVar(&request.Write, "write", false, "Append the workers to the workspace manifest")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace laravel-workers [--write] <name> <resource>")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) != 2 {
+		fs.Usage()
+		return fmt.Errorf("workspace laravel-workers requires <name> and <resource>")
+	}
+	workers, err := svc.LaravelWorkers(ctx, fs.Arg(0), fs.Arg(1), request)
+	if err != nil {
+		return err
+	}
+	if cfg.json {
+		return writeJSON(stdout, workers)
+	}
+	fmt.Fprintf(stdout, "Workers for %s: %s\n", workers.Resource, strings.Join(workers.Added, ", "))
+	if workers.Written {
+		fmt.Fprintf(stdout, "Added to %s:\n", workers.ManifestPath)
+	} else {
+		fmt.Fprintln(stdout, "Add under resources (or rerun with --write):")
+	}
+	fmt.Fprint(stdout, workers.YAML)
+	return nil
+}
+
+func runWorkspaceCapture(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch workspace capture", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var request appsvc.CaptureRequest
+	fs.DurationVar(&request.Duration, "duration", 0, "How long to capture (default 30s, at most 10m)")
+	fs.IntVar(&request.MaxPackets, "packets", 0, "Stop after this many packets")
+	fs.StringVar(&request.Filter, "filter", "", "tcpdump filter expression, such as 'port 5432'")
+	fs.StringVar(&request.Output, "output", "", "pcap file to write (default <workspace>-<resource>-<time>.pcap)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace capture [--duration D] [--packets N] [--filter EXPR] [--output FILE] <name> <resource>")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) != 2 {
+		fs.Usage()
+		return fmt.Errorf("workspace capture requires <name> and <resource>")
+	}
+	capture, err := svc.CaptureResourceTraffic(ctx, fs.Arg(0), fs.Arg(1), request)
+	if err != nil {
+		return err
+	}
+	if cfg.json {
+		return writeJSON(stdout, capture)
+	}
+	fmt.Fprintf(stdout, "Captured %d bytes from %s in %s to %s\n", capture.Bytes, capture.Resource, capture.Duration, capture.Path)
+	return nil
+}
+
+func runWorkspaceBrief(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch workspace brief", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var ifNoneMatch string
+	fs.StringVar(&ifNoneMatch, "if-none-match", "", "ETag from a previous call; report notModified when unchanged")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace brief [--if-none-match ETAG] <name>")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) != 1 {
+		fs.Usage()
+		return fmt.Errorf("workspace brief requires <name>")
+	}
+	brief, err := svc.WorkspaceBrief(ctx, fs.Arg(0), ifNoneMatch)
+	if err != nil {
+		return err
+	}
+	if cfg.json {
+		return writeJSON(stdout, brief)
+	}
+	printBrief(stdout, brief)
+	return nil
+}
+
+func runWorkspaceExec(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	if len(args) < 3 {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace exec <name> <resource> [--] <command...>")
+		return fmt.Errorf("workspace exec requires <name> <resource> and <command...>")
+	}
+	name := args[0]
+	resource := args[1]
+	command := append([]string(nil), args[2:]...)
+	if len(command) > 0 && command[0] == "--" {
+		command = command[1:]
+	}
+	if len(command) == 0 {
+		return fmt.Errorf("workspace exec requires <command...>")
+	}
+	result, err := svc.ExecWorkspace(ctx, name, resource, runtimepkg.ExecRequest{Command: command})
+	if err != nil {
+		return err
+	}
+	if cfg.json {
+		if err := writeJSON(stdout, result); err != nil {
+			return err
+		}
+	} else {
+		printExecResult(stdout, stderr, result)
+	}
+	if result != nil && result.ExitCode != 0 {
+		return &exitStatusError{code: result.ExitCode}
+	}
+	return nil
+}
+
+func runWorkspaceTask(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	if len(args) < 3 {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace run <name> <resource> [--] <command...>")
+		return fmt.Errorf("workspace run requires <name> <resource> and <command...>")
+	}
+	command := append([]string(nil), args[2:]...)
+	if len(command) > 0 && command[0] == "--" {
+		command = command[1:]
+	}
+	if len(command) == 0 {
+		return fmt.Errorf("workspace run requires <command...>")
+	}
+	result, err := svc.RunWorkspaceTask(ctx, args[0], args[1], command)
+	if err != nil {
+		return err
+	}
+	if cfg.json {
+		if err := writeJSON(stdout, result); err != nil {
+			return err
+		}
+	} else {
+		printExecResult(stdout, stderr, result)
+	}
+	if result != nil && result.ExitCode != 0 {
+		return &exitStatusError{code: result.ExitCode}
+	}
+	return nil
+}
+
+func runCatalog(ctx context.Context, cfg cliConfig, args []string, stdout, stderr io.Writer, factory serviceFactory) error {
+	if len(cfg.catalogRoots) == 0 {
+		return fmt.Errorf("catalog commands require at least one --catalog-root")
+	}
+	if len(args) == 0 {
+		writeCatalogUsage(stderr)
+		return fmt.Errorf("catalog subcommand is required")
+	}
+	svc, err := factory(cfg)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "list":
+		if len(args) != 1 {
+			fmt.Fprintln(stderr, "Usage: devarch [global flags] catalog list")
+			return fmt.Errorf("catalog list does not accept positional arguments")
+		}
+		templates, err := svc.CatalogTemplates(ctx)
+		if err != nil {
+			return err
+		}
+		if cfg.json {
+			return writeJSON(stdout, templates)
+		}
+		printCatalogList(stdout, templates)
+		return nil
+	case "show":
+		if len(args) != 2 {
+			fmt.Fprintln(stderr, "Usage: devarch [global flags] catalog show <template>")
+			return fmt.Errorf("catalog show requires <template>")
+		}
+		template, err := svc.CatalogTemplate(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		if cfg.json {
+			return writeJSON(stdout, template)
+		}
+		printCatalogDetail(stdout, template)
+		return nil
+	case "help", "-h", "--help":
+		writeCatalogUsage(stdout)
+		return nil
+	default:
+		writeCatalogUsage(stderr)
+		return fmt.Errorf("unknown catalog subcommand %q", args[0])
+	}
+}
+
+func runScan(ctx context.Context, cfg cliConfig, args []string, stdout, stderr io.Writer, factory serviceFactory) error {
+	if len(args) == 0 {
+		writeScanUsage(stderr)
+		return fmt.Errorf("scan subcommand is required")
+	}
+	svc, err := factory(cfg)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "project":
+		if len(args) != 2 {
+			fmt.Fprintln(stderr, "Usage: devarch [global flags] scan project <path>")
+			return fmt.Errorf("scan project requires <path>")
+		}
+		result, err := svc.ScanProject(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		if cfg.json {
+			return writeJSON(stdout, result)
+		}
+		printScanResult(stdout, result)
+		return nil
+	case "all":
+		return runScanAll(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "run":
+		return runScanScript(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "wp":
+		return runScanWP(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "suggest":
+		return runScanSuggest(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "history":
+		return runScanHistory(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "devcontainer":
+		return runScanDevcontainer(ctx, cfg, svc, args[1:], stdout, stderr)
+	case "help", "-h", "--help":
+		writeScanUsage(stdout)
+		return nil
+	default:
+		writeScanUsage(stderr)
+		return fmt.Errorf("unknown scan subcommand %q", args[0])
+	}
+}
+
+func runWorkspaceExport(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch workspace export", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var request appsvc.ExportRequest
+	fs.StringVar(&request.Compose, "compose", "docker", "Compose implementation to target: docker, podman, podman-compose, or nerdctl")
+	fs.StringVar(&request.Redact, "redact", "secrets", "Redaction profile: secrets, or share to also replace domains and host paths")
+	fs.BoolVar(&request.PortablePaths, "portable-paths", false, "Rewrite host paths under the workspace or home directory as ${PROJECT_ROOT} and ${HOME} paths")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] workspace export [--compose docker|podman|podman-compose|nerdctl] [--redact secrets|share] [--portable-paths] <name> <resource> <dir|file.zip>")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) != 3 {
+		fs.Usage()
+		return fmt.Errorf("workspace export requires <name>, <resource>, and <dir|file.zip>")
+	}
+	request.Target = fs.Arg(2)
+	export, err := svc.ExportResource(ctx, fs.Arg(0), fs.Arg(1), request)
+	if err != nil {
+		return err
+	}
+	if cfg.json {
+		return writeJSON(stdout, export)
+	}
+	fmt.Fprintf(stdout, "Exported %s from %s to %s for %s:\n", export.Resource, export.Workspace, export.Target, export.Compose)
+	for _, file := range export.Files {
+		fmt.Fprintf(stdout, "  %s\n", file)
+	}
+	for _, redaction := range export.Redactions {
+		fmt.Fprintf(stdout, "Redacted %s %s in %s\n", redaction.Kind, redaction.Field, redaction.File)
+	}
+	for _, warning := range export.Warnings {
+		fmt.Fprintf(stdout, "Warning: %s\n", warning)
+	}
+	return nil
+}
+
+func runScanAll(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch scan all", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var request appsvc.ProjectScanRequest
+	fs.Var((*stringSliceFlag)(&request.Include), "include", "Repeatable glob to scan even when an ignore rule excludes it")
+	fs.Var((*stringSliceFlag)(&request.Exclude), "exclude", "Repeatable glob to skip")
+	fs.IntVar(&request.MaxDepth, "depth", 0, "Directory levels below <dir> to look for projects (default 3)")
+	fs.IntVar(&request.Concurrency, "concurrency", 0, "Projects to scan at once (default 4)")
+	fs.DurationVar(&request.ProjectTimeout, "timeout", 0, "Give up on a project whose scan takes longer than this (default 10s)")
+	fs.StringVar(&request.Language, "language", "", "Only list projects in this language")
+	fs.StringVar(&request.Framework, "framework", "", "Only list projects using this framework")
+	fs.Func("frontend", "Only list projects with (true) or without (false) a frontend", func(value string) error {
+		hasFrontend, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		request.HasFrontend = &hasFrontend
+		return nil
+	})
+	fs.BoolVar(&request.Archived, "archived", false, "Also list projects marked archived")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] scan all [--include GLOB] [--exclude GLOB] [--depth N] [--concurrency N] [--timeout DURATION] [--language L] [--framework F] [--frontend BOOL] [--archived] <dir>")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) != 1 {
+		fs.Usage()
+		return fmt.Errorf("scan all requires <dir>")
+	}
+	inventory, err := svc.ScanProjects(ctx, fs.Arg(0), request)
+	if err != nil {
+		return err
+	}
+	if cfg.json {
+		return writeJSON(stdout, inventory)
+	}
+	printProjectInventory(stdout, inventory)
+	return nil
+}
+
+func runScanScript(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch scan run", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var request appsvc.ProjectScriptRequest
+	fs.StringVar(&request.Workspace, "workspace", "", "Run on this workspace's runtime and network")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] scan run [--workspace NAME] <path> <script> [-- args...]")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) < 2 {
+		fs.Usage()
+		return fmt.Errorf("scan run requires <path> and <script>")
+	}
+	request.Path, request.Script = fs.Arg(0), fs.Arg(1)
+	request.Args = append([]string(nil), fs.Args()[2:]...)
+	if len(request.Args) > 0 && request.Args[0] == "--" {
+		request.Args = request.Args[1:]
+	}
+	run, err := svc.RunProjectScript(ctx, request)
+	if err != nil {
+		return err
+	}
+	return printScriptRun(cfg, run, stdout, stderr)
+}
+
+func runScanWP(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch scan wp", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var request appsvc.ProjectScriptRequest
+	fs.StringVar(&request.Workspace, "workspace", "", "Run on this workspace's runtime and network")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] scan wp [--workspace NAME] <path> [--] <wp-cli args...>")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) < 2 {
+		fs.Usage()
+		return fmt.Errorf("scan wp requires <path> and <wp-cli args...>")
+	}
+	request.Path = fs.Arg(0)
+	request.Args = append([]string(nil), fs.Args()[1:]...)
+	if request.Args[0] == "--" {
+		request.Args = request.Args[1:]
+	}
+	run, err := svc.RunWPCLI(ctx, request)
+	if err != nil {
+		return err
+	}
+	return printScriptRun(cfg, run, stdout, stderr)
+}
+
+func printScriptRun(cfg cliConfig, run *appsvc.ProjectScriptRunView, stdout, stderr io.Writer) error {
+	if cfg.json {
+		if err := writeJSON(stdout, run); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(stderr, "Ran %s in %s: %s\n", run.Script, run.Image, strings.Join(run.Command, " "))
+		printExecResult(stdout, stderr, &runtimepkg.ExecResult{ExitCode: run.ExitCode, Stdout: run.Output})
+	}
+	if run.ExitCode != 0 {
+		return &exitStatusError{code: run.ExitCode}
+	}
+	return nil
+}
+
+func runScanHistory(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch scan history", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	actor := fs.String("actor", "", "Show only the script runs of this user")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] scan history [--actor USER] <path>")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) != 1 {
+		fs.Usage()
+		return fmt.Errorf("scan history requires <path>")
+	}
+	history, err := svc.ProjectScriptHistory(ctx, fs.Arg(0), *actor)
+	if err != nil {
+		return err
+	}
+	if cfg.json {
+		return writeJSON(stdout, history)
+	}
+	printScriptHistory(stdout, history)
+	return nil
+}
+
+func runScanSuggest(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch scan suggest", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var request appsvc.ProjectSuggestionRequest
+	fs.StringVar(&request.Name, "name", "", "Workspace name (default <project>-local)")
+	fs.BoolVar(&request.Write, "write", false, "Write the suggested stack to <path>/devarch.workspace.yaml")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] scan suggest [--name NAME] [--write] <path>")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) != 1 {
+		fs.Usage()
+		return fmt.Errorf("scan suggest requires <path>")
+	}
+	suggestions, err := svc.ProjectSuggestions(ctx, fs.Arg(0), request)
+	if err != nil {
+		return err
+	}
+	if cfg.json {
+		return writeJSON(stdout, suggestions)
+	}
+	printProjectSuggestions(stdout, suggestions)
+	return nil
+}
+
+func runScanDevcontainer(ctx context.Context, cfg cliConfig, svc serviceAPI, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("devarch scan devcontainer", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var workspaceName string
+	var write bool
+	fs.StringVar(&workspaceName, "workspace", "", "Join the network of this workspace")
+	fs.BoolVar(&write, "write", false, "Write the files under <path>/.devcontainer")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: devarch [global flags] scan devcontainer [--workspace NAME] [--write] <path>")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fs.Args()) != 1 {
+		fs.Usage()
+		return fmt.Errorf("scan devcontainer requires <path>")
+	}
+	view, err := svc.ProjectDevcontainer(ctx, fs.Arg(0), workspaceName, write)
+	if err != nil {
+		return err
+	}
+	if cfg.json {
+		return writeJSON(stdout, view)
+	}
+	printDevcontainer(stdout, view)
+	return nil
+}
+
+func runBackup(ctx context.Context, cfg cliConfig, args []string, stdout, stderr io.Writer, factory serviceFactory) error {
+	if len(args) == 0 {
+		writeBackupUsage(stderr)
+		return fmt.Errorf("backup subcommand is required")
+	}
+	svc, err := factory(cfg)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "create":
+		fs := flag.NewFlagSet("devarch backup create", flag.ContinueOnError)
+		fs.SetOutput(stderr)
+		var keep int
+		fs.IntVar(&keep, "keep", 0, "Number of backups to keep in <dir> (default 10)")
+		fs.Usage = func() { fmt.Fprintln(stderr, "Usage: devarch [global flags] backup create [--keep N] <dir>") }
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if len(fs.Args()) != 1 {
+			fs.Usage()
+			return fmt.Errorf("backup create requires <dir>")
+		}
+		view, err := svc.BackupDefinitions(ctx, appsvc.BackupRequest{Directory: fs.Arg(0), Keep: keep})
+		if err != nil {
+			return err
+		}
+		if cfg.json {
+			return writeJSON(stdout, view)
+		}
+		printBackup(stdout, "Backup", view)
+		return nil
+	case "list":
+		if len(args) != 2 {
+			fmt.Fprintln(stderr, "Usage: devarch [global flags] backup list <dir>")
+			return fmt.Errorf("backup list requires <dir>")
+		}
+		backups, err := svc.ListBackups(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		if cfg.json {
+			return writeJSON(stdout, backups)
+		}
+		printBackups(stdout, backups)
+		return nil
+	case "restore":
+		if len(args) != 3 {
+			fmt.Fprintln(stderr, "Usage: devarch [global flags] backup restore <archive> <dir>")
+			return fmt.Errorf("backup restore requires <archive> and <dir>")
+		}
+		view, err := svc.RestoreBackup(ctx, args[1], args[2])
+		if err != nil {
+			return err
+		}
+		if cfg.json {
+			return writeJSON(stdout, view)
+		}
+		printBackup(stdout, "Restored", view)
+		return nil
+	case "help", "-h", "--help":
+		writeBackupUsage(stdout)
+		return nil
+	default:
+		writeBackupUsage(stderr)
+		return fmt.Errorf("unknown backup subcommand %q", args[0])
+	}
+}
+
+// schemaDocuments maps the document kinds accepted by `schema show` to the
+// embedded schema files used for validation.
+var schemaDocuments = []struct {
+	Name string `json:"name"`
+	File string `json:"file"`
+}{
+	{Name: "team", File: spec.TeamSchemaFile},
+	{Name: "template", File: spec.TemplateSchemaFile},
+	{Name: "workspace", File: spec.WorkspaceSchemaFile},
+}
+
+func runSchema(cfg cliConfig, args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		writeSchemaUsage(stderr)
+		return fmt.Errorf("schema subcommand is required")
+	}
+	switch args[0] {
+	case "list":
+		if len(args) != 1 {
+			fmt.Fprintln(stderr, "Usage: devarch [global flags] schema list")
+			return fmt.Errorf("schema list does not accept positional arguments")
+		}
+		if cfg.json {
+			return writeJSON(stdout, schemaDocuments)
+		}
+		tw := newTabWriter(stdout)
+		fmt.Fprintln(tw, "NAME\tFILE")
+		for _, document := range schemaDocuments {
+			fmt.Fprintf(tw, "%s\t%s\n", document.Name, document.File)
+		}
+		return tw.Flush()
+	case "show":
+		if len(args) != 2 {
+			fmt.Fprintln(stderr, "Usage: devarch [global flags] schema show <workspace|template|team>")
+			return fmt.Errorf("schema show requires <workspace|template|team>")
+		}
+		for _, document := range schemaDocuments {
+			if document.Name != args[1] {
+				continue
+			}
+			data, err := spec.LoadSchema(document.File)
+			if err != nil {
+				return err
+			}
+			_, err = stdout.Write(data)
+			return err
+		}
+		return fmt.Errorf("unknown schema %q (expected workspace or template)", args[1])
+	case "help", "-h", "--help":
+		writeSchemaUsage(stdout)
+		return nil
+	default:
+		writeSchemaUsage(stderr)
+		return fmt.Errorf("unknown schema subcommand %q", args[0])
+	}
+}
+
+// runNames prints bare name lists for shell completion. It never inspects the
+// runtime, so it stays cheap enough to call on every tab press.
+func runNames(ctx context.Context, cfg cliConfig, args []string, stdout, stderr io.Writer, factory serviceFactory) error {
+	if len(args) == 0 {
+		writeNamesUsage(stderr)
+		return fmt.Errorf("names subcommand is required")
+	}
+
+	var names []string
+	switch args[0] {
+	case "workspaces":
+		if len(args) != 1 {
+			fmt.Fprintln(stderr, "Usage: devarch [global flags] names workspaces")
+			return fmt.Errorf("names workspaces does not accept positional arguments")
+		}
+		if len(cfg.workspaceRoots) == 0 {
+			return fmt.Errorf("names workspaces requires at least one --workspace-root")
+		}
+		svc, err := factory(cfg)
+		if err != nil {
+			return err
+		}
+		workspaces, err := svc.Workspaces(ctx)
+		if err != nil {
+			return err
+		}
+		for _, workspace := range workspaces {
+			names = append(names, workspace.Name)
+		}
+	case "templates":
+		if len(args) != 1 {
+			fmt.Fprintln(stderr, "Usage: devarch [global flags] names templates")
+			return fmt.Errorf("names templates does not accept positional arguments")
+		}
+		if len(cfg.catalogRoots) == 0 {
+			return fmt.Errorf("names templates requires at least one --catalog-root")
+		}
+		svc, err := factory(cfg)
+		if err != nil {
+			return err
+		}
+		templates, err := svc.CatalogTemplates(ctx)
+		if err != nil {
+			return err
+		}
+		for _, template := range templates {
+			names = append(names, template.Name)
+		}
+	case "resources":
+		if len(args) != 2 {
+			fmt.Fprintln(stderr, "Usage: devarch [global flags] names resources <workspace>")
+			return fmt.Errorf("names resources requires <workspace>")
+		}
+		if len(cfg.workspaceRoots) == 0 {
+			return fmt.Errorf("names resources requires at least one --workspace-root")
+		}
+		svc, err := factory(cfg)
+		if err != nil {
+			return err
+		}
+		workspace, err := svc.Workspace(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		names = append(names, workspace.ResourceKeys...)
+	case "help", "-h", "--help":
+		writeNamesUsage(stdout)
+		return nil
+	default:
+		writeNamesUsage(stderr)
+		return fmt.Errorf("unknown names subcommand %q", args[0])
+	}
+
+	sort.Strings(names)
+	if cfg.json {
+		if names == nil {
+			names = []string{}
+		}
+		return writeJSON(stdout, names)
+	}
+	for _, name := range names {
+		fmt.Fprintln(stdout, name)
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, value any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+func printChecks(w io.Writer, title string, status appsvc.WorkflowStatus, checks []appsvc.WorkflowCheckResult) {
+	fmt.Fprintf(w, "%s status: %s\n", title, status)
+	if len(checks) == 0 {
+		fmt.Fprintln(w, "Checks: none")
+		return
+	}
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "ID\tSTATUS\tMESSAGE")
+	for _, check := range checks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", check.ID, check.Status, orDash(check.Message))
+	}
+	_ = tw.Flush()
+}
+
+func printSocketDiagnosis(w io.Writer, report *appsvc.SocketDiagnosisReport) {
+	printChecks(w, "Socket", report.Status, report.Checks)
+	for _, repair := range report.Repairs {
+		fmt.Fprintf(w, "Ran: %s %s (%s)\n", repair.Command, strings.Join(repair.Args, " "), repair.Status)
+	}
+	if len(report.Suggestions) > 0 {
+		fmt.Fprintln(w, "Try:")
+		for _, suggestion := range report.Suggestions {
+			fmt.Fprintf(w, "  %s\n", suggestion)
+		}
+	}
+}
+
+func printCommandResult(w io.Writer, result *appsvc.WorkflowCommandResult) {
+	if result == nil {
+		fmt.Fprintln(w, "No command result.")
+		return
+	}
+	fmt.Fprintf(w, "Command: %s %s\n", result.Command, strings.Join(result.Args, " "))
+	fmt.Fprintf(w, "Status: %s\n", result.Status)
+	if result.StdoutSummary != "" {
+		fmt.Fprintf(w, "Stdout: %s\n", result.StdoutSummary)
+	}
+	if result.StderrSummary != "" {
+		fmt.Fprintf(w, "Stderr: %s\n", result.StderrSummary)
+	}
+	if result.Error != "" {
+		fmt.Fprintf(w, "Error: %s\n", result.Error)
+	}
+}
+
+func printWorkspaceList(w io.Writer, workspaces []appsvc.WorkspaceSummary) {
+	if len(workspaces) == 0 {
+		fmt.Fprintln(w, "No workspaces found.")
+		return
+	}
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "NAME\tDISPLAY NAME\tOWNER\tPROVIDER\tRESOURCES\tCAPABILITIES")
+	for _, workspace := range workspaces {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%s\n", workspace.Name, orDash(workspace.DisplayName), orDash(workspace.Owner), orDash(workspace.Provider), workspace.ResourceCount, orDash(capabilitiesText(workspace.Capabilities)))
+	}
+	_ = tw.Flush()
+}
 
-func printChecks(w io.Writer, title string, status appsvc.WorkflowStatus, checks []appsvc.WorkflowCheckResult) {
-	fmt.Fprintf(w, "%s status: %s\n", title, status)
-	if len(checks) == 0 {
-		fmt.Fprintln(w, "Checks: none")
+func printWorkspaceDetail(w io.Writer, workspace *appsvc.WorkspaceDetail) {
+	if workspace == nil {
+		fmt.Fprintln(w, "No workspace data.")
+		return
+	}
+	fmt.Fprintf(w, "Name: %s\n", workspace.Name)
+	if workspace.DisplayName != "" {
+		fmt.Fprintf(w, "Display name: %s\n", workspace.DisplayName)
+	}
+	if workspace.Description != "" {
+		fmt.Fprintf(w, "Description: %s\n", workspace.Description)
+	}
+	if workspace.Owner != "" {
+		fmt.Fprintf(w, "Owner: %s\n", workspace.Owner)
+	}
+	if workspace.Team != "" {
+		fmt.Fprintf(w, "Team: %s\n", workspace.Team)
+	}
+	if workspace.Contact != "" {
+		fmt.Fprintf(w, "Contact: %s\n", workspace.Contact)
+	}
+	if workspace.Expires != "" {
+		fmt.Fprintf(w, "Expires: %s\n", workspace.Expires)
+	}
+	fmt.Fprintf(w, "Provider: %s\n", orDash(workspace.Provider))
+	fmt.Fprintf(w, "Manifest: %s\n", workspace.ManifestPath)
+	fmt.Fprintf(w, "Resources (%d): %s\n", workspace.ResourceCount, strings.Join(workspace.ResourceKeys, ", "))
+	if capabilityText := capabilitiesText(workspace.Capabilities); capabilityText != "" {
+		fmt.Fprintf(w, "Capabilities: %s\n", capabilityText)
+	}
+}
+
+func printPlan(w io.Writer, plan *planpkg.Result) {
+	if plan == nil {
+		fmt.Fprintln(w, "No plan available.")
+		return
+	}
+	fmt.Fprintf(w, "Workspace: %s\n", plan.Workspace)
+	fmt.Fprintf(w, "Provider: %s\n", orDash(plan.Provider))
+	fmt.Fprintf(w, "Blocked: %t\n", plan.Blocked)
+	printRuntimeDiagnostics(w, plan.Diagnostics)
+	if len(plan.Actions) == 0 {
+		fmt.Fprintln(w, "Actions: none")
+		return
+	}
+	fmt.Fprintln(w, "Actions:")
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "SCOPE\tTARGET\tKIND\tRUNTIME NAME\tREASONS")
+	for _, action := range plan.Actions {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", action.Scope, action.Target, action.Kind, orDash(action.RuntimeName), orDash(strings.Join(action.Reasons, "; ")))
+	}
+	_ = tw.Flush()
+}
+
+func printApply(w io.Writer, result *apply.Result) {
+	if result == nil {
+		fmt.Fprintln(w, "No apply result.")
+		return
+	}
+	fmt.Fprintf(w, "Workspace: %s\n", result.Workspace)
+	fmt.Fprintf(w, "Provider: %s\n", orDash(result.Provider))
+	fmt.Fprintf(w, "Started: %s\n", result.StartedAt.Format(time.RFC3339))
+	fmt.Fprintf(w, "Finished: %s\n", result.FinishedAt.Format(time.RFC3339))
+	if len(result.Operations) == 0 {
+		fmt.Fprintln(w, "Operations: none")
+	} else {
+		fmt.Fprintln(w, "Operations:")
+		tw := newTabWriter(w)
+		fmt.Fprintln(tw, "SCOPE\tTARGET\tKIND\tSTATUS\tMESSAGE")
+		for _, operation := range result.Operations {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", operation.Scope, operation.Target, operation.Kind, operation.Status, orDash(operation.Message))
+		}
+		_ = tw.Flush()
+	}
+	printStartupWaterfall(w, result)
+	if result.Images != nil {
+		fmt.Fprintf(w, "Images: %d cached, %d pulled\n", result.Images.Cached, result.Images.Pulled)
+	}
+	if result.Snapshot != nil {
+		fmt.Fprintf(w, "Snapshot resources: %d\n", len(result.Snapshot.Resources))
+	}
+}
+
+// printStartupWaterfall draws each timed resource operation as a bar on the
+// apply's timeline: '=' while pulling, '#' while creating, '~' until healthy.
+func printStartupWaterfall(w io.Writer, result *apply.Result) {
+	const width = 40
+	var timed []apply.Operation
+	end := result.FinishedAt
+	for _, operation := range result.Operations {
+		if operation.Timing == nil {
+			continue
+		}
+		timed = append(timed, operation)
+		finished := operation.Timing.StartedAt.Add(time.Duration(operation.Timing.PullMs+operation.Timing.CreateMs+operation.Timing.HealthyMs) * time.Millisecond)
+		if finished.After(end) {
+			end = finished
+		}
+	}
+	span := end.Sub(result.StartedAt).Milliseconds()
+	if len(timed) == 0 || span <= 0 {
+		return
+	}
+	column := func(ms int64) int { return int(ms * width / span) }
+	fmt.Fprintln(w, "Startup:")
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "TARGET\tPULL\tCREATE\tHEALTHY\tTIMELINE")
+	for _, operation := range timed {
+		timing := operation.Timing
+		offset := column(timing.StartedAt.Sub(result.StartedAt).Milliseconds())
+		bar := strings.Repeat(" ", offset) + strings.Repeat("=", column(timing.PullMs)) + strings.Repeat("#", max(column(timing.CreateMs), 1)) + strings.Repeat("~", column(timing.HealthyMs))
+		healthy := "-"
+		switch {
+		case timing.HealthyMs > 0:
+			healthy = formatMillis(timing.HealthyMs)
+		case timing.Health != "":
+			healthy = timing.Health
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t|%s\n", operation.Target, formatMillis(timing.PullMs), formatMillis(timing.CreateMs), healthy, bar)
+	}
+	_ = tw.Flush()
+}
+
+func formatMillis(ms int64) string {
+	if ms == 0 {
+		return "-"
+	}
+	return (time.Duration(ms) * time.Millisecond).String()
+}
+
+func printStatus(w io.Writer, status *appsvc.WorkspaceStatusView) {
+	if status == nil || status.Desired == nil {
+		fmt.Fprintln(w, "No workspace status available.")
+		return
+	}
+	fmt.Fprintf(w, "Workspace: %s\n", status.Desired.Name)
+	fmt.Fprintf(w, "Provider: %s\n", orDash(status.Desired.Provider))
+	if status.Desired.Network != nil {
+		fmt.Fprintf(w, "Network: %s\n", status.Desired.Network.Name)
+	}
+	printRuntimeDiagnostics(w, status.Desired.Diagnostics)
+	if len(status.Desired.Resources) == 0 {
+		fmt.Fprintln(w, "Resources: none")
+		return
+	}
+	resources := append([]*runtimepkg.DesiredResource(nil), status.Desired.Resources...)
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].Key < resources[j].Key
+	})
+	fmt.Fprintln(w, "Resources:")
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "KEY\tRUNTIME NAME\tSTATUS\tHEALTH\tIMAGE")
+	for _, resource := range resources {
+		if resource == nil {
+			continue
+		}
+		state := "absent"
+		health := "-"
+		if snapshot := status.Snapshot; snapshot != nil {
+			if observed := snapshot.Resource(resource.Key); observed != nil {
+				if observed.State.Status != "" {
+					state = observed.State.Status
+				}
+				if observed.State.Health != "" {
+					health = observed.State.Health
+				}
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", resource.Key, resource.RuntimeName, state, health, orDash(resource.Spec.Image))
+	}
+	_ = tw.Flush()
+}
+
+func printStats(w io.Writer, stats *appsvc.WorkspaceStatsView) {
+	if stats == nil {
+		fmt.Fprintln(w, "No stats available.")
+		return
+	}
+	fmt.Fprintf(w, "Workspace: %s\n", stats.Workspace)
+	fmt.Fprintf(w, "Provider: %s\n", orDash(stats.Provider))
+	if len(stats.Resources) == 0 {
+		fmt.Fprintln(w, "Resources: none running")
+		return
+	}
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "KEY\tRUNTIME NAME\tCPU %\tMEMORY\tMEM %\tPIDS")
+	for _, sample := range stats.Resources {
+		fmt.Fprintf(tw, "%s\t%s\t%.2f\t%s\t%.2f\t%d\n", orDash(sample.Key), sample.RuntimeName, sample.CPUPercent, formatBytes(sample.MemoryBytes), sample.MemoryPercent, sample.PIDs)
+	}
+	fmt.Fprintf(tw, "TOTAL\t-\t%.2f\t%s\t-\t%d\n", stats.Total.CPUPercent, formatBytes(stats.Total.MemoryBytes), stats.Total.PIDs)
+	_ = tw.Flush()
+}
+
+func printRollingRestart(w io.Writer, result *appsvc.RollingRestartView) {
+	fmt.Fprintf(w, "Workspace: %s\n", result.Workspace)
+	fmt.Fprintf(w, "Provider: %s\n", orDash(result.Provider))
+	if len(result.Steps) == 0 {
+		fmt.Fprintln(w, "Resources: none")
+		return
+	}
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "KEY\tRUNTIME NAME\tSTATUS\tHEALTH\tREASON")
+	for _, step := range result.Steps {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", step.Key, step.RuntimeName, step.Status, orDash(step.Health), orDash(step.Reason))
+	}
+	_ = tw.Flush()
+}
+
+func printTopology(w io.Writer, topology *appsvc.WorkspaceTopologyView) {
+	fmt.Fprintf(w, "Workspace: %s\n", topology.Workspace)
+	fmt.Fprintf(w, "Provider: %s\n", orDash(topology.Provider))
+	printRuntimeDiagnostics(w, topology.Diagnostics)
+	fmt.Fprintln(w, "Nodes:")
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "ID\tKIND\tSTATUS\tHEALTH\tIMAGE")
+	for _, node := range topology.Nodes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", node.ID, node.Kind, orDash(node.Status), orDash(node.Health), orDash(node.Image))
+	}
+	_ = tw.Flush()
+	if len(topology.Edges) == 0 {
+		fmt.Fprintln(w, "Edges: none")
+		return
+	}
+	fmt.Fprintln(w, "Edges:")
+	tw = newTabWriter(w)
+	fmt.Fprintln(tw, "SOURCE\tTARGET\tKIND\tLABEL")
+	for _, edge := range topology.Edges {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", edge.Source, edge.Target, edge.Kind, orDash(edge.Label))
+	}
+	_ = tw.Flush()
+}
+
+func printNotes(w io.Writer, notes *appsvc.NotesView) {
+	if notes.Notes == "" && notes.TemplateNotes == "" {
+		fmt.Fprintln(w, "No notes.")
+		return
+	}
+	if notes.Notes != "" {
+		fmt.Fprintln(w, strings.TrimRight(notes.Notes, "\n"))
+	}
+	if notes.TemplateNotes != "" {
+		if notes.Notes != "" {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "Template %s notes:\n\n%s\n", notes.Template, strings.TrimRight(notes.TemplateNotes, "\n"))
+	}
+}
+
+func printWorkspaceExpiry(w io.Writer, expiring []appsvc.WorkspaceExpiryView) {
+	if len(expiring) == 0 {
+		fmt.Fprintln(w, "No expired or expiring workspaces.")
+		return
+	}
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "NAME\tSTATE\tEXPIRES\tDAYS LEFT\tOWNER\tCONTACT\tSTOPPED")
+	for _, entry := range expiring {
+		stopped := "-"
+		if entry.Error != "" {
+			stopped = "failed: " + entry.Error
+		} else if entry.Stopped != nil {
+			stopped = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\t%s\n", entry.Name, entry.State, entry.Expires, entry.DaysLeft, orDash(entry.Owner), orDash(entry.Contact), stopped)
+	}
+	_ = tw.Flush()
+}
+
+func printHealthSuggestions(w io.Writer, view *appsvc.HealthSuggestionsView) {
+	fmt.Fprintf(w, "Resource: %s/%s\n", view.Workspace, view.Resource)
+	fmt.Fprintf(w, "Image: %s\n", orDash(view.Image))
+	fmt.Fprintf(w, "Family: %s\n", orDash(view.Family))
+	current := "-"
+	if view.Current != nil {
+		current = healthCheckSummary(*view.Current)
+	}
+	fmt.Fprintf(w, "Current: %s\n", current)
+	if view.Applied != "" {
+		fmt.Fprintf(w, "Applied %s to %s\n", view.Applied, view.ManifestPath)
+		return
+	}
+	if len(view.Suggestions) == 0 {
+		fmt.Fprintln(w, "Suggestions: none")
+		return
+	}
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "NAME\tCHECK\tINTERVAL\tDESCRIPTION")
+	for _, suggestion := range view.Suggestions {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", suggestion.Name, healthCheckSummary(suggestion.Health), orDash(suggestion.Health.Interval), suggestion.Description)
+	}
+	_ = tw.Flush()
+}
+
+func healthCheckSummary(health workspacepkg.Health) string {
+	switch {
+	case health.TCP != 0:
+		return fmt.Sprintf("tcp %d", health.TCP)
+	case health.HTTP != "":
+		return "http " + health.HTTP
+	case len(health.Test) > 1 && (health.Test[0] == "CMD" || health.Test[0] == "CMD-SHELL"):
+		return strings.Join(health.Test[1:], " ")
+	default:
+		return orDash(strings.Join(health.Test, " "))
+	}
+}
+
+func printValidationSummary(w io.Writer, summary *appsvc.ValidationSummaryView) {
+	fmt.Fprintf(w, "Valid: %d  Warning: %d  Blocked: %d  Invalid: %d\n", summary.Counts["valid"], summary.Counts["warning"], summary.Counts["blocked"], summary.Counts["invalid"])
+	if len(summary.Workspaces) == 0 {
+		fmt.Fprintln(w, "No workspace manifests found.")
+		return
+	}
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "WORKSPACE\tSTATUS\tPROBLEMS\tPATH")
+	for _, entry := range summary.Workspaces {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", orDash(entry.Workspace), entry.Status, len(entry.Problems), entry.Path)
+	}
+	_ = tw.Flush()
+	if len(summary.RecentlyBroken) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "Recently broken:")
+	for _, entry := range summary.RecentlyBroken {
+		name := entry.Workspace
+		if name == "" {
+			name = entry.Path
+		}
+		fmt.Fprintf(w, "  %s (%s, edited %s)\n", name, entry.Status, entry.ModifiedAt.Format(time.RFC3339))
+		for _, problem := range entry.Problems {
+			fmt.Fprintf(w, "    %s\n", problem)
+		}
+	}
+}
+
+func printActivity(w io.Writer, activity *appsvc.WorkspaceActivityView) {
+	fmt.Fprintf(w, "Workspace: %s\n", activity.Workspace)
+	printRuntimeDiagnostics(w, activity.Diagnostics)
+	if len(activity.Entries) == 0 {
+		fmt.Fprintln(w, "Activity: none")
+		return
+	}
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "TIME\tKIND\tACTOR\tRESOURCE\tSTATUS\tMESSAGE")
+	for _, entry := range activity.Entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", entry.Time.Format(time.RFC3339), entry.Kind, orDash(entry.Actor), orDash(entry.Resource), entry.Status, orDash(entry.Message))
+	}
+	_ = tw.Flush()
+	if activity.NextBefore != nil {
+		fmt.Fprintf(w, "More: --before %s\n", activity.NextBefore.Format(time.RFC3339Nano))
+	}
+}
+
+func printLoadTest(w io.Writer, report *appsvc.LoadTestReport) {
+	fmt.Fprintf(w, "Workspace: %s\n", report.Workspace)
+	fmt.Fprintf(w, "Target: %s (%s)\n", report.Resource, report.Target)
+	fmt.Fprintf(w, "Duration: %s\n", report.FinishedAt.Sub(report.StartedAt).Round(time.Second))
+	switch {
+	case report.ThresholdsFailed:
+		fmt.Fprintln(w, "Result: thresholds failed")
+	case report.ExitCode != 0:
+		fmt.Fprintf(w, "Result: k6 exited with code %d\n", report.ExitCode)
+	default:
+		fmt.Fprintln(w, "Result: passed")
+	}
+	if summary := report.Summary; summary != nil {
+		fmt.Fprintf(w, "Requests: %d (%.1f/s, %.2f%% failed)\n", summary.Requests, summary.RequestsPerS, summary.FailedRatio*100)
+		fmt.Fprintf(w, "Latency: avg %.1fms, p95 %.1fms, max %.1fms\n", summary.DurationAvgMs, summary.DurationP95Ms, summary.DurationMaxMs)
+	}
+	if usage := report.Usage; usage != nil {
+		fmt.Fprintf(w, "CPU: avg %.1f%%, peak %.1f%% (%d samples)\n", usage.CPUAvgPercent, usage.CPUPeakPercent, usage.Samples)
+		fmt.Fprintf(w, "Memory peak: %s\n", formatBytes(usage.MemoryPeakBytes))
+	}
+	if report.Summary == nil && report.Output != "" {
+		fmt.Fprintf(w, "Output:\n%s", report.Output)
+	}
+}
+
+func printInspectedRequests(w io.Writer, view *appsvc.InspectorRequestsView) {
+	fmt.Fprintf(w, "Workspace: %s\n", view.Workspace)
+	fmt.Fprintf(w, "Resource: %s (via %s)\n", view.Resource, view.Inspector)
+	if len(view.Requests) == 0 {
+		fmt.Fprintln(w, "Requests: none")
+		return
+	}
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "TIME\tCLIENT\tMETHOD\tURL\tSTATUS\tRESPONSE")
+	for _, request := range view.Requests {
+		when := "-"
+		if request.Time != nil {
+			when = request.Time.Format(time.RFC3339)
+		}
+		status := "-"
+		if request.Status != 0 {
+			status = strconv.Itoa(request.Status)
+		}
+		response := request.Response
+		if request.Error != "" {
+			response = "error: " + request.Error
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", when, request.Client, request.Method, request.URL, status, orDash(response))
+	}
+	_ = tw.Flush()
+}
+
+func printUnmanagedContainers(w io.Writer, containers []runtimepkg.UnmanagedContainer) {
+	if len(containers) == 0 {
+		fmt.Fprintln(w, "No unmanaged running containers.")
+		return
+	}
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "NAME\tIMAGE\tPORTS\tCOMPOSE PROJECT")
+	for _, container := range containers {
+		ports := make([]string, 0, len(container.Ports))
+		for _, port := range container.Ports {
+			if port.Published > 0 {
+				ports = append(ports, fmt.Sprintf("%d->%d", port.Published, port.Container))
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", container.Name, container.Image, orDash(strings.Join(ports, ",")), orDash(container.ComposeProject))
+	}
+	_ = tw.Flush()
+}
+
+func printAdoption(w io.Writer, adopted *appsvc.AdoptView) {
+	fmt.Fprintf(w, "Container %s as resource %s (template %s, runtime name %s)\n", adopted.Container, adopted.Resource, adopted.Template, adopted.RuntimeName)
+	if adopted.Written {
+		fmt.Fprintf(w, "Added to %s:\n", adopted.ManifestPath)
+	} else {
+		fmt.Fprintln(w, "Add under resources (or rerun with --write):")
+	}
+	fmt.Fprint(w, adopted.YAML)
+	for _, warning := range adopted.Warnings {
+		fmt.Fprintf(w, "Note: %s\n", warning)
+	}
+}
+
+func printBackup(w io.Writer, label string, view *appsvc.BackupView) {
+	fmt.Fprintf(w, "%s: %s\n", label, view.Path)
+	fmt.Fprintf(w, "Created: %s\n", view.CreatedAt.Format(time.RFC3339))
+	fmt.Fprintf(w, "Files: %d\n", view.Files)
+	if len(view.Roots) > 0 {
+		tw := newTabWriter(w)
+		fmt.Fprintln(tw, "KIND\tDIRECTORY\tSOURCE")
+		for _, root := range view.Roots {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", root.Kind, root.Directory, root.Path)
+		}
+		_ = tw.Flush()
+	}
+	for _, removed := range view.Removed {
+		fmt.Fprintf(w, "Rotated out: %s\n", removed)
+	}
+}
+
+func printBackups(w io.Writer, backups []appsvc.BackupInfo) {
+	if len(backups) == 0 {
+		fmt.Fprintln(w, "No backups found.")
+		return
+	}
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "CREATED\tSIZE\tPATH")
+	for _, backup := range backups {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", backup.CreatedAt.Format(time.RFC3339), formatBytes(uint64(backup.Size)), backup.Path)
+	}
+	_ = tw.Flush()
+}
+
+func printNameConflicts(w io.Writer, view *appsvc.NameConflictsView) {
+	if len(view.Conflicts) == 0 {
+		fmt.Fprintf(w, "No runtime name conflicts for %s.\n", view.Workspace)
 		return
 	}
 	tw := newTabWriter(w)
-	fmt.Fprintln(tw, "ID\tSTATUS\tMESSAGE")
-	for _, check := range checks {
-		fmt.Fprintf(tw, "%s\t%s\t%s\n", check.ID, check.Status, orDash(check.Message))
+	fmt.Fprintln(tw, "KIND\tNAME\tRESOURCE\tOWNER")
+	for _, conflict := range view.Conflicts {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", conflict.Kind, conflict.Name, orDash(conflict.Resource), conflict.Owner)
 	}
 	_ = tw.Flush()
 }
 
-func printCommandResult(w io.Writer, result *appsvc.WorkflowCommandResult) {
-	if result == nil {
-		fmt.Fprintln(w, "No command result.")
-		return
-	}
-	fmt.Fprintf(w, "Command: %s %s\n", result.Command, strings.Join(result.Args, " "))
-	fmt.Fprintf(w, "Status: %s\n", result.Status)
-	if result.StdoutSummary != "" {
-		fmt.Fprintf(w, "Stdout: %s\n", result.StdoutSummary)
+func printPortForward(w io.Writer, forward *appsvc.PortForwardView) {
+	fmt.Fprintf(w, "Workspace: %s\n", forward.Workspace)
+	fmt.Fprintf(w, "Host: %s\n", forward.Host)
+	if len(forward.Forwards) > 0 {
+		tw := newTabWriter(w)
+		fmt.Fprintln(tw, "RESOURCE\tLOCAL\tREMOTE")
+		for _, item := range forward.Forwards {
+			fmt.Fprintf(tw, "%s\tlocalhost:%d\t%s:%d\n", item.Resource, item.Local, item.RemoteHost, item.Remote)
+		}
+		_ = tw.Flush()
 	}
-	if result.StderrSummary != "" {
-		fmt.Fprintf(w, "Stderr: %s\n", result.StderrSummary)
+	for _, skipped := range forward.Skipped {
+		fmt.Fprintf(w, "Skipped: %s\n", skipped)
 	}
-	if result.Error != "" {
-		fmt.Fprintf(w, "Error: %s\n", result.Error)
+	if len(forward.Command) == 0 {
+		fmt.Fprintln(w, "No ports to forward.")
+		return
 	}
+	fmt.Fprintf(w, "Run: %s\n", strings.Join(forward.Command, " "))
 }
 
-func printWorkspaceList(w io.Writer, workspaces []appsvc.WorkspaceSummary) {
-	if len(workspaces) == 0 {
-		fmt.Fprintln(w, "No workspaces found.")
+func printBrief(w io.Writer, brief *appsvc.WorkspaceBriefView) {
+	if brief.NotModified {
+		fmt.Fprintf(w, "%s: not modified (etag %s)\n", brief.Workspace, brief.ETag)
 		return
 	}
+	fmt.Fprintf(w, "%s: %s (etag %s)\n", brief.Workspace, brief.State, brief.ETag)
 	tw := newTabWriter(w)
-	fmt.Fprintln(tw, "NAME\tDISPLAY NAME\tPROVIDER\tRESOURCES\tCAPABILITIES")
-	for _, workspace := range workspaces {
-		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", workspace.Name, orDash(workspace.DisplayName), orDash(workspace.Provider), workspace.ResourceCount, orDash(capabilitiesText(workspace.Capabilities)))
+	for _, resource := range brief.Resources {
+		status := resource.Status
+		if resource.Health != "" {
+			status += "/" + resource.Health
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", resource.Key, status, orDash(strings.Join(resource.URLs, " ")))
 	}
 	_ = tw.Flush()
+	for _, message := range brief.Errors {
+		fmt.Fprintf(w, "  error: %s\n", message)
+	}
 }
 
-func printWorkspaceDetail(w io.Writer, workspace *appsvc.WorkspaceDetail) {
-	if workspace == nil {
-		fmt.Fprintln(w, "No workspace data.")
+func printWorkspaceLifecycle(w io.Writer, result *appsvc.WorkspaceLifecycleView) {
+	fmt.Fprintf(w, "Workspace: %s\n", result.Workspace)
+	fmt.Fprintf(w, "Provider: %s\n", orDash(result.Provider))
+	if len(result.Steps) == 0 {
+		fmt.Fprintln(w, "Resources: none")
 		return
 	}
-	fmt.Fprintf(w, "Name: %s\n", workspace.Name)
-	if workspace.DisplayName != "" {
-		fmt.Fprintf(w, "Display name: %s\n", workspace.DisplayName)
-	}
-	if workspace.Description != "" {
-		fmt.Fprintf(w, "Description: %s\n", workspace.Description)
-	}
-	fmt.Fprintf(w, "Provider: %s\n", orDash(workspace.Provider))
-	fmt.Fprintf(w, "Manifest: %s\n", workspace.ManifestPath)
-	fmt.Fprintf(w, "Resources (%d): %s\n", workspace.ResourceCount, strings.Join(workspace.ResourceKeys, ", "))
-	if capabilityText := capabilitiesText(workspace.Capabilities); capabilityText != "" {
-		fmt.Fprintf(w, "Capabilities: %s\n", capabilityText)
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "KEY\tRUNTIME NAME\tSTATUS\tREASON")
+	for _, step := range result.Steps {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", step.Key, step.RuntimeName, step.Status, orDash(step.Reason))
 	}
+	_ = tw.Flush()
 }
 
-func printPlan(w io.Writer, plan *planpkg.Result) {
-	if plan == nil {
-		fmt.Fprintln(w, "No plan available.")
-		return
-	}
-	fmt.Fprintf(w, "Workspace: %s\n", plan.Workspace)
-	fmt.Fprintf(w, "Provider: %s\n", orDash(plan.Provider))
-	fmt.Fprintf(w, "Blocked: %t\n", plan.Blocked)
-	printRuntimeDiagnostics(w, plan.Diagnostics)
-	if len(plan.Actions) == 0 {
-		fmt.Fprintln(w, "Actions: none")
+func printSchedules(w io.Writer, view *appsvc.ScheduleView) {
+	if len(view.Schedules) == 0 {
+		fmt.Fprintln(w, "No schedules.")
 		return
 	}
-	fmt.Fprintln(w, "Actions:")
 	tw := newTabWriter(w)
-	fmt.Fprintln(tw, "SCOPE\tTARGET\tKIND\tRUNTIME NAME\tREASONS")
-	for _, action := range plan.Actions {
-		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", action.Scope, action.Target, action.Kind, orDash(action.RuntimeName), orDash(strings.Join(action.Reasons, "; ")))
+	fmt.Fprintln(tw, "WORKSPACE\tACTION\tAT\tDAYS\tNEXT")
+	for _, item := range view.Schedules {
+		next := "disabled"
+		if item.Next != nil {
+			next = item.Next.Format("Mon 2006-01-02 15:04")
+		} else if item.Enabled {
+			next = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", item.Workspace, item.Action, item.At, strings.Join(item.Days, ","), next)
 	}
 	_ = tw.Flush()
 }
 
-func printApply(w io.Writer, result *apply.Result) {
-	if result == nil {
-		fmt.Fprintln(w, "No apply result.")
+func printScheduleRuns(w io.Writer, view *appsvc.ScheduleRunView) {
+	if len(view.Runs) == 0 {
+		fmt.Fprintln(w, "No schedules due.")
 		return
 	}
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "WORKSPACE\tACTION\tDUE\tSTATUS")
+	for _, item := range view.Runs {
+		status := "done"
+		if item.Error != "" {
+			status = "failed: " + item.Error
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", item.Workspace, item.Action, item.Due.Format("15:04"), status)
+	}
+	_ = tw.Flush()
+}
+
+func printWorkspaceIdle(w io.Writer, result *appsvc.WorkspaceIdleView) {
 	fmt.Fprintf(w, "Workspace: %s\n", result.Workspace)
 	fmt.Fprintf(w, "Provider: %s\n", orDash(result.Provider))
-	fmt.Fprintf(w, "Started: %s\n", result.StartedAt.Format(time.RFC3339))
-	fmt.Fprintf(w, "Finished: %s\n", result.FinishedAt.Format(time.RFC3339))
-	if len(result.Operations) == 0 {
-		fmt.Fprintln(w, "Operations: none")
+	fmt.Fprintf(w, "Idle: %t\n", result.Idle)
+	fmt.Fprintf(w, "Running: %d\n", result.Running)
+	fmt.Fprintf(w, "Samples: %d\n", result.Samples)
+	fmt.Fprintf(w, "Max CPU: %.2f%%\n", result.MaxCPUPercent)
+	fmt.Fprintf(w, "Network: %d bytes\n", result.NetworkBytes)
+	if result.Stopped != nil {
+		fmt.Fprintln(w)
+		printWorkspaceLifecycle(w, result.Stopped)
+	}
+}
+
+func printRender(w io.Writer, render *appsvc.WorkspaceRenderView) {
+	fmt.Fprintf(w, "Workspace: %s\n", render.Workspace)
+	fmt.Fprintf(w, "Digest: %s\n", render.Digest)
+	if render.Payload == nil || len(render.Payload.Resources) == 0 {
+		fmt.Fprintln(w, "Resources: none")
 	} else {
-		fmt.Fprintln(w, "Operations:")
 		tw := newTabWriter(w)
-		fmt.Fprintln(tw, "SCOPE\tTARGET\tKIND\tSTATUS\tMESSAGE")
-		for _, operation := range result.Operations {
-			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", operation.Scope, operation.Target, operation.Kind, operation.Status, orDash(operation.Message))
+		fmt.Fprintln(tw, "KEY\tRUNTIME NAME\tIMAGE")
+		for _, resource := range render.Payload.Resources {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", resource.Key, resource.RuntimeName, orDash(resource.Image))
 		}
 		_ = tw.Flush()
 	}
-	if result.Snapshot != nil {
-		fmt.Fprintf(w, "Snapshot resources: %d\n", len(result.Snapshot.Resources))
+	if render.Payload != nil {
+		printRuntimeDiagnostics(w, render.Payload.Diagnostics)
 	}
 }
 
-func printStatus(w io.Writer, status *appsvc.WorkspaceStatusView) {
-	if status == nil || status.Desired == nil {
-		fmt.Fprintln(w, "No workspace status available.")
+func printImageBundle(w io.Writer, operation string, bundle *appsvc.ImageBundleView) {
+	verb := "Saved"
+	if operation == "load-images" {
+		verb = "Loaded"
+	}
+	fmt.Fprintf(w, "%s %d image(s) for %s via %s: %s\n", verb, len(bundle.Images), bundle.Workspace, orDash(bundle.Provider), bundle.Archive)
+	for _, image := range bundle.Images {
+		fmt.Fprintf(w, "  %s\n", image)
+	}
+}
+
+func printImageUpdate(w io.Writer, update *appsvc.ImageUpdateView) {
+	if len(update.Resources) == 0 {
+		fmt.Fprintf(w, "No resources in %s use overrides.autoUpdate: managed.\n", update.Workspace)
 		return
 	}
-	fmt.Fprintf(w, "Workspace: %s\n", status.Desired.Name)
-	fmt.Fprintf(w, "Provider: %s\n", orDash(status.Desired.Provider))
-	if status.Desired.Network != nil {
-		fmt.Fprintf(w, "Network: %s\n", status.Desired.Network.Name)
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "RESOURCE\tIMAGE\tSTATUS")
+	for _, resource := range update.Resources {
+		status := "current"
+		if resource.CurrentID == "" {
+			status = "not running"
+		} else if resource.Outdated {
+			status = "outdated"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", resource.Resource, resource.Image, status)
 	}
-	printRuntimeDiagnostics(w, status.Desired.Diagnostics)
-	if len(status.Desired.Resources) == 0 {
-		fmt.Fprintln(w, "Resources: none")
+	_ = tw.Flush()
+	if update.Skipped != "" {
+		fmt.Fprintf(w, "Not applied: %s. Pass --ignore-window to update now.\n", update.Skipped)
+	}
+	if update.Apply != nil {
+		printApply(w, update.Apply)
+	}
+}
+
+func printProcesses(w io.Writer, processes *runtimepkg.ProcessList) {
+	if processes == nil || len(processes.Titles) == 0 {
+		fmt.Fprintln(w, "No processes.")
 		return
 	}
-	resources := append([]*runtimepkg.DesiredResource(nil), status.Desired.Resources...)
-	sort.Slice(resources, func(i, j int) bool {
-		return resources[i].Key < resources[j].Key
-	})
-	fmt.Fprintln(w, "Resources:")
 	tw := newTabWriter(w)
-	fmt.Fprintln(tw, "KEY\tRUNTIME NAME\tSTATUS\tHEALTH\tIMAGE")
-	for _, resource := range resources {
-		if resource == nil {
-			continue
+	fmt.Fprintln(tw, strings.Join(processes.Titles, "\t"))
+	for _, process := range processes.Processes {
+		fmt.Fprintln(tw, strings.Join(process, "\t"))
+	}
+	_ = tw.Flush()
+}
+
+func printResourceInspect(w io.Writer, inspect *appsvc.ResourceInspectView) {
+	if inspect == nil || inspect.Resource == nil {
+		fmt.Fprintln(w, "No inspect data.")
+		return
+	}
+	resource := inspect.Resource
+	fmt.Fprintf(w, "Resource: %s\n", resource.Key)
+	fmt.Fprintf(w, "Runtime name: %s\n", resource.RuntimeName)
+	fmt.Fprintf(w, "Provider: %s\n", orDash(inspect.Provider))
+	fmt.Fprintf(w, "ID: %s\n", orDash(resource.ID))
+	fmt.Fprintf(w, "Status: %s\n", orDash(resource.State.Status))
+	if resource.State.Health != "" {
+		fmt.Fprintf(w, "Health: %s\n", resource.State.Health)
+	}
+	fmt.Fprintf(w, "Image: %s\n", orDash(resource.Spec.Image))
+	if resource.LogicalHost != "" {
+		fmt.Fprintf(w, "Host alias: %s\n", resource.LogicalHost)
+	}
+	if len(resource.Spec.Ports) > 0 {
+		fmt.Fprintln(w, "Ports:")
+		for _, port := range resource.Spec.Ports {
+			fmt.Fprintf(w, "- %s:%d -> %d/%s\n", orDash(port.HostIP), port.Published, port.Container, orDash(port.Protocol))
 		}
-		state := "absent"
-		health := "-"
-		if snapshot := status.Snapshot; snapshot != nil {
-			if observed := snapshot.Resource(resource.Key); observed != nil {
-				if observed.State.Status != "" {
-					state = observed.State.Status
-				}
-				if observed.State.Health != "" {
-					health = observed.State.Health
-				}
+	}
+	if len(resource.Spec.Volumes) > 0 {
+		fmt.Fprintln(w, "Mounts:")
+		for _, volume := range resource.Spec.Volumes {
+			mode := "rw"
+			if volume.ReadOnly {
+				mode = "ro"
 			}
+			fmt.Fprintf(w, "- %s -> %s (%s)\n", orDash(volume.Source), volume.Target, mode)
+		}
+	}
+	if len(resource.Spec.Env) > 0 {
+		keys := make([]string, 0, len(resource.Spec.Env))
+		for key := range resource.Spec.Env {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fmt.Fprintln(w, "Env:")
+		for _, key := range keys {
+			fmt.Fprintf(w, "- %s=%s\n", key, resource.Spec.Env[key].Text())
 		}
-		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", resource.Key, resource.RuntimeName, state, health, orDash(resource.Spec.Image))
 	}
-	_ = tw.Flush()
 }
 
 func printLogs(w io.Writer, chunks []runtimepkg.LogChunk) {
@@ -714,8 +3002,18 @@ func printCatalogDetail(w io.Writer, template *appsvc.TemplateDetail) {
 	if len(template.Tags) > 0 {
 		fmt.Fprintf(w, "Tags: %s\n", strings.Join(template.Tags, ", "))
 	}
+	if template.Team != "" {
+		fmt.Fprintf(w, "Team: %s\n", template.Team)
+	}
+	if template.Visibility != "" {
+		fmt.Fprintf(w, "Visibility: %s\n", template.Visibility)
+	}
+	if len(template.SharedWith) > 0 {
+		fmt.Fprintf(w, "Shared with: %s\n", strings.Join(template.SharedWith, ", "))
+	}
 	printStructuredBlock(w, "Runtime", template.Runtime)
 	printStructuredBlock(w, "Env", template.Env)
+	printStructuredBlock(w, "Env schema", template.EnvSchema)
 	printStructuredBlock(w, "Ports", template.Ports)
 	printStructuredBlock(w, "Volumes", template.Volumes)
 	printStructuredBlock(w, "Imports", template.Imports)
@@ -724,6 +3022,228 @@ func printCatalogDetail(w io.Writer, template *appsvc.TemplateDetail) {
 	printStructuredBlock(w, "Develop", template.Develop)
 }
 
+func printDevcontainer(w io.Writer, view *appsvc.DevcontainerView) {
+	fmt.Fprintf(w, "Project: %s\n", view.Project)
+	if view.Network != "" {
+		fmt.Fprintf(w, "Network: %s (workspace %s)\n", view.Network, view.Workspace)
+	}
+	if len(view.Hosts) > 0 {
+		fmt.Fprintf(w, "Hosts: %s\n", strings.Join(view.Hosts, ", "))
+	}
+	for _, diagnostic := range view.Diagnostics {
+		fmt.Fprintf(w, "Warning: %s\n", diagnostic)
+	}
+	if view.Written {
+		fmt.Fprintln(w, "Wrote:")
+		for _, file := range view.Files {
+			fmt.Fprintf(w, "  %s\n", file.Path)
+		}
+		return
+	}
+	for _, file := range view.Files {
+		fmt.Fprintf(w, "\n--- %s\n%s", file.Path, file.Content)
+	}
+}
+
+func printProjectInventory(w io.Writer, inventory *appsvc.ProjectInventoryView) {
+	fmt.Fprintf(w, "Root: %s\n", inventory.Root)
+	if len(inventory.Projects) == 0 {
+		fmt.Fprintln(w, "No projects found.")
+	} else {
+		tw := newTabWriter(w)
+		fmt.Fprintln(tw, "PROJECT\tTYPE\tFRAMEWORK\tBRANCH\tSCAN\tPATH")
+		for _, project := range inventory.Projects {
+			relative, err := filepath.Rel(inventory.Root, project.Path)
+			if err != nil {
+				relative = project.Path
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", project.Name, orDash(project.ProjectType), orDash(project.Framework), orDash(project.GitBranch), formatMillis(project.ScanMs), relative)
+		}
+		_ = tw.Flush()
+	}
+	if inventory.Hidden > 0 {
+		fmt.Fprintf(w, "Hidden by filters: %d\n", inventory.Hidden)
+	}
+	fmt.Fprintf(w, "Max depth: %d\n", inventory.MaxDepth)
+	fmt.Fprintf(w, "Scan time: %s\n", formatMillis(inventory.TotalMs))
+	for _, rule := range inventory.Rules {
+		pattern := rule.Pattern
+		if rule.Action == "depth" {
+			pattern = fmt.Sprintf("%s (%d)", pattern, rule.Depth)
+		}
+		fmt.Fprintf(w, "Rule: %s %s [%s]\n", rule.Action, pattern, rule.Source)
+	}
+	for _, skipped := range inventory.Skipped {
+		fmt.Fprintf(w, "Skipped: %s (%s)\n", skipped.Path, skipped.Rule)
+	}
+}
+
+func printProjectSuggestions(w io.Writer, suggestions *appsvc.ProjectSuggestionView) {
+	fmt.Fprintf(w, "Project: %s (%s)\n", suggestions.Project, orDash(suggestions.Framework))
+	if len(suggestions.Resources) == 0 {
+		fmt.Fprintln(w, "No stack to suggest from the configured catalogs.")
+	} else {
+		tw := newTabWriter(w)
+		fmt.Fprintln(tw, "RESOURCE\tROLE\tTEMPLATE\tDEPENDS ON\tNOTE")
+		for _, resource := range suggestions.Resources {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", resource.Key, resource.Role, resource.Template, orDash(strings.Join(resource.DependsOn, ", ")), orDash(resource.Note))
+		}
+		_ = tw.Flush()
+	}
+	for _, missing := range suggestions.Missing {
+		fmt.Fprintf(w, "Missing template: %s\n", missing)
+	}
+	switch {
+	case suggestions.Written != "":
+		fmt.Fprintf(w, "Wrote workspace %s to %s\n", suggestions.Workspace, suggestions.Written)
+	case suggestions.Manifest != "":
+		fmt.Fprintln(w, "---")
+		_, _ = io.WriteString(w, suggestions.Manifest)
+	}
+}
+
+func printDatabaseInspection(w io.Writer, inspection *appsvc.DatabaseInspectionView) {
+	fmt.Fprintf(w, "Resource: %s (%s)\n", inspection.Resource, inspection.Engine)
+	fmt.Fprintf(w, "Size: %s\n", formatBytes(uint64(max(inspection.SizeBytes, 0))))
+	fmt.Fprintf(w, "Connections: %d\n", inspection.Connections)
+	if len(inspection.Databases) > 0 {
+		tw := newTabWriter(w)
+		if inspection.Engine == "redis" {
+			fmt.Fprintln(tw, "KEYSPACE\tKEYS")
+			for _, database := range inspection.Databases {
+				fmt.Fprintf(tw, "%s\t%d\n", database.Name, database.Keys)
+			}
+		} else {
+			fmt.Fprintln(tw, "DATABASE\tSIZE")
+			for _, database := range inspection.Databases {
+				fmt.Fprintf(tw, "%s\t%s\n", database.Name, formatBytes(uint64(max(database.SizeBytes, 0))))
+			}
+		}
+		_ = tw.Flush()
+	}
+	if len(inspection.Tables) > 0 {
+		tw := newTabWriter(w)
+		fmt.Fprintln(tw, "TABLE\tROWS\tSIZE")
+		for _, table := range inspection.Tables {
+			fmt.Fprintf(tw, "%s.%s\t%d\t%s\n", table.Schema, table.Name, table.Rows, formatBytes(uint64(max(table.SizeBytes, 0))))
+		}
+		_ = tw.Flush()
+	}
+}
+
+func printURLCatalog(w io.Writer, catalog *appsvc.URLCatalogView) {
+	if len(catalog.Entries) == 0 {
+		fmt.Fprintln(w, "No published URLs.")
+	} else {
+		tw := newTabWriter(w)
+		fmt.Fprintln(tw, "WORKSPACE\tNAME\tURL\tSTATUS\tHEALTH")
+		for _, entry := range catalog.Entries {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", entry.Workspace, entry.Label, entry.URL, entry.Status, orDash(entry.Health))
+		}
+		_ = tw.Flush()
+	}
+	for _, message := range catalog.Errors {
+		fmt.Fprintf(w, "Warning: %s\n", message)
+	}
+}
+
+func printStatusPage(w io.Writer, page *appsvc.StatusPageView) {
+	if len(page.Workspaces) == 0 {
+		fmt.Fprintln(w, "No workspaces.")
+		return
+	}
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "WORKSPACE\tOWNER\tEXPIRES\tSTATE\tNEXT MAINTENANCE")
+	for _, item := range page.Workspaces {
+		state := "available"
+		switch {
+		case item.Expired:
+			state = "expired"
+		case item.InMaintenance:
+			state = "maintenance"
+		}
+		next := "-"
+		if len(item.Upcoming) > 0 {
+			event := item.Upcoming[0]
+			next = fmt.Sprintf("%s %s", event.Start.Local().Format("2006-01-02 15:04"), event.Summary)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", item.Name, orDash(item.Owner), orDash(item.Expires), state, next)
+	}
+	_ = tw.Flush()
+}
+
+func printConnections(w io.Writer, connections *appsvc.ConnectionView) {
+	fmt.Fprintf(w, "Resource: %s", connections.Resource)
+	if connections.Engine != "" {
+		fmt.Fprintf(w, " (%s)", connections.Engine)
+	}
+	fmt.Fprintln(w)
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "FROM\tPORT\tKIND\tVALUE")
+	for _, scope := range []struct {
+		Name    string
+		Entries []appsvc.ConnectionEntry
+	}{{"host", connections.Host}, {"network", connections.Network}} {
+		for _, entry := range scope.Entries {
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", scope.Name, entry.Port, entry.Kind, entry.Value)
+		}
+	}
+	_ = tw.Flush()
+	for _, note := range connections.Notes {
+		fmt.Fprintf(w, "Note: %s\n", note)
+	}
+}
+
+func printQueryResult(w io.Writer, result *appsvc.DatabaseQueryView) {
+	if len(result.Columns) > 0 {
+		tw := newTabWriter(w)
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(result.Columns, "\t")))
+		for _, row := range result.Rows {
+			values := make([]string, len(result.Columns))
+			for i, column := range result.Columns {
+				values[i] = row[column]
+			}
+			fmt.Fprintln(tw, strings.Join(values, "\t"))
+		}
+		_ = tw.Flush()
+	}
+	suffix := ""
+	if result.Truncated {
+		suffix = ", truncated"
+	}
+	fmt.Fprintf(w, "%d rows in %s%s\n", len(result.Rows), formatMillis(result.DurationMs), suffix)
+}
+
+func printQueryHistory(w io.Writer, history *appsvc.QueryHistoryView) {
+	if len(history.Queries) == 0 {
+		fmt.Fprintf(w, "No queries recorded for %s.\n", history.Workspace)
+		return
+	}
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "STARTED\tACTOR\tRESOURCE\tROWS\tDURATION\tQUERY\tERROR")
+	for _, query := range history.Queries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\t%s\n", query.StartedAt.Format(time.RFC3339), orDash(query.Actor), query.Resource, query.Rows, formatMillis(query.DurationMs), strings.Join(strings.Fields(query.Query), " "), orDash(query.Error))
+	}
+	_ = tw.Flush()
+}
+
+func printScriptHistory(w io.Writer, history *appsvc.ProjectScriptHistoryView) {
+	if len(history.Runs) == 0 {
+		fmt.Fprintf(w, "No script runs recorded for %s.\n", history.Path)
+		return
+	}
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "STARTED\tACTOR\tSCRIPT\tEXIT\tDURATION\tCOMMAND")
+	for _, run := range history.Runs {
+		exit := strconv.Itoa(run.ExitCode)
+		if run.Error != "" {
+			exit = "error"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", run.StartedAt.Format(time.RFC3339), orDash(run.Actor), run.Script, exit, formatMillis(run.DurationMs), strings.Join(run.Command, " "))
+	}
+	_ = tw.Flush()
+}
+
 func printScanResult(w io.Writer, result *appsvc.ProjectScanView) {
 	if result == nil {
 		fmt.Fprintln(w, "No scan result.")
@@ -741,6 +3261,9 @@ func printScanResult(w io.Writer, result *appsvc.ProjectScanView) {
 	if result.PackageManager != "" {
 		fmt.Fprintf(w, "Package manager: %s\n", result.PackageManager)
 	}
+	if result.NodeVersion != "" {
+		fmt.Fprintf(w, "Node version: %s (%s)\n", result.NodeVersion, result.NodeVersionSource)
+	}
 	if result.EntryPoint != "" {
 		fmt.Fprintf(w, "Entry point: %s\n", result.EntryPoint)
 	}
@@ -750,12 +3273,52 @@ func printScanResult(w io.Writer, result *appsvc.ProjectScanView) {
 	if len(result.SuggestedTemplates) > 0 {
 		fmt.Fprintf(w, "Suggested templates: %s\n", strings.Join(result.SuggestedTemplates, ", "))
 	}
+	if len(result.Dependencies) > 0 {
+		fmt.Fprintf(w, "Dependencies: %s\n", strings.Join(result.Dependencies, ", "))
+	}
+	if len(result.Overrides) > 0 {
+		fmt.Fprintf(w, "Overridden: %s\n", strings.Join(result.Overrides, ", "))
+	}
+	if result.Archived {
+		fmt.Fprintln(w, "Archived: yes")
+	}
+	for _, group := range []struct {
+		title      string
+		extensions []appsvc.ProjectExtension
+	}{{"Plugins", result.Plugins}, {"Themes", result.Themes}} {
+		if len(group.extensions) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%s:\n", group.title)
+		tw := newTabWriter(w)
+		fmt.Fprintln(tw, "SLUG\tNAME\tVERSION")
+		for _, extension := range group.extensions {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", extension.Slug, extension.Name, orDash(extension.Version))
+		}
+		_ = tw.Flush()
+	}
+	if len(result.Scripts) > 0 {
+		fmt.Fprintln(w, "Scripts:")
+		tw := newTabWriter(w)
+		fmt.Fprintln(tw, "NAME\tSOURCE\tCOMMAND")
+		for _, script := range result.Scripts {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", script.Name, script.Source, script.Command)
+		}
+		_ = tw.Flush()
+	}
 	if len(result.Services) > 0 {
 		fmt.Fprintln(w, "Compose services:")
 		tw := newTabWriter(w)
-		fmt.Fprintln(tw, "NAME\tTYPE\tIMAGE\tPORTS\tDEPENDS ON")
+		fmt.Fprintln(tw, "NAME\tTYPE\tIMAGE\tPORTS\tDEPENDS ON\tVOLUMES")
 		for _, service := range result.Services {
-			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", service.Name, orDash(service.ServiceType), orDash(service.Image), orDash(strings.Join(service.Ports, ", ")), orDash(strings.Join(service.DependsOn, ", ")))
+			var dependsOn []string
+			for _, dependency := range service.Dependencies {
+				if dependency.Condition != "service_started" {
+					dependency.Service += " (" + dependency.Condition + ")"
+				}
+				dependsOn = append(dependsOn, dependency.Service)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", service.Name, orDash(service.ServiceType), orDash(service.Image), orDash(strings.Join(service.Ports, ", ")), orDash(strings.Join(dependsOn, ", ")), orDash(strings.Join(service.Volumes, ", ")))
 		}
 		_ = tw.Flush()
 	}
@@ -829,6 +3392,19 @@ func capabilitiesText(capabilities runtimepkg.AdapterCapabilities) string {
 	return strings.Join(values, ",")
 }
 
+func formatBytes(value uint64) string {
+	const unit = 1024
+	if value < unit {
+		return fmt.Sprintf("%dB", value)
+	}
+	div, exp := uint64(unit), 0
+	for n := value / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(value)/float64(div), "KMGTPE"[exp])
+}
+
 func orDash(value string) string {
 	if strings.TrimSpace(value) == "" {
 		return "-"
@@ -841,25 +3417,45 @@ func newTabWriter(w io.Writer) *tabwriter.Writer {
 }
 
 func writeRootUsage(w io.Writer) {
-	fmt.Fprintln(w, "Usage: devarch [--workspace-root PATH ...] [--catalog-root PATH ...] [--json] <command> ...")
+	fmt.Fprintln(w, "Usage: devarch [--workspace-root PATH ...] [--catalog-root PATH ...] [--json] [--read-only] [--team NAME] [--state-dir PATH] <command> ...")
 	fmt.Fprintln(w, "")
 	fmt.Fprintln(w, "Commands:")
 	fmt.Fprintln(w, "  workspace list")
 	fmt.Fprintln(w, "  workspace open <name>")
 	fmt.Fprintln(w, "  workspace plan <name>")
 	fmt.Fprintln(w, "  workspace apply <name>")
+	fmt.Fprintln(w, "  workspace render [--canonical] <name>")
 	fmt.Fprintln(w, "  workspace status <name>")
+	fmt.Fprintln(w, "  workspace stats <name> [resource]")
+	fmt.Fprintln(w, "  workspace top <name> <resource>")
+	fmt.Fprintln(w, "  workspace inspect <name> <resource>")
+	fmt.Fprintln(w, "  workspace save-images <name> <archive>")
+	fmt.Fprintln(w, "  workspace load-images <name> <archive>")
+	fmt.Fprintln(w, "  workspace update-images [--apply] [--ignore-window] <name>")
 	fmt.Fprintln(w, "  workspace logs [--tail N] [--since RFC3339] [--follow] <name> <resource>")
 	fmt.Fprintln(w, "  workspace exec <name> <resource> [--] <command...>")
-	fmt.Fprintln(w, "  workspace restart <name> <resource>")
+	fmt.Fprintln(w, "  workspace restart [--timeout SECONDS] <name> <resource>")
+	fmt.Fprintln(w, "  workspace rolling-restart [--timeout SECONDS] [--ready-timeout DURATION] <name>")
 	fmt.Fprintln(w, "  doctor")
 	fmt.Fprintln(w, "  runtime status")
 	fmt.Fprintln(w, "  socket status")
+	fmt.Fprintln(w, "  socket diagnose [--repair]")
 	fmt.Fprintln(w, "  socket start")
 	fmt.Fprintln(w, "  socket stop")
 	fmt.Fprintln(w, "  catalog list")
 	fmt.Fprintln(w, "  catalog show <template>")
 	fmt.Fprintln(w, "  scan project <path>")
+	fmt.Fprintln(w, "  scan all <dir>")
+	fmt.Fprintln(w, "  scan run <path> <script>")
+	fmt.Fprintln(w, "  scan suggest <path>")
+	fmt.Fprintln(w, "  schema list")
+	fmt.Fprintln(w, "  schema show <workspace|template|team>")
+	fmt.Fprintln(w, "  names workspaces")
+	fmt.Fprintln(w, "  names templates")
+	fmt.Fprintln(w, "  names resources <workspace>")
+	fmt.Fprintln(w, "  backup create [--keep N] <dir>")
+	fmt.Fprintln(w, "  backup list <dir>")
+	fmt.Fprintln(w, "  backup restore <archive> <dir>")
 }
 
 func writeWorkspaceUsage(w io.Writer) {
@@ -867,20 +3463,69 @@ func writeWorkspaceUsage(w io.Writer) {
 	fmt.Fprintln(w, "  devarch [global flags] workspace list")
 	fmt.Fprintln(w, "  devarch [global flags] workspace open <name>")
 	fmt.Fprintln(w, "  devarch [global flags] workspace plan <name>")
-	fmt.Fprintln(w, "  devarch [global flags] workspace apply <name>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace apply [--wait-healthy D] [--parallel N] [--changed-only] [--ignore-capacity] <name>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace render [--canonical] <name>")
 	fmt.Fprintln(w, "  devarch [global flags] workspace status <name>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace topology <name>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace stats <name> [resource]")
+	fmt.Fprintln(w, "  devarch [global flags] workspace top <name> <resource>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace inspect <name> <resource>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace export [--compose docker|podman|podman-compose|nerdctl] [--redact secrets|share] [--portable-paths] <name> <resource> <dir|file.zip>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace save-images <name> <archive>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace load-images <name> <archive>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace update-images [--apply] [--ignore-window] <name>")
 	fmt.Fprintln(w, "  devarch [global flags] workspace logs [--tail N] [--since RFC3339] [--follow] <name> <resource>")
 	fmt.Fprintln(w, "  devarch [global flags] workspace exec <name> <resource> [--] <command...>")
-	fmt.Fprintln(w, "  devarch [global flags] workspace restart <name> <resource>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace run <name> <resource> [--] <command...>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace restart [--timeout SECONDS] <name> <resource>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace rolling-restart [--timeout SECONDS] [--ready-timeout DURATION] <name>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace stop [--timeout SECONDS] <name>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace start <name>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace idle [--cpu PERCENT] [--net-bytes N] [--samples N] [--interval DURATION] [--stop] [--timeout SECONDS] <name>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace activity [--limit N] [--before RFC3339] [--actor USER] <name>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace brief [--if-none-match ETAG] <name>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace loadtest [--port N] [--interval D] <name> <resource> <script.js>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace chaos pause|unpause <name> <resource>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace chaos kill [--signal SIG] <name> <resource>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace chaos network --duration D [--latency D] [--jitter D] [--loss PCT] <name> <resource>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace requests [--tail N] <name> <resource>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace capture [--duration D] [--packets N] [--filter EXPR] [--output FILE] <name> <resource>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace port-forward <name> <user@host|ssh://user@host[:port]>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace conflicts <name>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace adopt [--as KEY] [--template NAME] [--write] <name> [container]")
+	fmt.Fprintln(w, "  devarch [global flags] workspace artisan <name> <resource> [--] <args...>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace laravel-workers [--write] <name> <resource>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace db <name> <resource>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace query [--limit N] <name> <resource> <sql>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace query-log [--actor USER] <name>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace connect <name> <resource>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace urls [--all]")
+	fmt.Fprintln(w, "  devarch [global flags] workspace badge [--resource KEY] [--output FILE] <name>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace calendar [--output FILE]")
+	fmt.Fprintln(w, "  devarch [global flags] workspace status-page [--days N]")
+	fmt.Fprintln(w, "  devarch [global flags] workspace schedules [--run] [--window D]")
+	fmt.Fprintln(w, "  devarch [global flags] workspace notes <name> [resource]")
+	fmt.Fprintln(w, "  devarch [global flags] workspace expiry [--warn-days N] [--stop] [--timeout SECONDS]")
+	fmt.Fprintln(w, "  devarch [global flags] workspace validate [--recent N]")
+	fmt.Fprintln(w, "  devarch [global flags] workspace health-suggest [--apply NAME] <name> <resource>")
+	fmt.Fprintln(w, "  devarch [global flags] workspace extend <name> <YYYY-MM-DD|+Nd>")
 }
 
 func writeSocketUsage(w io.Writer) {
 	fmt.Fprintln(w, "Socket commands:")
 	fmt.Fprintln(w, "  devarch [global flags] socket status")
+	fmt.Fprintln(w, "  devarch [global flags] socket diagnose [--repair]")
 	fmt.Fprintln(w, "  devarch [global flags] socket start")
 	fmt.Fprintln(w, "  devarch [global flags] socket stop")
 }
 
+func writeBackupUsage(w io.Writer) {
+	fmt.Fprintln(w, "Backup commands:")
+	fmt.Fprintln(w, "  devarch [global flags] backup create [--keep N] <dir>")
+	fmt.Fprintln(w, "  devarch [global flags] backup list <dir>")
+	fmt.Fprintln(w, "  devarch [global flags] backup restore <archive> <dir>")
+}
+
 func writeCatalogUsage(w io.Writer) {
 	fmt.Fprintln(w, "Catalog commands:")
 	fmt.Fprintln(w, "  devarch [global flags] catalog list")
@@ -890,4 +3535,23 @@ func writeCatalogUsage(w io.Writer) {
 func writeScanUsage(w io.Writer) {
 	fmt.Fprintln(w, "Scan commands:")
 	fmt.Fprintln(w, "  devarch [global flags] scan project <path>")
+	fmt.Fprintln(w, "  devarch [global flags] scan all [--include GLOB] [--exclude GLOB] [--depth N] [--concurrency N] [--timeout DURATION] [--language L] [--framework F] [--frontend BOOL] [--archived] <dir>")
+	fmt.Fprintln(w, "  devarch [global flags] scan run [--workspace NAME] <path> <script> [-- args...]")
+	fmt.Fprintln(w, "  devarch [global flags] scan wp [--workspace NAME] <path> [--] <wp-cli args...>")
+	fmt.Fprintln(w, "  devarch [global flags] scan history [--actor USER] <path>")
+	fmt.Fprintln(w, "  devarch [global flags] scan suggest [--name NAME] [--write] <path>")
+	fmt.Fprintln(w, "  devarch [global flags] scan devcontainer [--workspace NAME] [--write] <path>")
+}
+
+func writeSchemaUsage(w io.Writer) {
+	fmt.Fprintln(w, "Schema commands:")
+	fmt.Fprintln(w, "  devarch [global flags] schema list")
+	fmt.Fprintln(w, "  devarch [global flags] schema show <workspace|template|team>")
+}
+
+func writeNamesUsage(w io.Writer) {
+	fmt.Fprintln(w, "Names commands:")
+	fmt.Fprintln(w, "  devarch [global flags] names workspaces")
+	fmt.Fprintln(w, "  devarch [global flags] names templates")
+	fmt.Fprintln(w, "  devarch [global flags] names resources <workspace>")
 }
@@ -219,6 +219,8 @@ func newTestServiceFactory(t *testing.T) serviceFactory {
 		return appsvc.New(appsvc.Config{
 			WorkspaceRoots: cfg.workspaceRoots,
 			CatalogRoots:   cfg.catalogRoots,
+			ReadOnly:       cfg.readOnly,
+			ReadOnlyReason: cfg.readOnlyReason,
 			Adapters: map[string]runtimepkg.Adapter{
 				runtimepkg.ProviderDocker: &fakeAdapter{
 					provider: runtimepkg.ProviderDocker,
@@ -294,8 +296,10 @@ func (f *fakeAdapter) RemoveNetwork(context.Context, *runtimepkg.DesiredNetwork)
 func (f *fakeAdapter) ApplyResource(context.Context, runtimepkg.ApplyResourceRequest) error {
 	return nil
 }
-func (f *fakeAdapter) RemoveResource(context.Context, runtimepkg.ResourceRef) error  { return nil }
-func (f *fakeAdapter) RestartResource(context.Context, runtimepkg.ResourceRef) error { return nil }
+func (f *fakeAdapter) RemoveResource(context.Context, runtimepkg.ResourceRef) error { return nil }
+func (f *fakeAdapter) RestartResource(context.Context, runtimepkg.ResourceRef, runtimepkg.RestartRequest) error {
+	return nil
+}
 
 func (f *fakeAdapter) StreamLogs(_ context.Context, _ runtimepkg.ResourceRef, _ runtimepkg.LogsRequest, consume runtimepkg.LogsConsumer) error {
 	for _, chunk := range f.logChunks {
@@ -333,3 +337,259 @@ func writeFile(t *testing.T, path, content string) {
 }
 
 var _ runtimepkg.Adapter = (*fakeAdapter)(nil)
+
+func TestRunJSONErrorsUseStableEnvelope(t *testing.T) {
+	catalogRoot := t.TempDir()
+	args := []string{"--catalog-root", catalogRoot, "--json", "catalog", "show", "missing"}
+	stdout, stderr, err := runCLI(args, newTestServiceFactory(t))
+	if err == nil {
+		t.Fatal("runCLI catalog show missing returned nil error")
+	}
+	if silent, ok := err.(silentError); !ok || !silent.Silent() {
+		t.Fatalf("error = %#v, want silent error after JSON envelope", err)
+	}
+	if stdout != "" {
+		t.Fatalf("stdout = %q, want empty", stdout)
+	}
+	var envelope errorEnvelope
+	if err := json.Unmarshal([]byte(stderr), &envelope); err != nil {
+		t.Fatalf("json.Unmarshal error envelope returned error: %v\nstderr:\n%s", err, stderr)
+	}
+	if got, want := envelope.Error.Code, errorCodeTemplateNotFound; got != want {
+		t.Fatalf("error code = %q, want %q", got, want)
+	}
+	if !strings.Contains(envelope.Error.Message, "missing") {
+		t.Fatalf("error message = %q, want template name", envelope.Error.Message)
+	}
+}
+
+func TestRunReadOnlyRejectsMutatingCommands(t *testing.T) {
+	t.Setenv("DEVARCH_READ_ONLY", "host maintenance")
+	_, stderr, err := runCLI([]string{"--json", "socket", "start"}, newTestServiceFactory(t))
+	if err == nil {
+		t.Fatal("runCLI socket start returned nil error in read-only mode")
+	}
+	var envelope errorEnvelope
+	if err := json.Unmarshal([]byte(stderr), &envelope); err != nil {
+		t.Fatalf("json.Unmarshal error envelope returned error: %v\nstderr:\n%s", err, stderr)
+	}
+	if got, want := envelope.Error.Code, errorCodeReadOnly; got != want {
+		t.Fatalf("error code = %q, want %q", got, want)
+	}
+	if !strings.Contains(envelope.Error.Message, "host maintenance") {
+		t.Fatalf("error message = %q, want reason", envelope.Error.Message)
+	}
+	if _, _, err := runCLI([]string{"--json", "socket", "status"}, newTestServiceFactory(t)); err != nil {
+		t.Fatalf("runCLI socket status returned error in read-only mode: %v", err)
+	}
+	if enabled, reason := readOnlyFromEnv("0"); enabled || reason != "" {
+		t.Fatalf("readOnlyFromEnv(0) = %v, %q", enabled, reason)
+	}
+}
+
+func TestRunReadOnlyRejectsOutputFiles(t *testing.T) {
+	workspaceRoot := t.TempDir()
+	output := filepath.Join(t.TempDir(), "out")
+	for _, command := range [][]string{
+		{"workspace", "badge", "--output", output, "shop-local"},
+		{"workspace", "calendar", "--output", output},
+	} {
+		args := append([]string{"--read-only", "--workspace-root", workspaceRoot, "--json"}, command...)
+		_, stderr, err := runCLI(args, newTestServiceFactory(t))
+		if err == nil {
+			t.Fatalf("%v returned nil error in read-only mode", command)
+		}
+		var envelope errorEnvelope
+		if err := json.Unmarshal([]byte(stderr), &envelope); err != nil {
+			t.Fatalf("json.Unmarshal error envelope returned error: %v\nstderr:\n%s", err, stderr)
+		}
+		if got, want := envelope.Error.Code, errorCodeReadOnly; got != want {
+			t.Fatalf("%v error code = %q, want %q", command, got, want)
+		}
+		if _, err := os.Stat(output); !os.IsNotExist(err) {
+			t.Fatalf("%v wrote %s in read-only mode", command, output)
+		}
+	}
+}
+
+func TestRunNamesPrintsSortedCompletionLists(t *testing.T) {
+	catalogRoot := filepath.Join(repoRoot(t), "catalog", "builtin")
+	want := []string{"laravel-app", "nginx", "node-api", "postgres", "redis", "vite-web"}
+
+	stdout, stderr, err := runCLI([]string{"--catalog-root", catalogRoot, "names", "templates"}, newTestServiceFactory(t))
+	if err != nil {
+		t.Fatalf("runCLI names templates returned error: %v\nstderr:\n%s", err, stderr)
+	}
+	if got := strings.Fields(stdout); !reflect.DeepEqual(got, want) {
+		t.Fatalf("names templates = %v, want %v", got, want)
+	}
+
+	stdout, stderr, err = runCLI([]string{"--catalog-root", catalogRoot, "--json", "names", "templates"}, newTestServiceFactory(t))
+	if err != nil {
+		t.Fatalf("runCLI --json names templates returned error: %v\nstderr:\n%s", err, stderr)
+	}
+	var names []string
+	if err := json.Unmarshal([]byte(stdout), &names); err != nil {
+		t.Fatalf("json.Unmarshal names returned error: %v\nstdout:\n%s", err, stdout)
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("names = %v, want %v", names, want)
+	}
+}
+
+func TestRunWorkspaceRenderCanonicalIsByteStable(t *testing.T) {
+	workspaceRoot := t.TempDir()
+	catalogRoot := filepath.Join(repoRoot(t), "catalog", "builtin")
+	writeFile(t, filepath.Join(workspaceRoot, "render-local", "devarch.workspace.yaml"), `apiVersion: devarch.io/alpha1
+kind: Workspace
+metadata:
+  name: render-local
+runtime:
+  provider: docker
+  isolatedNetwork: true
+catalog:
+  sources:
+    - `+catalogRoot+`
+policies:
+  autoWire: true
+resources:
+  postgres:
+    template: postgres
+    env:
+      POSTGRES_DB: shop
+  redis:
+    template: redis
+`)
+	args := []string{"--workspace-root", workspaceRoot, "workspace", "render", "--canonical", "render-local"}
+	first, stderr, err := runCLI(args, newTestServiceFactory(t))
+	if err != nil {
+		t.Fatalf("runCLI workspace render returned error: %v\nstderr:\n%s", err, stderr)
+	}
+	for i := 0; i < 5; i++ {
+		again, _, err := runCLI(args, newTestServiceFactory(t))
+		if err != nil {
+			t.Fatalf("runCLI workspace render returned error: %v", err)
+		}
+		if again != first {
+			t.Fatalf("canonical render changed between runs\n--- first ---\n%s\n--- again ---\n%s", first, again)
+		}
+	}
+
+	stdout, stderr, err := runCLI([]string{"--workspace-root", workspaceRoot, "--json", "workspace", "render", "render-local"}, newTestServiceFactory(t))
+	if err != nil {
+		t.Fatalf("runCLI --json workspace render returned error: %v\nstderr:\n%s", err, stderr)
+	}
+	var view struct {
+		Digest  string `json:"digest"`
+		Payload struct {
+			Resources []struct {
+				Key string `json:"key"`
+			} `json:"resources"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &view); err != nil {
+		t.Fatalf("json.Unmarshal render returned error: %v\nstdout:\n%s", err, stdout)
+	}
+	if !strings.HasPrefix(view.Digest, "sha256:") || len(view.Payload.Resources) != 2 {
+		t.Fatalf("render view = %+v, want sha256 digest and two resources", view)
+	}
+}
+
+func TestRunSchemaShowPrintsEmbeddedSchema(t *testing.T) {
+	stdout, stderr, err := runCLI([]string{"schema", "show", "workspace"}, newTestServiceFactory(t))
+	if err != nil {
+		t.Fatalf("runCLI schema show returned error: %v\nstderr:\n%s", err, stderr)
+	}
+	var document map[string]any
+	if err := json.Unmarshal([]byte(stdout), &document); err != nil {
+		t.Fatalf("json.Unmarshal schema returned error: %v", err)
+	}
+	if got, want := document["$id"], "https://devarch.io/schemas/workspace.schema.json"; got != want {
+		t.Fatalf("schema $id = %v, want %q", got, want)
+	}
+
+	if _, _, err := runCLI([]string{"schema", "show", "stack"}, newTestServiceFactory(t)); err == nil {
+		t.Fatal("expected unknown schema error")
+	}
+}
+
+func TestRunNeedsNoStateDirectoryForCommandsWithoutHistory(t *testing.T) {
+	catalogRoot := filepath.Join(repoRoot(t), "catalog", "builtin")
+	t.Setenv("DEVARCH_STATE_DIR", "")
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("HOME", "")
+	if _, stderr, err := runCLI([]string{"--catalog-root", catalogRoot, "catalog", "list"}, nil); err != nil {
+		t.Fatalf("catalog list without a state directory returned error: %v\nstderr:\n%s", err, stderr)
+	}
+
+	stateDir := filepath.Join(t.TempDir(), "state")
+	if _, stderr, err := runCLI([]string{"--catalog-root", catalogRoot, "--read-only", "--state-dir", stateDir, "catalog", "list"}, nil); err != nil {
+		t.Fatalf("read-only catalog list returned error: %v\nstderr:\n%s", err, stderr)
+	}
+	if _, err := os.Stat(stateDir); !os.IsNotExist(err) {
+		t.Fatalf("state directory stat = %v, want it never created", err)
+	}
+}
+
+func TestRunKeepsQueryAuditWithActorAcrossCLIRuns(t *testing.T) {
+	workspaceRoot := t.TempDir()
+	stateDir := filepath.Join(t.TempDir(), "state")
+	t.Setenv("DEVARCH_STATE_DIR", stateDir)
+	t.Setenv("DEVARCH_ACTOR", "alice")
+	writeFile(t, filepath.Join(workspaceRoot, "audit-local", "devarch.workspace.yaml"), `apiVersion: devarch.io/alpha1
+kind: Workspace
+metadata:
+  name: audit-local
+runtime:
+  provider: docker
+catalog:
+  sources:
+    - `+filepath.Join(repoRoot(t), "catalog", "builtin")+`
+resources:
+  postgres:
+    template: postgres
+`)
+	// The service is built from serviceConfig, as the real CLI builds it,
+	// with only the runtime adapter replaced.
+	factory := func(cfg cliConfig) (serviceAPI, error) {
+		config := serviceConfig(cfg)
+		config.Adapters = map[string]runtimepkg.Adapter{
+			runtimepkg.ProviderDocker: &fakeAdapter{
+				provider:     runtimepkg.ProviderDocker,
+				capabilities: runtimepkg.AdapterCapabilities{Inspect: true, Exec: true},
+				execResult:   &runtimepkg.ExecResult{ExitCode: 0, Stdout: "id\n1\n"},
+			},
+		}
+		config.LookPath = func(file string) (string, error) { return "/usr/bin/" + file, nil }
+		return appsvc.New(config)
+	}
+
+	if _, stderr, err := runCLI([]string{"--workspace-root", workspaceRoot, "workspace", "query", "audit-local", "postgres", "select 1 as id"}, factory); err != nil {
+		t.Fatalf("workspace query returned error: %v\nstderr:\n%s", err, stderr)
+	}
+	if _, _, err := runCLI([]string{"--workspace-root", workspaceRoot, "workspace", "query", "audit-local", "postgres", "DELETE FROM orders"}, factory); err == nil {
+		t.Fatal("workspace query accepted a DELETE")
+	}
+	t.Setenv("DEVARCH_ACTOR", "bob")
+	stdout, stderr, err := runCLI([]string{"--workspace-root", workspaceRoot, "--json", "workspace", "query-log", "--actor", "alice", "audit-local"}, factory)
+	if err != nil {
+		t.Fatalf("workspace query-log returned error: %v\nstderr:\n%s", err, stderr)
+	}
+	var history appsvc.QueryHistoryView
+	if err := json.Unmarshal([]byte(stdout), &history); err != nil {
+		t.Fatalf("json.Unmarshal query-log returned error: %v\nstdout:\n%s", err, stdout)
+	}
+	if len(history.Queries) != 2 {
+		t.Fatalf("query-log = %+v, want both of alice's queries", history.Queries)
+	}
+	refused, ran := history.Queries[0], history.Queries[1]
+	if refused.Actor != "alice" || refused.Query != "DELETE FROM orders" || refused.Error == "" {
+		t.Fatalf("newest record = %+v, want alice's refused DELETE", refused)
+	}
+	if ran.Actor != "alice" || ran.Rows != 1 || ran.Error != "" {
+		t.Fatalf("oldest record = %+v, want alice's select with one row", ran)
+	}
+	if _, err := os.Stat(filepath.Join(stateDir, "queries.jsonl")); err != nil {
+		t.Fatalf("query history was not written to the state directory: %v", err)
+	}
+}
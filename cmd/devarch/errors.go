@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"io"
+
+	"github.com/prospect-ogujiuba/devarch/internal/appsvc"
+	resolvepkg "github.com/prospect-ogujiuba/devarch/internal/resolve"
+	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+)
+
+// Stable machine-readable error codes emitted in --json mode.
+const (
+	errorCodeWorkspaceNotFound     = "workspace_not_found"
+	errorCodeTemplateNotFound      = "template_not_found"
+	errorCodeResourceNotFound      = "resource_not_found"
+	errorCodeNotFound              = "not_found"
+	errorCodeNameConflict          = "name_conflict"
+	errorCodeRuntimeUnavailable    = "runtime_unavailable"
+	errorCodeUnsupportedCapability = "unsupported_capability"
+	errorCodeUnsupportedOperation  = "unsupported_operation"
+	errorCodeUndefinedVariable     = "undefined_variable"
+	errorCodeReadOnly              = "read_only"
+	errorCodeInsufficientMemory    = "insufficient_memory"
+	errorCodeCommandFailed         = "command_failed"
+)
+
+type errorEnvelope struct {
+	Error errorBody `json:"error"`
+}
+
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details any    `json:"details,omitempty"`
+}
+
+// reportedError wraps an error that was already written as a JSON envelope so
+// main does not print it a second time.
+type reportedError struct {
+	err error
+}
+
+func (e *reportedError) Error() string { return e.err.Error() }
+func (e *reportedError) Unwrap() error { return e.err }
+func (e *reportedError) Silent() bool  { return true }
+
+func (e *reportedError) ExitCode() int {
+	var coded exitCoder
+	if errors.As(e.err, &coded) {
+		return coded.ExitCode()
+	}
+	return 1
+}
+
+func newErrorBody(err error) errorBody {
+	body := errorBody{Code: errorCodeCommandFailed, Message: err.Error()}
+
+	var notFound *appsvc.NotFoundError
+	var duplicate *appsvc.DuplicateWorkspaceNameError
+	var capability *appsvc.UnsupportedCapabilityError
+	var runtimeName *appsvc.RuntimeNameConflictError
+	var readOnly *appsvc.ReadOnlyError
+	var memory *appsvc.InsufficientMemoryError
+	var operation *runtimepkg.UnsupportedOperationError
+	var variable *resolvepkg.UndefinedVariableError
+	switch {
+	case errors.As(err, &notFound):
+		switch notFound.Kind {
+		case "workspace":
+			body.Code = errorCodeWorkspaceNotFound
+		case "template":
+			body.Code = errorCodeTemplateNotFound
+		case "resource":
+			body.Code = errorCodeResourceNotFound
+		default:
+			body.Code = errorCodeNotFound
+		}
+		details := map[string]string{"kind": notFound.Kind, "name": notFound.Name}
+		if notFound.Workspace != "" {
+			details["workspace"] = notFound.Workspace
+		}
+		body.Details = details
+	case errors.As(err, &duplicate):
+		body.Code = errorCodeNameConflict
+		body.Details = map[string]string{"name": duplicate.Name, "firstPath": duplicate.FirstPath, "secondPath": duplicate.SecondPath}
+	case errors.As(err, &runtimeName):
+		body.Code = errorCodeNameConflict
+		body.Details = map[string]any{"workspace": runtimeName.Workspace, "conflicts": runtimeName.Conflicts}
+	case errors.As(err, &readOnly):
+		body.Code = errorCodeReadOnly
+		body.Details = readOnly
+	case errors.As(err, &memory):
+		body.Code = errorCodeInsufficientMemory
+		body.Details = memory
+	case errors.As(err, &capability):
+		body.Code = errorCodeUnsupportedCapability
+		if capability.Capability == "provider" {
+			body.Code = errorCodeRuntimeUnavailable
+		}
+		body.Details = capability
+	case errors.As(err, &operation):
+		body.Code = errorCodeUnsupportedOperation
+		body.Details = map[string]string{"provider": operation.Provider, "operation": operation.Operation}
+	case errors.As(err, &variable):
+		body.Code = errorCodeUndefinedVariable
+		body.Details = map[string]string{"resource": variable.ResourceKey, "field": variable.Field, "name": variable.Name}
+	}
+	return body
+}
+
+// writeJSONError reports err as a stable error envelope and returns an error
+// that keeps the original exit status without being printed again.
+func writeJSONError(w io.Writer, err error) error {
+	var status *exitStatusError
+	if errors.As(err, &status) {
+		return err
+	}
+	if writeErr := writeJSON(w, errorEnvelope{Error: newErrorBody(err)}); writeErr != nil {
+		return err
+	}
+	return &reportedError{err: err}
+}
@@ -11,15 +11,36 @@ import (
 	"github.com/prospect-ogujiuba/devarch/internal/events"
 	"github.com/prospect-ogujiuba/devarch/internal/plan"
 	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+	"github.com/prospect-ogujiuba/devarch/internal/workspace"
 )
 
 var ErrBlocked = errors.New("apply blocked by diagnostics")
 
+// OperationHook marks apply operations that record a resource hook run.
+const OperationHook plan.ActionKind = "hook"
+
+// DefaultInitTimeout bounds the wait for an init resource to exit.
+const DefaultInitTimeout = 5 * time.Minute
+
+// initPollInterval is how often the executor inspects a running init resource.
+var initPollInterval = time.Second
+
 type Executor struct {
 	Adapter   runtimepkg.Adapter
 	Cache     cachepkg.Store
 	Publisher events.Publisher
-	Now       func() time.Time
+	// Actor is recorded with the apply as the principal that ran it.
+	Actor       string
+	Now         func() time.Time
+	InitTimeout time.Duration
+	// HealthTimeout, when positive, makes apply wait up to that long for
+	// the resources it started to report healthy and record how long each
+	// took. Apply does not fail when a resource never gets there.
+	HealthTimeout time.Duration
+	// Parallelism, when above one, applies independent resources
+	// concurrently, up to that many at a time, while still starting each
+	// resource only after the resources it depends on.
+	Parallelism int
 }
 
 func (e *Executor) Execute(ctx context.Context, diff *plan.Result, payload *Payload) (*Result, error) {
@@ -49,8 +70,10 @@ func (e *Executor) Execute(ctx context.Context, diff *plan.Result, payload *Payl
 	store := cachepkg.Normalize(e.Cache)
 	defer func() {
 		result.FinishedAt = now()
+		result.Images = imageCacheStats(result.Operations)
 		_ = store.SaveApply(ctx, cachepkg.ApplyRecord{
 			Workspace:  result.Workspace,
+			Actor:      e.Actor,
 			Provider:   result.Provider,
 			StartedAt:  result.StartedAt,
 			FinishedAt: result.FinishedAt,
@@ -65,43 +88,31 @@ func (e *Executor) Execute(ctx context.Context, diff *plan.Result, payload *Payl
 		}
 	}
 
-	for _, action := range diff.Actions {
-		operation := Operation{Scope: action.Scope, Target: action.Target, RuntimeName: action.RuntimeName, Kind: action.Kind}
-		message := strings.Join(action.Reasons, "; ")
-		operation.Message = message
-		switch action.Kind {
-		case plan.ActionNoop:
-			operation.Status = "skipped"
-			result.Operations = append(result.Operations, operation)
-			if err := e.publishProgress(payload.Workspace, action, operation.Status, message); err != nil {
-				return nil, err
+	var err error
+	if e.Parallelism > 1 {
+		var operations []Operation
+		operations, err = e.executeParallel(ctx, payload, diff.Actions, e.Parallelism)
+		result.Operations = append(result.Operations, operations...)
+	} else {
+		for _, action := range diff.Actions {
+			var operations []Operation
+			operations, err = e.runAction(ctx, payload, action)
+			result.Operations = append(result.Operations, operations...)
+			if err != nil {
+				break
 			}
-			continue
-		case plan.ActionAdd, plan.ActionModify, plan.ActionRemove, plan.ActionRestart:
-		default:
-			return nil, fmt.Errorf("apply execute: unsupported plan action %q", action.Kind)
 		}
+	}
+	var abort *abortError
+	if errors.As(err, &abort) {
+		return nil, abort.err
+	}
+	if err != nil {
+		return result, err
+	}
 
-		if err := e.publishProgress(payload.Workspace, action, "started", message); err != nil {
-			return nil, err
-		}
-		err := e.executeAction(ctx, action, payload)
-		if err != nil {
-			operation.Status = "failed"
-			if operation.Message != "" {
-				operation.Message += ": " + err.Error()
-			} else {
-				operation.Message = err.Error()
-			}
-			result.Operations = append(result.Operations, operation)
-			_ = e.publishProgress(payload.Workspace, action, operation.Status, operation.Message)
-			return result, err
-		}
-		operation.Status = "success"
-		result.Operations = append(result.Operations, operation)
-		if err := e.publishProgress(payload.Workspace, action, operation.Status, message); err != nil {
-			return nil, err
-		}
+	if e.HealthTimeout > 0 {
+		e.waitForHealthy(ctx, payload, result.Operations)
 	}
 
 	if e.Adapter.Capabilities().Inspect {
@@ -121,7 +132,224 @@ func (e *Executor) Execute(ctx context.Context, diff *plan.Result, payload *Payl
 	return result, nil
 }
 
-func (e *Executor) executeAction(ctx context.Context, action plan.Action, payload *Payload) error {
+func (e *Executor) now() time.Time {
+	if e.Now != nil {
+		return e.Now()
+	}
+	return time.Now()
+}
+
+// abortError wraps failures, such as a rejected progress event, that end
+// apply without a partial result.
+type abortError struct {
+	err error
+}
+
+func (e *abortError) Error() string { return e.err.Error() }
+
+func (e *abortError) Unwrap() error { return e.err }
+
+// runAction applies one plan action together with its hooks and returns the
+// operations it recorded, in order.
+func (e *Executor) runAction(ctx context.Context, payload *Payload, action plan.Action) ([]Operation, error) {
+	var operations []Operation
+	operation := Operation{Scope: action.Scope, Target: action.Target, RuntimeName: action.RuntimeName, Kind: action.Kind}
+	message := strings.Join(action.Reasons, "; ")
+	operation.Message = message
+	switch action.Kind {
+	case plan.ActionNoop:
+		operation.Status = "skipped"
+		operations = append(operations, operation)
+		if err := e.publishProgress(payload.Workspace, action, operation.Status, message); err != nil {
+			return operations, &abortError{err: err}
+		}
+		return operations, nil
+	case plan.ActionAdd, plan.ActionModify, plan.ActionRemove, plan.ActionRestart:
+	default:
+		return operations, &abortError{err: fmt.Errorf("apply execute: unsupported plan action %q", action.Kind)}
+	}
+
+	if err := e.publishProgress(payload.Workspace, action, "started", message); err != nil {
+		return operations, &abortError{err: err}
+	}
+	hooks := actionHooks(action, payload)
+	if hooks != nil {
+		hookOperations, err := e.runHooks(ctx, payload, action, "preApply", hooks.PreApply)
+		operations = append(operations, hookOperations...)
+		if err != nil {
+			return operations, err
+		}
+	}
+	var timing *cachepkg.OperationTiming
+	if action.Scope == plan.ScopeResource && action.Kind != plan.ActionRemove {
+		timing = &cachepkg.OperationTiming{StartedAt: e.now()}
+		operation.Timing = timing
+	}
+	err := e.executeAction(ctx, action, payload, timing)
+	if timing != nil {
+		timing.TotalMs = e.now().Sub(timing.StartedAt).Milliseconds()
+	}
+	if err != nil {
+		operation.Status = "failed"
+		if operation.Message != "" {
+			operation.Message += ": " + err.Error()
+		} else {
+			operation.Message = err.Error()
+		}
+		operations = append(operations, operation)
+		_ = e.publishProgress(payload.Workspace, action, operation.Status, operation.Message)
+		return operations, err
+	}
+	operation.Status = "success"
+	operations = append(operations, operation)
+	if err := e.publishProgress(payload.Workspace, action, operation.Status, message); err != nil {
+		return operations, &abortError{err: err}
+	}
+	if hooks != nil {
+		hookOperations, err := e.runHooks(ctx, payload, action, "postApply", hooks.PostApply)
+		operations = append(operations, hookOperations...)
+		if err != nil {
+			return operations, err
+		}
+	}
+	return operations, nil
+}
+
+type actionOutcome struct {
+	target     string
+	operations []Operation
+	err        error
+}
+
+// executeParallel runs workspace-scope and remove actions in plan order, then
+// starts the remaining resource actions as soon as the resources they depend
+// on have been applied, with at most limit in flight. Init resources and
+// their dependencies keep their place ahead of everything else. After the
+// first failure no new action starts; the ones in flight are allowed to
+// finish. Operations are recorded in completion order.
+func (e *Executor) executeParallel(ctx context.Context, payload *Payload, actions []plan.Action, limit int) ([]Operation, error) {
+	var operations []Operation
+	var scheduled []plan.Action
+	for _, action := range actions {
+		if action.Scope == plan.ScopeResource && action.Kind != plan.ActionRemove {
+			scheduled = append(scheduled, action)
+			continue
+		}
+		actionOperations, err := e.runAction(ctx, payload, action)
+		operations = append(operations, actionOperations...)
+		if err != nil {
+			return operations, err
+		}
+	}
+
+	dependencies := actionDependencies(payload, scheduled)
+	started := make([]bool, len(scheduled))
+	done := make(map[string]bool, len(scheduled))
+	outcomes := make(chan actionOutcome)
+	running := 0
+	var firstErr error
+	for {
+		for index, action := range scheduled {
+			if firstErr != nil || running >= limit {
+				break
+			}
+			if started[index] || !allDone(dependencies[action.Target], done) {
+				continue
+			}
+			started[index] = true
+			running++
+			go func(action plan.Action) {
+				actionOperations, err := e.runAction(ctx, payload, action)
+				outcomes <- actionOutcome{target: action.Target, operations: actionOperations, err: err}
+			}(action)
+		}
+		if running == 0 {
+			break
+		}
+		outcome := <-outcomes
+		running--
+		done[outcome.target] = true
+		operations = append(operations, outcome.operations...)
+		if outcome.err != nil && firstErr == nil {
+			firstErr = outcome.err
+		}
+	}
+	if firstErr != nil {
+		return operations, firstErr
+	}
+	if len(done) < len(scheduled) {
+		var stuck []string
+		for index, action := range scheduled {
+			if !started[index] {
+				stuck = append(stuck, action.Target)
+			}
+		}
+		return operations, fmt.Errorf("apply execute: dependency cycle between %s", strings.Join(stuck, ", "))
+	}
+	return operations, nil
+}
+
+// actionDependencies maps each scheduled resource to the scheduled resources
+// it has to wait for: its own dependsOn, and for resources outside the init
+// group, every resource in it. Dependencies without an action in this apply
+// are already in place and are not waited on.
+func actionDependencies(payload *Payload, actions []plan.Action) map[string][]string {
+	scheduled := make(map[string]bool, len(actions))
+	for _, action := range actions {
+		scheduled[action.Target] = true
+	}
+	initGroup := map[string]bool{}
+	var visit func(key string)
+	visit = func(key string) {
+		resource := payload.Resource(key)
+		if resource == nil || initGroup[key] {
+			return
+		}
+		initGroup[key] = true
+		for _, dependency := range resource.DependsOn {
+			visit(dependency)
+		}
+	}
+	for _, resource := range payload.Resources {
+		if resource.Init {
+			visit(resource.Key)
+		}
+	}
+
+	dependencies := make(map[string][]string, len(actions))
+	for _, action := range actions {
+		var waits []string
+		if resource := payload.Resource(action.Target); resource != nil {
+			for _, dependency := range resource.DependsOn {
+				if scheduled[dependency] && dependency != action.Target {
+					waits = append(waits, dependency)
+				}
+			}
+		}
+		if !initGroup[action.Target] {
+			for _, other := range actions {
+				if initGroup[other.Target] {
+					waits = append(waits, other.Target)
+				}
+			}
+		}
+		dependencies[action.Target] = waits
+	}
+	return dependencies
+}
+
+func allDone(keys []string, done map[string]bool) bool {
+	for _, key := range keys {
+		if !done[key] {
+			return false
+		}
+	}
+	return true
+}
+
+// executeAction runs one plan action. For resource actions that start a
+// container, timing receives the pull and create durations.
+func (e *Executor) executeAction(ctx context.Context, action plan.Action, payload *Payload, timing *cachepkg.OperationTiming) error {
 	switch action.Scope {
 	case plan.ScopeWorkspace:
 		if payload.Network == nil {
@@ -147,11 +375,39 @@ func (e *Executor) executeAction(ctx context.Context, action plan.Action, payloa
 			if resource == nil {
 				return fmt.Errorf("resource payload %q not found", action.Target)
 			}
-			return e.Adapter.ApplyResource(ctx, runtimepkg.ApplyResourceRequest{Workspace: payload.Workspace, NetworkName: networkName(payload), Resource: applyResource(resource)})
+			if puller, ok := e.Adapter.(runtimepkg.ImagePuller); ok && resource.Image != "" && resource.Build == nil {
+				started := e.now()
+				pulled, err := puller.PullImage(ctx, resource.Image)
+				if err != nil {
+					return err
+				}
+				if timing != nil {
+					timing.Image = ImageCached
+					if pulled {
+						timing.Image = ImagePulled
+						timing.PullMs = e.now().Sub(started).Milliseconds()
+					}
+				}
+			}
+			started := e.now()
+			if err := e.Adapter.ApplyResource(ctx, runtimepkg.ApplyResourceRequest{Workspace: payload.Workspace, NetworkName: networkName(payload), Resource: applyResource(resource)}); err != nil {
+				return err
+			}
+			if timing != nil {
+				timing.CreateMs = e.now().Sub(started).Milliseconds()
+			}
+			return e.waitForInit(ctx, payload, resource)
 		case plan.ActionRemove:
 			return e.Adapter.RemoveResource(ctx, ref)
 		case plan.ActionRestart:
-			return e.Adapter.RestartResource(ctx, ref)
+			started := e.now()
+			if err := e.Adapter.RestartResource(ctx, ref, runtimepkg.RestartRequest{}); err != nil {
+				return err
+			}
+			if timing != nil {
+				timing.CreateMs = e.now().Sub(started).Milliseconds()
+			}
+			return e.waitForInit(ctx, payload, resource)
 		default:
 			return nil
 		}
@@ -160,6 +416,154 @@ func (e *Executor) executeAction(ctx context.Context, action plan.Action, payloa
 	}
 }
 
+// waitForInit blocks until an init resource exits, so the resources applied
+// after it start from its finished work. A non-zero exit fails the apply.
+// Adapters that cannot inspect are not waited on.
+func (e *Executor) waitForInit(ctx context.Context, payload *Payload, resource *ResourcePayload) error {
+	if resource == nil || !resource.Init || !e.Adapter.Capabilities().Inspect {
+		return nil
+	}
+	timeout := e.InitTimeout
+	if timeout <= 0 {
+		timeout = DefaultInitTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	boundary := desiredBoundaryFromPayload(payload)
+	for {
+		snapshot, err := e.Adapter.InspectWorkspace(ctx, boundary)
+		if err != nil {
+			return fmt.Errorf("wait for init resource %s: %w", resource.Key, err)
+		}
+		if observed := snapshot.Resource(resource.Key); observed != nil && observed.State.Status == "exited" {
+			if observed.State.ExitCode != 0 {
+				return fmt.Errorf("init resource %s exited with code %d", resource.Key, observed.State.ExitCode)
+			}
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("wait for init resource %s: %w", resource.Key, ctx.Err())
+		case <-time.After(initPollInterval):
+		}
+	}
+}
+
+// waitForHealthy polls until every resource this apply started that has a
+// health check reports healthy, or HealthTimeout runs out, and records the
+// time to healthy on its operation. Timing out is not an error: the
+// recorded health says where each resource ended up.
+func (e *Executor) waitForHealthy(ctx context.Context, payload *Payload, operations []Operation) {
+	if !e.Adapter.Capabilities().Inspect {
+		return
+	}
+	pending := map[string]*cachepkg.OperationTiming{}
+	for _, operation := range operations {
+		if operation.Timing == nil || operation.Status != "success" {
+			continue
+		}
+		if resource := payload.Resource(operation.Target); resource != nil && resource.Health != nil && !resource.Init {
+			pending[operation.Target] = operation.Timing
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, e.HealthTimeout)
+	defer cancel()
+	boundary := desiredBoundaryFromPayload(payload)
+	for len(pending) > 0 {
+		if snapshot, err := e.Adapter.InspectWorkspace(ctx, boundary); err == nil {
+			for key, timing := range pending {
+				observed := snapshot.Resource(key)
+				if observed == nil {
+					continue
+				}
+				timing.Health = observed.State.Health
+				if observed.State.Health == "healthy" {
+					created := timing.StartedAt.Add(time.Duration(timing.PullMs+timing.CreateMs) * time.Millisecond)
+					timing.HealthyMs = e.now().Sub(created).Milliseconds()
+					delete(pending, key)
+				}
+			}
+		}
+		if len(pending) == 0 {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(initPollInterval):
+		}
+	}
+}
+
+// actionHooks returns the hooks of the resource an add or modify action
+// applies; other actions run no hooks.
+func actionHooks(action plan.Action, payload *Payload) *workspace.Hooks {
+	if action.Scope != plan.ScopeResource || (action.Kind != plan.ActionAdd && action.Kind != plan.ActionModify) {
+		return nil
+	}
+	resource := payload.Resource(action.Target)
+	if resource == nil {
+		return nil
+	}
+	return resource.Hooks
+}
+
+// runHooks runs one phase of resource hooks in order, recording each as a
+// hook operation. A failing hook stops the apply unless it is marked
+// onFailure: continue. PreApply exec hooks are skipped when the action adds
+// the resource, since there is no container to exec into yet.
+func (e *Executor) runHooks(ctx context.Context, payload *Payload, action plan.Action, phase string, hooks []workspace.Hook) ([]Operation, error) {
+	resource := payload.Resource(action.Target)
+	operations := make([]Operation, 0, len(hooks))
+	for _, hook := range hooks {
+		mode, command := "exec", hook.Exec
+		if len(command) == 0 {
+			mode, command = "run", hook.Run
+		}
+		operation := Operation{Scope: action.Scope, Target: action.Target, RuntimeName: resource.RuntimeName, Kind: OperationHook, Message: fmt.Sprintf("%s %s: %s", phase, mode, strings.Join(command, " "))}
+		if mode == "exec" && phase == "preApply" && action.Kind == plan.ActionAdd {
+			operation.Status = "skipped"
+			operations = append(operations, operation)
+			continue
+		}
+		err := e.runHook(ctx, payload, resource, mode, command)
+		if err == nil {
+			operation.Status = "success"
+			operations = append(operations, operation)
+			continue
+		}
+		operation.Status = "failed"
+		operation.Message += ": " + err.Error()
+		operations = append(operations, operation)
+		if hook.OnFailure != "continue" {
+			return operations, fmt.Errorf("%s hook for %s: %w", phase, action.Target, err)
+		}
+	}
+	return operations, nil
+}
+
+func (e *Executor) runHook(ctx context.Context, payload *Payload, resource *ResourcePayload, mode string, command []string) error {
+	var output *runtimepkg.ExecResult
+	var err error
+	if mode == "exec" {
+		ref := runtimepkg.ResourceRef{Workspace: payload.Workspace, Key: resource.Key, RuntimeName: resource.RuntimeName}
+		output, err = e.Adapter.Exec(ctx, ref, runtimepkg.ExecRequest{Command: command})
+	} else {
+		runner, ok := e.Adapter.(runtimepkg.TaskRunner)
+		if !ok {
+			return fmt.Errorf("provider %q cannot run one-off tasks", e.Adapter.Provider())
+		}
+		output, err = runner.RunTask(ctx, runtimepkg.ApplyResourceRequest{Workspace: payload.Workspace, NetworkName: networkName(payload), Resource: applyResource(resource)}, command)
+	}
+	if err != nil {
+		return err
+	}
+	if output != nil && output.ExitCode != 0 {
+		return fmt.Errorf("exit code %d", output.ExitCode)
+	}
+	return nil
+}
+
 func (e *Executor) publishProgress(workspace string, action plan.Action, status, message string) error {
 	if e == nil || e.Publisher == nil {
 		return nil
@@ -187,9 +591,14 @@ func applyResource(resource *ResourcePayload) runtimepkg.AppliedResource {
 			Ports:         runtimePorts(resource.Ports),
 			Volumes:       runtimeVolumes(resource.Volumes),
 			Health:        cloneHealth(resource.Health),
+			StopSignal:    resource.StopSignal,
+			StopTimeout:   cloneIntPtr(resource.StopTimeout),
 			ProjectSource: cloneProjectSource(resource.ProjectSource),
 			DevelopWatch:  cloneWatchRules(resource.DevelopWatch),
 			Labels:        cloneStringMap(resource.Labels),
+			Init:          resource.Init,
+			Userns:        resource.Userns,
+			Memory:        resource.Memory,
 		},
 	}
 }
@@ -223,6 +632,27 @@ func runtimeVolumes(values []VolumePayload) []runtimepkg.VolumeSpec {
 	return volumes
 }
 
+// imageCacheStats counts the images apply found present and the ones it
+// pulled. It is nil when the runtime pulled nothing ahead of creating.
+func imageCacheStats(operations []Operation) *ImageCacheStats {
+	var stats ImageCacheStats
+	for _, operation := range operations {
+		if operation.Timing == nil {
+			continue
+		}
+		switch operation.Timing.Image {
+		case ImageCached:
+			stats.Cached++
+		case ImagePulled:
+			stats.Pulled++
+		}
+	}
+	if stats.Cached == 0 && stats.Pulled == 0 {
+		return nil
+	}
+	return &stats
+}
+
 func cacheOperations(values []Operation) []cachepkg.OperationRecord {
 	if len(values) == 0 {
 		return nil
@@ -230,6 +660,10 @@ func cacheOperations(values []Operation) []cachepkg.OperationRecord {
 	operations := make([]cachepkg.OperationRecord, len(values))
 	for i := range values {
 		operations[i] = cachepkg.OperationRecord{Scope: string(values[i].Scope), Target: values[i].Target, RuntimeName: values[i].RuntimeName, Kind: string(values[i].Kind), Status: values[i].Status, Message: values[i].Message}
+		if values[i].Timing != nil {
+			timing := *values[i].Timing
+			operations[i].Timing = &timing
+		}
 	}
 	return operations
 }
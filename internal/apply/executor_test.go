@@ -7,6 +7,8 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -14,6 +16,7 @@ import (
 	"github.com/prospect-ogujiuba/devarch/internal/events"
 	planpkg "github.com/prospect-ogujiuba/devarch/internal/plan"
 	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+	"github.com/prospect-ogujiuba/devarch/internal/workspace"
 )
 
 func TestExecutorShopLocalGolden(t *testing.T) {
@@ -128,7 +131,7 @@ func (m *mockAdapter) RemoveResource(_ context.Context, resource runtimepkg.Reso
 	return nil
 }
 
-func (m *mockAdapter) RestartResource(_ context.Context, resource runtimepkg.ResourceRef) error {
+func (m *mockAdapter) RestartResource(_ context.Context, resource runtimepkg.ResourceRef, _ runtimepkg.RestartRequest) error {
 	m.calls = append(m.calls, "restart-resource:"+resource.Key)
 	return nil
 }
@@ -140,3 +143,176 @@ func (m *mockAdapter) StreamLogs(context.Context, runtimepkg.ResourceRef, runtim
 func (m *mockAdapter) Exec(context.Context, runtimepkg.ResourceRef, runtimepkg.ExecRequest) (*runtimepkg.ExecResult, error) {
 	return &runtimepkg.ExecResult{ExitCode: 0}, nil
 }
+
+func TestExecutorRunsResourceHooksAroundApply(t *testing.T) {
+	payload := &apply.Payload{Workspace: "shop-local", Provider: runtimepkg.ProviderPodman, Resources: []*apply.ResourcePayload{
+		{Key: "api", RuntimeName: "devarch-shop-local-api", Image: "node:22", Hooks: &workspace.Hooks{
+			PreApply:  []workspace.Hook{{Exec: []string{"npm", "run", "drain"}}, {Run: []string{"npm", "run", "backup"}}},
+			PostApply: []workspace.Hook{{Exec: []string{"false"}, OnFailure: "continue"}, {Run: []string{"npm", "run", "migrate"}}},
+		}},
+	}}
+	diff := &planpkg.Result{Workspace: "shop-local", Actions: []planpkg.Action{{Scope: planpkg.ScopeResource, Target: "api", RuntimeName: "devarch-shop-local-api", Kind: planpkg.ActionAdd}}}
+	adapter := &hookAdapter{mockAdapter: mockAdapter{snapshot: &runtimepkg.Snapshot{}}, failing: "false"}
+	result, err := (&apply.Executor{Adapter: adapter}).Execute(context.Background(), diff, payload)
+	if err != nil {
+		t.Fatalf("Executor.Execute returned error: %v", err)
+	}
+	if got, want := adapter.calls, []string{
+		"run:npm run backup",
+		"apply-resource:api",
+		"exec:false",
+		"run:npm run migrate",
+		"inspect-workspace:shop-local",
+	}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("adapter calls = %v, want %v", got, want)
+	}
+	statuses := make([]string, 0, len(result.Operations))
+	for _, operation := range result.Operations {
+		statuses = append(statuses, string(operation.Kind)+":"+operation.Status)
+	}
+	if got, want := statuses, []string{"hook:skipped", "hook:success", "add:success", "hook:failed", "hook:success"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("operations = %v, want %v", got, want)
+	}
+
+	adapter = &hookAdapter{mockAdapter: mockAdapter{snapshot: &runtimepkg.Snapshot{}}, failing: "npm run backup"}
+	if _, err := (&apply.Executor{Adapter: adapter}).Execute(context.Background(), diff, payload); err == nil {
+		t.Fatal("expected failing preApply hook to abort apply")
+	}
+	if got, want := adapter.calls, []string{"run:npm run backup"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("adapter calls after abort = %v, want %v", got, want)
+	}
+}
+
+type hookAdapter struct {
+	mockAdapter
+	failing string
+}
+
+func (h *hookAdapter) Exec(_ context.Context, _ runtimepkg.ResourceRef, request runtimepkg.ExecRequest) (*runtimepkg.ExecResult, error) {
+	return h.record("exec", request.Command), nil
+}
+
+func (h *hookAdapter) RunTask(_ context.Context, _ runtimepkg.ApplyResourceRequest, command []string) (*runtimepkg.ExecResult, error) {
+	return h.record("run", command), nil
+}
+
+func (h *hookAdapter) record(mode string, command []string) *runtimepkg.ExecResult {
+	joined := strings.Join(command, " ")
+	h.calls = append(h.calls, mode+":"+joined)
+	if joined == h.failing {
+		return &runtimepkg.ExecResult{ExitCode: 1}
+	}
+	return &runtimepkg.ExecResult{}
+}
+
+func TestExecutorRecordsStartupTimings(t *testing.T) {
+	payload := &apply.Payload{Workspace: "shop-local", Provider: runtimepkg.ProviderPodman, Resources: []*apply.ResourcePayload{
+		{Key: "api", RuntimeName: "devarch-shop-local-api", Image: "node:22", Health: &workspace.Health{Test: []string{"CMD", "true"}}},
+		{Key: "redis", RuntimeName: "devarch-shop-local-redis", Image: "redis:7"},
+	}}
+	diff := &planpkg.Result{Workspace: "shop-local", Actions: []planpkg.Action{
+		{Scope: planpkg.ScopeResource, Target: "api", RuntimeName: "devarch-shop-local-api", Kind: planpkg.ActionAdd},
+		{Scope: planpkg.ScopeResource, Target: "redis", RuntimeName: "devarch-shop-local-redis", Kind: planpkg.ActionAdd},
+	}}
+	adapter := &pullAdapter{mockAdapter: mockAdapter{snapshot: &runtimepkg.Snapshot{Resources: []*runtimepkg.SnapshotResource{
+		{Key: "api", State: runtimepkg.ResourceState{Status: "running", Running: true, Health: "healthy"}},
+		{Key: "redis", State: runtimepkg.ResourceState{Status: "running", Running: true}},
+	}}}, present: map[string]bool{"redis:7": true}}
+	clock := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	now := func() time.Time {
+		clock = clock.Add(100 * time.Millisecond)
+		return clock
+	}
+	result, err := (&apply.Executor{Adapter: adapter, Now: now, HealthTimeout: time.Minute}).Execute(context.Background(), diff, payload)
+	if err != nil {
+		t.Fatalf("Executor.Execute returned error: %v", err)
+	}
+	if got, want := adapter.calls, []string{"pull:node:22", "apply-resource:api", "apply-resource:redis", "inspect-workspace:shop-local", "inspect-workspace:shop-local"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("adapter calls = %v, want %v", got, want)
+	}
+	api, redis := result.Operations[0].Timing, result.Operations[1].Timing
+	if api == nil || api.PullMs != 100 || api.CreateMs != 100 || api.HealthyMs <= 0 || api.Health != "healthy" {
+		t.Fatalf("api timing = %#v", api)
+	}
+	if redis == nil || redis.PullMs != 0 || redis.CreateMs != 100 || redis.HealthyMs != 0 {
+		t.Fatalf("redis timing = %#v", redis)
+	}
+	if api.Image != apply.ImagePulled || redis.Image != apply.ImageCached {
+		t.Fatalf("image cache = %q, %q; want pulled, cached", api.Image, redis.Image)
+	}
+	if got, want := result.Images, (&apply.ImageCacheStats{Cached: 1, Pulled: 1}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("images = %#v, want %#v", got, want)
+	}
+}
+
+type pullAdapter struct {
+	mockAdapter
+	present map[string]bool
+}
+
+func (p *pullAdapter) PullImage(_ context.Context, image string) (bool, error) {
+	if p.present[image] {
+		return false, nil
+	}
+	p.calls = append(p.calls, "pull:"+image)
+	return true, nil
+}
+
+func TestExecutorParallelRespectsDependencies(t *testing.T) {
+	payload := &apply.Payload{Workspace: "shop-local", Provider: runtimepkg.ProviderPodman, Resources: []*apply.ResourcePayload{
+		{Key: "api", RuntimeName: "devarch-shop-local-api", DependsOn: []string{"postgres"}},
+		{Key: "migrate", RuntimeName: "devarch-shop-local-migrate", Init: true, DependsOn: []string{"postgres"}},
+		{Key: "postgres", RuntimeName: "devarch-shop-local-postgres"},
+		{Key: "web", RuntimeName: "devarch-shop-local-web"},
+		{Key: "worker", RuntimeName: "devarch-shop-local-worker", DependsOn: []string{"postgres", "redis"}},
+	}}
+	diff := &planpkg.Result{Workspace: "shop-local"}
+	for _, key := range []string{"api", "migrate", "postgres", "web", "worker"} {
+		diff.Actions = append(diff.Actions, planpkg.Action{Scope: planpkg.ScopeResource, Target: key, Kind: planpkg.ActionAdd})
+	}
+	adapter := &parallelAdapter{mockAdapter: mockAdapter{snapshot: &runtimepkg.Snapshot{Resources: []*runtimepkg.SnapshotResource{
+		{Key: "migrate", State: runtimepkg.ResourceState{Status: "exited"}},
+	}}}}
+	result, err := (&apply.Executor{Adapter: adapter, Parallelism: 2}).Execute(context.Background(), diff, payload)
+	if err != nil {
+		t.Fatalf("Executor.Execute returned error: %v", err)
+	}
+	if len(result.Operations) != 5 {
+		t.Fatalf("operations = %#v", result.Operations)
+	}
+	if got, want := adapter.order[:2], []string{"postgres", "migrate"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("apply order = %v, want %v first", adapter.order, want)
+	}
+	if adapter.peak != 2 {
+		t.Fatalf("peak concurrency = %d, want 2", adapter.peak)
+	}
+}
+
+type parallelAdapter struct {
+	mockAdapter
+	mu       sync.Mutex
+	order    []string
+	inFlight int
+	peak     int
+}
+
+func (p *parallelAdapter) InspectWorkspace(ctx context.Context, desired *runtimepkg.DesiredWorkspace) (*runtimepkg.Snapshot, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.mockAdapter.InspectWorkspace(ctx, desired)
+}
+
+func (p *parallelAdapter) ApplyResource(_ context.Context, request runtimepkg.ApplyResourceRequest) error {
+	p.mu.Lock()
+	p.order = append(p.order, request.Resource.Key)
+	p.inFlight++
+	if p.inFlight > p.peak {
+		p.peak = p.inFlight
+	}
+	p.mu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	p.mu.Lock()
+	p.inFlight--
+	p.mu.Unlock()
+	return nil
+}
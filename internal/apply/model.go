@@ -3,6 +3,7 @@ package apply
 import (
 	"time"
 
+	cachepkg "github.com/prospect-ogujiuba/devarch/internal/cache"
 	"github.com/prospect-ogujiuba/devarch/internal/plan"
 	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
 	"github.com/prospect-ogujiuba/devarch/internal/workspace"
@@ -38,9 +39,16 @@ type ResourcePayload struct {
 	Ports         []PortPayload                 `json:"ports,omitempty"`
 	Volumes       []VolumePayload               `json:"volumes,omitempty"`
 	Health        *workspace.Health             `json:"health,omitempty"`
+	StopSignal    string                        `json:"stopSignal,omitempty"`
+	StopTimeout   *int                          `json:"stopTimeout,omitempty"`
 	ProjectSource *runtimepkg.ProjectSource     `json:"projectSource,omitempty"`
 	DevelopWatch  []runtimepkg.WatchRule        `json:"developWatch,omitempty"`
 	Labels        map[string]string             `json:"labels,omitempty"`
+	Hooks         *workspace.Hooks              `json:"hooks,omitempty"`
+	Init          bool                          `json:"init,omitempty"`
+	Userns        string                        `json:"userns,omitempty"`
+	Memory        string                        `json:"memory,omitempty"`
+	DependsOn     []string                      `json:"dependsOn,omitempty"`
 }
 
 type BuildPayload struct {
@@ -58,11 +66,12 @@ type PortPayload struct {
 }
 
 type VolumePayload struct {
-	Source   string `json:"source,omitempty"`
-	Target   string `json:"target"`
-	ReadOnly bool   `json:"readOnly,omitempty"`
-	Kind     string `json:"kind,omitempty"`
-	Type     string `json:"type,omitempty"`
+	Source   string   `json:"source,omitempty"`
+	Target   string   `json:"target"`
+	ReadOnly bool     `json:"readOnly,omitempty"`
+	Kind     string   `json:"kind,omitempty"`
+	Type     string   `json:"type,omitempty"`
+	Options  []string `json:"options,omitempty"`
 }
 
 type Result struct {
@@ -71,9 +80,24 @@ type Result struct {
 	StartedAt  time.Time            `json:"startedAt"`
 	FinishedAt time.Time            `json:"finishedAt"`
 	Operations []Operation          `json:"operations,omitempty"`
+	Images     *ImageCacheStats     `json:"images,omitempty"`
 	Snapshot   *runtimepkg.Snapshot `json:"snapshot,omitempty"`
 }
 
+// Values of OperationTiming.Image.
+const (
+	ImageCached = "cached"
+	ImagePulled = "pulled"
+)
+
+// ImageCacheStats counts the images an apply found in the runtime's local
+// image store and the ones it had to pull, through a registry mirror when
+// one is configured.
+type ImageCacheStats struct {
+	Cached int `json:"cached"`
+	Pulled int `json:"pulled"`
+}
+
 type Operation struct {
 	Scope       plan.ActionScope `json:"scope"`
 	Target      string           `json:"target"`
@@ -81,6 +105,8 @@ type Operation struct {
 	Kind        plan.ActionKind  `json:"kind"`
 	Status      string           `json:"status"`
 	Message     string           `json:"message,omitempty"`
+
+	Timing *cachepkg.OperationTiming `json:"timing,omitempty"`
 }
 
 func (p *Payload) Resource(key string) *ResourcePayload {
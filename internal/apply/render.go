@@ -1,6 +1,9 @@
 package apply
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 
 	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
@@ -49,9 +52,16 @@ func Render(desired *runtimepkg.DesiredWorkspace) (*Payload, error) {
 			Ports:         portPayloads(resource.Spec.Ports),
 			Volumes:       volumePayloads(resource.Spec.Volumes),
 			Health:        cloneHealth(resource.Spec.Health),
+			StopSignal:    resource.Spec.StopSignal,
+			StopTimeout:   cloneIntPtr(resource.Spec.StopTimeout),
 			ProjectSource: cloneProjectSource(resource.Spec.ProjectSource),
 			DevelopWatch:  cloneWatchRules(resource.Spec.DevelopWatch),
 			Labels:        cloneStringMap(resource.Spec.Labels),
+			Hooks:         resource.Hooks.Clone(),
+			Init:          resource.Spec.Init,
+			Userns:        resource.Spec.Userns,
+			Memory:        resource.Spec.Memory,
+			DependsOn:     cloneStringSlice(resource.DependsOn),
 		})
 	}
 	payload.Resources = resources
@@ -59,6 +69,31 @@ func Render(desired *runtimepkg.DesiredWorkspace) (*Payload, error) {
 	return payload, nil
 }
 
+// Canonical encodes payload in the stable form used by render goldens: sorted
+// map keys, two-space indentation, and a trailing newline. Resources and
+// diagnostics keep the deterministic order produced by Render.
+func Canonical(payload *Payload) ([]byte, error) {
+	if payload == nil {
+		return nil, fmt.Errorf("canonical payload: nil payload")
+	}
+	encoded, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("canonical payload: %w", err)
+	}
+	return append(encoded, '\n'), nil
+}
+
+// Digest returns the sha256 of the canonical payload encoding, so two renders
+// of the same workspace inputs can be compared without diffing documents.
+func Digest(payload *Payload) (string, error) {
+	encoded, err := Canonical(payload)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(encoded)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
 func payloadHasBlockingDiagnostics(payload *Payload) bool {
 	if payload == nil {
 		return false
@@ -153,6 +188,14 @@ func cloneHealth(health *workspace.Health) *workspace.Health {
 	return &cloned
 }
 
+func cloneIntPtr(value *int) *int {
+	if value == nil {
+		return nil
+	}
+	cloned := *value
+	return &cloned
+}
+
 func cloneProjectSource(source *runtimepkg.ProjectSource) *runtimepkg.ProjectSource {
 	if source == nil {
 		return nil
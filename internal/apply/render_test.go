@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	stdruntime "runtime"
+	"strings"
 	"testing"
 
 	"github.com/prospect-ogujiuba/devarch/internal/apply"
@@ -64,6 +65,54 @@ func TestRenderLaravelLocalGolden(t *testing.T) {
 	}
 }
 
+func TestCanonicalRenderIsStableAcrossBuilds(t *testing.T) {
+	build := func(image string) *apply.Payload {
+		t.Helper()
+		graph := &resolvepkg.Graph{
+			Workspace: resolvepkg.Workspace{Name: "shop-local", Runtime: workspacepkg.RuntimePreferences{IsolatedNetwork: true}},
+			Resources: []*resolvepkg.Resource{
+				{Key: "api", Enabled: true, Host: "api", Runtime: &resolvepkg.Runtime{Image: "node:22"}, DependsOn: []string{"postgres"}, Env: map[string]resolvepkg.EnvValue{"PORT": workspacepkg.StringEnvValue("3000"), "NODE_ENV": workspacepkg.StringEnvValue("development"), "LOG_LEVEL": workspacepkg.StringEnvValue("debug")}, Overrides: map[string]any{"labels": map[string]any{"z.team": "web", "a.tier": "app"}}},
+				{Key: "postgres", Enabled: true, Host: "postgres", Runtime: &resolvepkg.Runtime{Image: image}, Env: map[string]resolvepkg.EnvValue{"POSTGRES_DB": workspacepkg.StringEnvValue("shop"), "POSTGRES_USER": workspacepkg.StringEnvValue("devarch")}},
+			},
+		}
+		desired, err := runtimepkg.BuildDesiredWorkspace(graph, nil)
+		if err != nil {
+			t.Fatalf("runtime.BuildDesiredWorkspace returned error: %v", err)
+		}
+		payload, err := apply.Render(desired)
+		if err != nil {
+			t.Fatalf("apply.Render returned error: %v", err)
+		}
+		return payload
+	}
+
+	first, err := apply.Canonical(build("postgres:16"))
+	if err != nil {
+		t.Fatalf("apply.Canonical returned error: %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		again, err := apply.Canonical(build("postgres:16"))
+		if err != nil {
+			t.Fatalf("apply.Canonical returned error: %v", err)
+		}
+		if !bytes.Equal(first, again) {
+			t.Fatalf("canonical render changed between builds\n--- first ---\n%s\n--- again ---\n%s", first, again)
+		}
+	}
+
+	digest, err := apply.Digest(build("postgres:16"))
+	if err != nil {
+		t.Fatalf("apply.Digest returned error: %v", err)
+	}
+	changed, err := apply.Digest(build("postgres:17"))
+	if err != nil {
+		t.Fatalf("apply.Digest returned error: %v", err)
+	}
+	if !strings.HasPrefix(digest, "sha256:") || digest == changed {
+		t.Fatalf("digests = %q and %q, want distinct sha256 digests", digest, changed)
+	}
+}
+
 func loadDesiredWorkspace(t *testing.T, name string) *runtimepkg.DesiredWorkspace {
 	t.Helper()
 	manifestPath := filepath.Join(repoRoot(t), "examples", "workspaces", name, "devarch.workspace.yaml")
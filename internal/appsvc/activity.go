@@ -0,0 +1,109 @@
+package appsvc
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	cachepkg "github.com/prospect-ogujiuba/devarch/internal/cache"
+	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+)
+
+const (
+	defaultActivityLimit = 50
+	activityHistoryLimit = 500
+)
+
+// WorkspaceActivity merges the recorded apply history with container start
+// and exit times from a runtime inspect into one feed, newest first. Apply
+// history comes from the service's cache store, which the CLI keeps in the
+// state directory; container events need a runtime that can inspect, and
+// are left out with a warning when it cannot.
+func (s *Service) WorkspaceActivity(ctx context.Context, name string, request ActivityRequest) (*WorkspaceActivityView, error) {
+	if request.Limit < 0 {
+		return nil, fmt.Errorf("activity limit must not be negative")
+	}
+	if request.Limit == 0 {
+		request.Limit = defaultActivityLimit
+	}
+	state, err := s.loadWorkspaceState(name)
+	if err != nil {
+		return nil, err
+	}
+	view := &WorkspaceActivityView{Workspace: state.Desired.Name}
+
+	applies, err := cachepkg.Normalize(s.cache).ApplyHistory(ctx, state.Desired.Name, activityHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	adapter, provider, capabilities := s.planProvider(state.Desired.Provider)
+	var snapshot *runtimepkg.Snapshot
+	if adapter == nil || !capabilities.Inspect {
+		view.Diagnostics = append(view.Diagnostics, *inspectWarning(name, provider, fmt.Sprintf("runtime provider %q cannot be inspected; activity has no container events", provider)))
+	} else if snapshot, err = adapter.InspectWorkspace(ctx, state.Desired); err != nil {
+		view.Diagnostics = append(view.Diagnostics, *inspectWarning(name, provider, fmt.Sprintf("runtime inspection failed; activity has no container events: %v", err)))
+		snapshot = nil
+	} else {
+		s.saveSnapshot(ctx, state.Desired.Name, snapshot)
+	}
+
+	entries := activityEntries(applies, snapshot)
+	if request.Actor != "" {
+		entries = slices.DeleteFunc(entries, func(entry ActivityEntry) bool { return entry.Actor != request.Actor })
+	}
+	view.Entries, view.NextBefore = pageActivity(entries, request)
+	return view, nil
+}
+
+func activityEntries(applies []cachepkg.ApplyRecord, snapshot *runtimepkg.Snapshot) []ActivityEntry {
+	entries := make([]ActivityEntry, 0, len(applies))
+	for _, record := range applies {
+		status := "failed"
+		if record.Succeeded {
+			status = "succeeded"
+		}
+		entries = append(entries, ActivityEntry{
+			Time:       record.FinishedAt,
+			Kind:       "apply",
+			Actor:      record.Actor,
+			Status:     status,
+			Message:    fmt.Sprintf("%d operations", len(record.Operations)),
+			Operations: append([]cachepkg.OperationRecord(nil), record.Operations...),
+		})
+	}
+	if snapshot != nil {
+		for _, resource := range snapshot.Resources {
+			if resource == nil {
+				continue
+			}
+			if started := resource.State.StartedAt; started != nil && !started.IsZero() {
+				entries = append(entries, ActivityEntry{Time: *started, Kind: "container", Resource: resource.Key, Status: "started"})
+			}
+			if finished := resource.State.FinishedAt; finished != nil && !finished.IsZero() && !resource.State.Running {
+				entries = append(entries, ActivityEntry{Time: *finished, Kind: "container", Resource: resource.Key, Status: "exited", Message: fmt.Sprintf("exit code %d", resource.State.ExitCode)})
+			}
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.After(entries[j].Time)
+	})
+	return entries
+}
+
+func pageActivity(entries []ActivityEntry, request ActivityRequest) ([]ActivityEntry, *time.Time) {
+	if request.Before != nil {
+		start := sort.Search(len(entries), func(i int) bool {
+			return entries[i].Time.Before(*request.Before)
+		})
+		entries = entries[start:]
+	}
+	if len(entries) <= request.Limit {
+		return entries, nil
+	}
+	page := entries[:request.Limit]
+	next := page[len(page)-1].Time
+	return page, &next
+}
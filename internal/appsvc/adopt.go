@@ -0,0 +1,228 @@
+package appsvc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/prospect-ogujiuba/devarch/internal/catalog"
+	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+	"github.com/prospect-ogujiuba/devarch/internal/workspace"
+)
+
+var invalidResourceKeyChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// UnmanagedContainers lists the running containers on the workspace's
+// runtime that no workspace manages, as candidates for AdoptContainer.
+func (s *Service) UnmanagedContainers(ctx context.Context, name string) ([]runtimepkg.UnmanagedContainer, error) {
+	state, err := s.loadRuntimeState(name, "adopt")
+	if err != nil {
+		return nil, err
+	}
+	adopter, ok := state.Adapter.(runtimepkg.ContainerAdopter)
+	if !ok {
+		return nil, unsupportedCapability(name, "", state.Desired.Provider, "adopt", "adopt", "selected runtime cannot list unmanaged containers")
+	}
+	containers, err := adopter.UnmanagedContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if containers == nil {
+		containers = []runtimepkg.UnmanagedContainer{}
+	}
+	return containers, nil
+}
+
+// AdoptContainer describes an unmanaged container as a workspace resource:
+// a catalog template whose image matches (or request.Template), the exact
+// image as an override, and the container's published ports, mounts, and
+// non-default env. With request.Write the resource is appended to the
+// workspace manifest. The container itself is not touched. Labels cannot be
+// added to an existing container, so the next apply starts the resource as
+// a new DevArch container; the original has to be stopped first to free
+// its host ports.
+func (s *Service) AdoptContainer(ctx context.Context, name, container string, request AdoptRequest) (*AdoptView, error) {
+	if request.Write {
+		if err := s.checkWritable("adopt"); err != nil {
+			return nil, err
+		}
+	}
+	state, err := s.loadRuntimeState(name, "adopt")
+	if err != nil {
+		return nil, err
+	}
+	adopter, ok := state.Adapter.(runtimepkg.ContainerAdopter)
+	if !ok {
+		return nil, unsupportedCapability(name, "", state.Desired.Provider, "adopt", "adopt", "selected runtime cannot inspect unmanaged containers")
+	}
+	found, err := adopter.InspectUnmanagedContainer(ctx, container)
+	if err != nil {
+		return nil, err
+	}
+
+	key := strings.TrimSpace(request.Key)
+	if key == "" {
+		key = adoptedResourceKey(found.Name)
+	}
+	if key == "" {
+		return nil, fmt.Errorf("cannot derive a resource key from container %q; pass one", found.Name)
+	}
+	if _, exists := state.Workspace.Resources[key]; exists {
+		return nil, fmt.Errorf("workspace %q already has a resource %q", name, key)
+	}
+
+	paths, err := catalog.DiscoverTemplateFiles(state.Workspace.ResolvedCatalogSources())
+	if err != nil {
+		return nil, err
+	}
+	index, err := catalog.LoadIndex(paths)
+	if err != nil {
+		return nil, err
+	}
+	template, err := adoptionTemplate(index, found.Image, request.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	resource, warnings := adoptedResource(found, template)
+	var encoded bytes.Buffer
+	encoder := yaml.NewEncoder(&encoded)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(map[string]workspace.Resource{key: resource}); err != nil {
+		return nil, fmt.Errorf("encode resource %s: %w", key, err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("encode resource %s: %w", key, err)
+	}
+
+	view := &AdoptView{
+		Workspace:   state.Desired.Name,
+		Container:   found.Name,
+		Resource:    key,
+		Template:    template.Metadata.Name,
+		RuntimeName: runtimepkg.ResourceRuntimeName(state.Desired.Name, key, state.Desired.NamingStrategy),
+		YAML:        encoded.String(),
+		Warnings:    warnings,
+	}
+	view.Warnings = append(view.Warnings, fmt.Sprintf("apply starts %s as a new container; stop %s first so its host ports are free", view.RuntimeName, found.Name))
+	if request.Write {
+		if err := workspace.AddResource(state.Workspace.ManifestPath, key, resource); err != nil {
+			return nil, err
+		}
+		view.ManifestPath = state.Workspace.ManifestPath
+		view.Written = true
+	}
+	return view, nil
+}
+
+// adoptionTemplate returns the named template, or the first template whose
+// image has the same repository name as image.
+func adoptionTemplate(index *catalog.Index, image, name string) (*catalog.Template, error) {
+	if name != "" {
+		template, ok := index.ByName(name)
+		if !ok {
+			return nil, &NotFoundError{Kind: "template", Name: name}
+		}
+		return template, nil
+	}
+	want := imageBaseName(image)
+	for _, template := range index.Templates() {
+		if template == nil {
+			continue
+		}
+		if templateImage, _ := template.Spec.Runtime["image"].(string); templateImage != "" && imageBaseName(templateImage) == want {
+			return template, nil
+		}
+	}
+	return nil, fmt.Errorf("no catalog template matches image %q; pick one with --template", image)
+}
+
+// adoptedResource carries the container's settings onto template. Values
+// the template already sets the same way are left out; a different command
+// becomes overrides.command.
+func adoptedResource(container *runtimepkg.UnmanagedContainer, template *catalog.Template) (workspace.Resource, []string) {
+	resource := workspace.Resource{Template: template.Metadata.Name}
+	var warnings []string
+	if templateImage, _ := template.Spec.Runtime["image"].(string); templateImage != container.Image {
+		resource.Overrides = map[string]any{"image": container.Image}
+	}
+
+	templateEnv := make(map[string]string, len(template.Spec.Env))
+	for key, value := range template.Spec.Env {
+		templateEnv[key] = fmt.Sprint(value)
+	}
+	for key, value := range container.Env {
+		if current, ok := templateEnv[key]; ok && current == value.Text() {
+			continue
+		}
+		if resource.Env == nil {
+			resource.Env = map[string]workspace.EnvValue{}
+		}
+		resource.Env[key] = value
+	}
+	if len(resource.Env) > 0 {
+		warnings = append(warnings, "env values are copied as plain text; move secrets to secretRef values before committing the manifest")
+	}
+
+	for _, port := range container.Ports {
+		if port.Published == 0 {
+			continue
+		}
+		adopted := workspace.Port{Host: port.Published, Container: port.Container}
+		if port.Protocol != "" && port.Protocol != "tcp" {
+			adopted.Protocol = port.Protocol
+		}
+		if port.HostIP != "" && port.HostIP != "0.0.0.0" {
+			adopted.HostIP = port.HostIP
+		}
+		resource.Ports = append(resource.Ports, adopted)
+	}
+	for _, volume := range container.Volumes {
+		if volume.Type != "bind" && volume.Type != "volume" {
+			continue
+		}
+		resource.Volumes = append(resource.Volumes, workspace.Volume{Source: volume.Source, Target: volume.Target, ReadOnly: volume.ReadOnly})
+	}
+
+	if len(container.Command) > 0 {
+		if templateCommand := template.Spec.Runtime["command"]; templateCommand == nil || fmt.Sprint(templateCommand) != fmt.Sprint(container.Command) {
+			command := make([]any, 0, len(container.Command))
+			for _, arg := range container.Command {
+				command = append(command, arg)
+			}
+			if resource.Overrides == nil {
+				resource.Overrides = map[string]any{}
+			}
+			resource.Overrides["command"] = command
+		}
+	}
+	if container.ComposeProject != "" {
+		warnings = append(warnings, fmt.Sprintf("container belongs to compose project %q; remove it from that project so compose does not start it again", container.ComposeProject))
+	}
+	return resource, warnings
+}
+
+// imageBaseName is the last path segment of an image reference without tag
+// or digest, so docker.io/library/postgres:16 and postgres:15 both give
+// postgres.
+func imageBaseName(image string) string {
+	if index := strings.Index(image, "@"); index >= 0 {
+		image = image[:index]
+	}
+	if index := strings.LastIndex(image, "/"); index >= 0 {
+		image = image[index+1:]
+	}
+	if index := strings.Index(image, ":"); index >= 0 {
+		image = image[:index]
+	}
+	return image
+}
+
+// adoptedResourceKey turns a container name into a resource key.
+func adoptedResourceKey(name string) string {
+	return strings.Trim(invalidResourceKeyChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
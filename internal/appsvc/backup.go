@@ -0,0 +1,283 @@
+package appsvc
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prospect-ogujiuba/devarch/internal/catalog"
+)
+
+const (
+	backupPrefix      = "devarch-backup-"
+	backupTimeLayout  = "20060102T150405Z"
+	backupIndexName   = "backup.json"
+	defaultBackupKeep = 10
+)
+
+// backupIndex is stored in every backup as backup.json and maps the
+// archive's top-level directories back to the roots they were read from.
+type backupIndex struct {
+	CreatedAt time.Time    `json:"createdAt"`
+	Roots     []BackupRoot `json:"roots"`
+}
+
+// BackupDefinitions archives the workspace manifests and catalog templates
+// DevArch reads, meaning the configured roots plus every catalog source a
+// workspace declares, into a timestamped zip under request.Directory. Only
+// the newest request.Keep backups (default 10) are kept. Runtime state,
+// volumes, and images are not included.
+func (s *Service) BackupDefinitions(_ context.Context, request BackupRequest) (*BackupView, error) {
+	if err := s.checkWritable("backup"); err != nil {
+		return nil, err
+	}
+	if s.team != "" {
+		return nil, fmt.Errorf("backup covers the workspaces of every team; run it without a team scope")
+	}
+	if strings.TrimSpace(request.Directory) == "" {
+		return nil, fmt.Errorf("backup directory is required")
+	}
+	if request.Keep < 0 {
+		return nil, fmt.Errorf("backup keep count must not be negative")
+	}
+	if request.Keep == 0 {
+		request.Keep = defaultBackupKeep
+	}
+
+	roots, files, err := s.backupFiles()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(request.Directory, 0o755); err != nil {
+		return nil, fmt.Errorf("create backup directory: %w", err)
+	}
+	createdAt := time.Now().UTC().Truncate(time.Second)
+	target := filepath.Join(request.Directory, backupPrefix+createdAt.Format(backupTimeLayout)+".zip")
+	index, err := json.MarshalIndent(backupIndex{CreatedAt: createdAt, Roots: roots}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode backup index: %w", err)
+	}
+	archiveFiles := map[string][]byte{backupIndexName: append(index, '\n')}
+	for name, source := range files {
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", source, err)
+		}
+		archiveFiles[name] = data
+	}
+	if err := writeExportArchive(target, archiveFiles); err != nil {
+		return nil, err
+	}
+
+	view := &BackupView{Path: target, CreatedAt: createdAt, Roots: roots, Files: len(files)}
+	backups, err := listBackups(request.Directory)
+	if err != nil {
+		return nil, err
+	}
+	for len(backups) > request.Keep {
+		if err := os.Remove(backups[0].Path); err != nil {
+			return nil, fmt.Errorf("rotate backups: %w", err)
+		}
+		view.Removed = append(view.Removed, backups[0].Path)
+		backups = backups[1:]
+	}
+	return view, nil
+}
+
+// ListBackups lists the backups in directory, oldest first.
+func (s *Service) ListBackups(_ context.Context, directory string) ([]BackupInfo, error) {
+	backups, err := listBackups(directory)
+	if err != nil {
+		return nil, err
+	}
+	if backups == nil {
+		backups = []BackupInfo{}
+	}
+	return backups, nil
+}
+
+// RestoreBackup extracts a backup into target, which must be empty or not
+// exist, with one directory per original root. Nothing is written back to
+// the original roots; backup.json says where each directory came from, so
+// the files can be compared and copied back by hand.
+func (s *Service) RestoreBackup(_ context.Context, archive, target string) (*BackupView, error) {
+	if err := s.checkWritable("restore"); err != nil {
+		return nil, err
+	}
+	if entries, err := os.ReadDir(target); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("restore target %s is not empty", target)
+	}
+	reader, err := zip.OpenReader(archive)
+	if err != nil {
+		return nil, fmt.Errorf("open backup %s: %w", archive, err)
+	}
+	defer reader.Close()
+
+	view := &BackupView{Path: target}
+	for _, file := range reader.File {
+		name := path.Clean(file.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("backup %s: entry %q escapes the restore target", archive, file.Name)
+		}
+		data, err := readZipEntry(file)
+		if err != nil {
+			return nil, fmt.Errorf("backup %s: %w", archive, err)
+		}
+		if name == backupIndexName {
+			var index backupIndex
+			if err := json.Unmarshal(data, &index); err != nil {
+				return nil, fmt.Errorf("backup %s: decode %s: %w", archive, backupIndexName, err)
+			}
+			view.CreatedAt = index.CreatedAt
+			view.Roots = index.Roots
+		} else {
+			view.Files++
+		}
+		destination := filepath.Join(target, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(destination), 0o755); err != nil {
+			return nil, fmt.Errorf("create %s: %w", filepath.Dir(destination), err)
+		}
+		if err := os.WriteFile(destination, data, 0o644); err != nil {
+			return nil, fmt.Errorf("write %s: %w", destination, err)
+		}
+	}
+	if view.Roots == nil {
+		return nil, fmt.Errorf("backup %s: missing %s", archive, backupIndexName)
+	}
+	return view, nil
+}
+
+// backupFiles maps archive entry names to source files. Each root gets its
+// own top-level directory, workspaces-N or catalog-N, so roots with
+// overlapping relative paths do not collide.
+func (s *Service) backupFiles() ([]BackupRoot, map[string]string, error) {
+	var roots []BackupRoot
+	files := map[string]string{}
+	seen := map[string]bool{}
+	addRoot := func(kind, root string, paths []string) error {
+		absoluteRoot, err := filepath.Abs(root)
+		if err != nil {
+			return fmt.Errorf("resolve %s root %s: %w", kind, root, err)
+		}
+		if seen[kind+"\x00"+absoluteRoot] {
+			return nil
+		}
+		seen[kind+"\x00"+absoluteRoot] = true
+		directory := fmt.Sprintf("%s-%d", kind, len(roots)+1)
+		roots = append(roots, BackupRoot{Kind: kind, Path: absoluteRoot, Directory: directory})
+		for _, source := range paths {
+			absoluteSource, err := filepath.Abs(source)
+			if err != nil {
+				return fmt.Errorf("resolve %s: %w", source, err)
+			}
+			relative, err := filepath.Rel(absoluteRoot, absoluteSource)
+			if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+				return fmt.Errorf("%s is outside %s root %s", source, kind, root)
+			}
+			files[path.Join(directory, filepath.ToSlash(relative))] = absoluteSource
+		}
+		return nil
+	}
+
+	for _, root := range s.workspaceRoots {
+		manifests, err := discoverWorkspaceManifestPaths([]string{root})
+		if err != nil {
+			return nil, nil, err
+		}
+		teams, err := discoverTeamPaths([]string{root})
+		if err != nil {
+			return nil, nil, err
+		}
+		manifests = append(manifests, teams...)
+		if err := addRoot("workspaces", root, manifests); err != nil {
+			return nil, nil, err
+		}
+	}
+	catalogRoots := append([]string(nil), s.catalogRoots...)
+	workspaces, err := DiscoverWorkspaces(s.workspaceRoots)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, ws := range workspaces {
+		catalogRoots = append(catalogRoots, ws.ResolvedCatalogSources()...)
+	}
+	for _, root := range catalogRoots {
+		paths, err := catalogTemplateFiles(root)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := addRoot("catalog", root, paths); err != nil {
+			return nil, nil, err
+		}
+	}
+	return roots, files, nil
+}
+
+// catalogTemplateFiles returns every file in the directories that hold a
+// template.yaml under root, so files a template ships next to its
+// definition are kept too.
+func catalogTemplateFiles(root string) ([]string, error) {
+	templates, err := catalog.DiscoverTemplateFiles([]string{root})
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, template := range templates {
+		entries, err := os.ReadDir(filepath.Dir(template))
+		if err != nil {
+			return nil, fmt.Errorf("read template directory %s: %w", filepath.Dir(template), err)
+		}
+		for _, entry := range entries {
+			if entry.Type().IsRegular() {
+				paths = append(paths, filepath.Join(filepath.Dir(template), entry.Name()))
+			}
+		}
+	}
+	return paths, nil
+}
+
+func listBackups(directory string) ([]BackupInfo, error) {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, fmt.Errorf("read backup directory: %w", err)
+	}
+	var backups []BackupInfo
+	for _, entry := range entries {
+		stamp, ok := strings.CutPrefix(entry.Name(), backupPrefix)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		createdAt, err := time.Parse(backupTimeLayout, strings.TrimSuffix(stamp, ".zip"))
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("stat backup %s: %w", entry.Name(), err)
+		}
+		backups = append(backups, BackupInfo{Path: filepath.Join(directory, entry.Name()), CreatedAt: createdAt, Size: info.Size()})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].CreatedAt.Before(backups[j].CreatedAt) })
+	return backups, nil
+}
+
+func readZipEntry(file *zip.File) ([]byte, error) {
+	entry, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", file.Name, err)
+	}
+	defer entry.Close()
+	data, err := io.ReadAll(entry)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", file.Name, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,63 @@
+package appsvc
+
+import (
+	"context"
+	"fmt"
+	"html"
+)
+
+// badgeColors are the shields.io colors for each badge message.
+var badgeColors = map[string]string{
+	"running":   "#4c1",
+	"healthy":   "#4c1",
+	"degraded":  "#dfb317",
+	"starting":  "#dfb317",
+	"unhealthy": "#e05d44",
+	"stopped":   "#9f9f9f",
+	"exited":    "#9f9f9f",
+	"absent":    "#9f9f9f",
+}
+
+// WorkspaceBadge renders a status badge for a workspace, or for one of its
+// resources when resource is set, as an SVG in the style of shields.io. It
+// reads the same status as WorkspaceBrief, so a cron job can write badges
+// where a README or wiki serves them. A resource's health wins over its
+// status when it has one.
+func (s *Service) WorkspaceBadge(ctx context.Context, name, resource string) (*BadgeView, error) {
+	brief, err := s.WorkspaceBrief(ctx, name, "")
+	if err != nil {
+		return nil, err
+	}
+	view := &BadgeView{Workspace: brief.Workspace, Label: brief.Workspace, Message: brief.State}
+	if resource != "" {
+		found := false
+		for _, item := range brief.Resources {
+			if item.Key == resource {
+				view.Resource, view.Label, view.Message = item.Key, brief.Workspace+"/"+item.Key, firstNonEmpty(item.Health, item.Status)
+				found = true
+			}
+		}
+		if !found {
+			return nil, &NotFoundError{Kind: "resource", Name: resource, Workspace: name}
+		}
+	}
+	view.Color = firstNonEmpty(badgeColors[view.Message], "#bbb")
+	view.SVG = renderBadge(view.Label, view.Message, view.Color)
+	return view, nil
+}
+
+// renderBadge draws a flat two-part badge. Text widths are estimated at 7px
+// per character, close enough for the 11px Verdana shields.io uses.
+func renderBadge(label, message, color string) string {
+	labelWidth, messageWidth := 10+7*len(label), 10+7*len(message)
+	width := labelWidth + messageWidth
+	label, message = html.EscapeString(label), html.EscapeString(message)
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[4]s: %[5]s">
+<title>%[4]s: %[5]s</title>
+<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+<clipPath id="r"><rect width="%[1]d" height="20" rx="3" fill="#fff"/></clipPath>
+<g clip-path="url(#r)"><rect width="%[2]d" height="20" fill="#555"/><rect x="%[2]d" width="%[3]d" height="20" fill="%[6]s"/><rect width="%[1]d" height="20" fill="url(#s)"/></g>
+<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11"><text x="%[7]d" y="14">%[4]s</text><text x="%[8]d" y="14">%[5]s</text></g>
+</svg>
+`, width, labelWidth, messageWidth, label, message, color, labelWidth/2, labelWidth+messageWidth/2)
+}
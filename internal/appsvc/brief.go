@@ -0,0 +1,135 @@
+package appsvc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	cachepkg "github.com/prospect-ogujiuba/devarch/internal/cache"
+	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+)
+
+const briefErrorLimit = 5
+
+// WorkspaceBrief returns a compact workspace status for editor status bars and
+// other pollers. The ETag is a hash of the returned content, so a poller that
+// passes back the last ETag as ifNoneMatch gets NotModified instead of the
+// full body when nothing changed. The runtime is still inspected on every
+// call; the ETag saves the caller work, not DevArch.
+func (s *Service) WorkspaceBrief(ctx context.Context, name, ifNoneMatch string) (*WorkspaceBriefView, error) {
+	state, err := s.loadWorkspaceState(name)
+	if err != nil {
+		return nil, err
+	}
+	adapter, provider, capabilities := s.planProvider(state.Desired.Provider)
+	state.Desired.Provider = provider
+
+	var snapshot *runtimepkg.Snapshot
+	var errs []string
+	if adapter == nil || !capabilities.Inspect {
+		errs = append(errs, fmt.Sprintf("runtime provider %q cannot be inspected", provider))
+	} else if snapshot, err = adapter.InspectWorkspace(ctx, state.Desired); err != nil {
+		errs = append(errs, fmt.Sprintf("runtime inspection failed: %v", err))
+		snapshot = nil
+	} else {
+		s.saveSnapshot(ctx, state.Desired.Name, snapshot)
+	}
+
+	applies, err := cachepkg.Normalize(s.cache).ApplyHistory(ctx, state.Desired.Name, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	view := buildBrief(state.Desired, snapshot, applies)
+	view.Errors = append(errs, view.Errors...)
+	if len(view.Errors) > briefErrorLimit {
+		view.Errors = view.Errors[:briefErrorLimit]
+	}
+	etag, err := briefETag(view)
+	if err != nil {
+		return nil, err
+	}
+	if ifNoneMatch != "" && strings.Trim(ifNoneMatch, `"`) == etag {
+		return &WorkspaceBriefView{Workspace: view.Workspace, ETag: etag, NotModified: true}, nil
+	}
+	view.ETag = etag
+	return view, nil
+}
+
+func buildBrief(desired *runtimepkg.DesiredWorkspace, snapshot *runtimepkg.Snapshot, applies []cachepkg.ApplyRecord) *WorkspaceBriefView {
+	view := &WorkspaceBriefView{Workspace: desired.Name, Provider: desired.Provider, Resources: []BriefResource{}}
+	observed := map[string]*runtimepkg.SnapshotResource{}
+	if snapshot != nil {
+		for _, resource := range snapshot.Resources {
+			if resource != nil {
+				observed[resource.Key] = resource
+			}
+		}
+	}
+
+	enabled, running := 0, 0
+	for _, resource := range desired.Resources {
+		if resource == nil || !resource.Enabled {
+			continue
+		}
+		enabled++
+		item := BriefResource{Key: resource.Key, Status: "absent"}
+		if current, ok := observed[resource.Key]; ok {
+			item.Status = firstNonEmpty(current.State.Status, "unknown")
+			item.Health = current.State.Health
+			if current.State.Running || (resource.Spec.Init && current.State.Status == "exited" && current.State.ExitCode == 0) {
+				running++
+			}
+			if current.State.Error != "" {
+				view.Errors = append(view.Errors, fmt.Sprintf("%s: %s", resource.Key, current.State.Error))
+			} else if !current.State.Running && current.State.ExitCode != 0 {
+				view.Errors = append(view.Errors, fmt.Sprintf("%s: exited with code %d", resource.Key, current.State.ExitCode))
+			}
+			if current.State.Health == "unhealthy" {
+				view.Errors = append(view.Errors, fmt.Sprintf("%s: unhealthy", resource.Key))
+			}
+		}
+		for _, port := range resource.Spec.Ports {
+			if port.Published == 0 {
+				continue
+			}
+			item.Ports = append(item.Ports, port.Published)
+			if port.Protocol == "" || port.Protocol == "tcp" {
+				item.URLs = append(item.URLs, fmt.Sprintf("http://localhost:%d", port.Published))
+			}
+		}
+		view.Resources = append(view.Resources, item)
+	}
+
+	switch {
+	case snapshot == nil:
+		view.State = "unknown"
+	case enabled > 0 && running == enabled:
+		view.State = "running"
+	case running > 0:
+		view.State = "degraded"
+	default:
+		view.State = "stopped"
+	}
+
+	if len(applies) > 0 && !applies[0].Succeeded {
+		for _, operation := range applies[0].Operations {
+			if operation.Status == "failed" {
+				view.Errors = append(view.Errors, fmt.Sprintf("last apply: %s %s: %s", operation.Kind, operation.Target, operation.Message))
+			}
+		}
+	}
+	return view
+}
+
+func briefETag(view *WorkspaceBriefView) (string, error) {
+	data, err := json.Marshal(view)
+	if err != nil {
+		return "", fmt.Errorf("encode workspace brief: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8]), nil
+}
@@ -0,0 +1,201 @@
+package appsvc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prospect-ogujiuba/devarch/internal/workspace"
+)
+
+const (
+	defaultStatusPageDays = 14
+	icsTimestamp          = "20060102T150405"
+)
+
+// icsDays are the iCalendar BYDAY codes for maintenance window days.
+var icsDays = map[string]string{"mon": "MO", "tue": "TU", "wed": "WE", "thu": "TH", "fri": "FR", "sat": "SA", "sun": "SU"}
+
+// MaintenanceCalendar renders the expiry dates and maintenance windows of
+// every discovered workspace as an iCalendar feed, for teams that share a
+// host to subscribe to. Weekly windows become recurring events in floating
+// local time; one-off windows keep their UTC times.
+func (s *Service) MaintenanceCalendar(context.Context) (string, error) {
+	workspaces, err := s.discoverWorkspaces()
+	if err != nil {
+		return "", err
+	}
+	return renderCalendar(workspaces, time.Now()), nil
+}
+
+// StatusPage summarizes every discovered workspace for a public status page:
+// who owns it, whether it has expired, whether a maintenance window is open
+// now, and the windows starting within request.Days (default 14). It reads
+// only the manifests, never the runtime.
+func (s *Service) StatusPage(_ context.Context, request StatusPageRequest) (*StatusPageView, error) {
+	if request.Days < 0 {
+		return nil, fmt.Errorf("status page days must not be negative")
+	}
+	if request.Days == 0 {
+		request.Days = defaultStatusPageDays
+	}
+	workspaces, err := s.discoverWorkspaces()
+	if err != nil {
+		return nil, err
+	}
+	return statusPage(workspaces, time.Now(), request.Days), nil
+}
+
+func statusPage(workspaces []*workspace.Workspace, now time.Time, days int) *StatusPageView {
+	view := &StatusPageView{GeneratedAt: now, Workspaces: []StatusPageWorkspace{}}
+	until := now.AddDate(0, 0, days)
+	for _, ws := range sortedWorkspaces(workspaces) {
+		item := StatusPageWorkspace{
+			Name:        ws.Metadata.Name,
+			DisplayName: ws.Metadata.DisplayName,
+			Owner:       ws.Metadata.Owner,
+			Contact:     ws.Metadata.Contact,
+			Expires:     ws.Metadata.Expires,
+			Upcoming:    []MaintenanceEvent{},
+		}
+		if expires, ok, err := ws.Metadata.ExpiresAt(); err == nil && ok {
+			item.Expired = !dateOf(now).Before(expires)
+		}
+		for _, window := range ws.Metadata.Maintenance {
+			for _, event := range maintenanceOccurrences(window, now, until, now.Location()) {
+				if !event.Start.After(now) {
+					item.InMaintenance = true
+					continue
+				}
+				item.Upcoming = append(item.Upcoming, event)
+			}
+		}
+		sort.SliceStable(item.Upcoming, func(i, j int) bool { return item.Upcoming[i].Start.Before(item.Upcoming[j].Start) })
+		view.Workspaces = append(view.Workspaces, item)
+	}
+	return view
+}
+
+// maintenanceOccurrences lists the occurrences of window that overlap
+// [from, until), with weekly windows placed in loc.
+func maintenanceOccurrences(window workspace.MaintenanceWindow, from, until time.Time, loc *time.Location) []MaintenanceEvent {
+	var events []MaintenanceEvent
+	if !window.Recurring() {
+		start, end, err := window.Times()
+		if err == nil && end.After(from) && start.Before(until) {
+			events = append(events, MaintenanceEvent{Summary: window.Summary, Start: start, End: end})
+		}
+		return events
+	}
+	startOffset, endOffset, err := window.Clock()
+	if err != nil {
+		return nil
+	}
+	days := map[time.Weekday]bool{}
+	for _, day := range window.Days {
+		days[workspace.Weekdays[day]] = true
+	}
+	first := from.In(loc)
+	for day := time.Date(first.Year(), first.Month(), first.Day()-1, 0, 0, 0, 0, loc); day.Before(until); day = day.AddDate(0, 0, 1) {
+		if !days[day.Weekday()] {
+			continue
+		}
+		start, end := day.Add(startOffset), day.Add(endOffset)
+		if end.After(from) && start.Before(until) {
+			events = append(events, MaintenanceEvent{Summary: window.Summary, Start: start, End: end})
+		}
+	}
+	return events
+}
+
+func renderCalendar(workspaces []*workspace.Workspace, now time.Time) string {
+	var lines []string
+	add := func(line string) { lines = append(lines, foldICSLine(line)) }
+	add("BEGIN:VCALENDAR")
+	add("VERSION:2.0")
+	add("PRODID:-//DevArch//Maintenance//EN")
+	add("CALSCALE:GREGORIAN")
+	add("X-WR-CALNAME:DevArch maintenance")
+	stamp := "DTSTAMP:" + now.UTC().Format(icsTimestamp) + "Z"
+	for _, ws := range sortedWorkspaces(workspaces) {
+		name := firstNonEmpty(ws.Metadata.DisplayName, ws.Metadata.Name)
+		description := "Workspace " + ws.Metadata.Name
+		if ws.Metadata.Owner != "" {
+			description += ", owned by " + ws.Metadata.Owner
+		}
+		if ws.Metadata.Contact != "" {
+			description += ", contact " + ws.Metadata.Contact
+		}
+		if expires, ok, err := ws.Metadata.ExpiresAt(); err == nil && ok {
+			add("BEGIN:VEVENT")
+			add("UID:" + ws.Metadata.Name + "-expires@devarch")
+			add(stamp)
+			add("DTSTART;VALUE=DATE:" + expires.Format("20060102"))
+			add("SUMMARY:" + escapeICSText(name+" expires"))
+			add("DESCRIPTION:" + escapeICSText(description))
+			add("END:VEVENT")
+		}
+		for i, window := range ws.Metadata.Maintenance {
+			var start, end string
+			var rule []string
+			if window.Recurring() {
+				occurrences := maintenanceOccurrences(window, now, now.AddDate(0, 0, 8), now.Location())
+				if len(occurrences) == 0 {
+					continue
+				}
+				start, end = occurrences[0].Start.Format(icsTimestamp), occurrences[0].End.Format(icsTimestamp)
+				for _, day := range window.Days {
+					rule = append(rule, icsDays[day])
+				}
+			} else {
+				from, to, err := window.Times()
+				if err != nil {
+					continue
+				}
+				start, end = from.UTC().Format(icsTimestamp)+"Z", to.UTC().Format(icsTimestamp)+"Z"
+			}
+			add("BEGIN:VEVENT")
+			add(fmt.Sprintf("UID:%s-maintenance-%d@devarch", ws.Metadata.Name, i))
+			add(stamp)
+			add("DTSTART:" + start)
+			add("DTEND:" + end)
+			if len(rule) > 0 {
+				add("RRULE:FREQ=WEEKLY;BYDAY=" + strings.Join(rule, ","))
+			}
+			add("SUMMARY:" + escapeICSText(name+": "+window.Summary))
+			add("DESCRIPTION:" + escapeICSText(description))
+			add("END:VEVENT")
+		}
+	}
+	add("END:VCALENDAR")
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+func sortedWorkspaces(workspaces []*workspace.Workspace) []*workspace.Workspace {
+	sorted := append([]*workspace.Workspace(nil), workspaces...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Metadata.Name < sorted[j].Metadata.Name })
+	return sorted
+}
+
+func escapeICSText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(value)
+}
+
+// foldICSLine wraps a content line at 75 octets, continuing with a space,
+// without splitting a UTF-8 sequence.
+func foldICSLine(line string) string {
+	var out strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > 75 {
+			out.WriteString("\r\n ")
+			width = 1
+		}
+		out.WriteRune(r)
+		width += size
+	}
+	return out.String()
+}
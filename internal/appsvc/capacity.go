@@ -0,0 +1,37 @@
+package appsvc
+
+import (
+	planpkg "github.com/prospect-ogujiuba/devarch/internal/plan"
+	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+	"github.com/prospect-ogujiuba/devarch/internal/workflows"
+	"github.com/prospect-ogujiuba/devarch/internal/workspace"
+)
+
+// checkMemoryCapacity refuses an apply whose added containers are limited to
+// more memory, in total, than the host has available. Recreated containers
+// are not counted, since the ones they replace free their memory, and
+// neither are resources without a memory limit, whose use cannot be known
+// up front. Hosts without /proc/meminfo skip the check.
+func (s *Service) checkMemoryCapacity(desired *runtimepkg.DesiredWorkspace, diff *planpkg.Result) error {
+	var required uint64
+	for _, action := range diff.Actions {
+		if action.Scope != planpkg.ScopeResource || action.Kind != planpkg.ActionAdd {
+			continue
+		}
+		resource := desired.Resource(action.Target)
+		if resource == nil || resource.Spec.Memory == "" {
+			continue
+		}
+		if size, err := workspace.ParseMemory(resource.Spec.Memory); err == nil {
+			required += uint64(size)
+		}
+	}
+	if required == 0 {
+		return nil
+	}
+	available, ok := workflows.AvailableMemory(s.meminfoPath)
+	if !ok || required <= available {
+		return nil
+	}
+	return &InsufficientMemoryError{Workspace: desired.Name, RequiredBytes: required, AvailableBytes: available}
+}
@@ -0,0 +1,91 @@
+package appsvc
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+)
+
+const (
+	defaultCaptureDuration = 30 * time.Second
+	maxCaptureDuration     = 10 * time.Minute
+)
+
+// CaptureResourceTraffic records a resource's network traffic to a pcap file
+// with tcpdump run from a helper container in the resource's network
+// namespace. The capture is written into a private directory next to the
+// output path and moved into place once it finishes, so the helper never
+// mounts or relabels the directory the caller chose.
+func (s *Service) CaptureResourceTraffic(ctx context.Context, name, resource string, request CaptureRequest) (*CaptureView, error) {
+	if err := s.checkWritable("capture"); err != nil {
+		return nil, err
+	}
+	if request.Duration == 0 {
+		request.Duration = defaultCaptureDuration
+	}
+	if request.Duration < time.Second || request.Duration > maxCaptureDuration {
+		return nil, fmt.Errorf("capture duration must be between 1s and %s", maxCaptureDuration)
+	}
+	if request.MaxPackets < 0 {
+		return nil, fmt.Errorf("capture packet limit must not be negative")
+	}
+	state, item, err := s.loadRuntimeResource(name, resource, "capture")
+	if err != nil {
+		return nil, err
+	}
+	capturer, ok := state.Adapter.(runtimepkg.TrafficCapturer)
+	if !ok {
+		return nil, unsupportedCapability(name, item.Key, state.Desired.Provider, "capture", "capture", "selected runtime does not capture traffic")
+	}
+
+	output := strings.TrimSpace(request.Output)
+	if output == "" {
+		output = fmt.Sprintf("%s-%s-%s.pcap", state.Desired.Name, item.Key, time.Now().UTC().Format("20060102T150405Z"))
+	}
+	output, err = filepath.Abs(output)
+	if err != nil {
+		return nil, fmt.Errorf("resolve capture path: %w", err)
+	}
+	if _, err := os.Stat(output); err == nil {
+		return nil, fmt.Errorf("%s already exists", output)
+	}
+	scratch, err := os.MkdirTemp(filepath.Dir(output), ".devarch-capture-")
+	if err != nil {
+		return nil, fmt.Errorf("create capture directory: %w", err)
+	}
+	defer os.RemoveAll(scratch)
+
+	ref := runtimepkg.ResourceRef{Workspace: state.Desired.Name, Key: item.Key, RuntimeName: item.RuntimeName}
+	started := time.Now()
+	if err := capturer.CaptureTraffic(ctx, ref, runtimepkg.CaptureRequest{
+		Duration:   request.Duration,
+		MaxPackets: request.MaxPackets,
+		Filter:     request.Filter,
+		Directory:  scratch,
+		File:       "capture.pcap",
+	}); err != nil {
+		return nil, err
+	}
+	captured := filepath.Join(scratch, "capture.pcap")
+	info, err := os.Stat(captured)
+	if err != nil {
+		return nil, fmt.Errorf("capture produced no pcap file: %w", err)
+	}
+	if err := os.Rename(captured, output); err != nil {
+		return nil, fmt.Errorf("move capture to %s: %w", output, err)
+	}
+	return &CaptureView{
+		Workspace: state.Desired.Name,
+		Resource:  item.Key,
+		Provider:  state.Desired.Provider,
+		Path:      output,
+		Bytes:     info.Size(),
+		Duration:  time.Since(started).Round(time.Second).String(),
+		Filter:    request.Filter,
+	}, nil
+}
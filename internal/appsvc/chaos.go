@@ -0,0 +1,117 @@
+package appsvc
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+)
+
+const maxNetworkFaultDuration = 10 * time.Minute
+
+var signalPattern = regexp.MustCompile(`^(SIG)?[A-Z][A-Z0-9+-]*$|^[0-9]+$`)
+
+// PauseResource freezes a resource's processes, simulating a hung service.
+// The container keeps its state; UnpauseResource resumes it.
+func (s *Service) PauseResource(ctx context.Context, name, resource string) (*ChaosView, error) {
+	if err := s.checkWritable("chaos"); err != nil {
+		return nil, err
+	}
+	return s.runChaos(ctx, name, resource, "pause", "", func(injector runtimepkg.ChaosInjector, ref runtimepkg.ResourceRef) error {
+		return injector.PauseResource(ctx, ref)
+	})
+}
+
+func (s *Service) UnpauseResource(ctx context.Context, name, resource string) (*ChaosView, error) {
+	if err := s.checkWritable("chaos"); err != nil {
+		return nil, err
+	}
+	return s.runChaos(ctx, name, resource, "unpause", "", func(injector runtimepkg.ChaosInjector, ref runtimepkg.ResourceRef) error {
+		return injector.UnpauseResource(ctx, ref)
+	})
+}
+
+// KillResource sends signal (default KILL) to a resource's main process. The
+// container's restart policy decides whether it comes back.
+func (s *Service) KillResource(ctx context.Context, name, resource, signal string) (*ChaosView, error) {
+	if err := s.checkWritable("chaos"); err != nil {
+		return nil, err
+	}
+	signal = strings.ToUpper(strings.TrimSpace(signal))
+	if signal == "" {
+		signal = "KILL"
+	}
+	if !signalPattern.MatchString(signal) {
+		return nil, fmt.Errorf("invalid signal %q", signal)
+	}
+	return s.runChaos(ctx, name, resource, "kill", "signal "+signal, func(injector runtimepkg.ChaosInjector, ref runtimepkg.ResourceRef) error {
+		return injector.KillResource(ctx, ref, signal)
+	})
+}
+
+// DegradeResourceNetwork adds latency and packet loss to a resource's
+// network interface and blocks until the fault has been lifted again.
+func (s *Service) DegradeResourceNetwork(ctx context.Context, name, resource string, fault runtimepkg.NetworkFault) (*ChaosView, error) {
+	if err := s.checkWritable("chaos"); err != nil {
+		return nil, err
+	}
+	if fault.Duration < time.Second || fault.Duration > maxNetworkFaultDuration {
+		return nil, fmt.Errorf("fault duration must be between 1s and %s", maxNetworkFaultDuration)
+	}
+	if fault.Latency < 0 || fault.Jitter < 0 {
+		return nil, fmt.Errorf("latency and jitter must not be negative")
+	}
+	if fault.Loss < 0 || fault.Loss > 100 {
+		return nil, fmt.Errorf("loss must be between 0 and 100 percent")
+	}
+	if fault.Latency == 0 && fault.Loss == 0 {
+		return nil, fmt.Errorf("latency or loss is required")
+	}
+	detail := networkFaultDetail(fault)
+	return s.runChaos(ctx, name, resource, "network", detail, func(injector runtimepkg.ChaosInjector, ref runtimepkg.ResourceRef) error {
+		return injector.DegradeNetwork(ctx, ref, fault)
+	})
+}
+
+func (s *Service) runChaos(ctx context.Context, name, resource, action, detail string, run func(runtimepkg.ChaosInjector, runtimepkg.ResourceRef) error) (*ChaosView, error) {
+	state, item, err := s.loadRuntimeResource(name, resource, action)
+	if err != nil {
+		return nil, err
+	}
+	injector, ok := state.Adapter.(runtimepkg.ChaosInjector)
+	if !ok {
+		return nil, unsupportedCapability(name, item.Key, state.Desired.Provider, action, "chaos", "selected runtime does not support chaos actions")
+	}
+	ref := runtimepkg.ResourceRef{Workspace: state.Desired.Name, Key: item.Key, RuntimeName: item.RuntimeName}
+	started := time.Now()
+	if err := run(injector, ref); err != nil {
+		return nil, err
+	}
+	return &ChaosView{
+		Workspace:   state.Desired.Name,
+		Resource:    item.Key,
+		RuntimeName: item.RuntimeName,
+		Action:      action,
+		Detail:      detail,
+		StartedAt:   started.UTC(),
+		FinishedAt:  time.Now().UTC(),
+	}, nil
+}
+
+func networkFaultDetail(fault runtimepkg.NetworkFault) string {
+	var parts []string
+	if fault.Latency > 0 {
+		latency := "latency " + fault.Latency.String()
+		if fault.Jitter > 0 {
+			latency += " ± " + fault.Jitter.String()
+		}
+		parts = append(parts, latency)
+	}
+	if fault.Loss > 0 {
+		parts = append(parts, fmt.Sprintf("loss %g%%", fault.Loss))
+	}
+	return strings.Join(parts, ", ") + " for " + fault.Duration.String()
+}
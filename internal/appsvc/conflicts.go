@@ -0,0 +1,48 @@
+package appsvc
+
+import (
+	"context"
+
+	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+)
+
+// WorkspaceNameConflicts lists containers and networks that already hold the
+// runtime names the workspace wants but belong to another workspace, a
+// compose project, or nobody. Runtime names are derived from the workspace
+// name alone, so two manifests with the same name on one host, or a compose
+// project that happens to use a devarch-* container name, collide here.
+func (s *Service) WorkspaceNameConflicts(ctx context.Context, name string) (*NameConflictsView, error) {
+	state, err := s.loadRuntimeState(name, "conflicts")
+	if err != nil {
+		return nil, err
+	}
+	finder, ok := state.Adapter.(runtimepkg.NameConflictFinder)
+	if !ok {
+		return nil, unsupportedCapability(name, "", state.Desired.Provider, "conflicts", "name-conflicts", "selected runtime cannot look up containers by name")
+	}
+	conflicts, err := finder.FindNameConflicts(ctx, state.Desired)
+	if err != nil {
+		return nil, err
+	}
+	if conflicts == nil {
+		conflicts = []runtimepkg.NameConflict{}
+	}
+	return &NameConflictsView{Workspace: state.Desired.Name, Provider: state.Desired.Provider, Conflicts: conflicts}, nil
+}
+
+// checkNameConflicts refuses to apply over runtime names the workspace does
+// not own. Adapters that cannot look names up are not checked.
+func checkNameConflicts(ctx context.Context, state *workspaceState) error {
+	finder, ok := state.Adapter.(runtimepkg.NameConflictFinder)
+	if !ok {
+		return nil
+	}
+	conflicts, err := finder.FindNameConflicts(ctx, state.Desired)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return &RuntimeNameConflictError{Workspace: state.Desired.Name, Conflicts: conflicts}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package appsvc
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/prospect-ogujiuba/devarch/internal/workspace"
+)
+
+// databasePorts are the container ports the database engines listen on.
+var databasePorts = map[string]int{
+	"postgres": 5432,
+	"mysql":    3306,
+	"redis":    6379,
+}
+
+// ResourceConnections returns ready-to-paste connection details for a
+// resource: a DSN and client command for postgres, mysql, and redis, and
+// base URLs for every other TCP port. Host entries use the published port
+// on localhost; network entries use the resource's host name and container
+// port, as other resources of the workspace see it. Credentials come from
+// the resource's effective env. A port published without a fixed host port
+// is looked up in the runtime when it can be inspected.
+func (s *Service) ResourceConnections(ctx context.Context, name, resource string) (*ConnectionView, error) {
+	state, err := s.loadWorkspaceState(name)
+	if err != nil {
+		return nil, err
+	}
+	item := state.Desired.Resource(resource)
+	if item == nil {
+		return nil, &NotFoundError{Kind: "resource", Name: resource, Workspace: name}
+	}
+	published := map[int]int{}
+	for _, port := range item.Spec.Ports {
+		published[port.Container] = port.Published
+	}
+	if adapter, _, capabilities := s.planProvider(state.Desired.Provider); adapter != nil && capabilities.Inspect {
+		if snapshot, err := adapter.InspectWorkspace(ctx, state.Desired); err == nil {
+			for _, observed := range snapshot.Resources {
+				if observed == nil || observed.Key != item.Key {
+					continue
+				}
+				for _, port := range observed.Spec.Ports {
+					if published[port.Container] == 0 && port.Published != 0 {
+						published[port.Container] = port.Published
+					}
+				}
+			}
+		}
+	}
+
+	view := &ConnectionView{Workspace: name, Resource: item.Key, Engine: databaseEngine(item), Host: []ConnectionEntry{}, Network: []ConnectionEntry{}}
+	for _, port := range item.Spec.Ports {
+		if port.Protocol != "" && port.Protocol != "tcp" {
+			continue
+		}
+		build := func(host string, number int) []ConnectionEntry {
+			return connectionEntries(view.Engine, item.Spec.Env, port.Container, host, number)
+		}
+		view.Network = append(view.Network, build(item.LogicalHost, port.Container)...)
+		if number := published[port.Container]; number != 0 {
+			view.Host = append(view.Host, build("localhost", number)...)
+		} else {
+			view.Notes = append(view.Notes, fmt.Sprintf("port %d is not published on the host yet; apply the workspace to get its host port", port.Container))
+		}
+	}
+	keys := make([]string, 0, len(item.Spec.Env))
+	for key := range item.Spec.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if ref, ok := item.Spec.Env[key].SecretRef(); ok && connectionCredential(key) {
+			view.Notes = append(view.Notes, fmt.Sprintf("%s is read from secret %q; replace the <%s> placeholder, URL-escaped in DSNs, with its value", key, ref, ref))
+		}
+	}
+	return view, nil
+}
+
+// connectionEntries renders the entries for one port. The engine's own port
+// gets a DSN and client command; any other port gets a base URL.
+func connectionEntries(engine string, env map[string]workspace.EnvValue, containerPort int, host string, port int) []ConnectionEntry {
+	address := host + ":" + strconv.Itoa(port)
+	if engine == "" || databasePorts[engine] != containerPort {
+		return []ConnectionEntry{{Kind: "url", Port: containerPort, Value: "http://" + address}}
+	}
+	get := func(keys ...string) string {
+		for _, key := range keys {
+			if value, ok := env[key]; ok {
+				if ref, isSecret := value.SecretRef(); isSecret {
+					return "<" + ref + ">"
+				}
+				if text := value.Text(); text != "" {
+					return text
+				}
+			}
+		}
+		return ""
+	}
+	switch engine {
+	case "postgres":
+		user := firstNonEmpty(get("POSTGRES_USER"), "postgres")
+		password, database := get("POSTGRES_PASSWORD"), firstNonEmpty(get("POSTGRES_DB"), user)
+		dsn := (&url.URL{Scheme: "postgres", User: url.UserPassword(user, password), Host: address, Path: "/" + database}).String()
+		return []ConnectionEntry{
+			{Kind: "dsn", Port: containerPort, Value: dsn},
+			{Kind: "command", Port: containerPort, Value: fmt.Sprintf("psql %q", dsn)},
+		}
+	case "mysql":
+		user, password := get("MYSQL_USER", "MARIADB_USER"), get("MYSQL_PASSWORD", "MARIADB_PASSWORD")
+		if user == "" {
+			user, password = "root", get("MYSQL_ROOT_PASSWORD", "MARIADB_ROOT_PASSWORD")
+		}
+		database := get("MYSQL_DATABASE", "MARIADB_DATABASE")
+		dsn := (&url.URL{Scheme: "mysql", User: url.UserPassword(user, password), Host: address, Path: "/" + database}).String()
+		command := fmt.Sprintf("mysql -h %s -P %d -u %s --password=%q", host, port, user, password)
+		if database != "" {
+			command += " " + database
+		}
+		return []ConnectionEntry{
+			{Kind: "dsn", Port: containerPort, Value: dsn},
+			{Kind: "command", Port: containerPort, Value: command},
+		}
+	default:
+		dsn := &url.URL{Scheme: "redis", Host: address, Path: "/0"}
+		if password := get("REDIS_PASSWORD"); password != "" {
+			dsn.User = url.UserPassword("", password)
+		}
+		return []ConnectionEntry{
+			{Kind: "dsn", Port: containerPort, Value: dsn.String()},
+			{Kind: "command", Port: containerPort, Value: fmt.Sprintf("redis-cli -u %q", dsn.String())},
+		}
+	}
+}
+
+func connectionCredential(key string) bool {
+	switch key {
+	case "POSTGRES_PASSWORD", "MYSQL_PASSWORD", "MYSQL_ROOT_PASSWORD", "MARIADB_PASSWORD", "MARIADB_ROOT_PASSWORD", "REDIS_PASSWORD":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,190 @@
+package appsvc
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+)
+
+// databaseEngines maps template names and image repositories to the engine
+// InspectDatabase queries.
+var databaseEngines = map[string]string{
+	"postgres": "postgres",
+	"postgis":  "postgres",
+	"mysql":    "mysql",
+	"mariadb":  "mysql",
+	"redis":    "redis",
+	"valkey":   "redis",
+}
+
+// databaseQueries are the catalog queries run per SQL engine, in the order
+// databases, tables, connections. Each prints tab-separated rows.
+var databaseQueries = map[string][3]string{
+	"postgres": {
+		"SELECT datname, pg_database_size(datname) FROM pg_database WHERE NOT datistemplate ORDER BY 1",
+		"SELECT schemaname, relname, pg_total_relation_size(relid), n_live_tup FROM pg_stat_user_tables ORDER BY 1, 2",
+		"SELECT count(*) FROM pg_stat_activity WHERE backend_type = 'client backend'",
+	},
+	"mysql": {
+		"SELECT s.schema_name, COALESCE(SUM(t.data_length + t.index_length), 0) FROM information_schema.schemata s LEFT JOIN information_schema.tables t ON t.table_schema = s.schema_name GROUP BY s.schema_name ORDER BY 1",
+		"SELECT table_schema, table_name, COALESCE(data_length + index_length, 0), COALESCE(table_rows, 0) FROM information_schema.tables WHERE table_type = 'BASE TABLE' AND table_schema NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys') ORDER BY 1, 2",
+		"SELECT variable_value FROM performance_schema.global_status WHERE variable_name = 'Threads_connected'",
+	},
+}
+
+// databaseClients run a query with the client shipped in the engine's image.
+// They log in with the credentials from the container's own environment, so
+// none pass through the command line of the host.
+var databaseClients = map[string]string{
+	"postgres": `psql -X -q -A -t -F "$(printf '\t')" -v ON_ERROR_STOP=1 -U "${POSTGRES_USER:-postgres}" -d "${POSTGRES_DB:-${POSTGRES_USER:-postgres}}" -c "$1"`,
+	"mysql":    `MYSQL_PWD="${MYSQL_ROOT_PASSWORD:-$MARIADB_ROOT_PASSWORD}" "$(command -v mysql || command -v mariadb)" -uroot -N -B -e "$1"`,
+	"redis":    `REDISCLI_AUTH="${REDIS_PASSWORD:-}" redis-cli --no-auth-warning INFO`,
+}
+
+// InspectDatabase lists the databases, tables, sizes, and client
+// connections of a postgres, mysql, or redis resource by running the
+// engine's own client inside its running container. It only reads catalog
+// tables and INFO, so unlike exec it is allowed in read-only mode.
+func (s *Service) InspectDatabase(ctx context.Context, name, resource string) (*DatabaseInspectionView, error) {
+	state, item, engine, err := s.databaseResource(name, resource, "database inspect")
+	if err != nil {
+		return nil, err
+	}
+	query := func(sql string) ([][]string, error) {
+		output, err := s.runDatabaseClient(ctx, state, item, databaseClients[engine], sql)
+		return databaseRows(output), err
+	}
+
+	view := &DatabaseInspectionView{Workspace: name, Resource: item.Key, Engine: engine, Databases: []DatabaseInfo{}, Tables: []DatabaseTable{}}
+	if engine == "redis" {
+		rows, err := query("")
+		if err != nil {
+			return nil, err
+		}
+		applyRedisInfo(view, rows)
+		return view, nil
+	}
+	queries := databaseQueries[engine]
+	databases, err := query(queries[0])
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range databases {
+		if len(row) == 2 {
+			size := parseCount(row[1])
+			view.Databases = append(view.Databases, DatabaseInfo{Name: row[0], SizeBytes: size})
+			view.SizeBytes += size
+		}
+	}
+	tables, err := query(queries[1])
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range tables {
+		if len(row) == 4 {
+			view.Tables = append(view.Tables, DatabaseTable{Schema: row[0], Name: row[1], SizeBytes: parseCount(row[2]), Rows: parseCount(row[3])})
+		}
+	}
+	connections, err := query(queries[2])
+	if err != nil {
+		return nil, err
+	}
+	if len(connections) > 0 && len(connections[0]) > 0 {
+		view.Connections = parseCount(connections[0][0])
+	}
+	return view, nil
+}
+
+// databaseResource loads a database resource of a workspace and its engine,
+// and checks that the runtime can exec into it.
+func (s *Service) databaseResource(name, resource, operation string) (*workspaceState, *runtimepkg.DesiredResource, string, error) {
+	state, item, err := s.loadRuntimeResource(name, resource, operation)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	engine := databaseEngine(item)
+	if engine == "" {
+		return nil, nil, "", fmt.Errorf("resource %q in workspace %q is not a postgres, mysql, or redis database", item.Key, name)
+	}
+	if !state.Desired.Capabilities.Exec {
+		return nil, nil, "", unsupportedCapability(name, item.Key, state.Desired.Provider, operation, "exec", "selected runtime does not support exec")
+	}
+	return state, item, engine, nil
+}
+
+// runDatabaseClient runs a client script in the resource's container with
+// sql as $1, and returns its output.
+func (s *Service) runDatabaseClient(ctx context.Context, state *workspaceState, item *runtimepkg.DesiredResource, client, sql string) (string, error) {
+	ref := runtimepkg.ResourceRef{Workspace: state.Desired.Name, Key: item.Key, RuntimeName: item.RuntimeName}
+	command := []string{"sh", "-c", client, "devarch-db", sql}
+	result, err := runtimepkg.ExecWithEvents(ctx, state.Adapter, s.bus, ref, runtimepkg.ExecRequest{Command: command})
+	if err != nil {
+		return "", err
+	}
+	if result.ExitCode != 0 {
+		return "", fmt.Errorf("database %q: exit %d: %s", item.Key, result.ExitCode, strings.TrimSpace(result.Stderr))
+	}
+	return result.Stdout, nil
+}
+
+// databaseEngine picks the engine from the resource's template name, or
+// else from its image repository.
+func databaseEngine(item *runtimepkg.DesiredResource) string {
+	if engine := databaseEngines[item.TemplateName]; engine != "" {
+		return engine
+	}
+	image := item.Spec.Image
+	if index := strings.LastIndex(image, ":"); index > strings.LastIndex(image, "/") {
+		image = image[:index]
+	}
+	return databaseEngines[path.Base(image)]
+}
+
+// databaseRows splits client output into tab-separated rows, skipping blank
+// lines.
+func databaseRows(output string) [][]string {
+	var rows [][]string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		rows = append(rows, strings.Split(line, "\t"))
+	}
+	return rows
+}
+
+// applyRedisInfo reads redis INFO output: one database per keyspace line
+// such as "db0:keys=12,expires=0,avg_ttl=0", plus used_memory and
+// connected_clients.
+func applyRedisInfo(view *DatabaseInspectionView, rows [][]string) {
+	for _, row := range rows {
+		key, value, ok := strings.Cut(row[0], ":")
+		if !ok {
+			continue
+		}
+		switch {
+		case key == "used_memory":
+			view.SizeBytes = parseCount(value)
+		case key == "connected_clients":
+			view.Connections = parseCount(value)
+		case strings.HasPrefix(key, "db"):
+			database := DatabaseInfo{Name: key}
+			for _, field := range strings.Split(value, ",") {
+				if count, ok := strings.CutPrefix(field, "keys="); ok {
+					database.Keys = parseCount(count)
+				}
+			}
+			view.Databases = append(view.Databases, database)
+		}
+	}
+}
+
+func parseCount(value string) int64 {
+	count, _ := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	return count
+}
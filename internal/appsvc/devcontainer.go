@@ -0,0 +1,152 @@
+package appsvc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/prospect-ogujiuba/devarch/internal/projectscan"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	devcontainerDir     = ".devcontainer"
+	devcontainerCompose = "compose.yaml"
+)
+
+// devcontainerImages maps scanned languages to dev container base images.
+var devcontainerImages = map[string]string{
+	"go":         "mcr.microsoft.com/devcontainers/go:1",
+	"php":        "mcr.microsoft.com/devcontainers/php:8",
+	"javascript": "mcr.microsoft.com/devcontainers/javascript-node:22",
+	"typescript": "mcr.microsoft.com/devcontainers/typescript-node:22",
+}
+
+var devcontainerInstall = map[string]string{
+	"npm":      "npm install",
+	"yarn":     "yarn install",
+	"pnpm":     "pnpm install",
+	"bun":      "bun install",
+	"composer": "composer install",
+	"go mod":   "go mod download",
+}
+
+var devcontainerExtensions = map[string][]string{
+	"go":         {"golang.go"},
+	"php":        {"bmewburn.vscode-intelephense-client"},
+	"javascript": {"dbaeumer.vscode-eslint"},
+	"typescript": {"dbaeumer.vscode-eslint"},
+}
+
+// ProjectDevcontainer generates a devcontainer.json and compose file for the
+// project at path from its scan. With workspace set, the dev container joins
+// that workspace's network so its resources resolve by host. With write set,
+// the files are written under .devcontainer/, which must not exist yet.
+func (s *Service) ProjectDevcontainer(_ context.Context, path, workspaceName string, write bool) (*DevcontainerView, error) {
+	if write {
+		if err := s.checkWritable("devcontainer"); err != nil {
+			return nil, err
+		}
+	}
+	scan, err := projectscan.Scan(path)
+	if err != nil {
+		return nil, err
+	}
+	view := &DevcontainerView{Project: scan.Name}
+	network := ""
+	if workspaceName != "" {
+		state, err := s.loadWorkspaceState(workspaceName)
+		if err != nil {
+			return nil, err
+		}
+		view.Workspace = state.Desired.Name
+		if state.Desired.Network == nil {
+			view.Diagnostics = append(view.Diagnostics, fmt.Sprintf("workspace %q has no isolated network; set runtime.isolatedNetwork to reach its resources by host", workspaceName))
+		} else {
+			network = state.Desired.Network.Name
+			view.Network = network
+			for _, resource := range state.Desired.Resources {
+				if resource.Enabled && resource.LogicalHost != "" {
+					view.Hosts = append(view.Hosts, resource.LogicalHost)
+				}
+			}
+			sort.Strings(view.Hosts)
+		}
+	}
+
+	files, diagnostics, err := devcontainerFiles(scan, network)
+	if err != nil {
+		return nil, err
+	}
+	view.Files = files
+	view.Diagnostics = append(view.Diagnostics, diagnostics...)
+	if !write {
+		return view, nil
+	}
+
+	dir := filepath.Join(scan.Path, devcontainerDir)
+	if _, err := os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("%s already exists", dir)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create %s: %w", dir, err)
+	}
+	for _, file := range files {
+		target := filepath.Join(scan.Path, filepath.FromSlash(file.Path))
+		if err := os.WriteFile(target, []byte(file.Content), 0o644); err != nil {
+			return nil, fmt.Errorf("write %s: %w", target, err)
+		}
+	}
+	view.Written = true
+	return view, nil
+}
+
+func devcontainerFiles(scan *projectscan.Result, network string) ([]GeneratedFile, []string, error) {
+	var diagnostics []string
+	image, ok := devcontainerImages[scan.Language]
+	if !ok {
+		image = "mcr.microsoft.com/devcontainers/base:ubuntu"
+		diagnostics = append(diagnostics, fmt.Sprintf("no dev container image for language %q; using the base image", scan.Language))
+	}
+
+	service := map[string]any{
+		"image":   image,
+		"command": []string{"sleep", "infinity"},
+		"volumes": []string{"..:/workspace:cached"},
+	}
+	compose := map[string]any{"services": map[string]any{"dev": service}}
+	if network != "" {
+		service["networks"] = []string{"workspace"}
+		compose["networks"] = map[string]any{"workspace": map[string]any{"name": network, "external": true}}
+	}
+	composeData, err := yaml.Marshal(compose)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encode dev container compose file: %w", err)
+	}
+
+	config := map[string]any{
+		"name":              scan.Name,
+		"dockerComposeFile": devcontainerCompose,
+		"service":           "dev",
+		"workspaceFolder":   "/workspace",
+		"shutdownAction":    "stopCompose",
+	}
+	if install, ok := devcontainerInstall[scan.PackageManager]; ok {
+		config["postCreateCommand"] = install
+	}
+	if extensions, ok := devcontainerExtensions[scan.Language]; ok {
+		config["customizations"] = map[string]any{"vscode": map[string]any{"extensions": extensions}}
+	}
+	configData, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, nil, fmt.Errorf("encode devcontainer.json: %w", err)
+	}
+
+	return []GeneratedFile{
+		{Path: devcontainerDir + "/devcontainer.json", Content: string(configData) + "\n"},
+		{Path: devcontainerDir + "/" + devcontainerCompose, Content: string(composeData)},
+	}, diagnostics, nil
+}
@@ -14,12 +14,17 @@ import (
 
 // DiscoverWorkspaces recursively scans workspace roots for canonical manifest
 // files, loads them through workspace.Load, sorts them by workspace name, and
-// fails fast on duplicate metadata.name values.
+// fails fast on duplicate metadata.name values. Each workspace takes the
+// defaults of the team file its metadata.team names, if there is one.
 func DiscoverWorkspaces(roots []string) ([]*workspace.Workspace, error) {
 	manifestPaths, err := discoverWorkspaceManifestPaths(roots)
 	if err != nil {
 		return nil, err
 	}
+	teams, err := discoverTeams(roots)
+	if err != nil {
+		return nil, err
+	}
 
 	workspaces := make([]*workspace.Workspace, 0, len(manifestPaths))
 	seenByName := make(map[string]string, len(manifestPaths))
@@ -36,6 +41,9 @@ func DiscoverWorkspaces(roots []string) ([]*workspace.Workspace, error) {
 			}
 		}
 		seenByName[ws.Metadata.Name] = ws.ManifestPath
+		if ws.Metadata.Team != "" {
+			ws.ApplyTeamDefaults(teams[ws.Metadata.Team])
+		}
 		workspaces = append(workspaces, ws)
 	}
 
@@ -48,6 +56,44 @@ func DiscoverWorkspaces(roots []string) ([]*workspace.Workspace, error) {
 	return workspaces, nil
 }
 
+// discoverTeams loads the team files in the directory workspace roots, by
+// team name. Two files for one team are an error.
+func discoverTeams(roots []string) (map[string]*workspace.Team, error) {
+	paths, err := discoverTeamPaths(roots)
+	if err != nil {
+		return nil, err
+	}
+	teams := make(map[string]*workspace.Team, len(paths))
+	for _, path := range paths {
+		team, err := workspace.LoadTeam(path)
+		if err != nil {
+			return nil, err
+		}
+		if existing, ok := teams[team.Metadata.Name]; ok {
+			return nil, fmt.Errorf("duplicate team %q in %s and %s", team.Metadata.Name, existing.Path, team.Path)
+		}
+		teams[team.Metadata.Name] = team
+	}
+	return teams, nil
+}
+
+// discoverWorkspaces discovers the workspaces the service is scoped to: all
+// of them, or those of its team. Names stay unique across every team, since
+// runtime names are derived from them.
+func (s *Service) discoverWorkspaces() ([]*workspace.Workspace, error) {
+	workspaces, err := DiscoverWorkspaces(s.workspaceRoots)
+	if err != nil || s.team == "" {
+		return workspaces, err
+	}
+	scoped := workspaces[:0]
+	for _, ws := range workspaces {
+		if ws.Metadata.Team == s.team {
+			scoped = append(scoped, ws)
+		}
+	}
+	return scoped, nil
+}
+
 // LoadCatalogIndex loads the daemon-configured catalog roots used by the shared
 // catalog read endpoints.
 func LoadCatalogIndex(roots []string) (*catalog.Index, error) {
@@ -58,6 +104,35 @@ func LoadCatalogIndex(roots []string) (*catalog.Index, error) {
 	return catalog.LoadIndex(paths)
 }
 
+func discoverTeamPaths(roots []string) ([]string, error) {
+	seen := make(map[string]struct{})
+	var paths []string
+	for _, root := range roots {
+		cleanRoot := filepath.Clean(root)
+		if info, err := os.Stat(cleanRoot); err != nil || !info.IsDir() {
+			continue
+		}
+		if err := filepath.WalkDir(cleanRoot, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || filepath.Base(path) != spec.TeamFilename {
+				return err
+			}
+			absolutePath, err := filepath.Abs(path)
+			if err != nil {
+				return fmt.Errorf("resolve team %s: %w", path, err)
+			}
+			if _, ok := seen[absolutePath]; !ok {
+				seen[absolutePath] = struct{}{}
+				paths = append(paths, absolutePath)
+			}
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("walk workspace root %s: %w", cleanRoot, err)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
 func discoverWorkspaceManifestPaths(roots []string) ([]string, error) {
 	if len(roots) == 0 {
 		return nil, nil
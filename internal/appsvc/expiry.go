@@ -0,0 +1,125 @@
+package appsvc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prospect-ogujiuba/devarch/internal/workspace"
+)
+
+const defaultExpiryWarnDays = 7
+
+// WorkspaceExpiry lists workspaces whose metadata.expires date has passed or
+// falls within the warning window, soonest first. With Stop set, expired
+// workspaces are stopped; a stop failure is recorded on the entry rather than
+// failing the whole check.
+func (s *Service) WorkspaceExpiry(ctx context.Context, request ExpiryRequest) ([]WorkspaceExpiryView, error) {
+	if request.Stop {
+		if err := s.checkWritable("expiry"); err != nil {
+			return nil, err
+		}
+	}
+	if request.WarnDays < 0 {
+		return nil, fmt.Errorf("warn days must not be negative")
+	}
+	if request.WarnDays == 0 {
+		request.WarnDays = defaultExpiryWarnDays
+	}
+	workspaces, err := s.discoverWorkspaces()
+	if err != nil {
+		return nil, err
+	}
+	today := dateOf(time.Now())
+	views := make([]WorkspaceExpiryView, 0)
+	for _, ws := range workspaces {
+		view, ok := expiryView(ws, today, request.WarnDays)
+		if !ok {
+			continue
+		}
+		if request.Stop && view.State == "expired" {
+			stopped, err := s.StopWorkspace(ctx, ws.Metadata.Name, request.StopTimeout)
+			view.Stopped = stopped
+			if err != nil {
+				view.Error = err.Error()
+			}
+		}
+		views = append(views, view)
+	}
+	sort.Slice(views, func(i, j int) bool {
+		if views[i].DaysLeft != views[j].DaysLeft {
+			return views[i].DaysLeft < views[j].DaysLeft
+		}
+		return views[i].Name < views[j].Name
+	})
+	return views, nil
+}
+
+// ExtendWorkspace moves a workspace's expiry date. until is a YYYY-MM-DD date
+// or +Nd, counted from today.
+func (s *Service) ExtendWorkspace(ctx context.Context, name, until string) (*WorkspaceDetail, error) {
+	if err := s.checkWritable("extend"); err != nil {
+		return nil, err
+	}
+	ws, err := s.loadWorkspace(name)
+	if err != nil {
+		return nil, err
+	}
+	expires, err := parseExpiry(until, dateOf(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+	if err := workspace.SetExpires(ws.ManifestPath, expires); err != nil {
+		return nil, err
+	}
+	return s.Workspace(ctx, name)
+}
+
+func expiryView(ws *workspace.Workspace, today time.Time, warnDays int) (WorkspaceExpiryView, bool) {
+	expires, ok, err := ws.Metadata.ExpiresAt()
+	if err != nil || !ok {
+		return WorkspaceExpiryView{}, false
+	}
+	daysLeft := int(expires.Sub(today).Hours() / 24)
+	if daysLeft > warnDays {
+		return WorkspaceExpiryView{}, false
+	}
+	state := "expiring"
+	if daysLeft < 0 {
+		state = "expired"
+	}
+	return WorkspaceExpiryView{
+		Name:     ws.Metadata.Name,
+		Owner:    ws.Metadata.Owner,
+		Contact:  ws.Metadata.Contact,
+		Expires:  ws.Metadata.Expires,
+		DaysLeft: daysLeft,
+		State:    state,
+	}, true
+}
+
+func parseExpiry(until string, today time.Time) (time.Time, error) {
+	until = strings.TrimSpace(until)
+	if days, ok := strings.CutPrefix(until, "+"); ok {
+		count, err := strconv.Atoi(strings.TrimSuffix(days, "d"))
+		if err != nil || count <= 0 || !strings.HasSuffix(days, "d") {
+			return time.Time{}, fmt.Errorf("expiry %q: want YYYY-MM-DD or +Nd", until)
+		}
+		return today.AddDate(0, 0, count), nil
+	}
+	expires, err := time.Parse(time.DateOnly, until)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expiry %q: want YYYY-MM-DD or +Nd", until)
+	}
+	return expires, nil
+}
+
+// dateOf drops the clock from a local time so day counts line up with the
+// YYYY-MM-DD dates in manifests.
+func dateOf(now time.Time) time.Time {
+	year, month, day := now.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
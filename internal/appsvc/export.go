@@ -0,0 +1,393 @@
+package appsvc
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+	"gopkg.in/yaml.v3"
+)
+
+// ExportResource writes one resource as a standalone compose project: a
+// compose.yaml, an .env.example holding its environment with sensitive values
+// blanked, any single-file bind mounts copied under config/, and a README. A
+// target ending in .zip is written as an archive, anything else as a new
+// directory. request.Compose picks the compose implementation the project is
+// adapted for (default docker), and request.Redact the redaction profile
+// (default secrets). Every redaction is listed in the README and the view.
+// request.PortablePaths rewrites host paths under the workspace directory or
+// the home directory as ${PROJECT_ROOT} and ${HOME} paths.
+func (s *Service) ExportResource(_ context.Context, name, resource string, request ExportRequest) (*ResourceExportView, error) {
+	if err := s.checkWritable("export"); err != nil {
+		return nil, err
+	}
+	backendName := firstNonEmpty(strings.TrimSpace(request.Compose), "docker")
+	backend, ok := composeBackends[backendName]
+	if !ok {
+		return nil, fmt.Errorf("unknown compose backend %q (want docker, podman, podman-compose, or nerdctl)", backendName)
+	}
+	profileName := firstNonEmpty(strings.TrimSpace(request.Redact), "secrets")
+	profile, ok := redactionProfiles[profileName]
+	if !ok {
+		return nil, fmt.Errorf("unknown redaction profile %q (want secrets or share)", profileName)
+	}
+	profile.portablePaths = request.PortablePaths
+	target := request.Target
+	state, err := s.loadWorkspaceState(name)
+	if err != nil {
+		return nil, err
+	}
+	item := state.Desired.Resource(strings.TrimSpace(resource))
+	if item == nil {
+		return nil, &NotFoundError{Kind: "resource", Name: resource, Workspace: name}
+	}
+	files, warnings, redactions, err := composeExportFiles(state.Desired, item, backend, profile)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(strings.ToLower(target), ".zip") {
+		err = writeExportArchive(target, files)
+	} else {
+		err = writeExportDirectory(target, files)
+	}
+	if err != nil {
+		return nil, err
+	}
+	view := &ResourceExportView{Workspace: state.Desired.Name, Resource: item.Key, Target: target, Compose: backendName, Redact: profileName, Warnings: warnings, Redactions: redactions}
+	for file := range files {
+		view.Files = append(view.Files, file)
+	}
+	sort.Strings(view.Files)
+	return view, nil
+}
+
+// composeBackend adapts an export to the quirks of one compose
+// implementation. podman backends run rootless without a daemon, so there is
+// nothing to bring unless-stopped containers back after a reboot, and host
+// ports below 1024 need a sysctl.
+type composeBackend struct {
+	upCommand string
+	podman    bool
+}
+
+var composeBackends = map[string]composeBackend{
+	"docker":         {upCommand: "docker compose up -d"},
+	"podman":         {upCommand: "podman compose up -d", podman: true},
+	"podman-compose": {upCommand: "podman-compose up -d", podman: true},
+	"nerdctl":        {upCommand: "nerdctl compose up -d"},
+}
+
+// adapt rewrites service for the backend and returns what the user still has
+// to do by hand.
+func (b composeBackend) adapt(service *composeService, ports []runtimepkg.PortSpec) []string {
+	if !b.podman {
+		if service.UsernsMode == "" {
+			return nil
+		}
+		mode := service.UsernsMode
+		service.UsernsMode = ""
+		return []string{fmt.Sprintf("userns %s is podman-only and was left out; files the container writes to bind mounts may not be owned by your user", mode)}
+	}
+	var warnings []string
+	if service.Restart == "unless-stopped" {
+		// podman-restart.service only restarts containers with restart policy always.
+		service.Restart = "always"
+		warnings = append(warnings, "restart is always instead of unless-stopped; run `systemctl --user enable podman-restart.service` so the container starts again after a reboot")
+	}
+	for _, port := range ports {
+		if port.Published > 0 && port.Published < 1024 {
+			warnings = append(warnings, fmt.Sprintf("host port %d is privileged; rootless podman needs `sudo sysctl net.ipv4.ip_unprivileged_port_start=%d` or a host port of 1024 or above", port.Published, port.Published))
+		}
+	}
+	return warnings
+}
+
+type composeProject struct {
+	Services map[string]composeService `yaml:"services"`
+	Volumes  map[string]struct{}       `yaml:"volumes,omitempty"`
+}
+
+type composeService struct {
+	Image       string              `yaml:"image,omitempty"`
+	Build       *composeBuild       `yaml:"build,omitempty"`
+	Command     []string            `yaml:"command,omitempty"`
+	Entrypoint  []string            `yaml:"entrypoint,omitempty"`
+	WorkingDir  string              `yaml:"working_dir,omitempty"`
+	EnvFile     []string            `yaml:"env_file,omitempty"`
+	Ports       []string            `yaml:"ports,omitempty"`
+	Volumes     []string            `yaml:"volumes,omitempty"`
+	Healthcheck *composeHealthcheck `yaml:"healthcheck,omitempty"`
+	StopSignal  string              `yaml:"stop_signal,omitempty"`
+	StopGrace   string              `yaml:"stop_grace_period,omitempty"`
+	Restart     string              `yaml:"restart,omitempty"`
+	UsernsMode  string              `yaml:"userns_mode,omitempty"`
+	MemLimit    string              `yaml:"mem_limit,omitempty"`
+}
+
+type composeBuild struct {
+	Context    string `yaml:"context"`
+	Dockerfile string `yaml:"dockerfile,omitempty"`
+	Target     string `yaml:"target,omitempty"`
+}
+
+type composeHealthcheck struct {
+	Test        []string `yaml:"test"`
+	Interval    string   `yaml:"interval,omitempty"`
+	Timeout     string   `yaml:"timeout,omitempty"`
+	Retries     int      `yaml:"retries,omitempty"`
+	StartPeriod string   `yaml:"start_period,omitempty"`
+}
+
+func composeExportFiles(desired *runtimepkg.DesiredWorkspace, item *runtimepkg.DesiredResource, backend composeBackend, profile redactionProfile) (map[string][]byte, []string, []ExportRedaction, error) {
+	files := map[string][]byte{}
+	redactor := newExportRedactor(profile, desired)
+	service := composeService{
+		Image:      item.Spec.Image,
+		Command:    redactList(redactor, "command", item.Spec.Command),
+		Entrypoint: redactList(redactor, "entrypoint", item.Spec.Entrypoint),
+		WorkingDir: item.Spec.WorkingDir,
+		StopSignal: item.Spec.StopSignal,
+		Restart:    "unless-stopped",
+		UsernsMode: item.Spec.Userns,
+		MemLimit:   item.Spec.Memory,
+	}
+	if item.Spec.Init {
+		service.Restart = "no"
+	}
+	if build := item.Spec.Build; build != nil && service.Image == "" {
+		context, _ := redactor.hostPath("build context", redactor.portablePath(firstNonEmpty(build.ResolvedContext, build.Context)))
+		service.Build = &composeBuild{Context: context, Dockerfile: build.Dockerfile, Target: build.Target}
+	}
+	if item.Spec.StopTimeout != nil {
+		service.StopGrace = strconv.Itoa(*item.Spec.StopTimeout) + "s"
+	}
+	if health := item.Spec.Health; health != nil && len(health.Test) > 0 {
+		service.Healthcheck = &composeHealthcheck{Test: redactList(redactor, "healthcheck", health.Test), Interval: health.Interval, Timeout: health.Timeout, Retries: health.Retries, StartPeriod: health.StartPeriod}
+	}
+	for _, port := range item.Spec.Ports {
+		service.Ports = append(service.Ports, composePort(port))
+	}
+
+	project := composeProject{Services: map[string]composeService{item.Key: service}}
+	var hostPaths, warnings []string
+	for _, volume := range item.Spec.Volumes {
+		source := volume.Source
+		switch {
+		case source == "":
+		case !isHostPath(source):
+			if project.Volumes == nil {
+				project.Volumes = map[string]struct{}{}
+			}
+			project.Volumes[source] = struct{}{}
+		default:
+			hostPath := source
+			if strings.HasPrefix(hostPath, ".") {
+				hostPath = filepath.Join(desired.ManifestDir, hostPath)
+			}
+			if info, err := os.Stat(hostPath); err == nil && info.Mode().IsRegular() {
+				data, err := os.ReadFile(hostPath)
+				if err != nil {
+					return nil, nil, nil, fmt.Errorf("export resource %s: read %s: %w", item.Key, hostPath, err)
+				}
+				name := path.Join("config", filepath.Base(hostPath))
+				files[name] = []byte(redactor.text(name, "contents", string(data)))
+				source = "./" + name
+			} else {
+				source = redactor.portablePath(hostPath)
+				if placeholder, redacted := redactor.hostPath("volume "+volume.Target, source); redacted {
+					source = placeholder
+				} else {
+					hostPaths = append(hostPaths, source)
+				}
+			}
+		}
+		mount := volume.Target
+		if source != "" {
+			mount = source + ":" + volume.Target
+		}
+		var options []string
+		if volume.ReadOnly {
+			options = append(options, "ro")
+		}
+		for _, option := range volume.Options {
+			if option == "U" && !backend.podman {
+				warnings = append(warnings, fmt.Sprintf("volume %s drops the podman-only U option; chown the source to the container user instead", volume.Target))
+				continue
+			}
+			options = append(options, option)
+		}
+		if len(options) > 0 {
+			mount += ":" + strings.Join(options, ",")
+		}
+		service.Volumes = append(service.Volumes, mount)
+	}
+
+	if len(item.Spec.Env) > 0 {
+		service.EnvFile = []string{".env"}
+	}
+	if env := exportEnvFile(item, redactor); len(env) > 0 {
+		files[".env.example"] = env
+	}
+	warnings = append(warnings, backend.adapt(&service, item.Spec.Ports)...)
+	project.Services[item.Key] = service
+	compose, err := yaml.Marshal(project)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("export resource %s: encode compose.yaml: %w", item.Key, err)
+	}
+	files["compose.yaml"] = compose
+	files["README.md"] = exportReadme(desired, item, backend, hostPaths, warnings, redactor)
+	return files, warnings, redactor.redactions, nil
+}
+
+func redactList(redactor *exportRedactor, field string, values []string) []string {
+	var redacted []string
+	for _, value := range values {
+		redacted = append(redacted, redactor.text("compose.yaml", field, value))
+	}
+	return redacted
+}
+
+func composePort(port runtimepkg.PortSpec) string {
+	value := strconv.Itoa(port.Container)
+	if port.Published > 0 {
+		value = strconv.Itoa(port.Published) + ":" + value
+		if port.HostIP != "" {
+			value = port.HostIP + ":" + value
+		}
+	}
+	if port.Protocol != "" && port.Protocol != "tcp" {
+		value += "/" + port.Protocol
+	}
+	return value
+}
+
+func isHostPath(source string) bool {
+	return strings.HasPrefix(source, "/") || strings.HasPrefix(source, ".") || strings.HasPrefix(source, "~")
+}
+
+// exportEnvFile writes the resource's env, redacted, followed by the host
+// path placeholders compose.yaml refers to. Call it after every other part of
+// the export has been redacted, so all placeholders are known.
+func exportEnvFile(item *runtimepkg.DesiredResource, redactor *exportRedactor) []byte {
+	keys := make([]string, 0, len(item.Spec.Env))
+	for key := range item.Spec.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&b, "%s=%s\n", key, redactor.env(key, item.DeclaredEnv[key], item.Spec.Env[key]))
+	}
+	for _, placeholder := range redactor.placeholders {
+		fmt.Fprintf(&b, "%s=\n", placeholder)
+	}
+	return []byte(b.String())
+}
+
+func exportReadme(desired *runtimepkg.DesiredWorkspace, item *runtimepkg.DesiredResource, backend composeBackend, hostPaths, warnings []string, redactor *exportRedactor) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", item.Key)
+	fmt.Fprintf(&b, "Exported from the DevArch workspace `%s`.\n\n", desired.Name)
+	b.WriteString("## Start\n\n```bash\n")
+	if len(item.Spec.Env) > 0 || len(redactor.placeholders) > 0 {
+		b.WriteString("cp .env.example .env   # then fill in blank values\n")
+	}
+	b.WriteString(backend.upCommand + "\n```\n")
+	if len(item.DependsOn) > 0 || len(item.InjectedEnv) > 0 {
+		b.WriteString("\n## Dependencies\n\n")
+		if len(item.DependsOn) > 0 {
+			fmt.Fprintf(&b, "In DevArch this service depends on: %s. They are not part of this export.\n", strings.Join(item.DependsOn, ", "))
+		}
+		if len(item.InjectedEnv) > 0 {
+			keys := make([]string, 0, len(item.InjectedEnv))
+			for key := range item.InjectedEnv {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			fmt.Fprintf(&b, "These variables were wired from other resources; point them at your own services: %s.\n", strings.Join(keys, ", "))
+		}
+	}
+	if len(hostPaths) > 0 {
+		b.WriteString("\n## Host paths\n\nThese mounts refer to directories on the original machine. Adjust them in compose.yaml:\n\n")
+		for _, hostPath := range hostPaths {
+			fmt.Fprintf(&b, "- `%s`\n", hostPath)
+		}
+	}
+	if len(redactor.redactions) > 0 {
+		b.WriteString("\n## Redacted\n\nThese values were removed or replaced before export:\n\n")
+		seen := map[ExportRedaction]bool{}
+		for _, redaction := range redactor.redactions {
+			if !seen[redaction] {
+				seen[redaction] = true
+				fmt.Fprintf(&b, "- `%s` %s: %s\n", redaction.File, redaction.Field, redaction.Note)
+			}
+		}
+	}
+	if len(warnings) > 0 {
+		b.WriteString("\n## Compatibility\n\n")
+		for _, warning := range warnings {
+			fmt.Fprintf(&b, "- %s\n", warning)
+		}
+	}
+	return []byte(b.String())
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func writeExportDirectory(target string, files map[string][]byte) error {
+	if entries, err := os.ReadDir(target); err == nil && len(entries) > 0 {
+		return fmt.Errorf("export target %s is not empty", target)
+	}
+	for name, data := range files {
+		filePath := filepath.Join(target, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+			return fmt.Errorf("create %s: %w", filepath.Dir(filePath), err)
+		}
+		if err := os.WriteFile(filePath, data, 0o644); err != nil {
+			return fmt.Errorf("write %s: %w", filePath, err)
+		}
+	}
+	return nil
+}
+
+func writeExportArchive(target string, files map[string][]byte) error {
+	file, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("create export archive: %w", err)
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	archive := zip.NewWriter(file)
+	for _, name := range names {
+		entry, err := archive.Create(name)
+		if err == nil {
+			_, err = entry.Write(files[name])
+		}
+		if err != nil {
+			_ = file.Close()
+			return fmt.Errorf("write %s to export archive: %w", name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("finish export archive: %w", err)
+	}
+	return file.Close()
+}
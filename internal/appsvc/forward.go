@@ -0,0 +1,96 @@
+package appsvc
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// WorkspacePortForward builds the ssh command that forwards a workspace's
+// published ports from a remote host to the same ports on this machine. host
+// is an ssh destination (user@host) or a podman connection URL
+// (ssh://user@host:port/run/podman/podman.sock). DevArch does not run or
+// track the tunnel; the command runs in the foreground and closes on Ctrl-C.
+// Ports are read from the desired state, so random host ports and UDP ports
+// cannot be forwarded and are reported as skipped.
+func (s *Service) WorkspacePortForward(_ context.Context, name, host string) (*PortForwardView, error) {
+	state, err := s.loadWorkspaceState(name)
+	if err != nil {
+		return nil, err
+	}
+	destination, sshPort, err := sshDestination(host)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &PortForwardView{Workspace: state.Desired.Name, Host: destination, Forwards: []PortForward{}}
+	seen := map[int]string{}
+	for _, resource := range state.Desired.Resources {
+		if resource == nil || !resource.Enabled {
+			continue
+		}
+		for _, port := range resource.Spec.Ports {
+			switch {
+			case port.Published == 0:
+				view.Skipped = append(view.Skipped, fmt.Sprintf("%s: container port %d has no fixed host port", resource.Key, port.Container))
+				continue
+			case port.Protocol != "" && port.Protocol != "tcp":
+				view.Skipped = append(view.Skipped, fmt.Sprintf("%s: %d/%s cannot be forwarded over ssh", resource.Key, port.Published, port.Protocol))
+				continue
+			}
+			if owner, ok := seen[port.Published]; ok {
+				view.Skipped = append(view.Skipped, fmt.Sprintf("%s: host port %d is already forwarded for %s", resource.Key, port.Published, owner))
+				continue
+			}
+			seen[port.Published] = resource.Key
+			remoteHost := port.HostIP
+			if remoteHost == "" || remoteHost == "0.0.0.0" {
+				remoteHost = "127.0.0.1"
+			}
+			view.Forwards = append(view.Forwards, PortForward{Resource: resource.Key, Local: port.Published, RemoteHost: remoteHost, Remote: port.Published})
+		}
+	}
+	sort.SliceStable(view.Forwards, func(i, j int) bool { return view.Forwards[i].Local < view.Forwards[j].Local })
+
+	if len(view.Forwards) > 0 {
+		view.Command = portForwardCommand(destination, sshPort, view.Forwards)
+	}
+	return view, nil
+}
+
+// sshDestination accepts user@host, host, or an ssh:// URL and returns the
+// destination plus a non-default ssh port, if any.
+func sshDestination(host string) (string, string, error) {
+	host = strings.TrimSpace(host)
+	if host == "" {
+		return "", "", fmt.Errorf("remote host is required")
+	}
+	if !strings.Contains(host, "://") {
+		return host, "", nil
+	}
+	parsed, err := url.Parse(host)
+	if err != nil {
+		return "", "", fmt.Errorf("parse remote host %q: %w", host, err)
+	}
+	if parsed.Scheme != "ssh" || parsed.Hostname() == "" {
+		return "", "", fmt.Errorf("remote host %q must be an ssh:// URL or user@host", host)
+	}
+	destination := parsed.Hostname()
+	if parsed.User != nil && parsed.User.Username() != "" {
+		destination = parsed.User.Username() + "@" + destination
+	}
+	return destination, parsed.Port(), nil
+}
+
+func portForwardCommand(destination, sshPort string, forwards []PortForward) []string {
+	command := []string{"ssh", "-N", "-o", "ExitOnForwardFailure=yes"}
+	if sshPort != "" && sshPort != "22" {
+		command = append(command, "-p", sshPort)
+	}
+	for _, forward := range forwards {
+		command = append(command, "-L", fmt.Sprintf("%d:%s:%d", forward.Local, forward.RemoteHost, forward.Remote))
+	}
+	return append(command, destination)
+}
@@ -0,0 +1,138 @@
+package appsvc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+	"github.com/prospect-ogujiuba/devarch/internal/workspace"
+)
+
+// healthFamilies maps image repository names to the family whose health
+// checks fit them.
+var healthFamilies = map[string]string{
+	"postgres":           "postgres",
+	"postgis":            "postgres",
+	"timescaledb":        "postgres",
+	"mysql":              "mysql",
+	"percona":            "mysql",
+	"mariadb":            "mariadb",
+	"redis":              "redis",
+	"redis-stack-server": "redis",
+	"valkey":             "valkey",
+	"nginx":              "nginx",
+	"nginx-unprivileged": "nginx",
+	"mongo":              "mongo",
+	"rabbitmq":           "rabbitmq",
+}
+
+// familyHealthChecks are the curated checks for each image family. Each uses
+// a tool the official image ships, so none needs extra packages.
+var familyHealthChecks = map[string][]HealthSuggestion{
+	"postgres": {{
+		Name:        "pg_isready",
+		Description: "Healthy once the server accepts connections",
+		Health:      workspace.Health{Test: workspace.StringList{"CMD-SHELL", "pg_isready -h localhost"}, Interval: "10s", Timeout: "5s", Retries: 5, StartPeriod: "30s"},
+	}},
+	"mysql": {{
+		Name:        "mysqladmin",
+		Description: "Healthy once the server answers a ping",
+		Health:      workspace.Health{Test: workspace.StringList{"CMD-SHELL", "mysqladmin ping -h localhost --silent"}, Interval: "10s", Timeout: "5s", Retries: 5, StartPeriod: "30s"},
+	}},
+	"mariadb": {{
+		Name:        "healthcheck.sh",
+		Description: "Healthy once the server accepts connections and InnoDB is initialized",
+		Health:      workspace.Health{Test: workspace.StringList{"CMD", "healthcheck.sh", "--connect", "--innodb_initialized"}, Interval: "10s", Timeout: "5s", Retries: 5, StartPeriod: "30s"},
+	}},
+	"redis": {{
+		Name:        "redis-cli",
+		Description: "Healthy once the server answers PING, with or without a password set",
+		Health:      workspace.Health{Test: workspace.StringList{"CMD-SHELL", "redis-cli ping | grep -qE 'PONG|NOAUTH'"}, Interval: "10s", Timeout: "3s", Retries: 5, StartPeriod: "5s"},
+	}},
+	"valkey": {{
+		Name:        "valkey-cli",
+		Description: "Healthy once the server answers PING, with or without a password set",
+		Health:      workspace.Health{Test: workspace.StringList{"CMD-SHELL", "valkey-cli ping | grep -qE 'PONG|NOAUTH'"}, Interval: "10s", Timeout: "3s", Retries: 5, StartPeriod: "5s"},
+	}},
+	"nginx": {{
+		Name:        "http",
+		Description: "Healthy once the default server answers with 2xx",
+		Health:      workspace.Health{HTTP: "http://localhost/", Interval: "30s", Timeout: "10s", Retries: 3, StartPeriod: "10s"},
+	}},
+	"mongo": {{
+		Name:        "mongosh",
+		Description: "Healthy once the server answers the ping command",
+		Health:      workspace.Health{Test: workspace.StringList{"CMD-SHELL", `mongosh --quiet --eval "db.adminCommand('ping').ok" | grep -q 1`}, Interval: "10s", Timeout: "5s", Retries: 5, StartPeriod: "30s"},
+	}},
+	"rabbitmq": {{
+		Name:        "diagnostics",
+		Description: "Healthy once the node answers rabbitmq-diagnostics ping",
+		Health:      workspace.Health{Test: workspace.StringList{"CMD-SHELL", "rabbitmq-diagnostics -q ping"}, Interval: "30s", Timeout: "10s", Retries: 5, StartPeriod: "30s"},
+	}},
+}
+
+// HealthSuggestions lists ready-made health checks for one resource: the
+// curated checks for its image family (postgres, mysql, mariadb, redis,
+// valkey, nginx, mongo, or rabbitmq) and a tcp check on its first port. With
+// request.Apply set to a suggestion name, that check is written into the
+// resource's entry in the workspace manifest, where it overrides the
+// template's health check. A resource whose manifest entry already sets
+// health is refused.
+func (s *Service) HealthSuggestions(_ context.Context, name, resource string, request HealthSuggestionRequest) (*HealthSuggestionsView, error) {
+	if request.Apply != "" {
+		if err := s.checkWritable("health-suggest"); err != nil {
+			return nil, err
+		}
+	}
+	state, err := s.loadWorkspaceState(name)
+	if err != nil {
+		return nil, err
+	}
+	item := state.Desired.Resource(resource)
+	if item == nil {
+		return nil, &NotFoundError{Kind: "resource", Name: resource, Workspace: name}
+	}
+	view := &HealthSuggestionsView{
+		Workspace:   state.Desired.Name,
+		Resource:    item.Key,
+		Image:       item.Spec.Image,
+		Family:      healthFamilies[imageBaseName(item.Spec.Image)],
+		Current:     item.Spec.Health,
+		Suggestions: healthSuggestions(item),
+	}
+	if request.Apply == "" {
+		return view, nil
+	}
+	var names []string
+	for _, suggestion := range view.Suggestions {
+		if suggestion.Name == request.Apply {
+			if err := workspace.SetResourceHealth(state.Workspace.ManifestPath, item.Key, suggestion.Health); err != nil {
+				return nil, err
+			}
+			view.Applied, view.ManifestPath = suggestion.Name, state.Workspace.ManifestPath
+			return view, nil
+		}
+		names = append(names, suggestion.Name)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no health check suggestions for resource %q (image %q)", item.Key, item.Spec.Image)
+	}
+	return nil, fmt.Errorf("unknown health check suggestion %q for resource %q (expected %s)", request.Apply, item.Key, strings.Join(names, ", "))
+}
+
+func healthSuggestions(item *runtimepkg.DesiredResource) []HealthSuggestion {
+	suggestions := append([]HealthSuggestion{}, familyHealthChecks[healthFamilies[imageBaseName(item.Spec.Image)]]...)
+	for _, port := range item.Spec.Ports {
+		if port.Protocol != "" && port.Protocol != "tcp" {
+			continue
+		}
+		suggestions = append(suggestions, HealthSuggestion{
+			Name:        "tcp",
+			Description: fmt.Sprintf("Healthy once port %d accepts connections", port.Container),
+			Health:      workspace.Health{TCP: port.Container, Interval: "10s", Timeout: "5s", Retries: 5, StartPeriod: "10s"},
+		})
+		break
+	}
+	return suggestions
+}
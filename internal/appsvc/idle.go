@@ -0,0 +1,98 @@
+package appsvc
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultIdleCPUPercent   = 2.0
+	defaultIdleNetworkBytes = 64 << 10
+	defaultIdleSamples      = 3
+	defaultIdleInterval     = 20 * time.Second
+)
+
+// IdleWorkspace samples resource stats several times and reports whether the
+// workspace stayed idle: total CPU under the threshold in every sample and
+// network traffic between the first and last sample within the byte budget.
+// With Stop set, an idle workspace is stopped so StartWorkspace can resume it.
+func (s *Service) IdleWorkspace(ctx context.Context, name string, request IdleRequest) (*WorkspaceIdleView, error) {
+	if request.Stop {
+		if err := s.checkWritable("idle"); err != nil {
+			return nil, err
+		}
+	}
+	if request.CPUPercent < 0 || request.Samples < 0 || request.Interval < 0 {
+		return nil, fmt.Errorf("idle thresholds must not be negative")
+	}
+	if request.CPUPercent == 0 {
+		request.CPUPercent = defaultIdleCPUPercent
+	}
+	if request.NetworkBytes == 0 {
+		request.NetworkBytes = defaultIdleNetworkBytes
+	}
+	if request.Samples == 0 {
+		request.Samples = defaultIdleSamples
+	}
+	if request.Interval == 0 {
+		request.Interval = defaultIdleInterval
+	}
+
+	samples := make([]*WorkspaceStatsView, 0, request.Samples)
+	for i := 0; i < request.Samples; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(request.Interval):
+			}
+		}
+		sample, err := s.WorkspaceStats(ctx, name, "")
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, sample)
+	}
+
+	view := evaluateIdle(samples, request)
+	if view.Idle && request.Stop && view.Running > 0 {
+		stopped, err := s.StopWorkspace(ctx, name, request.StopTimeout)
+		view.Stopped = stopped
+		if err != nil {
+			return view, err
+		}
+	}
+	return view, nil
+}
+
+func evaluateIdle(samples []*WorkspaceStatsView, request IdleRequest) *WorkspaceIdleView {
+	view := &WorkspaceIdleView{Samples: len(samples)}
+	if len(samples) == 0 {
+		return view
+	}
+	first, last := samples[0], samples[len(samples)-1]
+	view.Workspace = last.Workspace
+	view.Provider = last.Provider
+	view.Running = len(last.Resources)
+	for _, sample := range samples {
+		if sample.Total.CPUPercent > view.MaxCPUPercent {
+			view.MaxCPUPercent = sample.Total.CPUPercent
+		}
+	}
+	before := map[string]uint64{}
+	for _, resource := range first.Resources {
+		before[resource.Key] = resource.NetInputBytes + resource.NetOutputBytes
+	}
+	for _, resource := range last.Resources {
+		// Counters reset when a container restarts between samples; count
+		// the new total rather than a negative delta.
+		current := resource.NetInputBytes + resource.NetOutputBytes
+		if previous, ok := before[resource.Key]; ok && current >= previous {
+			current -= previous
+		}
+		view.NetworkBytes += current
+	}
+	view.Idle = view.MaxCPUPercent < request.CPUPercent && view.NetworkBytes <= request.NetworkBytes
+	return view
+}
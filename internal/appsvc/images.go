@@ -0,0 +1,177 @@
+package appsvc
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prospect-ogujiuba/devarch/internal/apply"
+	planpkg "github.com/prospect-ogujiuba/devarch/internal/plan"
+	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+	"github.com/prospect-ogujiuba/devarch/internal/workspace"
+)
+
+// SaveWorkspaceImages writes every image referenced by the enabled resources
+// of a workspace into one archive that LoadWorkspaceImages can restore on a
+// host without registry access.
+func (s *Service) SaveWorkspaceImages(ctx context.Context, name, archive string) (*ImageBundleView, error) {
+	if err := s.checkWritable("save-images"); err != nil {
+		return nil, err
+	}
+	state, archiver, archivePath, err := s.loadImageArchiver(name, archive, "save-images")
+	if err != nil {
+		return nil, err
+	}
+	images := workspaceImages(state.Desired)
+	if len(images) == 0 {
+		return nil, fmt.Errorf("workspace %q has no resource images to save", name)
+	}
+	if err := archiver.SaveImages(ctx, images, archivePath); err != nil {
+		return nil, err
+	}
+	return &ImageBundleView{Workspace: state.Desired.Name, Provider: state.Desired.Provider, Archive: archivePath, Images: images}, nil
+}
+
+// LoadWorkspaceImages loads an image archive into the runtime selected by the
+// workspace.
+func (s *Service) LoadWorkspaceImages(ctx context.Context, name, archive string) (*ImageBundleView, error) {
+	if err := s.checkWritable("load-images"); err != nil {
+		return nil, err
+	}
+	state, archiver, archivePath, err := s.loadImageArchiver(name, archive, "load-images")
+	if err != nil {
+		return nil, err
+	}
+	images, err := archiver.LoadImages(ctx, archivePath)
+	if err != nil {
+		return nil, err
+	}
+	if images == nil {
+		images = []string{}
+	}
+	return &ImageBundleView{Workspace: state.Desired.Name, Provider: state.Desired.Provider, Archive: archivePath, Images: images}, nil
+}
+
+// UpdateWorkspaceImages pulls the image of every resource whose
+// overrides.autoUpdate is managed and reports which running containers are
+// on an older digest. With request.Apply it recreates those resources, but
+// only while one of the workspace's maintenance windows is open, unless
+// request.IgnoreWindow is set. Nothing else in the workspace is applied.
+func (s *Service) UpdateWorkspaceImages(ctx context.Context, name string, request ImageUpdateRequest) (*ImageUpdateView, error) {
+	if err := s.checkWritable("update-images"); err != nil {
+		return nil, err
+	}
+	state, err := s.loadRuntimeState(name, "update-images")
+	if err != nil {
+		return nil, err
+	}
+	refresher, ok := state.Adapter.(runtimepkg.ImageRefresher)
+	if !ok {
+		return nil, unsupportedCapability(name, "", state.Desired.Provider, "update-images", "images", "selected runtime does not support refreshing images")
+	}
+	if !state.Desired.Capabilities.Inspect {
+		return nil, unsupportedCapability(name, "", state.Desired.Provider, "update-images", "inspect", "selected runtime does not support workspace inspection")
+	}
+	snapshot, err := state.Adapter.InspectWorkspace(ctx, state.Desired)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	view := &ImageUpdateView{
+		Workspace:     state.Desired.Name,
+		Provider:      state.Desired.Provider,
+		InMaintenance: inMaintenance(state.Workspace, now),
+		Resources:     []ImageUpdateResource{},
+	}
+	outdated := map[string]bool{}
+	for _, resource := range state.Desired.Resources {
+		if resource == nil || !resource.Enabled || resource.Spec.Image == "" || resource.OverrideLabels[runtimepkg.LabelManagedUpdate] != runtimepkg.AutoUpdateManaged {
+			continue
+		}
+		latest, err := refresher.RefreshImage(ctx, resource.Spec.Image)
+		if err != nil {
+			return nil, err
+		}
+		item := ImageUpdateResource{Resource: resource.Key, Image: resource.Spec.Image, LatestID: latest}
+		if current := snapshot.Resource(resource.Key); current != nil {
+			item.CurrentID = current.ImageID
+			item.Outdated = !runtimepkg.SameImageID(current.ImageID, latest)
+		}
+		if item.Outdated {
+			outdated[resource.Key] = true
+		}
+		view.Resources = append(view.Resources, item)
+	}
+	if !request.Apply || len(outdated) == 0 {
+		return view, nil
+	}
+	if !view.InMaintenance && !request.IgnoreWindow {
+		view.Skipped = "outside the workspace's maintenance windows"
+		return view, nil
+	}
+	diff, err := planpkg.Diff(state.Desired, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	diff = planpkg.ImageUpdates(diff, outdated)
+	if err := ensureApplyCapabilities(name, state.Desired.Provider, state.Desired.Capabilities, diff); err != nil {
+		return nil, err
+	}
+	payload, err := apply.Render(state.Desired)
+	if err != nil {
+		return nil, err
+	}
+	executor := &apply.Executor{Adapter: state.Adapter, Cache: s.cache, Publisher: s.bus, Actor: s.actor}
+	view.Apply, err = executor.Execute(ctx, diff, payload)
+	return view, err
+}
+
+// inMaintenance reports whether one of the workspace's maintenance windows
+// is open at now.
+func inMaintenance(ws *workspace.Workspace, now time.Time) bool {
+	for _, window := range ws.Metadata.Maintenance {
+		for _, event := range maintenanceOccurrences(window, now, now.Add(time.Second), now.Location()) {
+			if !event.Start.After(now) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *Service) loadImageArchiver(name, archive, operation string) (*workspaceState, runtimepkg.ImageArchiver, string, error) {
+	archive = strings.TrimSpace(archive)
+	if archive == "" {
+		return nil, nil, "", fmt.Errorf("archive path is required")
+	}
+	archivePath, err := filepath.Abs(archive)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("resolve archive path: %w", err)
+	}
+	state, err := s.loadRuntimeState(name, operation)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	archiver, ok := state.Adapter.(runtimepkg.ImageArchiver)
+	if !ok {
+		return nil, nil, "", unsupportedCapability(name, "", state.Desired.Provider, operation, "images", "selected runtime does not support image archives")
+	}
+	return state, archiver, archivePath, nil
+}
+
+func workspaceImages(desired *runtimepkg.DesiredWorkspace) []string {
+	seen := map[string]bool{}
+	images := make([]string, 0, len(desired.Resources))
+	for _, resource := range desired.Resources {
+		if resource == nil || !resource.Enabled || resource.Spec.Image == "" || seen[resource.Spec.Image] {
+			continue
+		}
+		seen[resource.Spec.Image] = true
+		images = append(images, resource.Spec.Image)
+	}
+	sort.Strings(images)
+	return images
+}
@@ -0,0 +1,79 @@
+package appsvc
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+)
+
+const defaultInspectorTail = 200
+
+var (
+	inspectorRequestLine  = regexp.MustCompile(`^(\S+): ([A-Z]+) (\S+)`)
+	inspectorResponseLine = regexp.MustCompile(`^<< (?:HTTP/\S+ )?(\d{3})(?: (.*))?$`)
+)
+
+// ResourceRequests lists the HTTP exchanges recorded by a resource's
+// inspector, oldest first. They are parsed from the last tail lines of the
+// inspector's logs, so the history is whatever the runtime still keeps.
+func (s *Service) ResourceRequests(ctx context.Context, name, resource string, tail int) (*InspectorRequestsView, error) {
+	if tail < 0 {
+		return nil, fmt.Errorf("tail must not be negative")
+	}
+	if tail == 0 {
+		tail = defaultInspectorTail
+	}
+	state, item, err := s.loadRuntimeResource(name, resource, "requests")
+	if err != nil {
+		return nil, err
+	}
+	inspector := state.Desired.Resource(runtimepkg.InspectorKey(item.Key))
+	if inspector == nil {
+		return nil, fmt.Errorf("resource %q has no inspector; set resources.%s.inspector", item.Key, item.Key)
+	}
+	if !state.Desired.Capabilities.Logs {
+		return nil, unsupportedCapability(name, item.Key, state.Desired.Provider, "requests", "logs", "selected runtime does not support log streaming")
+	}
+
+	var chunks []runtimepkg.LogChunk
+	ref := runtimepkg.ResourceRef{Workspace: state.Desired.Name, Key: inspector.Key, RuntimeName: inspector.RuntimeName}
+	if err := state.Adapter.StreamLogs(ctx, ref, runtimepkg.LogsRequest{Tail: tail}, func(chunk runtimepkg.LogChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return &InspectorRequestsView{Workspace: state.Desired.Name, Resource: item.Key, Inspector: inspector.Key, Requests: parseInspectorLog(chunks)}, nil
+}
+
+// parseInspectorLog pairs mitmdump's "client: METHOD URL" lines with the
+// "<< STATUS reason size" line that follows them. A "<<" line without a
+// status is a proxy error, such as the upstream refusing the connection.
+func parseInspectorLog(chunks []runtimepkg.LogChunk) []InspectedRequest {
+	requests := []InspectedRequest{}
+	pending := -1
+	for _, chunk := range chunks {
+		line := strings.TrimSpace(chunk.Line)
+		if match := inspectorRequestLine.FindStringSubmatch(line); match != nil {
+			requests = append(requests, InspectedRequest{Time: chunk.Timestamp, Client: match[1], Method: match[2], URL: match[3]})
+			pending = len(requests) - 1
+			continue
+		}
+		rest, ok := strings.CutPrefix(line, "<<")
+		if !ok || pending < 0 {
+			continue
+		}
+		if match := inspectorResponseLine.FindStringSubmatch(line); match != nil {
+			requests[pending].Status, _ = strconv.Atoi(match[1])
+			requests[pending].Response = match[2]
+		} else {
+			requests[pending].Error = strings.TrimSpace(rest)
+		}
+		pending = -1
+	}
+	return requests
+}
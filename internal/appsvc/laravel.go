@@ -0,0 +1,129 @@
+package appsvc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/prospect-ogujiuba/devarch/internal/projectscan"
+	"github.com/prospect-ogujiuba/devarch/internal/resolve"
+	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+	"github.com/prospect-ogujiuba/devarch/internal/workspace"
+)
+
+// laravelWorkers are the companion resources LaravelWorkers adds, keyed by
+// the suffix appended to the app resource key.
+var laravelWorkers = []struct {
+	Suffix  string
+	Command []string
+}{
+	{Suffix: "queue", Command: []string{"php", "artisan", "queue:work", "--tries=3"}},
+	{Suffix: "scheduler", Command: []string{"php", "artisan", "schedule:work"}},
+}
+
+// RunArtisan runs php artisan with args inside a Laravel resource's running
+// container.
+func (s *Service) RunArtisan(ctx context.Context, name, resource string, args []string) (*runtimepkg.ExecResult, error) {
+	if _, err := s.laravelResource(name, resource); err != nil {
+		return nil, err
+	}
+	command := append([]string{"php", "artisan"}, args...)
+	return s.ExecWorkspace(ctx, name, resource, runtimepkg.ExecRequest{Command: command})
+}
+
+// LaravelWorkers describes a queue worker and a scheduler for a Laravel
+// resource: copies of its template, source, env, volumes, imports, and
+// overrides that run queue:work and schedule:work instead, and depend on
+// the app. With request.Write they are appended to the workspace manifest.
+func (s *Service) LaravelWorkers(_ context.Context, name, resource string, request LaravelWorkersRequest) (*LaravelWorkersView, error) {
+	if request.Write {
+		if err := s.checkWritable("laravel workers"); err != nil {
+			return nil, err
+		}
+	}
+	ws, err := s.laravelResource(name, resource)
+	if err != nil {
+		return nil, err
+	}
+	app := ws.Resources[resource]
+	view := &LaravelWorkersView{Workspace: name, Resource: resource, ManifestPath: ws.ManifestPath}
+	workers := map[string]workspace.Resource{}
+	for _, worker := range laravelWorkers {
+		key := resource + "-" + worker.Suffix
+		if _, exists := ws.Resources[key]; exists {
+			return nil, fmt.Errorf("workspace %q already has a resource %q", name, key)
+		}
+		overrides := map[string]any{}
+		for field, value := range app.Overrides {
+			overrides[field] = value
+		}
+		overrides["command"] = worker.Command
+		workers[key] = workspace.Resource{
+			Template:  app.Template,
+			Source:    app.Source,
+			Env:       app.Env,
+			Volumes:   app.Volumes,
+			DependsOn: append([]string{resource}, app.DependsOn...),
+			Imports:   app.Imports,
+			Overrides: overrides,
+		}
+		view.Added = append(view.Added, key)
+	}
+
+	var encoded bytes.Buffer
+	encoder := yaml.NewEncoder(&encoded)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(workers); err != nil {
+		return nil, fmt.Errorf("encode workers for %s: %w", resource, err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("encode workers for %s: %w", resource, err)
+	}
+	view.YAML = encoded.String()
+	if !request.Write {
+		return view, nil
+	}
+	for _, key := range view.Added {
+		if err := workspace.AddResource(ws.ManifestPath, key, workers[key]); err != nil {
+			return nil, err
+		}
+	}
+	view.Written = true
+	return view, nil
+}
+
+// laravelResource loads the workspace and checks that resource is a Laravel
+// app: built from a template with "laravel" in its name, or from a project
+// source that scans as Laravel.
+func (s *Service) laravelResource(name, resource string) (*workspace.Workspace, error) {
+	resource = strings.TrimSpace(resource)
+	if resource == "" {
+		return nil, fmt.Errorf("resource is required")
+	}
+	state, err := s.loadWorkspaceState(name)
+	if err != nil {
+		return nil, err
+	}
+	item := state.Graph.Resource(resource)
+	if item == nil || state.Workspace.Resources[resource] == nil {
+		return nil, &NotFoundError{Kind: "resource", Name: resource, Workspace: name}
+	}
+	if !isLaravelResource(item) {
+		return nil, fmt.Errorf("resource %q in workspace %q is not a Laravel app", resource, name)
+	}
+	return state.Workspace, nil
+}
+
+func isLaravelResource(item *resolve.Resource) bool {
+	if item.Template != nil && strings.Contains(item.Template.Name, "laravel") {
+		return true
+	}
+	if item.Source == nil || item.Source.Type != "project" || item.Source.ResolvedPath == "" {
+		return false
+	}
+	scan, err := projectscan.Scan(item.Source.ResolvedPath)
+	return err == nil && scan.ProjectType == "laravel"
+}
@@ -0,0 +1,86 @@
+package appsvc
+
+import (
+	"context"
+	"fmt"
+
+	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+)
+
+// StopWorkspace stops every running resource without removing it, dependents
+// first. Stopped containers keep their filesystem, so StartWorkspace resumes
+// them without a fresh apply.
+func (s *Service) StopWorkspace(ctx context.Context, name string, timeout *int) (*WorkspaceLifecycleView, error) {
+	if err := s.checkWritable("stop"); err != nil {
+		return nil, err
+	}
+	if timeout != nil && *timeout < 0 {
+		return nil, fmt.Errorf("stop timeout must not be negative")
+	}
+	return s.runWorkspaceLifecycle(ctx, name, "stop", func(lifecycle runtimepkg.ResourceLifecycle, ref runtimepkg.ResourceRef) error {
+		return lifecycle.StopResource(ctx, ref, timeout)
+	})
+}
+
+// StartWorkspace starts stopped resources, dependencies first. Resources that
+// were never applied are skipped; run apply to create them.
+func (s *Service) StartWorkspace(ctx context.Context, name string) (*WorkspaceLifecycleView, error) {
+	if err := s.checkWritable("start"); err != nil {
+		return nil, err
+	}
+	return s.runWorkspaceLifecycle(ctx, name, "start", func(lifecycle runtimepkg.ResourceLifecycle, ref runtimepkg.ResourceRef) error {
+		return lifecycle.StartResource(ctx, ref)
+	})
+}
+
+func (s *Service) runWorkspaceLifecycle(ctx context.Context, name, action string, run func(runtimepkg.ResourceLifecycle, runtimepkg.ResourceRef) error) (*WorkspaceLifecycleView, error) {
+	state, err := s.loadRuntimeState(name, action)
+	if err != nil {
+		return nil, err
+	}
+	lifecycle, ok := state.Adapter.(runtimepkg.ResourceLifecycle)
+	if !ok {
+		return nil, unsupportedCapability(name, "", state.Desired.Provider, action, "lifecycle", "selected runtime does not support stopping and starting resources")
+	}
+	snapshot, err := state.Adapter.InspectWorkspace(ctx, state.Desired)
+	if err != nil {
+		return nil, err
+	}
+
+	order := rollingRestartOrder(state.Desired.Resources)
+	if action == "start" {
+		for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+			order[i], order[j] = order[j], order[i]
+		}
+	}
+	view := &WorkspaceLifecycleView{Workspace: state.Desired.Name, Provider: state.Desired.Provider, Action: action, Steps: []LifecycleStep{}}
+	for _, item := range order {
+		step := LifecycleStep{Key: item.Key, RuntimeName: item.RuntimeName}
+		observed := snapshot.Resource(item.Key)
+		switch {
+		case observed == nil:
+			step.Status, step.Reason = "skipped", "not created"
+		case action == "stop" && !observed.State.Running:
+			step.Status, step.Reason = "skipped", "not running"
+		case action == "start" && observed.State.Running:
+			step.Status, step.Reason = "skipped", "already running"
+		}
+		if step.Status != "" {
+			view.Steps = append(view.Steps, step)
+			continue
+		}
+		ref := runtimepkg.ResourceRef{Workspace: state.Desired.Name, Key: item.Key, RuntimeName: item.RuntimeName}
+		if err := run(lifecycle, ref); err != nil {
+			step.Status = "failed"
+			step.Reason = err.Error()
+			view.Steps = append(view.Steps, step)
+			return view, fmt.Errorf("%s %s: %w", action, item.Key, err)
+		}
+		step.Status = "started"
+		if action == "stop" {
+			step.Status = "stopped"
+		}
+		view.Steps = append(view.Steps, step)
+	}
+	return view, nil
+}
@@ -0,0 +1,184 @@
+package appsvc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+)
+
+const (
+	defaultLoadTestSampleInterval = 2 * time.Second
+	loadTestOutputLimit           = 4000
+)
+
+// RunLoadTest runs a k6 script against one resource and samples the
+// resource's CPU and memory while it runs. The script reaches the resource
+// through the TARGET environment variable (http://<runtime name>:<port>), so
+// the workspace needs an isolated network. Failed k6 thresholds are reported
+// in the result, not as an error.
+func (s *Service) RunLoadTest(ctx context.Context, name, resource string, request LoadTestRequest) (*LoadTestReport, error) {
+	if err := s.checkWritable("loadtest"); err != nil {
+		return nil, err
+	}
+	if request.SampleInterval == 0 {
+		request.SampleInterval = defaultLoadTestSampleInterval
+	}
+	if request.SampleInterval < 0 {
+		return nil, fmt.Errorf("sample interval must not be negative")
+	}
+	script, err := os.ReadFile(request.Script)
+	if err != nil {
+		return nil, fmt.Errorf("read load test script: %w", err)
+	}
+	state, item, err := s.loadRuntimeResource(name, resource, "loadtest")
+	if err != nil {
+		return nil, err
+	}
+	tester, ok := state.Adapter.(runtimepkg.LoadTester)
+	if !ok {
+		return nil, unsupportedCapability(name, item.Key, state.Desired.Provider, "loadtest", "loadtest", "selected runtime does not run load tests")
+	}
+	if state.Desired.Network == nil {
+		return nil, fmt.Errorf("workspace %q needs runtime.isolatedNetwork for load tests to reach %q", name, item.Key)
+	}
+	port := request.Port
+	if port == 0 {
+		if len(item.Spec.Ports) == 0 {
+			return nil, fmt.Errorf("resource %q has no ports; pass a port to load test", item.Key)
+		}
+		port = item.Spec.Ports[0].Container
+	}
+	target := fmt.Sprintf("http://%s:%d", item.RuntimeName, port)
+
+	scratch, err := os.MkdirTemp("", "devarch-loadtest-")
+	if err != nil {
+		return nil, fmt.Errorf("create load test directory: %w", err)
+	}
+	defer os.RemoveAll(scratch)
+	if err := os.WriteFile(filepath.Join(scratch, "script.js"), script, 0o644); err != nil {
+		return nil, fmt.Errorf("write load test script: %w", err)
+	}
+	// The directory stays private; the k6 image runs as an unprivileged user,
+	// so only the summary file it is handed is writable by others.
+	summaryPath := filepath.Join(scratch, "summary.json")
+	if err := os.WriteFile(summaryPath, nil, 0o600); err != nil {
+		return nil, fmt.Errorf("prepare load test summary: %w", err)
+	}
+	if err := os.Chmod(summaryPath, 0o666); err != nil {
+		return nil, fmt.Errorf("prepare load test summary: %w", err)
+	}
+
+	ref := runtimepkg.ResourceRef{Workspace: state.Desired.Name, Key: item.Key, RuntimeName: item.RuntimeName}
+	var samples []runtimepkg.ResourceStats
+	sampleCtx, stopSampling := context.WithCancel(ctx)
+	var sampling sync.WaitGroup
+	if reader, ok := state.Adapter.(runtimepkg.StatsReader); ok {
+		sampling.Add(1)
+		go func() {
+			defer sampling.Done()
+			samples = sampleResourceStats(sampleCtx, reader, ref, request.SampleInterval)
+		}()
+	}
+
+	report := &LoadTestReport{Workspace: state.Desired.Name, Resource: item.Key, Target: target, Script: request.Script, StartedAt: time.Now().UTC()}
+	result, err := tester.RunLoadTest(ctx, runtimepkg.LoadTestRequest{
+		Network:   state.Desired.Network.Name,
+		Directory: scratch,
+		Script:    "script.js",
+		Summary:   "summary.json",
+		Env:       map[string]string{"TARGET": target},
+	})
+	stopSampling()
+	sampling.Wait()
+	report.FinishedAt = time.Now().UTC()
+	if err != nil {
+		return nil, err
+	}
+	report.ExitCode = result.ExitCode
+	report.ThresholdsFailed = result.ExitCode == runtimepkg.LoadTestThresholdExitCode
+	report.Output = tailText(result.Stdout, loadTestOutputLimit)
+	if data, err := os.ReadFile(summaryPath); err == nil && len(data) > 0 {
+		summary, err := parseK6Summary(data)
+		if err != nil {
+			return nil, err
+		}
+		report.Summary = summary
+	}
+	report.Usage = summarizeUsage(samples)
+	return report, nil
+}
+
+// sampleResourceStats samples ref every interval until ctx is done. Failed
+// samples are skipped; a container restarting under load should not abort
+// the report.
+func sampleResourceStats(ctx context.Context, reader runtimepkg.StatsReader, ref runtimepkg.ResourceRef, interval time.Duration) []runtimepkg.ResourceStats {
+	var samples []runtimepkg.ResourceStats
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return samples
+		case <-ticker.C:
+			stats, err := reader.ResourceStats(ctx, []runtimepkg.ResourceRef{ref})
+			if err == nil && len(stats) > 0 {
+				samples = append(samples, stats[0])
+			}
+		}
+	}
+}
+
+func summarizeUsage(samples []runtimepkg.ResourceStats) *LoadTestUsage {
+	if len(samples) == 0 {
+		return nil
+	}
+	usage := &LoadTestUsage{Samples: len(samples)}
+	var cpuTotal float64
+	for _, sample := range samples {
+		cpuTotal += sample.CPUPercent
+		usage.CPUPeakPercent = max(usage.CPUPeakPercent, sample.CPUPercent)
+		usage.MemoryPeakBytes = max(usage.MemoryPeakBytes, sample.MemoryBytes)
+	}
+	usage.CPUAvgPercent = cpuTotal / float64(len(samples))
+	return usage
+}
+
+// parseK6Summary reads the request metrics from k6's --summary-export file.
+// Durations are milliseconds, as k6 reports them.
+func parseK6Summary(data []byte) (*LoadTestSummary, error) {
+	var export struct {
+		Metrics map[string]map[string]float64 `json:"metrics"`
+	}
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("parse k6 summary: %w", err)
+	}
+	requests := export.Metrics["http_reqs"]
+	duration := export.Metrics["http_req_duration"]
+	failed := export.Metrics["http_req_failed"]
+	return &LoadTestSummary{
+		Requests:      int(requests["count"]),
+		RequestsPerS:  requests["rate"],
+		FailedRatio:   failed["value"],
+		DurationAvgMs: duration["avg"],
+		DurationP95Ms: duration["p(95)"],
+		DurationMaxMs: duration["max"],
+	}, nil
+}
+
+func tailText(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	text = text[len(text)-limit:]
+	if newline := strings.IndexByte(text, '\n'); newline >= 0 {
+		text = text[newline+1:]
+	}
+	return text
+}
@@ -2,7 +2,11 @@ package appsvc
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/prospect-ogujiuba/devarch/internal/apply"
+	cachepkg "github.com/prospect-ogujiuba/devarch/internal/cache"
+	"github.com/prospect-ogujiuba/devarch/internal/catalog"
 	"github.com/prospect-ogujiuba/devarch/internal/contracts"
 	"github.com/prospect-ogujiuba/devarch/internal/projectscan"
 	"github.com/prospect-ogujiuba/devarch/internal/resolve"
@@ -15,6 +19,7 @@ type WorkflowStatus = workflows.WorkflowStatus
 type DoctorReport = workflows.DoctorReport
 type RuntimeStatusReport = workflows.RuntimeStatusReport
 type SocketStatusReport = workflows.SocketStatusReport
+type SocketDiagnosisReport = workflows.SocketDiagnosisReport
 type WorkflowCommandResult = workflows.CommandResult
 type WorkflowCheckResult = workflows.CheckResult
 
@@ -23,25 +28,32 @@ type TemplateSummary struct {
 	Name        string   `json:"name"`
 	Description string   `json:"description,omitempty"`
 	Tags        []string `json:"tags,omitempty"`
+	Team        string   `json:"team,omitempty"`
+	Visibility  string   `json:"visibility,omitempty"`
 }
 
 // TemplateDetail is the API-safe catalog detail shape. It intentionally omits
 // internal file paths and uses stable JSON field names instead of the raw
 // catalog package struct layout.
 type TemplateDetail struct {
-	APIVersion  string                        `json:"apiVersion,omitempty"`
-	Kind        string                        `json:"kind,omitempty"`
-	Name        string                        `json:"name"`
-	Description string                        `json:"description,omitempty"`
-	Tags        []string                      `json:"tags,omitempty"`
-	Runtime     map[string]any                `json:"runtime,omitempty"`
-	Env         map[string]workspace.EnvValue `json:"env,omitempty"`
-	Ports       []workspace.Port              `json:"ports,omitempty"`
-	Volumes     []workspace.Volume            `json:"volumes,omitempty"`
-	Imports     []workspace.Import            `json:"imports,omitempty"`
-	Exports     []workspace.Export            `json:"exports,omitempty"`
-	Health      *workspace.Health             `json:"health,omitempty"`
-	Develop     map[string]any                `json:"develop,omitempty"`
+	APIVersion  string                            `json:"apiVersion,omitempty"`
+	Kind        string                            `json:"kind,omitempty"`
+	Name        string                            `json:"name"`
+	Description string                            `json:"description,omitempty"`
+	Tags        []string                          `json:"tags,omitempty"`
+	Team        string                            `json:"team,omitempty"`
+	Visibility  string                            `json:"visibility,omitempty"`
+	SharedWith  []string                          `json:"sharedWith,omitempty"`
+	Runtime     map[string]any                    `json:"runtime,omitempty"`
+	Env         map[string]workspace.EnvValue     `json:"env,omitempty"`
+	EnvSchema   map[string]catalog.TemplateEnvVar `json:"envSchema,omitempty"`
+	Ports       []workspace.Port                  `json:"ports,omitempty"`
+	Volumes     []workspace.Volume                `json:"volumes,omitempty"`
+	Imports     []workspace.Import                `json:"imports,omitempty"`
+	Exports     []workspace.Export                `json:"exports,omitempty"`
+	Health      *workspace.Health                 `json:"health,omitempty"`
+	Develop     map[string]any                    `json:"develop,omitempty"`
+	Notes       string                            `json:"notes,omitempty"`
 }
 
 // WorkspaceSummary is the locked list shape for /api/workspaces.
@@ -52,6 +64,10 @@ type WorkspaceSummary struct {
 	Provider      string                         `json:"provider,omitempty"`
 	Capabilities  runtimepkg.AdapterCapabilities `json:"capabilities,omitempty"`
 	ResourceCount int                            `json:"resourceCount"`
+	Owner         string                         `json:"owner,omitempty"`
+	Team          string                         `json:"team,omitempty"`
+	Contact       string                         `json:"contact,omitempty"`
+	Expires       string                         `json:"expires,omitempty"`
 }
 
 // WorkspaceDetail is the locked detail shape for /api/workspaces/{name}.
@@ -62,7 +78,12 @@ type WorkspaceDetail struct {
 	Provider      string                         `json:"provider,omitempty"`
 	Capabilities  runtimepkg.AdapterCapabilities `json:"capabilities,omitempty"`
 	ResourceCount int                            `json:"resourceCount"`
+	Owner         string                         `json:"owner,omitempty"`
+	Team          string                         `json:"team,omitempty"`
+	Contact       string                         `json:"contact,omitempty"`
+	Expires       string                         `json:"expires,omitempty"`
 	ManifestPath  string                         `json:"manifestPath"`
+	Notes         string                         `json:"notes,omitempty"`
 	ResourceKeys  []string                       `json:"resourceKeys,omitempty"`
 }
 
@@ -80,10 +101,828 @@ type WorkspaceStatusView struct {
 	Snapshot *runtimepkg.Snapshot         `json:"snapshot,omitempty"`
 }
 
+// WorkspaceStatsView reports per-resource usage samples for running resources
+// plus a workspace-level rollup.
+type WorkspaceStatsView struct {
+	Workspace string                     `json:"workspace"`
+	Provider  string                     `json:"provider"`
+	Resources []runtimepkg.ResourceStats `json:"resources"`
+	Total     WorkspaceStatsTotal        `json:"total"`
+}
+
+type WorkspaceStatsTotal struct {
+	CPUPercent  float64 `json:"cpuPercent"`
+	MemoryBytes uint64  `json:"memoryBytes"`
+	PIDs        int     `json:"pids,omitempty"`
+}
+
+// ResourceInspectView is the sanitized inspect shape for one resource. Env
+// values backed by secrets or with sensitive-looking keys are masked.
+type ResourceInspectView struct {
+	Workspace string                       `json:"workspace"`
+	Provider  string                       `json:"provider"`
+	Resource  *runtimepkg.SnapshotResource `json:"resource"`
+}
+
+// WorkspaceRenderView is the canonical apply payload for a workspace plus its
+// content digest. It is rendered from manifests and templates only, so the
+// digest does not depend on which runtime is installed.
+type WorkspaceRenderView struct {
+	Workspace string         `json:"workspace"`
+	Digest    string         `json:"digest"`
+	Payload   *apply.Payload `json:"payload"`
+}
+
+// RollingRestartRequest tunes a one-at-a-time workspace restart. Timeout is
+// passed to each resource restart; ReadyTimeout bounds the wait for a
+// restarted resource to report running (and healthy when it declares a check).
+type RollingRestartRequest struct {
+	Timeout      *int
+	ReadyTimeout time.Duration
+}
+
+// RollingRestartView records the restart order and outcome per resource.
+type RollingRestartView struct {
+	Workspace string               `json:"workspace"`
+	Provider  string               `json:"provider"`
+	Steps     []RollingRestartStep `json:"steps"`
+}
+
+type RollingRestartStep struct {
+	Key         string `json:"key"`
+	RuntimeName string `json:"runtimeName"`
+	Status      string `json:"status"`
+	Health      string `json:"health,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// WorkspaceLifecycleView records a whole-workspace stop or start in the order
+// resources were visited.
+type WorkspaceLifecycleView struct {
+	Workspace string          `json:"workspace"`
+	Provider  string          `json:"provider"`
+	Action    string          `json:"action"`
+	Steps     []LifecycleStep `json:"steps"`
+}
+
+type LifecycleStep struct {
+	Key         string `json:"key"`
+	RuntimeName string `json:"runtimeName"`
+	Status      string `json:"status"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// ScheduleView lists the stop and start schedules of every workspace. Next
+// is unset for disabled schedules.
+type ScheduleView struct {
+	GeneratedAt time.Time         `json:"generatedAt"`
+	Schedules   []ScheduledAction `json:"schedules"`
+}
+
+type ScheduledAction struct {
+	Workspace string     `json:"workspace"`
+	Action    string     `json:"action"`
+	At        string     `json:"at"`
+	Days      []string   `json:"days"`
+	Enabled   bool       `json:"enabled"`
+	Next      *time.Time `json:"next,omitempty"`
+}
+
+// ScheduleRunRequest sets how far back RunSchedules looks for due
+// schedules; zero means 15 minutes.
+type ScheduleRunRequest struct {
+	Window time.Duration
+}
+
+// ScheduleRunView reports the schedules RunSchedules found due and what
+// running each did.
+type ScheduleRunView struct {
+	RanAt time.Time     `json:"ranAt"`
+	Runs  []ScheduleRun `json:"runs"`
+}
+
+type ScheduleRun struct {
+	Workspace string                  `json:"workspace"`
+	Action    string                  `json:"action"`
+	Due       time.Time               `json:"due"`
+	Error     string                  `json:"error,omitempty"`
+	Result    *WorkspaceLifecycleView `json:"result,omitempty"`
+}
+
+// IdleRequest tunes IdleWorkspace. Zero values fall back to the defaults: 2%
+// total CPU, 64 KiB of network traffic, and three samples 20s apart.
+type IdleRequest struct {
+	CPUPercent   float64
+	NetworkBytes uint64
+	Samples      int
+	Interval     time.Duration
+	Stop         bool
+	StopTimeout  *int
+}
+
+// WorkspaceIdleView summarizes the sampled activity of a workspace and, when
+// it was stopped for being idle, the stop steps.
+type WorkspaceIdleView struct {
+	Workspace     string                  `json:"workspace"`
+	Provider      string                  `json:"provider"`
+	Idle          bool                    `json:"idle"`
+	Running       int                     `json:"running"`
+	Samples       int                     `json:"samples"`
+	MaxCPUPercent float64                 `json:"maxCpuPercent"`
+	NetworkBytes  uint64                  `json:"networkBytes"`
+	Stopped       *WorkspaceLifecycleView `json:"stopped,omitempty"`
+}
+
+// WorkspaceTopologyView is a graph of a workspace for layout: one node per
+// resource plus one per workspace network, and edges for dependencies,
+// volumes mounted by more than one resource, and network membership.
+type WorkspaceTopologyView struct {
+	Workspace   string                  `json:"workspace"`
+	Provider    string                  `json:"provider"`
+	Nodes       []TopologyNode          `json:"nodes"`
+	Edges       []TopologyEdge          `json:"edges"`
+	Diagnostics []runtimepkg.Diagnostic `json:"diagnostics,omitempty"`
+}
+
+type TopologyNode struct {
+	ID          string                `json:"id"`
+	Kind        string                `json:"kind"`
+	Label       string                `json:"label"`
+	RuntimeName string                `json:"runtimeName,omitempty"`
+	Image       string                `json:"image,omitempty"`
+	Ports       []runtimepkg.PortSpec `json:"ports,omitempty"`
+	Status      string                `json:"status,omitempty"`
+	Health      string                `json:"health,omitempty"`
+	Enabled     bool                  `json:"enabled"`
+}
+
+// TopologyEdge kinds are "dependsOn" (source depends on target), "volume"
+// (both mount Label), and "network" (source is attached to the network node).
+type TopologyEdge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Kind   string `json:"kind"`
+	Label  string `json:"label,omitempty"`
+}
+
+// ActivityRequest pages through a workspace activity feed. Entries are
+// returned newest first; Before excludes entries at or after that time and
+// Limit caps the page (default 50).
+type ActivityRequest struct {
+	Limit  int
+	Before *time.Time
+	// Actor keeps only the entries of that principal. Container events
+	// have no actor and are left out.
+	Actor string
+}
+
+// WorkspaceActivityView is one page of a workspace activity feed. NextBefore
+// is set when older entries remain and is the Before value for the next page.
+type WorkspaceActivityView struct {
+	Workspace   string                  `json:"workspace"`
+	Entries     []ActivityEntry         `json:"entries"`
+	NextBefore  *time.Time              `json:"nextBefore,omitempty"`
+	Diagnostics []runtimepkg.Diagnostic `json:"diagnostics,omitempty"`
+}
+
+// ActivityEntry kinds are "apply" (one recorded apply run) and "container"
+// (a resource container started or exited).
+type ActivityEntry struct {
+	Time       time.Time                  `json:"time"`
+	Kind       string                     `json:"kind"`
+	Actor      string                     `json:"actor,omitempty"`
+	Resource   string                     `json:"resource,omitempty"`
+	Status     string                     `json:"status"`
+	Message    string                     `json:"message,omitempty"`
+	Operations []cachepkg.OperationRecord `json:"operations,omitempty"`
+}
+
+// NotesView carries the markdown notes for a workspace, or for one resource
+// together with the notes of the template it is built from.
+type NotesView struct {
+	Workspace     string `json:"workspace"`
+	Resource      string `json:"resource,omitempty"`
+	Template      string `json:"template,omitempty"`
+	Notes         string `json:"notes"`
+	TemplateNotes string `json:"templateNotes,omitempty"`
+}
+
+// ExpiryRequest tunes WorkspaceExpiry. WarnDays (default 7) is how far ahead
+// an expiry counts as expiring; Stop stops expired workspaces.
+type ExpiryRequest struct {
+	WarnDays    int
+	Stop        bool
+	StopTimeout *int
+}
+
+// WorkspaceExpiryView reports one workspace that is expired or expiring.
+// DaysLeft is negative once the expiry date has passed.
+type WorkspaceExpiryView struct {
+	Name     string                  `json:"name"`
+	Owner    string                  `json:"owner,omitempty"`
+	Contact  string                  `json:"contact,omitempty"`
+	Expires  string                  `json:"expires"`
+	DaysLeft int                     `json:"daysLeft"`
+	State    string                  `json:"state"`
+	Stopped  *WorkspaceLifecycleView `json:"stopped,omitempty"`
+	Error    string                  `json:"error,omitempty"`
+}
+
+// ExportRequest selects where ExportResource writes, which compose
+// implementation (docker, podman, podman-compose, or nerdctl) it targets,
+// which redaction profile (secrets or share) it applies, and whether host
+// paths are rewritten relative to ${PROJECT_ROOT} and ${HOME}.
+type ExportRequest struct {
+	Target        string
+	Compose       string
+	Redact        string
+	PortablePaths bool
+}
+
+// ResourceExportView lists the files written for a standalone compose export
+// of one resource, relative to Target (a directory or a .zip archive).
+// Warnings are the backend quirks the export could not adapt away.
+type ResourceExportView struct {
+	Workspace string   `json:"workspace"`
+	Resource  string   `json:"resource"`
+	Target    string   `json:"target"`
+	Compose   string   `json:"compose"`
+	Redact    string   `json:"redact"`
+	Files     []string `json:"files"`
+	Warnings  []string `json:"warnings,omitempty"`
+	// Redactions lists what the profile removed or replaced; the bundle's
+	// README lists the same.
+	Redactions []ExportRedaction `json:"redactions,omitempty"`
+}
+
+// ExportRedaction is one value an export removed or replaced. Field is the
+// env var, compose field, or file part it was in; Note says what to fill in.
+type ExportRedaction struct {
+	Kind  string `json:"kind"`
+	File  string `json:"file"`
+	Field string `json:"field"`
+	Note  string `json:"note"`
+}
+
+// BackupRequest selects where backups go and how many are kept.
+type BackupRequest struct {
+	Directory string
+	Keep      int
+}
+
+// BackupRoot is one workspace or catalog root in a backup, stored under
+// Directory in the archive.
+type BackupRoot struct {
+	Kind      string `json:"kind"`
+	Path      string `json:"path"`
+	Directory string `json:"directory"`
+}
+
+// BackupView reports a backup written or restored at Path. Removed lists
+// older backups dropped by rotation.
+type BackupView struct {
+	Path      string       `json:"path"`
+	CreatedAt time.Time    `json:"createdAt"`
+	Roots     []BackupRoot `json:"roots"`
+	Files     int          `json:"files"`
+	Removed   []string     `json:"removed,omitempty"`
+}
+
+// BackupInfo is one backup archive found in a backup directory.
+type BackupInfo struct {
+	Path      string    `json:"path"`
+	CreatedAt time.Time `json:"createdAt"`
+	Size      int64     `json:"size"`
+}
+
+// AdoptRequest tunes AdoptContainer. Key defaults to the container name and
+// Template to the catalog template whose image matches the container's.
+type AdoptRequest struct {
+	Key      string
+	Template string
+	Write    bool
+}
+
+// AdoptView is an unmanaged container described as a workspace resource.
+// YAML is the resources entry; Written reports whether it was added to the
+// manifest at ManifestPath.
+type AdoptView struct {
+	Workspace    string   `json:"workspace"`
+	Container    string   `json:"container"`
+	Resource     string   `json:"resource"`
+	Template     string   `json:"template"`
+	RuntimeName  string   `json:"runtimeName"`
+	YAML         string   `json:"yaml"`
+	Written      bool     `json:"written"`
+	ManifestPath string   `json:"manifestPath,omitempty"`
+	Warnings     []string `json:"warnings,omitempty"`
+}
+
+// LaravelWorkersRequest controls LaravelWorkers; Write appends the workers
+// to the workspace manifest.
+type LaravelWorkersRequest struct {
+	Write bool
+}
+
+// LaravelWorkersView is the queue worker and scheduler described for a
+// Laravel resource. YAML is the resources entries; Written reports whether
+// they were added to the manifest at ManifestPath.
+type LaravelWorkersView struct {
+	Workspace    string   `json:"workspace"`
+	Resource     string   `json:"resource"`
+	Added        []string `json:"added"`
+	YAML         string   `json:"yaml"`
+	Written      bool     `json:"written"`
+	ManifestPath string   `json:"manifestPath,omitempty"`
+}
+
+// DatabaseInspectionView is what InspectDatabase read from a database
+// resource. SizeBytes is the total of the database sizes, or redis used
+// memory; Tables is empty for redis.
+type DatabaseInspectionView struct {
+	Workspace   string          `json:"workspace"`
+	Resource    string          `json:"resource"`
+	Engine      string          `json:"engine"`
+	SizeBytes   int64           `json:"sizeBytes"`
+	Connections int64           `json:"connections"`
+	Databases   []DatabaseInfo  `json:"databases"`
+	Tables      []DatabaseTable `json:"tables"`
+}
+
+// DatabaseInfo is one database, or one redis keyspace with its key count.
+type DatabaseInfo struct {
+	Name      string `json:"name"`
+	SizeBytes int64  `json:"sizeBytes,omitempty"`
+	Keys      int64  `json:"keys,omitempty"`
+}
+
+// DatabaseTable is one user table. Rows is the engine's estimate.
+type DatabaseTable struct {
+	Schema    string `json:"schema"`
+	Name      string `json:"name"`
+	SizeBytes int64  `json:"sizeBytes"`
+	Rows      int64  `json:"rows"`
+}
+
+// DatabaseQueryRequest is one ad-hoc query. Limit caps the returned rows;
+// zero means 500.
+type DatabaseQueryRequest struct {
+	Query string `json:"query"`
+	Limit int    `json:"limit,omitempty"`
+}
+
+// DatabaseQueryView is the result of QueryDatabase. Rows map column names
+// to values as the client printed them; Columns keeps their order.
+// Truncated reports that more rows than the limit came back.
+type DatabaseQueryView struct {
+	Workspace  string              `json:"workspace"`
+	Resource   string              `json:"resource"`
+	Engine     string              `json:"engine"`
+	Query      string              `json:"query"`
+	StartedAt  time.Time           `json:"startedAt"`
+	DurationMs int64               `json:"durationMs"`
+	Columns    []string            `json:"columns"`
+	Rows       []map[string]string `json:"rows"`
+	Truncated  bool                `json:"truncated,omitempty"`
+}
+
+// QueryHistoryView is the query audit trail of a workspace, newest first.
+type QueryHistoryView struct {
+	Workspace string                 `json:"workspace"`
+	Queries   []cachepkg.QueryRecord `json:"queries"`
+}
+
+// ConnectionView is how to reach a resource from the host and from the
+// other resources of its workspace network. Notes explain missing host
+// entries and credentials that come from secrets.
+type ConnectionView struct {
+	Workspace string            `json:"workspace"`
+	Resource  string            `json:"resource"`
+	Engine    string            `json:"engine,omitempty"`
+	Host      []ConnectionEntry `json:"host"`
+	Network   []ConnectionEntry `json:"network"`
+	Notes     []string          `json:"notes,omitempty"`
+}
+
+// ConnectionEntry is one DSN, client command, or base URL for the
+// resource's container port Port.
+type ConnectionEntry struct {
+	Kind  string `json:"kind"`
+	Port  int    `json:"port"`
+	Value string `json:"value"`
+}
+
+// URLCatalogRequest selects the URL catalog. All includes resources that
+// are not running.
+type URLCatalogRequest struct {
+	All bool `json:"all,omitempty"`
+}
+
+// URLCatalogView is every browsable URL across the workspaces, sorted by
+// workspace, resource, and port.
+type URLCatalogView struct {
+	Entries []URLEntry `json:"entries"`
+	Errors  []string   `json:"errors,omitempty"`
+}
+
+// URLEntry is one published port of a resource. Port is the container port;
+// Label names the resource and, when it differs, its template.
+type URLEntry struct {
+	Workspace   string `json:"workspace"`
+	DisplayName string `json:"displayName,omitempty"`
+	Resource    string `json:"resource"`
+	Label       string `json:"label"`
+	Port        int    `json:"port"`
+	URL         string `json:"url"`
+	Status      string `json:"status"`
+	Health      string `json:"health,omitempty"`
+}
+
+// BadgeView is a status badge for a workspace, or one of its resources when
+// Resource is set. SVG is the rendered badge.
+type BadgeView struct {
+	Workspace string `json:"workspace"`
+	Resource  string `json:"resource,omitempty"`
+	Label     string `json:"label"`
+	Message   string `json:"message"`
+	Color     string `json:"color"`
+	SVG       string `json:"svg"`
+}
+
+// StatusPageRequest selects the status page window. Days defaults to 14.
+type StatusPageRequest struct {
+	Days int `json:"days,omitempty"`
+}
+
+// StatusPageView is the public status of every discovered workspace.
+type StatusPageView struct {
+	GeneratedAt time.Time             `json:"generatedAt"`
+	Workspaces  []StatusPageWorkspace `json:"workspaces"`
+}
+
+// StatusPageWorkspace is one workspace on the status page. Upcoming lists
+// the maintenance windows starting within the requested days.
+type StatusPageWorkspace struct {
+	Name          string             `json:"name"`
+	DisplayName   string             `json:"displayName,omitempty"`
+	Owner         string             `json:"owner,omitempty"`
+	Contact       string             `json:"contact,omitempty"`
+	Expires       string             `json:"expires,omitempty"`
+	Expired       bool               `json:"expired"`
+	InMaintenance bool               `json:"inMaintenance"`
+	Upcoming      []MaintenanceEvent `json:"upcoming"`
+}
+
+// MaintenanceEvent is one occurrence of a maintenance window.
+type MaintenanceEvent struct {
+	Summary string    `json:"summary"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+}
+
+// ValidationRequest tunes ValidateWorkspaces. Recent (default 5) caps the
+// RecentlyBroken list.
+type ValidationRequest struct {
+	Recent int
+}
+
+// ValidationSummaryView is the result of re-validating every workspace
+// manifest: a count per status (valid, warning, blocked, or invalid), every
+// manifest checked, and the broken ones most recently edited.
+type ValidationSummaryView struct {
+	CheckedAt      time.Time             `json:"checkedAt"`
+	Counts         map[string]int        `json:"counts"`
+	Workspaces     []WorkspaceValidation `json:"workspaces"`
+	RecentlyBroken []WorkspaceValidation `json:"recentlyBroken"`
+}
+
+// WorkspaceValidation reports one manifest. Workspace is empty when the
+// manifest could not be loaded.
+type WorkspaceValidation struct {
+	Workspace  string    `json:"workspace,omitempty"`
+	Path       string    `json:"path"`
+	Status     string    `json:"status"`
+	Problems   []string  `json:"problems,omitempty"`
+	ModifiedAt time.Time `json:"modifiedAt"`
+}
+
+// HealthSuggestionRequest controls HealthSuggestions; Apply names the
+// suggestion to write into the workspace manifest.
+type HealthSuggestionRequest struct {
+	Apply string
+}
+
+// HealthSuggestionsView lists the health checks suggested for one resource.
+// Current is the check the resource runs today, from its template or its
+// manifest entry. Applied names the suggestion written to ManifestPath.
+type HealthSuggestionsView struct {
+	Workspace    string             `json:"workspace"`
+	Resource     string             `json:"resource"`
+	Image        string             `json:"image,omitempty"`
+	Family       string             `json:"family,omitempty"`
+	Current      *workspace.Health  `json:"current,omitempty"`
+	Suggestions  []HealthSuggestion `json:"suggestions"`
+	Applied      string             `json:"applied,omitempty"`
+	ManifestPath string             `json:"manifestPath,omitempty"`
+}
+
+// HealthSuggestion is one ready-made health check, in manifest form.
+type HealthSuggestion struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Health      workspace.Health `json:"health"`
+}
+
+// NameConflictsView lists runtime names the workspace wants that are held by
+// containers or networks it does not manage.
+type NameConflictsView struct {
+	Workspace string                    `json:"workspace"`
+	Provider  string                    `json:"provider,omitempty"`
+	Conflicts []runtimepkg.NameConflict `json:"conflicts"`
+}
+
+// ApplyRequest tunes one apply. A positive HealthTimeout waits up to that
+// long for started resources to become healthy and records how long it took.
+// Parallelism above one applies independent resources concurrently, up to
+// that many at a time. ChangedOnly skips restarts of unchanged resources.
+// IgnoreCapacity skips the check that the host has the memory the new
+// containers are limited to.
+type ApplyRequest struct {
+	HealthTimeout  time.Duration
+	Parallelism    int
+	ChangedOnly    bool
+	IgnoreCapacity bool
+}
+
+// LoadTestRequest selects the k6 script to run. Port is the container port
+// the script targets; zero uses the resource's first port.
+type LoadTestRequest struct {
+	Script         string
+	Port           int
+	SampleInterval time.Duration
+}
+
+// LoadTestReport combines k6's request metrics with the target resource's
+// usage sampled during the run. Summary is nil when k6 wrote no summary,
+// Usage when the runtime reports no stats.
+type LoadTestReport struct {
+	Workspace        string           `json:"workspace"`
+	Resource         string           `json:"resource"`
+	Target           string           `json:"target"`
+	Script           string           `json:"script"`
+	StartedAt        time.Time        `json:"startedAt"`
+	FinishedAt       time.Time        `json:"finishedAt"`
+	ExitCode         int              `json:"exitCode"`
+	ThresholdsFailed bool             `json:"thresholdsFailed,omitempty"`
+	Summary          *LoadTestSummary `json:"summary,omitempty"`
+	Usage            *LoadTestUsage   `json:"usage,omitempty"`
+	Output           string           `json:"output,omitempty"`
+}
+
+type LoadTestSummary struct {
+	Requests      int     `json:"requests"`
+	RequestsPerS  float64 `json:"requestsPerSecond"`
+	FailedRatio   float64 `json:"failedRatio"`
+	DurationAvgMs float64 `json:"durationAvgMs"`
+	DurationP95Ms float64 `json:"durationP95Ms"`
+	DurationMaxMs float64 `json:"durationMaxMs"`
+}
+
+type LoadTestUsage struct {
+	Samples         int     `json:"samples"`
+	CPUAvgPercent   float64 `json:"cpuAvgPercent"`
+	CPUPeakPercent  float64 `json:"cpuPeakPercent"`
+	MemoryPeakBytes uint64  `json:"memoryPeakBytes"`
+}
+
+// ChaosView records one chaos action against a resource. For network faults
+// FinishedAt is when the fault was lifted again.
+type ChaosView struct {
+	Workspace   string    `json:"workspace"`
+	Resource    string    `json:"resource"`
+	RuntimeName string    `json:"runtimeName"`
+	Action      string    `json:"action"`
+	Detail      string    `json:"detail,omitempty"`
+	StartedAt   time.Time `json:"startedAt"`
+	FinishedAt  time.Time `json:"finishedAt"`
+}
+
+// InspectorRequestsView lists the HTTP exchanges recorded by a resource's
+// inspector proxy.
+type InspectorRequestsView struct {
+	Workspace string             `json:"workspace"`
+	Resource  string             `json:"resource"`
+	Inspector string             `json:"inspector"`
+	Requests  []InspectedRequest `json:"requests"`
+}
+
+// InspectedRequest is one proxied request. Response holds the reason phrase
+// and body size as mitmdump printed them; Error is set when the proxy could
+// not get a response.
+type InspectedRequest struct {
+	Time     *time.Time `json:"time,omitempty"`
+	Client   string     `json:"client"`
+	Method   string     `json:"method"`
+	URL      string     `json:"url"`
+	Status   int        `json:"status,omitempty"`
+	Response string     `json:"response,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// CaptureRequest bounds a traffic capture. Duration defaults to 30 seconds;
+// MaxPackets of zero means no packet limit. Filter is a tcpdump expression.
+type CaptureRequest struct {
+	Duration   time.Duration
+	MaxPackets int
+	Filter     string
+	Output     string
+}
+
+// CaptureView reports where a finished traffic capture was written.
+type CaptureView struct {
+	Workspace string `json:"workspace"`
+	Resource  string `json:"resource"`
+	Provider  string `json:"provider"`
+	Path      string `json:"path"`
+	Bytes     int64  `json:"bytes"`
+	Duration  string `json:"duration"`
+	Filter    string `json:"filter,omitempty"`
+}
+
+// PortForwardView lists the ssh local forwards for a workspace on a remote
+// host and the command that opens them. Command is empty when nothing can be
+// forwarded.
+type PortForwardView struct {
+	Workspace string        `json:"workspace"`
+	Host      string        `json:"host"`
+	Forwards  []PortForward `json:"forwards"`
+	Skipped   []string      `json:"skipped,omitempty"`
+	Command   []string      `json:"command,omitempty"`
+}
+
+type PortForward struct {
+	Resource   string `json:"resource"`
+	Local      int    `json:"local"`
+	RemoteHost string `json:"remoteHost"`
+	Remote     int    `json:"remote"`
+}
+
+// WorkspaceBriefView is the compact workspace status polled by editor
+// integrations. When NotModified is set only Workspace and ETag are filled.
+type WorkspaceBriefView struct {
+	Workspace   string          `json:"workspace"`
+	Provider    string          `json:"provider,omitempty"`
+	State       string          `json:"state,omitempty"`
+	Resources   []BriefResource `json:"resources,omitempty"`
+	Errors      []string        `json:"errors,omitempty"`
+	ETag        string          `json:"etag,omitempty"`
+	NotModified bool            `json:"notModified,omitempty"`
+}
+
+type BriefResource struct {
+	Key    string   `json:"key"`
+	Status string   `json:"status"`
+	Health string   `json:"health,omitempty"`
+	Ports  []int    `json:"ports,omitempty"`
+	URLs   []string `json:"urls,omitempty"`
+}
+
+// DevcontainerView is a generated dev container setup for a scanned project.
+// Files are relative to the project root. Hosts lists the workspace resource
+// hosts reachable from the container when it joins the workspace network.
+type DevcontainerView struct {
+	Project     string          `json:"project"`
+	Workspace   string          `json:"workspace,omitempty"`
+	Network     string          `json:"network,omitempty"`
+	Hosts       []string        `json:"hosts,omitempty"`
+	Files       []GeneratedFile `json:"files"`
+	Written     bool            `json:"written,omitempty"`
+	Diagnostics []string        `json:"diagnostics,omitempty"`
+}
+
+// ProjectScriptRequest names a project script to run. Args are appended to
+// the script's command. With Workspace set, the script runs on that
+// workspace's runtime and network so it can reach its resources.
+type ProjectScriptRequest struct {
+	Path      string
+	Script    string
+	Args      []string
+	Workspace string
+}
+
+// ProjectScriptRunView is the outcome of one project script run. Output
+// holds the combined stdout and stderr of the container.
+type ProjectScriptRunView struct {
+	Project    string    `json:"project"`
+	Path       string    `json:"path"`
+	Script     string    `json:"script"`
+	Source     string    `json:"source"`
+	Command    []string  `json:"command"`
+	Image      string    `json:"image"`
+	Workspace  string    `json:"workspace,omitempty"`
+	StartedAt  time.Time `json:"startedAt"`
+	DurationMs int64     `json:"durationMs"`
+	ExitCode   int       `json:"exitCode"`
+	Output     string    `json:"output,omitempty"`
+}
+
+// ProjectScriptHistoryView lists recorded script runs for a project, newest
+// first.
+type ProjectScriptHistoryView struct {
+	Path string                     `json:"path"`
+	Runs []cachepkg.ScriptRunRecord `json:"runs"`
+}
+
+// ProjectSuggestionRequest controls ProjectSuggestions. Name is the
+// workspace name; it defaults to the project name with a "-local" suffix.
+type ProjectSuggestionRequest struct {
+	Name  string
+	Write bool
+}
+
+// ProjectSuggestionView is the stack recommended for a scanned project.
+// Missing lists roles the catalog has no template for, and Manifest is the
+// suggested resources rendered as a workspace.
+type ProjectSuggestionView struct {
+	Project   string              `json:"project"`
+	Framework string              `json:"framework,omitempty"`
+	Recipe    string              `json:"recipe,omitempty"`
+	Resources []SuggestedResource `json:"resources"`
+	Missing   []string            `json:"missing,omitempty"`
+	Workspace string              `json:"workspace,omitempty"`
+	Manifest  string              `json:"manifest,omitempty"`
+	Written   string              `json:"written,omitempty"`
+}
+
+// SuggestedResource is one resource of a suggested stack. ImageTag replaces
+// the template's image tag to match the project's Node.js version. Note
+// explains a fallback to a template other than the preferred one, or the
+// image tag choice.
+type SuggestedResource struct {
+	Key       string   `json:"key"`
+	Role      string   `json:"role"`
+	Template  string   `json:"template"`
+	ImageTag  string   `json:"imageTag,omitempty"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+	Note      string   `json:"note,omitempty"`
+}
+
+type GeneratedFile struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// ImageBundleView reports the images written to or read from an image archive.
+type ImageBundleView struct {
+	Workspace string   `json:"workspace"`
+	Provider  string   `json:"provider"`
+	Archive   string   `json:"archive"`
+	Images    []string `json:"images"`
+}
+
+// ImageUpdateRequest selects whether UpdateWorkspaceImages recreates
+// outdated resources, and whether it may do so outside a maintenance window.
+type ImageUpdateRequest struct {
+	Apply        bool
+	IgnoreWindow bool
+}
+
+// ImageUpdateView reports the managed images of a workspace and, when the
+// update was applied, the apply result. Skipped explains why outdated
+// resources were left running.
+type ImageUpdateView struct {
+	Workspace     string                `json:"workspace"`
+	Provider      string                `json:"provider"`
+	InMaintenance bool                  `json:"inMaintenance"`
+	Resources     []ImageUpdateResource `json:"resources"`
+	Skipped       string                `json:"skipped,omitempty"`
+	Apply         *apply.Result         `json:"apply,omitempty"`
+}
+
+// ImageUpdateResource compares the image a managed resource runs with the
+// newest pull of its image reference.
+type ImageUpdateResource struct {
+	Resource  string `json:"resource"`
+	Image     string `json:"image"`
+	CurrentID string `json:"currentId,omitempty"`
+	LatestID  string `json:"latestId"`
+	Outdated  bool   `json:"outdated"`
+}
+
 // ProjectScanView is the transport-safe project scan result returned by the
 // shared service boundary.
 type ProjectScanView = projectscan.Result
 
+// ProjectExtension is a WordPress plugin or theme found by a project scan.
+type ProjectExtension = projectscan.Extension
+
+// ProjectInventoryView is the result of scanning every project below a
+// directory, with the ignore and include rules that were applied.
+type ProjectInventoryView = projectscan.Inventory
+
+// ProjectScanRequest is the include/exclude/depth tuning for ScanProjects.
+type ProjectScanRequest = projectscan.ScanOptions
+
 // NotFoundError reports a typed missing service object.
 type NotFoundError struct {
 	Kind      string
@@ -116,6 +955,55 @@ func (e *DuplicateWorkspaceNameError) Error() string {
 	return fmt.Sprintf("duplicate workspace name %q in %s and %s", e.Name, e.FirstPath, e.SecondPath)
 }
 
+// RuntimeNameConflictError reports runtime names a workspace wants that are
+// held by containers or networks it does not manage.
+type RuntimeNameConflictError struct {
+	Workspace string
+	Conflicts []runtimepkg.NameConflict
+}
+
+func (e *RuntimeNameConflictError) Error() string {
+	if e == nil || len(e.Conflicts) == 0 {
+		return "runtime name conflict"
+	}
+	first := e.Conflicts[0]
+	message := fmt.Sprintf("workspace %q: %s %q is owned by %s", e.Workspace, first.Kind, first.Name, first.Owner)
+	if len(e.Conflicts) > 1 {
+		message += fmt.Sprintf(" (and %d more)", len(e.Conflicts)-1)
+	}
+	return message + "; remove or rename it before applying"
+}
+
+// ReadOnlyError rejects an operation that would change runtime state or write
+// files while the service runs read-only.
+type ReadOnlyError struct {
+	Operation string `json:"operation"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+func (e *ReadOnlyError) Error() string {
+	if e == nil {
+		return "devarch is read-only"
+	}
+	message := fmt.Sprintf("%s rejected: devarch is read-only", e.Operation)
+	if e.Reason != "" {
+		message += ": " + e.Reason
+	}
+	return message
+}
+
+// InsufficientMemoryError rejects an apply whose new containers are limited
+// to more memory, in total, than the host has available.
+type InsufficientMemoryError struct {
+	Workspace      string `json:"workspace"`
+	RequiredBytes  uint64 `json:"requiredBytes"`
+	AvailableBytes uint64 `json:"availableBytes"`
+}
+
+func (e *InsufficientMemoryError) Error() string {
+	return fmt.Sprintf("workspace %q: new resources are limited to %.1f GiB of memory, but only %.1f GiB is available", e.Workspace, float64(e.RequiredBytes)/(1<<30), float64(e.AvailableBytes)/(1<<30))
+}
+
 // UnsupportedCapabilityError reports an operation gated by the selected runtime
 // capability surface.
 type UnsupportedCapabilityError struct {
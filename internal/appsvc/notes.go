@@ -0,0 +1,42 @@
+package appsvc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/prospect-ogujiuba/devarch/internal/catalog"
+)
+
+// WorkspaceNotes returns the markdown notes of a workspace or, when resource
+// is set, of that resource plus its template. Notes live in the manifests and
+// templates, so their revision history is the history of those files.
+func (s *Service) WorkspaceNotes(_ context.Context, name, resource string) (*NotesView, error) {
+	ws, err := s.loadWorkspace(name)
+	if err != nil {
+		return nil, err
+	}
+	resource = strings.TrimSpace(resource)
+	if resource == "" {
+		return &NotesView{Workspace: ws.Metadata.Name, Notes: ws.Metadata.Notes}, nil
+	}
+	item, ok := ws.Resources[resource]
+	if !ok || item == nil {
+		return nil, &NotFoundError{Kind: "resource", Name: resource, Workspace: name}
+	}
+	view := &NotesView{Workspace: ws.Metadata.Name, Resource: resource, Template: item.Template, Notes: item.Notes}
+	if item.Template == "" {
+		return view, nil
+	}
+	paths, err := catalog.DiscoverTemplateFiles(ws.ResolvedCatalogSources())
+	if err != nil {
+		return nil, err
+	}
+	index, err := catalog.LoadIndex(paths)
+	if err != nil {
+		return nil, err
+	}
+	if template, ok := index.ByName(item.Template); ok {
+		view.TemplateNotes = template.Metadata.Notes
+	}
+	return view, nil
+}
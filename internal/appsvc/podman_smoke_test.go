@@ -69,7 +69,7 @@ func TestPodmanSmoke(t *testing.T) {
 	}
 	t.Logf("podman smoke plan actions: %#v", plan.Actions)
 
-	result, err := service.ApplyWorkspace(ctx, "podman-smoke")
+	result, err := service.ApplyWorkspace(ctx, "podman-smoke", ApplyRequest{})
 	if err != nil {
 		t.Fatalf("ApplyWorkspace returned error: %v", err)
 	}
@@ -0,0 +1,192 @@
+package appsvc
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	cachepkg "github.com/prospect-ogujiuba/devarch/internal/cache"
+)
+
+const (
+	defaultQueryLimit = 500
+	queryHistoryLimit = 50
+)
+
+// queryClients run one statement read-only: postgres through a session
+// whose transactions default to read only, mysql inside a read-only
+// transaction that is rolled back. Both print a header row.
+var queryClients = map[string]string{
+	"postgres": `PGOPTIONS="-c default_transaction_read_only=on -c statement_timeout=30000" psql -X -q -A -F "$(printf '\t')" -P footer=off -v ON_ERROR_STOP=1 -U "${POSTGRES_USER:-postgres}" -d "${POSTGRES_DB:-${POSTGRES_USER:-postgres}}" -c "$1"`,
+	"mysql":    `MYSQL_PWD="${MYSQL_ROOT_PASSWORD:-$MARIADB_ROOT_PASSWORD}" "$(command -v mysql || command -v mariadb)" -uroot -B -e "START TRANSACTION READ ONLY; $1; ROLLBACK"`,
+}
+
+// queryStatements are the statements a query may start with.
+var queryStatements = []string{"select", "with", "show", "explain", "describe", "desc", "values", "table"}
+
+// queryFileWrite matches the mysql forms that write files on the server,
+// which a read-only transaction does not stop.
+var queryFileWrite = regexp.MustCompile(`(?i)\binto\s+(outfile|dumpfile)\b`)
+
+// QueryDatabase runs one read-only SQL statement against a postgres or
+// mysql resource with the engine's client inside its container, and
+// returns at most request.Limit rows. The statement must start with one of
+// queryStatements, and the engine runs it read only as well. When the
+// workspace sets policies.queryResources, only those resources may be
+// queried. Every attempt, refused or not, is recorded in the cache store.
+func (s *Service) QueryDatabase(ctx context.Context, name, resource string, request DatabaseQueryRequest) (*DatabaseQueryView, error) {
+	if request.Limit < 0 {
+		return nil, fmt.Errorf("query limit must not be negative")
+	}
+	if request.Limit == 0 {
+		request.Limit = defaultQueryLimit
+	}
+	state, item, engine, err := s.databaseResource(name, resource, "query")
+	if err != nil {
+		return nil, err
+	}
+	view := &DatabaseQueryView{Workspace: name, Resource: item.Key, Engine: engine, Query: strings.TrimSpace(request.Query), StartedAt: time.Now()}
+	var rows [][]string
+	allowed := state.Workspace.Policies.QueryResources
+	switch {
+	case queryClients[engine] == "":
+		err = fmt.Errorf("resource %q is a %s database; queries support postgres and mysql", item.Key, engine)
+	case len(allowed) > 0 && !slices.Contains(allowed, item.Key):
+		err = fmt.Errorf("workspace %q policies.queryResources does not allow queries against %q", name, item.Key)
+	default:
+		var statement string
+		if statement, err = readOnlyStatement(engine, request.Query); err == nil {
+			var output string
+			if output, err = s.runDatabaseClient(ctx, state, item, queryClients[engine], statement); err == nil {
+				rows = queryRows(output)
+			}
+		}
+	}
+	view.DurationMs = time.Since(view.StartedAt).Milliseconds()
+	if err == nil && len(rows) > 0 {
+		view.Columns = rows[0]
+		for _, row := range rows[1:] {
+			if len(view.Rows) == request.Limit {
+				view.Truncated = true
+				break
+			}
+			record := make(map[string]string, len(view.Columns))
+			for i, column := range view.Columns {
+				if i < len(row) {
+					record[column] = row[i]
+				}
+			}
+			view.Rows = append(view.Rows, record)
+		}
+	}
+	record := cachepkg.QueryRecord{
+		Workspace:  name,
+		Actor:      s.actor,
+		Resource:   item.Key,
+		Engine:     engine,
+		Query:      view.Query,
+		StartedAt:  view.StartedAt,
+		DurationMs: view.DurationMs,
+		Rows:       len(view.Rows),
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	_ = cachepkg.Normalize(s.cache).SaveQuery(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+	if view.Rows == nil {
+		view.Rows = []map[string]string{}
+	}
+	return view, nil
+}
+
+// QueryHistory returns the recorded queries against a workspace's
+// databases, newest first, only those of actor when it is set. Queries are
+// only recorded when the service has a cache store.
+func (s *Service) QueryHistory(ctx context.Context, name, actor string) (*QueryHistoryView, error) {
+	state, err := s.loadWorkspaceState(name)
+	if err != nil {
+		return nil, err
+	}
+	queries, err := cachepkg.Normalize(s.cache).QueryHistory(ctx, state.Desired.Name, queryHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+	if actor != "" {
+		queries = slices.DeleteFunc(queries, func(query cachepkg.QueryRecord) bool { return query.Actor != actor })
+	}
+	if queries == nil {
+		queries = []cachepkg.QueryRecord{}
+	}
+	return &QueryHistoryView{Workspace: state.Desired.Name, Queries: queries}, nil
+}
+
+// queryRows splits query output into tab-separated rows. Unlike
+// databaseRows it keeps blank lines, since a row of empty values is still a
+// row; only the final newline is dropped.
+func queryRows(output string) [][]string {
+	output = strings.TrimSuffix(strings.ReplaceAll(output, "\r\n", "\n"), "\n")
+	if output == "" {
+		return nil
+	}
+	var rows [][]string
+	for _, line := range strings.Split(output, "\n") {
+		rows = append(rows, strings.Split(line, "\t"))
+	}
+	return rows
+}
+
+// readOnlyStatement checks that query is a single statement starting with
+// one of queryStatements, and returns it without a trailing semicolon. Any
+// other semicolon is refused, even inside a string, so the engine's client
+// can never split the query into more statements. For mysql, /*! and /*+
+// comments are refused anywhere, since the server runs what is inside them.
+func readOnlyStatement(engine, query string) (string, error) {
+	statement := strings.TrimSpace(query)
+	for strings.HasSuffix(statement, ";") {
+		statement = strings.TrimSpace(strings.TrimSuffix(statement, ";"))
+	}
+	if statement == "" {
+		return "", fmt.Errorf("query is required")
+	}
+	if strings.Contains(statement, ";") {
+		return "", fmt.Errorf("query must be a single statement")
+	}
+	if engine == "mysql" && (strings.Contains(statement, "/*!") || strings.Contains(statement, "/*+")) {
+		return "", fmt.Errorf("query must not contain /*! or /*+ comments")
+	}
+	if !slices.Contains(queryStatements, leadingKeyword(statement)) {
+		return "", fmt.Errorf("query must start with one of %s", strings.ToUpper(strings.Join(queryStatements, ", ")))
+	}
+	if queryFileWrite.MatchString(statement) {
+		return "", fmt.Errorf("query must not write files")
+	}
+	return statement, nil
+}
+
+// leadingKeyword returns the first word of statement in lower case,
+// skipping comments and opening parentheses.
+func leadingKeyword(statement string) string {
+	for {
+		statement = strings.TrimLeft(statement, " \t\r\n(")
+		switch {
+		case strings.HasPrefix(statement, "--"):
+			_, statement, _ = strings.Cut(statement, "\n")
+		case strings.HasPrefix(statement, "/*"):
+			_, statement, _ = strings.Cut(statement, "*/")
+		default:
+			end := strings.IndexFunc(statement, func(r rune) bool {
+				return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+			})
+			if end < 0 {
+				end = len(statement)
+			}
+			return strings.ToLower(statement[:end])
+		}
+	}
+}
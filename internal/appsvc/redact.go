@@ -0,0 +1,182 @@
+package appsvc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+	"github.com/prospect-ogujiuba/devarch/internal/workspace"
+)
+
+const maskedValue = "********"
+
+var sensitiveEnvMarkers = []string{"PASSWORD", "PASSWD", "SECRET", "TOKEN", "API_KEY", "PRIVATE_KEY", "CREDENTIAL"}
+
+// maskSensitiveEnv masks observed env values that are declared as secretRefs
+// in the desired workspace or whose key looks sensitive.
+func maskSensitiveEnv(observed, declared map[string]workspace.EnvValue) map[string]workspace.EnvValue {
+	if len(observed) == 0 {
+		return observed
+	}
+	masked := make(map[string]workspace.EnvValue, len(observed))
+	for key, value := range observed {
+		if isSensitiveEnv(key, declared[key]) {
+			masked[key] = workspace.StringEnvValue(maskedValue)
+			continue
+		}
+		masked[key] = value.Clone()
+	}
+	return masked
+}
+
+func isSensitiveEnv(key string, declared workspace.EnvValue) bool {
+	if _, secret := declared.SecretRef(); secret {
+		return true
+	}
+	upper := strings.ToUpper(key)
+	for _, marker := range sensitiveEnvMarkers {
+		if strings.Contains(upper, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactionProfile selects what an export strips before it leaves the
+// machine. Secrets are always stripped. portablePaths is not part of a named
+// profile; ExportRequest.PortablePaths sets it.
+type redactionProfile struct {
+	domains       bool
+	hostPaths     bool
+	portablePaths bool
+}
+
+// redactionProfiles are the profiles workspace export accepts: secrets, the
+// default, only blanks sensitive env values; share also replaces the
+// workspace's domains with example.test names and host paths with
+// placeholders, for bundles handed outside the team.
+var redactionProfiles = map[string]redactionProfile{
+	"secrets": {},
+	"share":   {domains: true, hostPaths: true},
+}
+
+// exportRedactor applies one redaction profile across every file of an
+// export and records each redaction for the bundle's README.
+type exportRedactor struct {
+	profile       redactionProfile
+	manifestDir   string
+	domains       *strings.Replacer
+	localPaths    []string
+	placeholders  []string
+	hostPathCount int
+	redactions    []ExportRedaction
+}
+
+func newExportRedactor(profile redactionProfile, desired *runtimepkg.DesiredWorkspace) *exportRedactor {
+	r := &exportRedactor{profile: profile, manifestDir: desired.ManifestDir}
+	if profile.domains {
+		var domains []string
+		for _, resource := range desired.Resources {
+			if resource != nil {
+				domains = append(domains, resource.Domains...)
+			}
+		}
+		// Longer domains first, so api.shop.test is not rewritten as shop.test.
+		sort.Slice(domains, func(i, j int) bool {
+			if len(domains[i]) != len(domains[j]) {
+				return len(domains[i]) > len(domains[j])
+			}
+			return domains[i] < domains[j]
+		})
+		var pairs []string
+		used := map[string]bool{}
+		for _, domain := range slices.Compact(domains) {
+			label, _, _ := strings.Cut(domain, ".")
+			replacement := label + ".example.test"
+			for n := 2; used[replacement]; n++ {
+				replacement = fmt.Sprintf("%s-%d.example.test", label, n)
+			}
+			used[replacement] = true
+			pairs = append(pairs, domain, replacement)
+		}
+		if len(pairs) > 0 {
+			r.domains = strings.NewReplacer(pairs...)
+		}
+	}
+	if profile.hostPaths {
+		if desired.ManifestDir != "" {
+			r.localPaths = append(r.localPaths, desired.ManifestDir)
+		}
+		if home, err := os.UserHomeDir(); err == nil && home != "" && home != "/" {
+			r.localPaths = append(r.localPaths, home)
+		}
+	}
+	return r
+}
+
+// env returns the value written to .env.example for one variable.
+func (r *exportRedactor) env(key string, declared, value workspace.EnvValue) string {
+	if isSensitiveEnv(key, declared) {
+		r.record("secret", ".env.example", key, "secret removed; fill in the value")
+		return ""
+	}
+	text := value.Text()
+	for _, local := range r.localPaths {
+		if strings.Contains(text, local) {
+			r.record("host-path", ".env.example", key, "local path removed; fill in the value")
+			return ""
+		}
+	}
+	return r.text(".env.example", key, text)
+}
+
+// text replaces the workspace's domains in one value.
+func (r *exportRedactor) text(file, field, value string) string {
+	if r.domains == nil {
+		return value
+	}
+	replaced := r.domains.Replace(value)
+	if replaced != value {
+		r.record("domain", file, field, "domains replaced with example.test names; use your own")
+	}
+	return replaced
+}
+
+// hostPath returns the mount source or build context to write for a host
+// path: the path itself, or a placeholder filled from .env.
+func (r *exportRedactor) hostPath(field, path string) (string, bool) {
+	if !r.profile.hostPaths || !filepath.IsAbs(path) {
+		return path, false
+	}
+	r.hostPathCount++
+	variable := fmt.Sprintf("HOST_PATH_%d", r.hostPathCount)
+	r.placeholders = append(r.placeholders, variable)
+	r.record("host-path", "compose.yaml", field, "host path removed; set "+variable+" in .env")
+	return "${" + variable + "}", true
+}
+
+// portablePath rewrites a host path under the manifest directory or the home
+// directory to start with ${PROJECT_ROOT} or ${HOME}, which compose resolves
+// wherever the bundle is started. PROJECT_ROOT is added to .env.example;
+// HOME comes from the environment.
+func (r *exportRedactor) portablePath(path string) string {
+	if !r.profile.portablePaths {
+		return path
+	}
+	portable, ok := runtimepkg.PortableHostPath(path, r.manifestDir)
+	if !ok {
+		return path
+	}
+	if strings.HasPrefix(portable, runtimepkg.ProjectRootVar) && !slices.Contains(r.placeholders, "PROJECT_ROOT") {
+		r.placeholders = append(r.placeholders, "PROJECT_ROOT")
+	}
+	return portable
+}
+
+func (r *exportRedactor) record(kind, file, field, note string) {
+	r.redactions = append(r.redactions, ExportRedaction{Kind: kind, File: file, Field: field, Note: note})
+}
@@ -0,0 +1,164 @@
+package appsvc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+)
+
+const defaultRollingReadyTimeout = 60 * time.Second
+
+// rollingRestartPollInterval is how often a rolling restart re-inspects the
+// workspace while waiting for a restarted resource to become ready.
+var rollingRestartPollInterval = time.Second
+
+// RollingRestartWorkspace restarts running resources one at a time in reverse
+// dependency order, so dependents go down before the resources they rely on,
+// and waits for each to become ready before moving on. It stops at the first
+// resource that fails to restart or become ready and returns the steps taken
+// so far alongside the error.
+func (s *Service) RollingRestartWorkspace(ctx context.Context, name string, request RollingRestartRequest) (*RollingRestartView, error) {
+	if err := s.checkWritable("rolling-restart"); err != nil {
+		return nil, err
+	}
+	if request.Timeout != nil && *request.Timeout < 0 {
+		return nil, fmt.Errorf("restart timeout must not be negative")
+	}
+	if request.ReadyTimeout < 0 {
+		return nil, fmt.Errorf("ready timeout must not be negative")
+	}
+	if request.ReadyTimeout == 0 {
+		request.ReadyTimeout = defaultRollingReadyTimeout
+	}
+	state, err := s.loadRuntimeState(name, "rolling-restart")
+	if err != nil {
+		return nil, err
+	}
+	if !state.Desired.Capabilities.Apply {
+		return nil, unsupportedCapability(name, "", state.Desired.Provider, "rolling-restart", "apply", "selected runtime does not support resource restart")
+	}
+	snapshot, err := state.Adapter.InspectWorkspace(ctx, state.Desired)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &RollingRestartView{Workspace: state.Desired.Name, Provider: state.Desired.Provider, Steps: []RollingRestartStep{}}
+	for _, item := range rollingRestartOrder(state.Desired.Resources) {
+		step := RollingRestartStep{Key: item.Key, RuntimeName: item.RuntimeName}
+		if observed := snapshot.Resource(item.Key); observed == nil || !observed.State.Running {
+			step.Status = "skipped"
+			step.Reason = "not running"
+			view.Steps = append(view.Steps, step)
+			continue
+		}
+		ref := runtimepkg.ResourceRef{Workspace: state.Desired.Name, Key: item.Key, RuntimeName: item.RuntimeName}
+		if err := state.Adapter.RestartResource(ctx, ref, runtimepkg.RestartRequest{Timeout: request.Timeout}); err != nil {
+			step.Status = "failed"
+			step.Reason = err.Error()
+			view.Steps = append(view.Steps, step)
+			return view, fmt.Errorf("restart %s: %w", item.Key, err)
+		}
+		observed, err := s.waitForResourceReady(ctx, state, item, request.ReadyTimeout)
+		if observed != nil {
+			step.Health = observed.State.Health
+		}
+		if err != nil {
+			step.Status = "failed"
+			step.Reason = err.Error()
+			view.Steps = append(view.Steps, step)
+			return view, fmt.Errorf("restart %s: %w", item.Key, err)
+		}
+		step.Status = "restarted"
+		view.Steps = append(view.Steps, step)
+	}
+	return view, nil
+}
+
+func (s *Service) waitForResourceReady(ctx context.Context, state *workspaceState, item *runtimepkg.DesiredResource, timeout time.Duration) (*runtimepkg.SnapshotResource, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		snapshot, err := state.Adapter.InspectWorkspace(ctx, state.Desired)
+		if err != nil {
+			return nil, err
+		}
+		observed := snapshot.Resource(item.Key)
+		if observed != nil && observed.State.Running {
+			switch observed.State.Health {
+			case "healthy":
+				s.saveSnapshot(ctx, state.Desired.Name, snapshot)
+				return observed, nil
+			case "unhealthy":
+				return observed, fmt.Errorf("resource reported unhealthy after restart")
+			case "":
+				if item.Spec.Health == nil {
+					s.saveSnapshot(ctx, state.Desired.Name, snapshot)
+					return observed, nil
+				}
+			}
+		}
+		if !time.Now().Before(deadline) {
+			return observed, fmt.Errorf("resource not ready after %s", timeout)
+		}
+		select {
+		case <-ctx.Done():
+			return observed, ctx.Err()
+		case <-time.After(rollingRestartPollInterval):
+		}
+	}
+}
+
+// rollingRestartOrder returns enabled resources with dependents ahead of their
+// dependencies. Ties, and any resources caught in a dependency cycle, fall
+// back to key order so the sequence is deterministic.
+func rollingRestartOrder(resources []*runtimepkg.DesiredResource) []*runtimepkg.DesiredResource {
+	byKey := make(map[string]*runtimepkg.DesiredResource, len(resources))
+	keys := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		if resource == nil || !resource.Enabled {
+			continue
+		}
+		byKey[resource.Key] = resource
+		keys = append(keys, resource.Key)
+	}
+	sort.Strings(keys)
+
+	dependents := make(map[string]int, len(keys))
+	for _, key := range keys {
+		for _, dependency := range byKey[key].DependsOn {
+			if _, ok := byKey[dependency]; ok && dependency != key {
+				dependents[dependency]++
+			}
+		}
+	}
+
+	ordered := make([]*runtimepkg.DesiredResource, 0, len(keys))
+	done := make(map[string]bool, len(keys))
+	for len(ordered) < len(keys) {
+		next := ""
+		for _, key := range keys {
+			if !done[key] && dependents[key] == 0 {
+				next = key
+				break
+			}
+		}
+		if next == "" {
+			for _, key := range keys {
+				if !done[key] {
+					next = key
+					break
+				}
+			}
+		}
+		done[next] = true
+		ordered = append(ordered, byKey[next])
+		for _, dependency := range byKey[next].DependsOn {
+			if _, ok := byKey[dependency]; ok && dependency != next && !done[dependency] {
+				dependents[dependency]--
+			}
+		}
+	}
+	return ordered
+}
@@ -0,0 +1,122 @@
+package appsvc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/prospect-ogujiuba/devarch/internal/workspace"
+)
+
+const defaultScheduleWindow = 15 * time.Minute
+
+// WorkspaceSchedules previews the stop and start schedules of every
+// discovered workspace and when each runs next. It reads only the manifests.
+func (s *Service) WorkspaceSchedules(context.Context) (*ScheduleView, error) {
+	workspaces, err := s.discoverWorkspaces()
+	if err != nil {
+		return nil, err
+	}
+	return scheduleView(workspaces, time.Now()), nil
+}
+
+// RunSchedules stops or starts each workspace whose schedule fell due within
+// the last request.Window (default 15 minutes), so calling it from cron at
+// that interval runs every schedule once. When several schedules of one
+// workspace are due, only the latest runs. A workspace that fails to stop or
+// start does not keep the others from running.
+func (s *Service) RunSchedules(ctx context.Context, request ScheduleRunRequest) (*ScheduleRunView, error) {
+	if err := s.checkWritable("schedules"); err != nil {
+		return nil, err
+	}
+	if request.Window < 0 {
+		return nil, fmt.Errorf("schedule window must not be negative")
+	}
+	if request.Window == 0 {
+		request.Window = defaultScheduleWindow
+	}
+	workspaces, err := s.discoverWorkspaces()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	view := &ScheduleRunView{RanAt: now, Runs: dueSchedules(workspaces, now, request.Window)}
+	for i := range view.Runs {
+		run := &view.Runs[i]
+		if run.Action == "stop" {
+			run.Result, err = s.StopWorkspace(ctx, run.Workspace, nil)
+		} else {
+			run.Result, err = s.StartWorkspace(ctx, run.Workspace)
+		}
+		if err != nil {
+			run.Error = err.Error()
+		}
+	}
+	return view, nil
+}
+
+func scheduleView(workspaces []*workspace.Workspace, now time.Time) *ScheduleView {
+	view := &ScheduleView{GeneratedAt: now, Schedules: []ScheduledAction{}}
+	for _, ws := range sortedWorkspaces(workspaces) {
+		for _, schedule := range ws.Schedules {
+			item := ScheduledAction{Workspace: ws.Metadata.Name, Action: schedule.Action, At: schedule.At, Days: schedule.Days, Enabled: !schedule.Disabled}
+			if item.Enabled {
+				if times := scheduleTimes(schedule, now, now.AddDate(0, 0, 7), now.Location()); len(times) > 0 {
+					item.Next = &times[0]
+				}
+			}
+			view.Schedules = append(view.Schedules, item)
+		}
+	}
+	return view
+}
+
+// dueSchedules picks, per workspace, the latest enabled schedule that fired
+// in (now-window, now].
+func dueSchedules(workspaces []*workspace.Workspace, now time.Time, window time.Duration) []ScheduleRun {
+	runs := []ScheduleRun{}
+	for _, ws := range sortedWorkspaces(workspaces) {
+		var due *ScheduleRun
+		for _, schedule := range ws.Schedules {
+			if schedule.Disabled {
+				continue
+			}
+			times := scheduleTimes(schedule, now.Add(-window), now, now.Location())
+			if len(times) == 0 {
+				continue
+			}
+			if last := times[len(times)-1]; due == nil || last.After(due.Due) {
+				due = &ScheduleRun{Workspace: ws.Metadata.Name, Action: schedule.Action, Due: last}
+			}
+		}
+		if due != nil {
+			runs = append(runs, *due)
+		}
+	}
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].Due.Before(runs[j].Due) })
+	return runs
+}
+
+// scheduleTimes lists the times schedule fires in (from, until], in loc.
+func scheduleTimes(schedule workspace.Schedule, from, until time.Time, loc *time.Location) []time.Time {
+	offset, err := schedule.Clock()
+	if err != nil {
+		return nil
+	}
+	days := map[time.Weekday]bool{}
+	for _, day := range schedule.Days {
+		days[workspace.Weekdays[day]] = true
+	}
+	var times []time.Time
+	first := from.In(loc)
+	for day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc); !day.After(until); day = day.AddDate(0, 0, 1) {
+		if !days[day.Weekday()] {
+			continue
+		}
+		if at := day.Add(offset); at.After(from) && !at.After(until) {
+			times = append(times, at)
+		}
+	}
+	return times
+}
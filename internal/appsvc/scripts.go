@@ -0,0 +1,196 @@
+package appsvc
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	cachepkg "github.com/prospect-ogujiuba/devarch/internal/cache"
+	"github.com/prospect-ogujiuba/devarch/internal/projectscan"
+	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+)
+
+const (
+	scriptWorkdir      = "/workspace"
+	scriptHistoryLimit = 50
+)
+
+// scriptImages maps the tool that runs a script to the image it runs in.
+var scriptImages = map[string]string{
+	"npm":      "docker.io/library/node:22",
+	"yarn":     "docker.io/library/node:22",
+	"pnpm":     "docker.io/library/node:22",
+	"bun":      "docker.io/oven/bun:1",
+	"composer": "docker.io/library/composer:2",
+	"php":      "docker.io/library/php:8.3-cli",
+	"wp":       "docker.io/library/wordpress:cli",
+}
+
+// RunProjectScript runs a package.json or composer.json script, or an
+// artisan command for Laravel projects, in a throwaway container with the
+// project mounted at /workspace. The run is recorded in the cache store
+// whatever its outcome. A non-zero exit is reported in the view rather than
+// as an error.
+func (s *Service) RunProjectScript(ctx context.Context, request ProjectScriptRequest) (*ProjectScriptRunView, error) {
+	if err := s.checkWritable("script"); err != nil {
+		return nil, err
+	}
+	scan, err := projectscan.Scan(request.Path)
+	if err != nil {
+		return nil, err
+	}
+	source, command, err := projectScriptCommand(scan, strings.TrimSpace(request.Script), request.Args)
+	if err != nil {
+		return nil, err
+	}
+	return s.runProjectCommand(ctx, scan, request, source, command)
+}
+
+// RunWPCLI runs wp-cli with request.Args against a WordPress project, in a
+// throwaway container like RunProjectScript. request.Script is ignored.
+func (s *Service) RunWPCLI(ctx context.Context, request ProjectScriptRequest) (*ProjectScriptRunView, error) {
+	if err := s.checkWritable("wp"); err != nil {
+		return nil, err
+	}
+	scan, err := projectscan.Scan(request.Path)
+	if err != nil {
+		return nil, err
+	}
+	if scan.ProjectType != "wordpress" {
+		return nil, fmt.Errorf("project %s is not a WordPress site", scan.Path)
+	}
+	if len(request.Args) == 0 {
+		return nil, fmt.Errorf("wp-cli arguments are required")
+	}
+	request.Script = "wp"
+	return s.runProjectCommand(ctx, scan, request, "wp-cli", append([]string{"wp"}, request.Args...))
+}
+
+// runProjectCommand runs command for a scanned project and records the run.
+// The image is picked from the first word of command.
+func (s *Service) runProjectCommand(ctx context.Context, scan *projectscan.Result, request ProjectScriptRequest, source string, command []string) (*ProjectScriptRunView, error) {
+	var err error
+	var adapter runtimepkg.Adapter
+	var capabilities runtimepkg.AdapterCapabilities
+	provider, network := runtimepkg.ProviderPodman, ""
+	if request.Workspace != "" {
+		state, err := s.loadRuntimeState(request.Workspace, "script")
+		if err != nil {
+			return nil, err
+		}
+		adapter, capabilities, provider = state.Adapter, state.Desired.Capabilities, state.Desired.Provider
+		if state.Desired.Network != nil {
+			network = state.Desired.Network.Name
+		}
+	} else if adapter, provider, capabilities, err = s.requireProvider(runtimepkg.ProviderPodman, "", "script"); err != nil {
+		return nil, err
+	}
+	runner, ok := adapter.(runtimepkg.TaskRunner)
+	if !ok || !capabilities.Apply {
+		return nil, unsupportedCapability(request.Workspace, "", provider, "script", "tasks", "selected runtime does not run one-off tasks")
+	}
+
+	view := &ProjectScriptRunView{
+		Project:   scan.Name,
+		Path:      scan.Path,
+		Script:    request.Script,
+		Source:    source,
+		Command:   command,
+		Image:     scriptImages[command[0]],
+		Workspace: request.Workspace,
+		StartedAt: time.Now(),
+	}
+	task := runtimepkg.ApplyResourceRequest{
+		Workspace:   request.Workspace,
+		NetworkName: network,
+		Resource: runtimepkg.AppliedResource{
+			Key:         "script",
+			RuntimeName: "devarch-script-" + scan.Name,
+			Spec: runtimepkg.ResourceSpec{
+				Image:      view.Image,
+				WorkingDir: scriptWorkdir,
+				Volumes:    []runtimepkg.VolumeSpec{{Source: scan.Path, Target: scriptWorkdir, Kind: "bind"}},
+			},
+		},
+	}
+	result, runErr := runner.RunTask(ctx, task, command)
+	view.DurationMs = time.Since(view.StartedAt).Milliseconds()
+	record := cachepkg.ScriptRunRecord{
+		Project:    scan.Path,
+		Actor:      s.actor,
+		Script:     view.Script,
+		Command:    command,
+		Image:      view.Image,
+		StartedAt:  view.StartedAt,
+		DurationMs: view.DurationMs,
+	}
+	if runErr != nil {
+		record.ExitCode, record.Error = -1, runErr.Error()
+	} else {
+		record.ExitCode = result.ExitCode
+		view.ExitCode, view.Output = result.ExitCode, result.Stdout+result.Stderr
+	}
+	_ = cachepkg.Normalize(s.cache).SaveScriptRun(ctx, record)
+	if runErr != nil {
+		return nil, runErr
+	}
+	return view, nil
+}
+
+// ProjectScriptHistory returns the recorded script runs of the project at
+// path, newest first, only those of actor when it is set. Runs are read
+// from the service's cache store; the CLI keeps one in the state directory.
+func (s *Service) ProjectScriptHistory(ctx context.Context, path, actor string) (*ProjectScriptHistoryView, error) {
+	scan, err := projectscan.Scan(path)
+	if err != nil {
+		return nil, err
+	}
+	runs, err := cachepkg.Normalize(s.cache).ScriptRunHistory(ctx, scan.Path, scriptHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+	if actor != "" {
+		runs = slices.DeleteFunc(runs, func(run cachepkg.ScriptRunRecord) bool { return run.Actor != actor })
+	}
+	if runs == nil {
+		runs = []cachepkg.ScriptRunRecord{}
+	}
+	return &ProjectScriptHistoryView{Path: scan.Path, Runs: runs}, nil
+}
+
+// projectScriptCommand returns the source and command for script. A
+// package.json script wins over a composer.json script of the same name;
+// Laravel projects fall back to an artisan command.
+func projectScriptCommand(scan *projectscan.Result, script string, args []string) (string, []string, error) {
+	if script == "" {
+		return "", nil, fmt.Errorf("script is required")
+	}
+	var found *projectscan.Script
+	for i := range scan.Scripts {
+		if scan.Scripts[i].Name == script && (found == nil || scan.Scripts[i].Source == "package.json") {
+			found = &scan.Scripts[i]
+		}
+	}
+	switch {
+	case found != nil && found.Source == "package.json":
+		manager := scan.PackageManager
+		switch manager {
+		case "npm", "yarn", "pnpm", "bun":
+		default:
+			manager = "npm"
+		}
+		command := []string{manager, "run", script}
+		if manager == "npm" && len(args) > 0 {
+			command = append(command, "--")
+		}
+		return found.Source, append(command, args...), nil
+	case found != nil:
+		return found.Source, append([]string{"composer", "run-script", script}, args...), nil
+	case scan.ProjectType == "laravel":
+		return "artisan", append([]string{"php", "artisan", script}, args...), nil
+	default:
+		return "", nil, &NotFoundError{Kind: "script", Name: script}
+	}
+}
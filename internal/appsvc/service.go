@@ -3,7 +3,10 @@ package appsvc
 import (
 	"context"
 	"fmt"
+	"maps"
+	"os"
 	"os/exec"
+	"os/user"
 	"sort"
 	"strings"
 	"sync"
@@ -19,6 +22,7 @@ import (
 	resolvepkg "github.com/prospect-ogujiuba/devarch/internal/resolve"
 	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
 	dockeradapter "github.com/prospect-ogujiuba/devarch/internal/runtime/docker"
+	nerdctladapter "github.com/prospect-ogujiuba/devarch/internal/runtime/nerdctl"
 	podmanadapter "github.com/prospect-ogujiuba/devarch/internal/runtime/podman"
 	"github.com/prospect-ogujiuba/devarch/internal/workflows"
 	"github.com/prospect-ogujiuba/devarch/internal/workspace"
@@ -34,6 +38,20 @@ type Config struct {
 	Cache          cachepkg.Store
 	LookPath       func(string) (string, error)
 	WorkflowRunner workflows.Runner
+	// ReadOnly rejects every operation that changes runtime state or writes
+	// files, for shared dashboards, demos, and host maintenance.
+	// ReadOnlyReason is reported with the rejection.
+	ReadOnly       bool
+	ReadOnlyReason string
+	// Team scopes the service to the workspaces whose metadata.team matches.
+	// Other workspaces are left out of listings and cannot be loaded.
+	Team string
+	// Actor is recorded as the principal behind applies, queries, and
+	// script runs. It defaults to the login name of the current user.
+	Actor string
+	// MeminfoPath overrides /proc/meminfo for doctor and the apply memory
+	// check.
+	MeminfoPath string
 }
 
 // Service is the narrow shared seam consumed by transports.
@@ -45,6 +63,11 @@ type Service struct {
 	cache          cachepkg.Store
 	lookPath       func(string) (string, error)
 	workflowRunner workflows.Runner
+	readOnly       bool
+	readOnlyReason string
+	team           string
+	actor          string
+	meminfoPath    string
 }
 
 type workspaceState struct {
@@ -64,6 +87,14 @@ func New(config Config) (*Service, error) {
 		cache:          config.Cache,
 		lookPath:       config.LookPath,
 		workflowRunner: config.WorkflowRunner,
+		readOnly:       config.ReadOnly,
+		readOnlyReason: config.ReadOnlyReason,
+		team:           config.Team,
+		actor:          config.Actor,
+		meminfoPath:    config.MeminfoPath,
+	}
+	if service.actor == "" {
+		service.actor = currentUser()
 	}
 	if len(service.adapters) == 0 {
 		service.adapters = defaultAdapters()
@@ -84,6 +115,8 @@ func New(config Config) (*Service, error) {
 	return service, nil
 }
 
+// CatalogTemplates lists the catalog templates. A team-scoped service lists
+// only the templates visible to its team.
 func (s *Service) CatalogTemplates(context.Context) ([]TemplateSummary, error) {
 	index, err := LoadCatalogIndex(s.catalogRoots)
 	if err != nil {
@@ -92,20 +125,22 @@ func (s *Service) CatalogTemplates(context.Context) ([]TemplateSummary, error) {
 	templates := index.Templates()
 	summaries := make([]TemplateSummary, 0, len(templates))
 	for _, template := range templates {
-		if template == nil {
+		if template == nil || !s.templateVisible(template) {
 			continue
 		}
 		summaries = append(summaries, TemplateSummary{
 			Name:        template.Metadata.Name,
 			Description: template.Metadata.Description,
 			Tags:        append([]string(nil), template.Metadata.Tags...),
+			Team:        template.Metadata.Team,
+			Visibility:  template.Metadata.Visibility,
 		})
 	}
 	return summaries, nil
 }
 
 func (s *Service) Workspaces(context.Context) ([]WorkspaceSummary, error) {
-	workspaces, err := DiscoverWorkspaces(s.workspaceRoots)
+	workspaces, err := s.discoverWorkspaces()
 	if err != nil {
 		return nil, err
 	}
@@ -119,6 +154,10 @@ func (s *Service) Workspaces(context.Context) ([]WorkspaceSummary, error) {
 			Provider:      provider,
 			Capabilities:  capabilities,
 			ResourceCount: len(ws.Resources),
+			Owner:         ws.Metadata.Owner,
+			Team:          ws.Metadata.Team,
+			Contact:       ws.Metadata.Contact,
+			Expires:       ws.Metadata.Expires,
 		})
 	}
 	return summaries, nil
@@ -192,7 +231,39 @@ func (s *Service) WorkspacePlan(ctx context.Context, name string) (*planpkg.Resu
 	return result, nil
 }
 
-func (s *Service) ApplyWorkspace(ctx context.Context, name string) (*apply.Result, error) {
+// WorkspaceRender renders the apply payload for a workspace without touching
+// the runtime and returns it with its canonical digest.
+func (s *Service) WorkspaceRender(_ context.Context, name string) (*WorkspaceRenderView, error) {
+	state, err := s.loadWorkspaceState(name)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := apply.Render(state.Desired)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := apply.Digest(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &WorkspaceRenderView{Workspace: state.Desired.Name, Digest: digest, Payload: payload}, nil
+}
+
+// ApplyWorkspace plans and applies a workspace. Resource operations in the
+// result carry pull, create, and, when request.HealthTimeout is set, time to
+// healthy timings. request.Parallelism lets independent resources start
+// concurrently, and request.ChangedOnly limits the apply to resources whose
+// configuration or image changed.
+func (s *Service) ApplyWorkspace(ctx context.Context, name string, request ApplyRequest) (*apply.Result, error) {
+	if err := s.checkWritable("apply"); err != nil {
+		return nil, err
+	}
+	if request.HealthTimeout < 0 {
+		return nil, fmt.Errorf("health timeout must not be negative")
+	}
+	if request.Parallelism < 0 {
+		return nil, fmt.Errorf("parallelism must not be negative")
+	}
 	state, err := s.loadRuntimeState(name, "apply")
 	if err != nil {
 		return nil, err
@@ -208,14 +279,25 @@ func (s *Service) ApplyWorkspace(ctx context.Context, name string) (*apply.Resul
 	if err != nil {
 		return nil, err
 	}
+	if request.ChangedOnly {
+		diff = planpkg.ChangedOnly(diff)
+	}
+	if err := checkNameConflicts(ctx, state); err != nil {
+		return nil, err
+	}
 	if err := ensureApplyCapabilities(name, state.Desired.Provider, state.Desired.Capabilities, diff); err != nil {
 		return nil, err
 	}
+	if !request.IgnoreCapacity {
+		if err := s.checkMemoryCapacity(state.Desired, diff); err != nil {
+			return nil, err
+		}
+	}
 	payload, err := apply.Render(state.Desired)
 	if err != nil {
 		return nil, err
 	}
-	executor := &apply.Executor{Adapter: state.Adapter, Cache: s.cache, Publisher: s.bus}
+	executor := &apply.Executor{Adapter: state.Adapter, Cache: s.cache, Publisher: s.bus, Actor: s.actor, HealthTimeout: request.HealthTimeout, Parallelism: request.Parallelism}
 	return executor.Execute(ctx, diff, payload)
 }
 
@@ -264,6 +346,9 @@ func (s *Service) WorkspaceLogs(ctx context.Context, name, resource string, requ
 }
 
 func (s *Service) ExecWorkspace(ctx context.Context, name, resource string, request runtimepkg.ExecRequest) (*runtimepkg.ExecResult, error) {
+	if err := s.checkWritable("exec"); err != nil {
+		return nil, err
+	}
 	resource = strings.TrimSpace(resource)
 	if resource == "" {
 		return nil, fmt.Errorf("resource is required")
@@ -286,8 +371,135 @@ func (s *Service) ExecWorkspace(ctx context.Context, name, resource string, requ
 	return runtimepkg.ExecWithEvents(ctx, state.Adapter, s.bus, ref, request)
 }
 
+// RunWorkspaceTask runs command once in a new container built from a
+// resource's image, environment, volumes, and network, for migrations and
+// seeders that should not run inside the long-lived container.
+func (s *Service) RunWorkspaceTask(ctx context.Context, name, resource string, command []string) (*runtimepkg.ExecResult, error) {
+	if err := s.checkWritable("run"); err != nil {
+		return nil, err
+	}
+	resource = strings.TrimSpace(resource)
+	if resource == "" {
+		return nil, fmt.Errorf("resource is required")
+	}
+	if len(command) == 0 {
+		return nil, fmt.Errorf("command is required")
+	}
+	state, err := s.loadRuntimeState(name, "run")
+	if err != nil {
+		return nil, err
+	}
+	item := state.Desired.Resource(resource)
+	if item == nil {
+		return nil, &NotFoundError{Kind: "resource", Name: resource, Workspace: name}
+	}
+	runner, ok := state.Adapter.(runtimepkg.TaskRunner)
+	if !ok || !state.Desired.Capabilities.Apply {
+		return nil, unsupportedCapability(name, resource, state.Desired.Provider, "run", "tasks", "selected runtime does not run one-off tasks")
+	}
+	request := runtimepkg.ApplyResourceRequest{
+		Workspace: state.Desired.Name,
+		Resource:  runtimepkg.AppliedResource{Key: item.Key, LogicalHost: item.LogicalHost, RuntimeName: item.RuntimeName, Spec: item.Spec.Clone()},
+	}
+	if state.Desired.Network != nil {
+		request.NetworkName = state.Desired.Network.Name
+	}
+	return runner.RunTask(ctx, request, command)
+}
+
+// WorkspaceStats samples usage for the running resources of a workspace. When
+// resource is non-empty only that resource is sampled.
+func (s *Service) WorkspaceStats(ctx context.Context, name, resource string) (*WorkspaceStatsView, error) {
+	resource = strings.TrimSpace(resource)
+	state, err := s.loadRuntimeState(name, "stats")
+	if err != nil {
+		return nil, err
+	}
+	if resource != "" && state.Desired.Resource(resource) == nil {
+		return nil, &NotFoundError{Kind: "resource", Name: resource, Workspace: name}
+	}
+	reader, ok := state.Adapter.(runtimepkg.StatsReader)
+	if !ok || !state.Desired.Capabilities.Inspect {
+		return nil, unsupportedCapability(name, resource, state.Desired.Provider, "stats", "stats", "selected runtime does not report resource stats")
+	}
+	snapshot, err := state.Adapter.InspectWorkspace(ctx, state.Desired)
+	if err != nil {
+		return nil, err
+	}
+	s.saveSnapshot(ctx, state.Desired.Name, snapshot)
+
+	refs := make([]runtimepkg.ResourceRef, 0, len(state.Desired.Resources))
+	for _, item := range state.Desired.Resources {
+		if item == nil || (resource != "" && item.Key != resource) {
+			continue
+		}
+		observed := snapshot.Resource(item.Key)
+		if observed == nil || !observed.State.Running {
+			continue
+		}
+		refs = append(refs, runtimepkg.ResourceRef{Workspace: state.Desired.Name, Key: item.Key, RuntimeName: item.RuntimeName})
+	}
+
+	view := &WorkspaceStatsView{Workspace: state.Desired.Name, Provider: state.Desired.Provider, Resources: []runtimepkg.ResourceStats{}}
+	if len(refs) == 0 {
+		return view, nil
+	}
+	stats, err := reader.ResourceStats(ctx, refs)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Key < stats[j].Key
+	})
+	view.Resources = stats
+	for _, sample := range stats {
+		view.Total.CPUPercent += sample.CPUPercent
+		view.Total.MemoryBytes += sample.MemoryBytes
+		view.Total.PIDs += sample.PIDs
+	}
+	return view, nil
+}
+
+// ResourceTop lists the processes running inside one workspace resource.
+func (s *Service) ResourceTop(ctx context.Context, name, resource string) (*runtimepkg.ProcessList, error) {
+	state, item, err := s.loadRuntimeResource(name, resource, "top")
+	if err != nil {
+		return nil, err
+	}
+	lister, ok := state.Adapter.(runtimepkg.ProcessLister)
+	if !ok {
+		return nil, unsupportedCapability(name, item.Key, state.Desired.Provider, "top", "top", "selected runtime does not list container processes")
+	}
+	return lister.TopResource(ctx, runtimepkg.ResourceRef{Workspace: state.Desired.Name, Key: item.Key, RuntimeName: item.RuntimeName})
+}
+
+// ResourceInspect returns the observed state of one resource with sensitive
+// env values masked.
+func (s *Service) ResourceInspect(ctx context.Context, name, resource string) (*ResourceInspectView, error) {
+	state, item, err := s.loadRuntimeResource(name, resource, "inspect")
+	if err != nil {
+		return nil, err
+	}
+	if !state.Desired.Capabilities.Inspect {
+		return nil, unsupportedCapability(name, item.Key, state.Desired.Provider, "inspect", "inspect", "selected runtime does not support workspace inspection")
+	}
+	snapshot, err := state.Adapter.InspectWorkspace(ctx, state.Desired)
+	if err != nil {
+		return nil, err
+	}
+	s.saveSnapshot(ctx, state.Desired.Name, snapshot)
+	observed := snapshot.Resource(item.Key)
+	if observed == nil {
+		return nil, &NotFoundError{Kind: "container", Name: item.RuntimeName, Workspace: name}
+	}
+	sanitized := *observed
+	sanitized.Spec = observed.Spec.Clone()
+	sanitized.Spec.Env = maskSensitiveEnv(sanitized.Spec.Env, item.EffectiveEnv())
+	return &ResourceInspectView{Workspace: state.Desired.Name, Provider: state.Desired.Provider, Resource: &sanitized}, nil
+}
+
 func (s *Service) Doctor(ctx context.Context) (*workflows.DoctorReport, error) {
-	return workflows.Doctor(ctx, s.workflowRunner, workflows.DoctorOptions{WorkspaceRoots: s.workspaceRoots, CatalogRoots: s.catalogRoots})
+	return workflows.Doctor(ctx, s.workflowRunner, workflows.DoctorOptions{WorkspaceRoots: s.workspaceRoots, CatalogRoots: s.catalogRoots, MeminfoPath: s.meminfoPath})
 }
 
 func (s *Service) RuntimeStatus(ctx context.Context) (*workflows.RuntimeStatusReport, error) {
@@ -298,15 +510,38 @@ func (s *Service) SocketStatus(ctx context.Context) (*workflows.SocketStatusRepo
 	return workflows.SocketStatus(ctx, s.workflowRunner), nil
 }
 
+// SocketDiagnose explains why the Podman socket is unusable. With repair it
+// also starts or restarts podman.socket and retries the connection.
+func (s *Service) SocketDiagnose(ctx context.Context, repair bool) (*workflows.SocketDiagnosisReport, error) {
+	if repair {
+		if err := s.checkWritable("socket repair"); err != nil {
+			return nil, err
+		}
+	}
+	return workflows.SocketDiagnose(ctx, s.workflowRunner, repair), nil
+}
+
 func (s *Service) SocketStart(ctx context.Context) (*workflows.CommandResult, error) {
+	if err := s.checkWritable("socket start"); err != nil {
+		return nil, err
+	}
 	return workflows.SocketStart(ctx, s.workflowRunner)
 }
 
 func (s *Service) SocketStop(ctx context.Context) (*workflows.CommandResult, error) {
+	if err := s.checkWritable("socket stop"); err != nil {
+		return nil, err
+	}
 	return workflows.SocketStop(ctx, s.workflowRunner)
 }
 
-func (s *Service) RestartWorkspaceResource(ctx context.Context, name, resource string) error {
+func (s *Service) RestartWorkspaceResource(ctx context.Context, name, resource string, request runtimepkg.RestartRequest) error {
+	if err := s.checkWritable("restart"); err != nil {
+		return err
+	}
+	if request.Timeout != nil && *request.Timeout < 0 {
+		return fmt.Errorf("restart timeout must not be negative")
+	}
 	resource = strings.TrimSpace(resource)
 	if resource == "" {
 		return fmt.Errorf("resource is required")
@@ -322,7 +557,7 @@ func (s *Service) RestartWorkspaceResource(ctx context.Context, name, resource s
 	if !state.Desired.Capabilities.Apply {
 		return unsupportedCapability(name, resource, state.Desired.Provider, "restart", "apply", "selected runtime does not support resource restart")
 	}
-	return state.Adapter.RestartResource(ctx, runtimepkg.ResourceRef{Workspace: state.Desired.Name, Key: item.Key, RuntimeName: item.RuntimeName})
+	return state.Adapter.RestartResource(ctx, runtimepkg.ResourceRef{Workspace: state.Desired.Name, Key: item.Key, RuntimeName: item.RuntimeName}, request)
 }
 
 func (s *Service) SubscribeWorkspaceEvents(ctx context.Context, name string, buffer int) (<-chan events.Envelope, func(), error) {
@@ -377,16 +612,28 @@ func (s *Service) CatalogTemplate(_ context.Context, name string) (*TemplateDeta
 		return nil, err
 	}
 	template, ok := index.ByName(name)
-	if !ok {
+	if !ok || !s.templateVisible(template) {
 		return nil, &NotFoundError{Kind: "template", Name: name}
 	}
 	return templateDetailFromCatalog(template)
 }
 
+// templateVisible reports whether the service's team may see template. An
+// unscoped service sees every template.
+func (s *Service) templateVisible(template *catalog.Template) bool {
+	return s.team == "" || template.Metadata.VisibleTo(s.team)
+}
+
 func (s *Service) ScanProject(_ context.Context, path string) (*ProjectScanView, error) {
 	return projectscan.Scan(path)
 }
 
+// ScanProjects scans every project below root, honoring the root's
+// .devarchignore and the include and exclude globs in request.
+func (s *Service) ScanProjects(_ context.Context, root string, request ProjectScanRequest) (*ProjectInventoryView, error) {
+	return projectscan.ScanAll(root, request)
+}
+
 func (s *Service) Workspace(_ context.Context, name string) (*WorkspaceDetail, error) {
 	ws, err := s.loadWorkspace(name)
 	if err != nil {
@@ -400,13 +647,18 @@ func (s *Service) Workspace(_ context.Context, name string) (*WorkspaceDetail, e
 		Provider:      provider,
 		Capabilities:  capabilities,
 		ResourceCount: len(ws.Resources),
+		Owner:         ws.Metadata.Owner,
+		Team:          ws.Metadata.Team,
+		Contact:       ws.Metadata.Contact,
+		Expires:       ws.Metadata.Expires,
 		ManifestPath:  ws.ManifestPath,
+		Notes:         ws.Metadata.Notes,
 		ResourceKeys:  ws.SortedResourceKeys(),
 	}, nil
 }
 
 func (s *Service) loadWorkspace(name string) (*workspace.Workspace, error) {
-	workspaces, err := DiscoverWorkspaces(s.workspaceRoots)
+	workspaces, err := s.discoverWorkspaces()
 	if err != nil {
 		return nil, err
 	}
@@ -423,6 +675,12 @@ func (s *Service) loadWorkspaceState(name string) (*workspaceState, error) {
 	if err != nil {
 		return nil, err
 	}
+	return buildWorkspaceState(ws)
+}
+
+// buildWorkspaceState resolves a loaded workspace against its catalog
+// sources and builds its desired runtime state.
+func buildWorkspaceState(ws *workspace.Workspace) (*workspaceState, error) {
 	paths, err := catalog.DiscoverTemplateFiles(ws.ResolvedCatalogSources())
 	if err != nil {
 		return nil, err
@@ -440,6 +698,9 @@ func (s *Service) loadWorkspaceState(name string) (*workspaceState, error) {
 	if err != nil {
 		return nil, err
 	}
+	if runtimepkg.SELinuxEnforcing() {
+		desired.Diagnostics = append(desired.Diagnostics, runtimepkg.SELinuxLabelDiagnostics(desired)...)
+	}
 	return &workspaceState{Workspace: ws, Graph: graph, Contracts: contractResult, Desired: desired}, nil
 }
 
@@ -458,6 +719,22 @@ func (s *Service) loadRuntimeState(name, operation string) (*workspaceState, err
 	return state, nil
 }
 
+func (s *Service) loadRuntimeResource(name, resource, operation string) (*workspaceState, *runtimepkg.DesiredResource, error) {
+	resource = strings.TrimSpace(resource)
+	if resource == "" {
+		return nil, nil, fmt.Errorf("resource is required")
+	}
+	state, err := s.loadRuntimeState(name, operation)
+	if err != nil {
+		return nil, nil, err
+	}
+	item := state.Desired.Resource(resource)
+	if item == nil {
+		return nil, nil, &NotFoundError{Kind: "resource", Name: resource, Workspace: name}
+	}
+	return state, item, nil
+}
+
 func (s *Service) describeProvider(provider string) (string, runtimepkg.AdapterCapabilities) {
 	adapter, resolvedProvider, capabilities, err := s.resolveProvider(normalizeProvider(provider), false)
 	if err != nil || adapter == nil {
@@ -484,7 +761,7 @@ func (s *Service) requireProvider(provider, workspaceName, operation string) (ru
 func (s *Service) resolveProvider(provider string, strict bool, details ...string) (runtimepkg.Adapter, string, runtimepkg.AdapterCapabilities, error) {
 	switch provider {
 	case "", runtimepkg.ProviderAuto:
-		for _, candidate := range []string{runtimepkg.ProviderDocker, runtimepkg.ProviderPodman} {
+		for _, candidate := range []string{runtimepkg.ProviderDocker, runtimepkg.ProviderPodman, runtimepkg.ProviderNerdctl} {
 			adapter, ok := s.adapters[candidate]
 			if !ok || adapter == nil {
 				continue
@@ -497,8 +774,8 @@ func (s *Service) resolveProvider(provider string, strict bool, details ...strin
 			return nil, runtimepkg.ProviderAuto, runtimepkg.AdapterCapabilities{}, nil
 		}
 		workspaceName, operation := detailPair(details)
-		return nil, runtimepkg.ProviderAuto, runtimepkg.AdapterCapabilities{}, unsupportedCapability(workspaceName, "", runtimepkg.ProviderAuto, operation, "provider", "no available runtime found for auto provider (order: docker, podman)")
-	case runtimepkg.ProviderDocker, runtimepkg.ProviderPodman:
+		return nil, runtimepkg.ProviderAuto, runtimepkg.AdapterCapabilities{}, unsupportedCapability(workspaceName, "", runtimepkg.ProviderAuto, operation, "provider", "no available runtime found for auto provider (order: docker, podman, nerdctl)")
+	case runtimepkg.ProviderDocker, runtimepkg.ProviderPodman, runtimepkg.ProviderNerdctl:
 		adapter, ok := s.adapters[provider]
 		if !ok || adapter == nil {
 			if strict {
@@ -549,9 +826,19 @@ func (s *Service) saveSnapshot(ctx context.Context, workspaceName string, snapsh
 
 func defaultAdapters() map[string]runtimepkg.Adapter {
 	return map[string]runtimepkg.Adapter{
-		runtimepkg.ProviderDocker: dockeradapter.New(nil),
-		runtimepkg.ProviderPodman: podmanadapter.New(nil),
+		runtimepkg.ProviderDocker:  dockeradapter.New(nil),
+		runtimepkg.ProviderPodman:  podmanadapter.New(nil),
+		runtimepkg.ProviderNerdctl: nerdctladapter.New(nil),
+	}
+}
+
+// currentUser returns the login name of the user running devarch, or an
+// empty string when it cannot be determined.
+func currentUser() string {
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		return current.Username
 	}
+	return firstNonEmpty(os.Getenv("USER"), os.Getenv("USERNAME"))
 }
 
 func cloneAdapters(adapters map[string]runtimepkg.Adapter) map[string]runtimepkg.Adapter {
@@ -583,14 +870,19 @@ func templateDetailFromCatalog(template *catalog.Template) (*TemplateDetail, err
 		Name:        template.Metadata.Name,
 		Description: template.Metadata.Description,
 		Tags:        append([]string(nil), template.Metadata.Tags...),
+		Team:        template.Metadata.Team,
+		Visibility:  template.Metadata.Visibility,
+		SharedWith:  append([]string(nil), template.Metadata.SharedWith...),
 		Runtime:     cloneMap(template.Spec.Runtime),
 		Env:         env,
+		EnvSchema:   maps.Clone(template.Spec.EnvSchema),
 		Ports:       templatePorts(template.Spec.Ports),
 		Volumes:     templateVolumes(template.Spec.Volumes),
 		Imports:     templateImports(template.Spec.Imports),
 		Exports:     templateExports(template.Spec.Exports),
 		Health:      health,
 		Develop:     cloneMap(template.Spec.Develop),
+		Notes:       template.Metadata.Notes,
 	}, nil
 }
 
@@ -646,7 +938,7 @@ func templateVolumes(values []catalog.TemplateVolume) []workspace.Volume {
 	}
 	volumes := make([]workspace.Volume, len(values))
 	for i := range values {
-		volumes[i] = workspace.Volume{Source: values[i].Source, Target: values[i].Target, ReadOnly: values[i].ReadOnly, Kind: values[i].Kind}
+		volumes[i] = workspace.Volume{Source: values[i].Source, Target: values[i].Target, ReadOnly: values[i].ReadOnly, Kind: values[i].Kind, Options: append([]string(nil), values[i].Options...)}
 	}
 	return volumes
 }
@@ -748,6 +1040,14 @@ func ensureApplyCapabilities(workspaceName, provider string, capabilities runtim
 	return nil
 }
 
+// checkWritable rejects operation when the service runs read-only.
+func (s *Service) checkWritable(operation string) error {
+	if !s.readOnly {
+		return nil
+	}
+	return &ReadOnlyError{Operation: operation, Reason: s.readOnlyReason}
+}
+
 func unsupportedCapability(workspaceName, resourceName, provider, operation, capability, reason string) error {
 	return &UnsupportedCapabilityError{
 		Workspace:  workspaceName,
@@ -0,0 +1,263 @@
+package appsvc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/prospect-ogujiuba/devarch/internal/catalog"
+	"github.com/prospect-ogujiuba/devarch/internal/projectscan"
+	"github.com/prospect-ogujiuba/devarch/internal/workspace"
+)
+
+const suggestedManifestName = "devarch.workspace.yaml"
+
+// stackRole is one part of a suggested stack. Templates are tried in order,
+// so a recipe can ask for mysql and settle for postgres when the catalog
+// has no mysql template.
+type stackRole struct {
+	Key       string
+	Role      string
+	Templates []string
+	DependsOn []string
+}
+
+// stackRecipes are the stacks suggested per framework family.
+var stackRecipes = map[string][]stackRole{
+	"laravel": {
+		{Key: "app", Role: "app", Templates: []string{"php-fpm", "laravel-app"}, DependsOn: []string{"db", "cache"}},
+		{Key: "web", Role: "proxy", Templates: []string{"nginx"}, DependsOn: []string{"app"}},
+		{Key: "db", Role: "database", Templates: []string{"mysql", "mariadb", "postgres"}},
+		{Key: "cache", Role: "cache", Templates: []string{"redis"}},
+	},
+	"wordpress": {
+		{Key: "app", Role: "app", Templates: []string{"wordpress"}, DependsOn: []string{"db"}},
+		{Key: "db", Role: "database", Templates: []string{"mysql", "mariadb"}},
+	},
+	"node-fullstack": {
+		{Key: "app", Role: "app", Templates: []string{"node-api"}, DependsOn: []string{"db"}},
+		{Key: "db", Role: "database", Templates: []string{"postgres"}},
+	},
+	"node-api": {
+		{Key: "app", Role: "app", Templates: []string{"node-api"}, DependsOn: []string{"db"}},
+		{Key: "db", Role: "database", Templates: []string{"postgres"}},
+	},
+	"frontend": {
+		{Key: "web", Role: "app", Templates: []string{"vite-web"}},
+	},
+	"go": {
+		{Key: "app", Role: "app", Templates: []string{"go-api"}, DependsOn: []string{"db"}},
+		{Key: "db", Role: "database", Templates: []string{"postgres"}},
+	},
+}
+
+// composeRoles add a role for each kind of compose service a project
+// already runs, when its recipe does not cover it.
+var composeRoles = map[string]stackRole{
+	"database": {Key: "db", Role: "database", Templates: []string{"postgres"}},
+	"cache":    {Key: "cache", Role: "cache", Templates: []string{"redis"}},
+	"proxy":    {Key: "web", Role: "proxy", Templates: []string{"nginx"}, DependsOn: []string{"app"}},
+}
+
+// ProjectSuggestions recommends a stack for the project at path from its
+// scan, mapped onto the templates in the configured catalogs, and renders
+// it as a workspace manifest. With request.Write the manifest is written to
+// devarch.workspace.yaml in the project, which must not exist yet.
+func (s *Service) ProjectSuggestions(_ context.Context, path string, request ProjectSuggestionRequest) (*ProjectSuggestionView, error) {
+	if request.Write {
+		if err := s.checkWritable("suggest"); err != nil {
+			return nil, err
+		}
+	}
+	scan, err := projectscan.Scan(path)
+	if err != nil {
+		return nil, err
+	}
+	index, err := LoadCatalogIndex(s.catalogRoots)
+	if err != nil {
+		return nil, err
+	}
+	recipe, roles := suggestedStack(scan)
+	view := &ProjectSuggestionView{Project: scan.Name, Framework: scan.Framework, Recipe: recipe, Resources: []SuggestedResource{}}
+	for _, role := range roles {
+		suggested, ok := resolveStackRole(index, role, s.team)
+		if !ok {
+			view.Missing = append(view.Missing, fmt.Sprintf("%s (%s)", role.Role, role.Templates[0]))
+			continue
+		}
+		if role.Role == "app" && scan.NodeVersion != "" {
+			template, _ := index.ByName(suggested.Template)
+			image, _ := template.Spec.Runtime["image"].(string)
+			tag, note := suggestedNodeImageTag(image, scan.NodeVersion, scan.NodeVersionSource)
+			suggested.ImageTag = tag
+			suggested.Note = strings.TrimPrefix(suggested.Note+"; "+note, "; ")
+		}
+		view.Resources = append(view.Resources, suggested)
+	}
+	if len(view.Resources) == 0 {
+		return view, nil
+	}
+
+	name := request.Name
+	if name == "" {
+		name = adoptedResourceKey(scan.Name) + "-local"
+	}
+	manifest, err := suggestedManifest(name, s.team, s.catalogRoots, view.Resources)
+	if err != nil {
+		return nil, err
+	}
+	view.Workspace, view.Manifest = name, manifest
+	if !request.Write {
+		return view, nil
+	}
+	target := filepath.Join(scan.Path, suggestedManifestName)
+	if _, err := os.Stat(target); err == nil {
+		return nil, fmt.Errorf("%s already exists", target)
+	}
+	if err := os.WriteFile(target, []byte(manifest), 0o644); err != nil {
+		return nil, fmt.Errorf("write %s: %w", target, err)
+	}
+	view.Written = target
+	return view, nil
+}
+
+// suggestedStack picks the recipe for scan and adds roles for compose
+// services the recipe does not cover.
+func suggestedStack(scan *projectscan.Result) (string, []stackRole) {
+	recipe := ""
+	switch scan.ProjectType {
+	case "laravel", "wordpress", "go":
+		recipe = scan.ProjectType
+	case "node":
+		switch scan.Framework {
+		case "Next.js", "Nuxt", "Remix":
+			recipe = "node-fullstack"
+		case "Express", "Fastify", "NestJS", "Hono":
+			recipe = "node-api"
+		default:
+			recipe = "node-api"
+			if scan.HasFrontend {
+				recipe = "frontend"
+			}
+		}
+	}
+	roles := append([]stackRole(nil), stackRecipes[recipe]...)
+	for _, service := range scan.Services {
+		extra, ok := composeRoles[service.ServiceType]
+		if !ok {
+			continue
+		}
+		covered := false
+		for _, role := range roles {
+			covered = covered || role.Role == extra.Role
+		}
+		if !covered {
+			roles = append(roles, extra)
+		}
+	}
+	return recipe, roles
+}
+
+// resolveStackRole picks the first of the role's templates that is in the
+// catalog and visible to team, which the suggested workspace belongs to.
+func resolveStackRole(index *catalog.Index, role stackRole, team string) (SuggestedResource, bool) {
+	for _, name := range role.Templates {
+		if template, ok := index.ByName(name); !ok || !template.Metadata.VisibleTo(team) {
+			continue
+		}
+		suggested := SuggestedResource{Key: role.Key, Role: role.Role, Template: name, DependsOn: role.DependsOn}
+		if name != role.Templates[0] {
+			suggested.Note = fmt.Sprintf("no %s template in the catalog; using %s", role.Templates[0], name)
+		}
+		return suggested, true
+	}
+	return SuggestedResource{}, false
+}
+
+// suggestedNodeImageTag returns the tag to swap onto a node image so it runs
+// the major the project's Node.js constraint asks for, keeping the variant
+// suffix, so node:22-alpine becomes node:20-alpine. It returns an empty tag
+// when the template image is not node or already matches, and a note
+// saying what was decided.
+func suggestedNodeImageTag(image, constraint, source string) (string, string) {
+	name, tag, _ := strings.Cut(path.Base(image), ":")
+	if name != "node" {
+		return "", ""
+	}
+	major, ok := projectscan.NodeMajor(constraint)
+	if !ok {
+		return "", fmt.Sprintf("no node release satisfies %s %q", source, constraint)
+	}
+	version, variant, _ := strings.Cut(tag, "-")
+	current, err := strconv.Atoi(strings.SplitN(version, ".", 2)[0])
+	if err == nil && projectscan.NodeSatisfies(constraint, current) {
+		return "", ""
+	}
+	if err != nil {
+		variant = strings.TrimPrefix(strings.TrimPrefix(tag, "latest"), "lts")
+		variant = strings.TrimPrefix(variant, "-")
+	}
+	suggested := strconv.Itoa(major)
+	if variant != "" {
+		suggested += "-" + variant
+	}
+	return suggested, fmt.Sprintf("node %s from %s", suggested, source)
+}
+
+// suggestedManifest renders the suggested resources as a workspace. The
+// app resource is built from the project directory, and dependencies on
+// resources that were not suggested are dropped.
+func suggestedManifest(name, team string, catalogRoots []string, resources []SuggestedResource) (string, error) {
+	ws := workspace.Workspace{
+		APIVersion: "devarch.io/alpha1",
+		Kind:       "Workspace",
+		Metadata:   workspace.Metadata{Name: name, Team: team},
+		Runtime:    workspace.RuntimePreferences{Provider: "podman", IsolatedNetwork: true, NamingStrategy: "workspace-resource"},
+		Resources:  map[string]*workspace.Resource{},
+	}
+	for _, root := range catalogRoots {
+		absolute, err := filepath.Abs(root)
+		if err != nil {
+			return "", err
+		}
+		ws.Catalog.Sources = append(ws.Catalog.Sources, filepath.ToSlash(absolute))
+	}
+	keys := map[string]bool{}
+	for _, resource := range resources {
+		keys[resource.Key] = true
+	}
+	for i := range resources {
+		resource := &workspace.Resource{Template: resources[i].Template}
+		if resources[i].Role == "app" {
+			resource.Source = &workspace.Source{Type: "project", Path: "."}
+		}
+		if resources[i].ImageTag != "" {
+			resource.Overrides = map[string]any{"imageTag": resources[i].ImageTag}
+		}
+		var dependsOn []string
+		for _, dependency := range resources[i].DependsOn {
+			if keys[dependency] {
+				dependsOn = append(dependsOn, dependency)
+			}
+		}
+		resources[i].DependsOn, resource.DependsOn = dependsOn, dependsOn
+		ws.Resources[resources[i].Key] = resource
+	}
+	var encoded bytes.Buffer
+	encoder := yaml.NewEncoder(&encoded)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&ws); err != nil {
+		return "", fmt.Errorf("encode suggested workspace: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("encode suggested workspace: %w", err)
+	}
+	return encoded.String(), nil
+}
@@ -0,0 +1,101 @@
+package appsvc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+)
+
+// WorkspaceTopology returns the workspace as nodes and edges. Status comes
+// from a runtime inspect when one is available; otherwise the desired shape is
+// returned with a warning, the same way WorkspacePlan degrades.
+func (s *Service) WorkspaceTopology(ctx context.Context, name string) (*WorkspaceTopologyView, error) {
+	state, err := s.loadWorkspaceState(name)
+	if err != nil {
+		return nil, err
+	}
+	adapter, provider, capabilities := s.planProvider(state.Desired.Provider)
+	state.Desired.Provider = provider
+
+	var snapshot *runtimepkg.Snapshot
+	var warning *runtimepkg.Diagnostic
+	if adapter == nil {
+		warning = inspectWarning(name, provider, fmt.Sprintf("runtime provider %q is unavailable; topology has no runtime status", provider))
+	} else if !capabilities.Inspect {
+		warning = inspectWarning(name, provider, fmt.Sprintf("runtime provider %q does not support inspection; topology has no runtime status", provider))
+	} else if snapshot, err = adapter.InspectWorkspace(ctx, state.Desired); err != nil {
+		warning = inspectWarning(name, provider, fmt.Sprintf("runtime inspection failed; topology has no runtime status: %v", err))
+		snapshot = nil
+	} else {
+		s.saveSnapshot(ctx, state.Desired.Name, snapshot)
+	}
+
+	view := buildTopology(state.Desired, snapshot)
+	if warning != nil {
+		view.Diagnostics = append(view.Diagnostics, *warning)
+	}
+	return view, nil
+}
+
+func buildTopology(desired *runtimepkg.DesiredWorkspace, snapshot *runtimepkg.Snapshot) *WorkspaceTopologyView {
+	view := &WorkspaceTopologyView{Workspace: desired.Name, Provider: desired.Provider, Nodes: []TopologyNode{}, Edges: []TopologyEdge{}}
+	networkID := ""
+	if desired.Network != nil {
+		networkID = "network:" + desired.Network.Name
+		view.Nodes = append(view.Nodes, TopologyNode{ID: networkID, Kind: "network", Label: desired.Network.Name, Enabled: true})
+	}
+
+	mounts := map[string][]string{}
+	for _, resource := range desired.Resources {
+		if resource == nil {
+			continue
+		}
+		node := TopologyNode{
+			ID:          resource.Key,
+			Kind:        "resource",
+			Label:       resource.Key,
+			RuntimeName: resource.RuntimeName,
+			Image:       resource.Spec.Image,
+			Ports:       append([]runtimepkg.PortSpec(nil), resource.Spec.Ports...),
+			Enabled:     resource.Enabled,
+		}
+		if observed := snapshot.Resource(resource.Key); observed != nil {
+			node.Status = observed.State.Status
+			node.Health = observed.State.Health
+		} else if snapshot != nil {
+			node.Status = "absent"
+		}
+		view.Nodes = append(view.Nodes, node)
+
+		for _, dependency := range resource.DependsOn {
+			view.Edges = append(view.Edges, TopologyEdge{Source: resource.Key, Target: dependency, Kind: "dependsOn"})
+		}
+		for _, volume := range resource.Spec.Volumes {
+			if volume.Source != "" {
+				mounts[volume.Source] = append(mounts[volume.Source], resource.Key)
+			}
+		}
+		if networkID != "" {
+			view.Edges = append(view.Edges, TopologyEdge{Source: resource.Key, Target: networkID, Kind: "network"})
+		}
+	}
+
+	sources := make([]string, 0, len(mounts))
+	for source := range mounts {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	for _, source := range sources {
+		keys := mounts[source]
+		for i := 0; i < len(keys); i++ {
+			for j := i + 1; j < len(keys); j++ {
+				if keys[i] != keys[j] {
+					view.Edges = append(view.Edges, TopologyEdge{Source: keys[i], Target: keys[j], Kind: "volume", Label: source})
+				}
+			}
+		}
+	}
+	return view
+}
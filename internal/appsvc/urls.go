@@ -0,0 +1,112 @@
+package appsvc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+)
+
+// URLCatalog lists a URL for every published TCP port of every enabled
+// resource across the discovered workspaces, with the resource's observed
+// status and health, so a landing page can link every UI in the dev
+// environment. Database engine ports are left out, since they are not
+// browsable; workspace connect covers them. Unless request.All is set, only
+// running resources are listed. A workspace that cannot be loaded or
+// inspected is reported in Errors and does not fail the catalog.
+func (s *Service) URLCatalog(ctx context.Context, request URLCatalogRequest) (*URLCatalogView, error) {
+	workspaces, err := s.discoverWorkspaces()
+	if err != nil {
+		return nil, err
+	}
+	view := &URLCatalogView{Entries: []URLEntry{}}
+	for _, ws := range workspaces {
+		name := ws.Metadata.Name
+		state, err := s.loadWorkspaceState(name)
+		if err != nil {
+			view.Errors = append(view.Errors, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		observed := map[string]*runtimepkg.SnapshotResource{}
+		adapter, provider, capabilities := s.planProvider(state.Desired.Provider)
+		if adapter == nil || !capabilities.Inspect {
+			view.Errors = append(view.Errors, fmt.Sprintf("%s: runtime provider %q cannot be inspected", name, provider))
+		} else if snapshot, err := adapter.InspectWorkspace(ctx, state.Desired); err != nil {
+			view.Errors = append(view.Errors, fmt.Sprintf("%s: runtime inspection failed: %v", name, err))
+		} else {
+			s.saveSnapshot(ctx, state.Desired.Name, snapshot)
+			for _, resource := range snapshot.Resources {
+				if resource != nil {
+					observed[resource.Key] = resource
+				}
+			}
+		}
+		view.Entries = append(view.Entries, urlEntries(state.Desired, ws.Metadata.DisplayName, observed, request.All)...)
+	}
+	sort.SliceStable(view.Entries, func(i, j int) bool {
+		if view.Entries[i].Workspace != view.Entries[j].Workspace {
+			return view.Entries[i].Workspace < view.Entries[j].Workspace
+		}
+		if view.Entries[i].Resource != view.Entries[j].Resource {
+			return view.Entries[i].Resource < view.Entries[j].Resource
+		}
+		return view.Entries[i].Port < view.Entries[j].Port
+	})
+	return view, nil
+}
+
+// urlEntries builds the catalog entries of one workspace. A port without a
+// fixed host port takes the one the runtime assigned, if observed.
+func urlEntries(desired *runtimepkg.DesiredWorkspace, displayName string, observed map[string]*runtimepkg.SnapshotResource, all bool) []URLEntry {
+	var entries []URLEntry
+	for _, resource := range desired.Resources {
+		if resource == nil || !resource.Enabled {
+			continue
+		}
+		status, health, running := "absent", "", false
+		current := observed[resource.Key]
+		if current != nil {
+			status, health, running = firstNonEmpty(current.State.Status, "unknown"), current.State.Health, current.State.Running
+		}
+		if !running && !all {
+			continue
+		}
+		engine := databaseEngine(resource)
+		for _, port := range resource.Spec.Ports {
+			if (port.Protocol != "" && port.Protocol != "tcp") || (engine != "" && databasePorts[engine] == port.Container) {
+				continue
+			}
+			published := port.Published
+			if published == 0 && current != nil {
+				for _, assigned := range current.Spec.Ports {
+					if assigned.Container == port.Container {
+						published = assigned.Published
+					}
+				}
+			}
+			if published == 0 {
+				continue
+			}
+			scheme := "http"
+			if port.Container == 443 || port.Container == 8443 {
+				scheme = "https"
+			}
+			label := resource.Key
+			if resource.TemplateName != "" && resource.TemplateName != resource.Key {
+				label += " (" + resource.TemplateName + ")"
+			}
+			entries = append(entries, URLEntry{
+				Workspace:   desired.Name,
+				DisplayName: displayName,
+				Resource:    resource.Key,
+				Label:       label,
+				Port:        port.Container,
+				URL:         fmt.Sprintf("%s://localhost:%d", scheme, published),
+				Status:      status,
+				Health:      health,
+			})
+		}
+	}
+	return entries
+}
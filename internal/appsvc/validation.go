@@ -0,0 +1,120 @@
+package appsvc
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+	"github.com/prospect-ogujiuba/devarch/internal/workspace"
+)
+
+const (
+	validationValid   = "valid"
+	validationWarning = "warning"
+	validationBlocked = "blocked"
+	validationInvalid = "invalid"
+
+	defaultValidationRecent = 5
+)
+
+// ValidateWorkspaces re-validates every workspace manifest in roots, or
+// only those of team when it is set: each is loaded, resolved against its
+// catalog, and built into its desired runtime state, without touching the
+// runtime. Unlike DiscoverWorkspaces, and so unlike New, a broken manifest
+// does not stop the run; it is reported as invalid. A workspace whose plan
+// would carry apply-blocking diagnostics is blocked. RecentlyBroken lists
+// the invalid and blocked ones, most recently edited first, up to
+// request.Recent (default 5).
+func ValidateWorkspaces(roots []string, team string, request ValidationRequest) (*ValidationSummaryView, error) {
+	if request.Recent < 0 {
+		return nil, fmt.Errorf("validation recent count must not be negative")
+	}
+	if request.Recent == 0 {
+		request.Recent = defaultValidationRecent
+	}
+	manifestPaths, err := discoverWorkspaceManifestPaths(roots)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := discoverTeams(roots)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &ValidationSummaryView{
+		CheckedAt:      time.Now(),
+		Counts:         map[string]int{validationValid: 0, validationWarning: 0, validationBlocked: 0, validationInvalid: 0},
+		Workspaces:     []WorkspaceValidation{},
+		RecentlyBroken: []WorkspaceValidation{},
+	}
+	seenByName := map[string]string{}
+	for _, manifestPath := range manifestPaths {
+		entry := WorkspaceValidation{Path: manifestPath}
+		if info, err := os.Stat(manifestPath); err == nil {
+			entry.ModifiedAt = info.ModTime()
+		}
+		ws, err := workspace.Load(manifestPath)
+		if err == nil {
+			if team != "" && ws.Metadata.Team != team {
+				continue
+			}
+			entry.Workspace = ws.Metadata.Name
+			if firstPath, ok := seenByName[ws.Metadata.Name]; ok {
+				err = &DuplicateWorkspaceNameError{Name: ws.Metadata.Name, FirstPath: firstPath, SecondPath: ws.ManifestPath}
+			}
+			seenByName[ws.Metadata.Name] = ws.ManifestPath
+		}
+		if err == nil {
+			if ws.Metadata.Team != "" {
+				ws.ApplyTeamDefaults(teams[ws.Metadata.Team])
+			}
+			entry = validateWorkspace(entry, ws)
+		} else {
+			entry.Status, entry.Problems = validationInvalid, []string{err.Error()}
+		}
+		view.Counts[entry.Status]++
+		view.Workspaces = append(view.Workspaces, entry)
+	}
+
+	for _, entry := range view.Workspaces {
+		if entry.Status == validationInvalid || entry.Status == validationBlocked {
+			view.RecentlyBroken = append(view.RecentlyBroken, entry)
+		}
+	}
+	sort.SliceStable(view.RecentlyBroken, func(i, j int) bool {
+		return view.RecentlyBroken[i].ModifiedAt.After(view.RecentlyBroken[j].ModifiedAt)
+	})
+	if len(view.RecentlyBroken) > request.Recent {
+		view.RecentlyBroken = view.RecentlyBroken[:request.Recent]
+	}
+	return view, nil
+}
+
+// validateWorkspace fills the status and problems of one loaded workspace
+// from the diagnostics its desired state carries.
+func validateWorkspace(entry WorkspaceValidation, ws *workspace.Workspace) WorkspaceValidation {
+	state, err := buildWorkspaceState(ws)
+	if err != nil {
+		entry.Status, entry.Problems = validationInvalid, []string{err.Error()}
+		return entry
+	}
+	diagnostics := append([]runtimepkg.Diagnostic(nil), state.Desired.Diagnostics...)
+	for _, resource := range state.Desired.Resources {
+		if resource != nil {
+			diagnostics = append(diagnostics, resource.Diagnostics...)
+		}
+	}
+	entry.Status = validationValid
+	for _, diagnostic := range diagnostics {
+		entry.Problems = append(entry.Problems, diagnostic.Severity+": "+diagnostic.Message)
+		switch {
+		case diagnostic.BlocksApply():
+			entry.Status = validationBlocked
+		case entry.Status == validationValid:
+			entry.Status = validationWarning
+		}
+	}
+	return entry
+}
@@ -1,2 +1,4 @@
-// Package cache is reserved for optional runtime cache and history boundaries owned by surgeon-runtime.
+// Package cache holds the runtime cache and history boundaries owned by
+// surgeon-runtime: the Store interface, a no-op store, and a file store that
+// keeps history under the user state directory.
 package cache
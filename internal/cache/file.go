@@ -0,0 +1,206 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	applyHistoryFile     = "applies.jsonl"
+	scriptRunHistoryFile = "script-runs.jsonl"
+	queryHistoryFile     = "queries.jsonl"
+	snapshotDir          = "snapshots"
+)
+
+// FileStore keeps history as JSON lines under one directory, one file per
+// record kind, and the latest snapshot of each workspace as a JSON file.
+// Records are only appended, so a history survives across CLI runs. The
+// directory is not touched until the first record is written; reads of a
+// missing directory return no history.
+type FileStore struct {
+	dir    string
+	create bool
+	mu     sync.Mutex
+}
+
+// NewFileStore returns a store under dir, which is created when the first
+// record is written.
+func NewFileStore(dir string) (*FileStore, error) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		return nil, fmt.Errorf("cache directory is required")
+	}
+	return &FileStore{dir: dir, create: true}, nil
+}
+
+// OpenFileStore returns a store under dir that never creates it, for runs
+// that must not write new files: records are kept only when the directory
+// already exists.
+func OpenFileStore(dir string) (*FileStore, error) {
+	store, err := NewFileStore(dir)
+	if err != nil {
+		return nil, err
+	}
+	store.create = false
+	return store, nil
+}
+
+// DefaultDir returns the user state directory devarch keeps its history
+// in: $XDG_STATE_HOME/devarch, or ~/.local/state/devarch.
+func DefaultDir() (string, error) {
+	if state := strings.TrimSpace(os.Getenv("XDG_STATE_HOME")); filepath.IsAbs(state) {
+		return filepath.Join(state, "devarch"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "state", "devarch"), nil
+}
+
+// Dir returns the directory the store writes to.
+func (s *FileStore) Dir() string { return s.dir }
+
+func (s *FileStore) SaveSnapshot(_ context.Context, record SnapshotRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.ensureDir(snapshotDir); err != nil {
+		return err
+	}
+	path := s.snapshotPath(record.Workspace)
+	temp := path + ".tmp"
+	if err := os.WriteFile(temp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(temp, path)
+}
+
+func (s *FileStore) LatestSnapshot(_ context.Context, workspace string) (*SnapshotRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := os.ReadFile(s.snapshotPath(workspace))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var record SnapshotRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("read snapshot of workspace %q: %w", workspace, err)
+	}
+	return &record, nil
+}
+
+func (s *FileStore) SaveApply(_ context.Context, record ApplyRecord) error {
+	return s.appendRecord(applyHistoryFile, record)
+}
+
+func (s *FileStore) ApplyHistory(_ context.Context, workspace string, limit int) ([]ApplyRecord, error) {
+	return readHistory(s, applyHistoryFile, limit, func(record ApplyRecord) bool { return record.Workspace == workspace })
+}
+
+func (s *FileStore) SaveScriptRun(_ context.Context, record ScriptRunRecord) error {
+	return s.appendRecord(scriptRunHistoryFile, record)
+}
+
+func (s *FileStore) ScriptRunHistory(_ context.Context, project string, limit int) ([]ScriptRunRecord, error) {
+	return readHistory(s, scriptRunHistoryFile, limit, func(record ScriptRunRecord) bool { return record.Project == project })
+}
+
+func (s *FileStore) SaveQuery(_ context.Context, record QueryRecord) error {
+	return s.appendRecord(queryHistoryFile, record)
+}
+
+func (s *FileStore) QueryHistory(_ context.Context, workspace string, limit int) ([]QueryRecord, error) {
+	return readHistory(s, queryHistoryFile, limit, func(record QueryRecord) bool { return record.Workspace == workspace })
+}
+
+func (s *FileStore) Close() error { return nil }
+
+func (s *FileStore) snapshotPath(workspace string) string {
+	return filepath.Join(s.dir, snapshotDir, url.PathEscape(workspace)+".json")
+}
+
+// ensureDir makes sub of the store directory ready for a write, creating it
+// only when the store may create directories. The caller holds s.mu.
+func (s *FileStore) ensureDir(sub string) error {
+	path := filepath.Join(s.dir, sub)
+	if !s.create {
+		if _, err := os.Stat(s.dir); err != nil {
+			return fmt.Errorf("cache directory %s: %w", s.dir, err)
+		}
+	}
+	if err := os.MkdirAll(path, 0o700); err != nil {
+		return fmt.Errorf("create cache directory: %w", err)
+	}
+	return nil
+}
+
+func (s *FileStore) appendRecord(name string, record any) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.ensureDir("."); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(filepath.Join(s.dir, name), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// readHistory returns the records of one history file that match keep,
+// newest first and at most limit of them when limit is positive. Lines
+// that do not decode, such as one cut short by a crash, are skipped.
+func readHistory[T any](s *FileStore, name string, limit int, keep func(T) bool) ([]T, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := os.Open(filepath.Join(s.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var records []T
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var record T
+		if json.Unmarshal(scanner.Bytes(), &record) != nil || !keep(record) {
+			continue
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", name, err)
+	}
+	slices.Reverse(records)
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+	return records, nil
+}
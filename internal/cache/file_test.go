@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+)
+
+func TestFileStoreKeepsHistoryAcrossInstances(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "state")
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore returned error: %v", err)
+	}
+	started := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	for i, workspace := range []string{"shop", "blog", "shop"} {
+		if err := store.SaveApply(ctx, ApplyRecord{Workspace: workspace, Actor: "alice", StartedAt: started.Add(time.Duration(i) * time.Minute), Succeeded: i != 2}); err != nil {
+			t.Fatalf("SaveApply returned error: %v", err)
+		}
+	}
+	if err := store.SaveQuery(ctx, QueryRecord{Workspace: "shop", Actor: "bob", Query: "select 1"}); err != nil {
+		t.Fatalf("SaveQuery returned error: %v", err)
+	}
+	if err := store.SaveScriptRun(ctx, ScriptRunRecord{Project: "/src/api", Script: "test", ExitCode: 1}); err != nil {
+		t.Fatalf("SaveScriptRun returned error: %v", err)
+	}
+	if err := store.SaveSnapshot(ctx, SnapshotRecord{Workspace: "shop", CapturedAt: started, Snapshot: &runtimepkg.Snapshot{Workspace: runtimepkg.SnapshotWorkspace{Name: "shop"}}}); err != nil {
+		t.Fatalf("SaveSnapshot returned error: %v", err)
+	}
+	// A line cut short by a crash is skipped, not fatal.
+	file, err := os.OpenFile(filepath.Join(dir, applyHistoryFile), os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("open apply history: %v", err)
+	}
+	file.WriteString(`{"workspace":"shop","star`)
+	file.Close()
+
+	reopened, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore returned error: %v", err)
+	}
+	applies, err := reopened.ApplyHistory(ctx, "shop", 0)
+	if err != nil {
+		t.Fatalf("ApplyHistory returned error: %v", err)
+	}
+	if len(applies) != 2 || applies[0].Succeeded || !applies[1].Succeeded || applies[0].Actor != "alice" {
+		t.Fatalf("apply history = %+v, want the two shop applies newest first", applies)
+	}
+	if limited, _ := reopened.ApplyHistory(ctx, "shop", 1); len(limited) != 1 || limited[0].Succeeded {
+		t.Fatalf("limited apply history = %+v, want only the newest", limited)
+	}
+	if queries, _ := reopened.QueryHistory(ctx, "shop", 10); len(queries) != 1 || queries[0].Actor != "bob" {
+		t.Fatalf("query history = %+v", queries)
+	}
+	if runs, _ := reopened.ScriptRunHistory(ctx, "/src/api", 10); len(runs) != 1 || runs[0].ExitCode != 1 {
+		t.Fatalf("script run history = %+v", runs)
+	}
+	snapshot, err := reopened.LatestSnapshot(ctx, "shop")
+	if err != nil || snapshot == nil || snapshot.Snapshot.Workspace.Name != "shop" {
+		t.Fatalf("LatestSnapshot = %+v, %v", snapshot, err)
+	}
+	if missing, err := reopened.LatestSnapshot(ctx, "blog"); missing != nil || err != nil {
+		t.Fatalf("LatestSnapshot of a workspace never captured = %+v, %v; want nil", missing, err)
+	}
+}
+
+func TestFileStoreCreatesItsDirectoryOnlyOnWrite(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "state")
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore returned error: %v", err)
+	}
+	if applies, err := store.ApplyHistory(ctx, "shop", 0); err != nil || applies != nil {
+		t.Fatalf("ApplyHistory = %+v, %v; want no history", applies, err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("stat before the first record = %v, want the directory missing", err)
+	}
+
+	opened, err := OpenFileStore(dir)
+	if err != nil {
+		t.Fatalf("OpenFileStore returned error: %v", err)
+	}
+	if err := opened.SaveQuery(ctx, QueryRecord{Workspace: "shop", Query: "select 1"}); err == nil {
+		t.Fatal("OpenFileStore wrote into a missing directory")
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("stat after OpenFileStore = %v, want the directory still missing", err)
+	}
+
+	if err := store.SaveQuery(ctx, QueryRecord{Workspace: "shop", Query: "select 1"}); err != nil {
+		t.Fatalf("SaveQuery returned error: %v", err)
+	}
+	if err := opened.SaveQuery(ctx, QueryRecord{Workspace: "shop", Query: "select 2"}); err != nil {
+		t.Fatalf("SaveQuery into an existing directory returned error: %v", err)
+	}
+	if queries, _ := opened.QueryHistory(ctx, "shop", 0); len(queries) != 2 {
+		t.Fatalf("query history = %+v, want both queries", queries)
+	}
+}
+
+func TestDefaultDirFollowsXDGStateHome(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/var/state")
+	if dir, err := DefaultDir(); err != nil || dir != "/var/state/devarch" {
+		t.Fatalf("DefaultDir = %q, %v; want /var/state/devarch", dir, err)
+	}
+	t.Setenv("XDG_STATE_HOME", "relative")
+	t.Setenv("HOME", "/home/alice")
+	if dir, err := DefaultDir(); err != nil || dir != "/home/alice/.local/state/devarch" {
+		t.Fatalf("DefaultDir = %q, %v; want the home fallback", dir, err)
+	}
+}
@@ -12,6 +12,10 @@ type Store interface {
 	LatestSnapshot(ctx context.Context, workspace string) (*SnapshotRecord, error)
 	SaveApply(ctx context.Context, record ApplyRecord) error
 	ApplyHistory(ctx context.Context, workspace string, limit int) ([]ApplyRecord, error)
+	SaveScriptRun(ctx context.Context, record ScriptRunRecord) error
+	ScriptRunHistory(ctx context.Context, project string, limit int) ([]ScriptRunRecord, error)
+	SaveQuery(ctx context.Context, record QueryRecord) error
+	QueryHistory(ctx context.Context, workspace string, limit int) ([]QueryRecord, error)
 	Close() error
 }
 
@@ -21,8 +25,11 @@ type SnapshotRecord struct {
 	Snapshot   *runtimepkg.Snapshot `json:"snapshot"`
 }
 
+// ApplyRecord is one apply. Actor, here and in the other records, is who
+// ran the command.
 type ApplyRecord struct {
 	Workspace  string            `json:"workspace"`
+	Actor      string            `json:"actor,omitempty"`
 	Provider   string            `json:"provider,omitempty"`
 	StartedAt  time.Time         `json:"startedAt"`
 	FinishedAt time.Time         `json:"finishedAt"`
@@ -37,6 +44,53 @@ type OperationRecord struct {
 	Kind        string `json:"kind"`
 	Status      string `json:"status"`
 	Message     string `json:"message,omitempty"`
+
+	Timing *OperationTiming `json:"timing,omitempty"`
+}
+
+// OperationTiming breaks down one resource operation of an apply, in
+// milliseconds from StartedAt. PullMs is set when the image had to be pulled.
+// CreateMs covers creating and starting the container, or restarting it.
+// HealthyMs is measured from the end of create and is only set when apply
+// waited for health; Health is the last health status it observed.
+type OperationTiming struct {
+	StartedAt time.Time `json:"startedAt"`
+	PullMs    int64     `json:"pullMs,omitempty"`
+	CreateMs  int64     `json:"createMs"`
+	HealthyMs int64     `json:"healthyMs,omitempty"`
+	Health    string    `json:"health,omitempty"`
+	TotalMs   int64     `json:"totalMs"`
+	// Image is "cached" when the image was already present and "pulled"
+	// when apply fetched it. It is empty when the runtime does not pull.
+	Image string `json:"image,omitempty"`
+}
+
+// ScriptRunRecord is one project script run. Project is the absolute
+// project path.
+type ScriptRunRecord struct {
+	Project    string    `json:"project"`
+	Actor      string    `json:"actor,omitempty"`
+	Script     string    `json:"script"`
+	Command    []string  `json:"command"`
+	Image      string    `json:"image"`
+	StartedAt  time.Time `json:"startedAt"`
+	DurationMs int64     `json:"durationMs"`
+	ExitCode   int       `json:"exitCode"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// QueryRecord is one ad-hoc database query, kept as an audit trail. Error
+// is set when the query was refused or failed.
+type QueryRecord struct {
+	Workspace  string    `json:"workspace"`
+	Actor      string    `json:"actor,omitempty"`
+	Resource   string    `json:"resource"`
+	Engine     string    `json:"engine"`
+	Query      string    `json:"query"`
+	StartedAt  time.Time `json:"startedAt"`
+	DurationMs int64     `json:"durationMs"`
+	Rows       int       `json:"rows"`
+	Error      string    `json:"error,omitempty"`
 }
 
 type NopStore struct{}
@@ -56,4 +110,14 @@ func (NopStore) SaveApply(context.Context, ApplyRecord) error { return nil }
 
 func (NopStore) ApplyHistory(context.Context, string, int) ([]ApplyRecord, error) { return nil, nil }
 
+func (NopStore) SaveScriptRun(context.Context, ScriptRunRecord) error { return nil }
+
+func (NopStore) ScriptRunHistory(context.Context, string, int) ([]ScriptRunRecord, error) {
+	return nil, nil
+}
+
+func (NopStore) SaveQuery(context.Context, QueryRecord) error { return nil }
+
+func (NopStore) QueryHistory(context.Context, string, int) ([]QueryRecord, error) { return nil, nil }
+
 func (NopStore) Close() error { return nil }
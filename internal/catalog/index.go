@@ -2,8 +2,11 @@ package catalog
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"sort"
 
 	"github.com/prospect-ogujiuba/devarch/internal/spec"
@@ -23,17 +26,67 @@ type TemplateMetadata struct {
 	Name        string   `yaml:"name"`
 	Tags        []string `yaml:"tags,omitempty"`
 	Description string   `yaml:"description,omitempty"`
+	Notes       string   `yaml:"notes,omitempty"`
+	// Team owns the template. Visibility is global (the default) for every
+	// workspace, shared for Team and the SharedWith teams, or private for
+	// Team alone.
+	Team       string   `yaml:"team,omitempty"`
+	Visibility string   `yaml:"visibility,omitempty"`
+	SharedWith []string `yaml:"sharedWith,omitempty"`
+}
+
+// Template visibilities.
+const (
+	VisibilityGlobal  = "global"
+	VisibilityShared  = "shared"
+	VisibilityPrivate = "private"
+)
+
+// VisibleTo reports whether workspaces of team may use the template. A
+// workspace without a team only sees global templates.
+func (m TemplateMetadata) VisibleTo(team string) bool {
+	switch m.Visibility {
+	case "", VisibilityGlobal:
+		return true
+	case VisibilityShared:
+		return team != "" && (team == m.Team || slices.Contains(m.SharedWith, team))
+	default:
+		return team != "" && team == m.Team
+	}
 }
 
 type TemplateSpec struct {
-	Runtime map[string]any   `yaml:"runtime"`
-	Env     map[string]any   `yaml:"env,omitempty"`
-	Ports   []TemplatePort   `yaml:"ports,omitempty"`
-	Volumes []TemplateVolume `yaml:"volumes,omitempty"`
-	Imports []TemplateImport `yaml:"imports,omitempty"`
-	Exports []TemplateExport `yaml:"exports,omitempty"`
-	Health  map[string]any   `yaml:"health,omitempty"`
-	Develop map[string]any   `yaml:"develop,omitempty"`
+	Runtime map[string]any `yaml:"runtime"`
+	Env     map[string]any `yaml:"env,omitempty"`
+	// EnvSchema describes the env vars the template expects, whether or
+	// not it sets them in Env.
+	EnvSchema map[string]TemplateEnvVar `yaml:"envSchema,omitempty"`
+	Ports     []TemplatePort            `yaml:"ports,omitempty"`
+	Volumes   []TemplateVolume          `yaml:"volumes,omitempty"`
+	Imports   []TemplateImport          `yaml:"imports,omitempty"`
+	Exports   []TemplateExport          `yaml:"exports,omitempty"`
+	Health    map[string]any            `yaml:"health,omitempty"`
+	Develop   map[string]any            `yaml:"develop,omitempty"`
+}
+
+// TemplateEnvVar is the contract for one env var. A required var must end up
+// with a non-empty value; Default fills a var nothing else sets; Pattern is
+// a regular expression the whole value must match.
+type TemplateEnvVar struct {
+	Required    bool   `yaml:"required,omitempty" json:"required,omitempty"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	Default     string `yaml:"default,omitempty" json:"default,omitempty"`
+	Pattern     string `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+}
+
+// Match reports whether value satisfies Pattern. An invalid pattern
+// matches nothing; loadTemplate rejects those.
+func (v TemplateEnvVar) Match(value string) bool {
+	if v.Pattern == "" {
+		return true
+	}
+	pattern, err := regexp.Compile("^(?:" + v.Pattern + ")$")
+	return err == nil && pattern.MatchString(value)
 }
 
 type TemplatePort struct {
@@ -44,10 +97,11 @@ type TemplatePort struct {
 }
 
 type TemplateVolume struct {
-	Source   string `yaml:"source,omitempty"`
-	Target   string `yaml:"target"`
-	ReadOnly bool   `yaml:"readOnly,omitempty"`
-	Kind     string `yaml:"kind,omitempty"`
+	Source   string   `yaml:"source,omitempty"`
+	Target   string   `yaml:"target"`
+	ReadOnly bool     `yaml:"readOnly,omitempty"`
+	Kind     string   `yaml:"kind,omitempty"`
+	Options  []string `yaml:"options,omitempty"`
 }
 
 type TemplateImport struct {
@@ -169,6 +223,21 @@ func loadTemplate(path string) (*Template, error) {
 		return nil, fmt.Errorf("decode template %s: %w", path, err)
 	}
 	template.Path = filepath.Clean(path)
+	if visibility := template.Metadata.Visibility; visibility != "" && visibility != VisibilityGlobal && template.Metadata.Team == "" {
+		return nil, fmt.Errorf("validate template %s: metadata.team is required for %s visibility", path, visibility)
+	}
+	for _, key := range slices.Sorted(maps.Keys(template.Spec.EnvSchema)) {
+		envVar := template.Spec.EnvSchema[key]
+		if _, err := regexp.Compile(envVar.Pattern); err != nil {
+			return nil, fmt.Errorf("validate template %s: envSchema.%s.pattern: %w", path, key, err)
+		}
+		if envVar.Required && envVar.Default != "" {
+			return nil, fmt.Errorf("validate template %s: envSchema.%s: a required var cannot have a default", path, key)
+		}
+		if envVar.Default != "" && !envVar.Match(envVar.Default) {
+			return nil, fmt.Errorf("validate template %s: envSchema.%s: default %q does not match pattern %q", path, key, envVar.Default, envVar.Pattern)
+		}
+	}
 	return &template, nil
 }
 
@@ -121,6 +121,40 @@ spec:
 	}
 }
 
+func TestTemplateVisibility(t *testing.T) {
+	for _, tc := range []struct {
+		metadata TemplateMetadata
+		team     string
+		want     bool
+	}{
+		{TemplateMetadata{}, "", true},
+		{TemplateMetadata{Team: "payments", Visibility: VisibilityGlobal}, "search", true},
+		{TemplateMetadata{Team: "payments", Visibility: VisibilityPrivate}, "payments", true},
+		{TemplateMetadata{Team: "payments", Visibility: VisibilityPrivate}, "search", false},
+		{TemplateMetadata{Team: "payments", Visibility: VisibilityShared, SharedWith: []string{"billing"}}, "billing", true},
+		{TemplateMetadata{Team: "payments", Visibility: VisibilityShared, SharedWith: []string{"billing"}}, "search", false},
+		{TemplateMetadata{Team: "payments", Visibility: VisibilityShared}, "", false},
+	} {
+		if got := tc.metadata.VisibleTo(tc.team); got != tc.want {
+			t.Fatalf("%+v.VisibleTo(%q) = %t, want %t", tc.metadata, tc.team, got, tc.want)
+		}
+	}
+
+	root := t.TempDir()
+	path := writeCatalogFixture(t, filepath.Join(root, "ledger", TemplateFilename), `apiVersion: devarch.io/alpha1
+kind: Template
+metadata:
+  name: ledger
+  visibility: private
+spec:
+  runtime:
+    image: ledger:1
+`)
+	if _, err := LoadIndex([]string{path}); err == nil || !strings.Contains(err.Error(), "metadata.team is required") {
+		t.Fatalf("expected missing team error, got %v", err)
+	}
+}
+
 func TestLoadIndexRejectsInvalidTemplateDocuments(t *testing.T) {
 	root := t.TempDir()
 	invalidPath := writeCatalogFixture(t, filepath.Join(root, "backend", "broken", TemplateFilename), `apiVersion: devarch.io/alpha1
@@ -144,6 +178,41 @@ spec:
 	}
 }
 
+func TestLoadIndexValidatesEnvSchema(t *testing.T) {
+	root := t.TempDir()
+	template := func(name, envSchema string) string {
+		return writeCatalogFixture(t, filepath.Join(root, name, TemplateFilename), `apiVersion: devarch.io/alpha1
+kind: Template
+metadata:
+  name: `+name+`
+spec:
+  runtime:
+    image: example/`+name+`:1
+  envSchema:
+`+envSchema)
+	}
+	valid := template("valid", "    DB_PASSWORD:\n      required: true\n      description: Database password\n    LOG_LEVEL:\n      default: info\n      pattern: debug|info|warn\n")
+	index, err := LoadIndex([]string{valid})
+	if err != nil {
+		t.Fatalf("LoadIndex returned error: %v", err)
+	}
+	loaded, _ := index.ByName("valid")
+	logLevel := loaded.Spec.EnvSchema["LOG_LEVEL"]
+	if !loaded.Spec.EnvSchema["DB_PASSWORD"].Required || !logLevel.Match("warn") || logLevel.Match("warning") {
+		t.Fatalf("env schema = %#v", loaded.Spec.EnvSchema)
+	}
+
+	for name, envSchema := range map[string]string{
+		"bad-pattern":      "    PORT:\n      pattern: \"[0-9\"\n",
+		"required-default": "    PORT:\n      required: true\n      default: \"80\"\n",
+		"default-mismatch": "    PORT:\n      default: http\n      pattern: \"[0-9]+\"\n",
+	} {
+		if _, err := LoadIndex([]string{template(name, envSchema)}); err == nil || !strings.Contains(err.Error(), "envSchema.PORT") {
+			t.Fatalf("%s: LoadIndex error = %v, want envSchema.PORT error", name, err)
+		}
+	}
+}
+
 func templateNames(templates []*Template) []string {
 	names := make([]string, 0, len(templates))
 	for _, template := range templates {
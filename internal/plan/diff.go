@@ -6,6 +6,7 @@ import (
 	"sort"
 
 	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+	"github.com/prospect-ogujiuba/devarch/internal/workspace"
 )
 
 func Diff(desired *runtimepkg.DesiredWorkspace, snapshot *runtimepkg.Snapshot) (*Result, error) {
@@ -79,10 +80,57 @@ func Diff(desired *runtimepkg.DesiredWorkspace, snapshot *runtimepkg.Snapshot) (
 		}
 		return actions[i].RuntimeName < actions[j].RuntimeName
 	})
+	if order := initOrder(desiredByKey); len(order) > 0 {
+		sort.SliceStable(actions, func(i, j int) bool {
+			return initRank(actions[i], order) < initRank(actions[j], order)
+		})
+	}
 	result.Actions = actions
 	return result, nil
 }
 
+// ChangedOnly narrows a plan to resources whose desired configuration or
+// image differs from what is running: restarts of stopped or unhealthy but
+// otherwise unchanged resources become noops, so apply leaves them alone.
+func ChangedOnly(result *Result) *Result {
+	if result == nil {
+		return nil
+	}
+	narrowed := *result
+	narrowed.Actions = make([]Action, len(result.Actions))
+	for i, action := range result.Actions {
+		if action.Kind == ActionRestart {
+			action.Kind = ActionNoop
+			action.Reasons = append(append([]string(nil), action.Reasons...), changedOnlySkipReason())
+		}
+		narrowed.Actions[i] = action
+	}
+	return &narrowed
+}
+
+// ImageUpdates narrows a plan to recreating the resources in outdated,
+// whose image has a newer digest than the one they run. Every other action
+// becomes a noop, so a managed update touches nothing else.
+func ImageUpdates(result *Result, outdated map[string]bool) *Result {
+	if result == nil {
+		return nil
+	}
+	narrowed := *result
+	narrowed.Actions = make([]Action, len(result.Actions))
+	for i, action := range result.Actions {
+		switch {
+		case action.Scope == ScopeResource && outdated[action.Target]:
+			action.Kind = ActionModify
+			action.Reasons = []string{imageDigestChangedReason()}
+		case action.Kind != ActionNoop:
+			action.Kind = ActionNoop
+			action.Reasons = append(append([]string(nil), action.Reasons...), imageUpdateSkipReason())
+		}
+		narrowed.Actions[i] = action
+	}
+	return &narrowed
+}
+
 func diffWorkspaceNetwork(desired *runtimepkg.DesiredWorkspace, snapshot *runtimepkg.Snapshot) Action {
 	if desired.Network != nil && snapshot.Workspace.Network == nil {
 		return Action{Scope: ScopeWorkspace, Target: "network", RuntimeName: desired.Network.Name, Kind: ActionAdd, Reasons: workspaceNetworkAddReasons()}
@@ -120,6 +168,9 @@ func diffResource(desired *runtimepkg.DesiredResource, snapshot *runtimepkg.Snap
 	if len(fields) > 0 {
 		return Action{Scope: ScopeResource, Target: desired.Key, RuntimeName: desired.RuntimeName, Kind: ActionModify, Reasons: modifyReasons(fields)}
 	}
+	if desired.Spec.Init && initCompleted(snapshot) {
+		return Action{Scope: ScopeResource, Target: desired.Key, RuntimeName: desired.RuntimeName, Kind: ActionNoop, Reasons: initCompletedNoopReasons()}
+	}
 	if requiresRestart(snapshot) {
 		return Action{Scope: ScopeResource, Target: desired.Key, RuntimeName: desired.RuntimeName, Kind: ActionRestart, Reasons: resourceRestartReasons(snapshot.State.Running, snapshot.State.Health)}
 	}
@@ -158,6 +209,20 @@ func changedFields(desired *runtimepkg.DesiredResource, snapshot *runtimepkg.Sna
 	if !reflect.DeepEqual(desired.Spec.Health, snapshot.Spec.Health) {
 		fields = append(fields, "health")
 	}
+	// Runtimes report their own default stop signal and timeout, so these
+	// are only compared when the resource or its template sets them.
+	if desired.Spec.StopSignal != "" && runtimepkg.CanonicalStopSignal(desired.Spec.StopSignal) != runtimepkg.CanonicalStopSignal(snapshot.Spec.StopSignal) {
+		fields = append(fields, "stopSignal")
+	}
+	if desired.Spec.StopTimeout != nil && (snapshot.Spec.StopTimeout == nil || *desired.Spec.StopTimeout != *snapshot.Spec.StopTimeout) {
+		fields = append(fields, "stopTimeout")
+	}
+	if desired.Spec.Userns != snapshot.Spec.Userns {
+		fields = append(fields, "userns")
+	}
+	if memoryChanged(desired.Spec.Memory, snapshot.Spec.Memory) {
+		fields = append(fields, "memory")
+	}
 	if !reflect.DeepEqual(desired.Spec.ProjectSource, snapshot.Spec.ProjectSource) {
 		fields = append(fields, "projectSource")
 	}
@@ -171,6 +236,20 @@ func changedFields(desired *runtimepkg.DesiredResource, snapshot *runtimepkg.Sna
 	return fields
 }
 
+// memoryChanged compares memory limits by size, since inspect reports in
+// bytes what the manifest may write as 512m.
+func memoryChanged(desired, actual string) bool {
+	if desired == "" || actual == "" {
+		return desired != actual
+	}
+	want, err := workspace.ParseMemory(desired)
+	if err != nil {
+		return true
+	}
+	got, err := workspace.ParseMemory(actual)
+	return err != nil || want != got
+}
+
 func requiresRestart(snapshot *runtimepkg.SnapshotResource) bool {
 	if snapshot == nil {
 		return false
@@ -180,3 +259,60 @@ func requiresRestart(snapshot *runtimepkg.SnapshotResource) bool {
 	}
 	return snapshot.State.Health == "unhealthy"
 }
+
+// initCompleted reports an init resource that ran to completion. Exited init
+// containers are left in place, so a clean exit is the settled state rather
+// than one that needs a restart.
+func initCompleted(snapshot *runtimepkg.SnapshotResource) bool {
+	return snapshot != nil && !snapshot.State.Running && snapshot.State.Status == "exited" && snapshot.State.ExitCode == 0
+}
+
+// initOrder lists init resources and everything they transitively depend on,
+// dependencies first, so apply finishes them before any other resource.
+func initOrder(desiredByKey map[string]*runtimepkg.DesiredResource) map[string]int {
+	keys := make([]string, 0, len(desiredByKey))
+	for key, resource := range desiredByKey {
+		if resource.Enabled && resource.Spec.Init {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Strings(keys)
+
+	order := make(map[string]int)
+	next := 0
+	var visit func(key string)
+	visit = func(key string) {
+		if _, ok := order[key]; ok {
+			return
+		}
+		resource, ok := desiredByKey[key]
+		if !ok {
+			return
+		}
+		order[key] = -1
+		for _, dependency := range resource.DependsOn {
+			visit(dependency)
+		}
+		order[key] = next
+		next++
+	}
+	for _, key := range keys {
+		visit(key)
+	}
+	return order
+}
+
+// initRank keeps workspace actions first, then init-ordered resource actions,
+// then everything else in its existing order.
+func initRank(action Action, order map[string]int) int {
+	if action.Scope == ScopeWorkspace {
+		return -1
+	}
+	if rank, ok := order[action.Target]; ok {
+		return rank
+	}
+	return len(order)
+}
@@ -155,6 +155,198 @@ func TestDiffComputesModifyRestartRemoveAndNoopReasons(t *testing.T) {
 	}
 }
 
+func TestDiffOrdersInitResourcesFirstAndSettlesCompletedRuns(t *testing.T) {
+	desired := &runtimepkg.DesiredWorkspace{
+		Name:     "shop-local",
+		Provider: runtimepkg.ProviderPodman,
+		Resources: []*runtimepkg.DesiredResource{
+			{Key: "api", Enabled: true, RuntimeName: "devarch-shop-local-api", DependsOn: []string{"migrate"}, Spec: runtimepkg.ResourceSpec{Image: "node:22-alpine"}},
+			{Key: "cache", Enabled: true, RuntimeName: "devarch-shop-local-cache", Spec: runtimepkg.ResourceSpec{Image: "redis:7-alpine"}},
+			{Key: "migrate", Enabled: true, RuntimeName: "devarch-shop-local-migrate", DependsOn: []string{"postgres"}, Spec: runtimepkg.ResourceSpec{Image: "node:22-alpine", Init: true}},
+			{Key: "postgres", Enabled: true, RuntimeName: "devarch-shop-local-postgres", Spec: runtimepkg.ResourceSpec{Image: "postgres:16"}},
+		},
+	}
+	result, err := planpkg.Diff(desired, nil)
+	if err != nil {
+		t.Fatalf("plan.Diff returned error: %v", err)
+	}
+	targets := make([]string, 0, len(result.Actions))
+	for _, action := range result.Actions {
+		targets = append(targets, action.Target)
+	}
+	if got, want := targets, []string{"postgres", "migrate", "api", "cache"}; !bytes.Equal(marshalJSON(t, got), marshalJSON(t, want)) {
+		t.Fatalf("action order = %v, want %v", got, want)
+	}
+
+	completed := &runtimepkg.Snapshot{Workspace: runtimepkg.SnapshotWorkspace{Name: desired.Name}, Resources: []*runtimepkg.SnapshotResource{{
+		Key:         "migrate",
+		RuntimeName: "devarch-shop-local-migrate",
+		State:       runtimepkg.ResourceState{Status: "exited"},
+		Spec:        runtimepkg.ResourceSpec{Image: "node:22-alpine"},
+	}}}
+	result, err = planpkg.Diff(&runtimepkg.DesiredWorkspace{Name: desired.Name, Provider: desired.Provider, Resources: desired.Resources[2:3]}, completed)
+	if err != nil {
+		t.Fatalf("plan.Diff returned error: %v", err)
+	}
+	if got, want := result.Actions[0].Kind, planpkg.ActionNoop; got != want {
+		t.Fatalf("completed init action kind = %q, want %q", got, want)
+	}
+
+	completed.Resources[0].State.ExitCode = 1
+	result, err = planpkg.Diff(&runtimepkg.DesiredWorkspace{Name: desired.Name, Provider: desired.Provider, Resources: desired.Resources[2:3]}, completed)
+	if err != nil {
+		t.Fatalf("plan.Diff returned error: %v", err)
+	}
+	if got, want := result.Actions[0].Kind, planpkg.ActionRestart; got != want {
+		t.Fatalf("failed init action kind = %q, want %q", got, want)
+	}
+}
+
+func TestDiffComparesStopSignalAndTimeoutWhenSet(t *testing.T) {
+	timeout := func(seconds int) *int { return &seconds }
+	desired := &runtimepkg.DesiredWorkspace{Name: "shop-local", Resources: []*runtimepkg.DesiredResource{
+		{Key: "postgres", Enabled: true, RuntimeName: "devarch-shop-local-postgres", Spec: runtimepkg.ResourceSpec{Image: "postgres:16", StopSignal: "SIGINT", StopTimeout: timeout(30)}},
+		{Key: "redis", Enabled: true, RuntimeName: "devarch-shop-local-redis", Spec: runtimepkg.ResourceSpec{Image: "redis:7"}},
+	}}
+	running := runtimepkg.ResourceState{Running: true, Status: "running"}
+	snapshot := &runtimepkg.Snapshot{Workspace: runtimepkg.SnapshotWorkspace{Name: desired.Name}, Resources: []*runtimepkg.SnapshotResource{
+		{Key: "postgres", RuntimeName: "devarch-shop-local-postgres", State: running, Spec: runtimepkg.ResourceSpec{Image: "postgres:16", StopSignal: "SIGTERM", StopTimeout: timeout(10)}},
+		// The runtime reports its defaults for a resource that sets neither.
+		{Key: "redis", RuntimeName: "devarch-shop-local-redis", State: running, Spec: runtimepkg.ResourceSpec{Image: "redis:7", StopSignal: "SIGTERM", StopTimeout: timeout(10)}},
+	}}
+	result, err := planpkg.Diff(desired, snapshot)
+	if err != nil {
+		t.Fatalf("plan.Diff returned error: %v", err)
+	}
+	if got, want := result.Actions[0].Kind, planpkg.ActionModify; got != want {
+		t.Fatalf("postgres action kind = %q, want %q", got, want)
+	}
+	if got, want := result.Actions[0].Reasons, []string{"stop signal changed", "stop timeout changed"}; !bytes.Equal(marshalJSON(t, got), marshalJSON(t, want)) {
+		t.Fatalf("postgres reasons = %v, want %v", got, want)
+	}
+	if got, want := result.Actions[1].Kind, planpkg.ActionNoop; got != want {
+		t.Fatalf("redis action kind = %q, want %q", got, want)
+	}
+
+	snapshot.Resources[0].Spec.StopSignal, snapshot.Resources[0].Spec.StopTimeout = "SIGINT", timeout(30)
+	if result, err = planpkg.Diff(desired, snapshot); err != nil || result.Actions[0].Kind != planpkg.ActionNoop {
+		t.Fatalf("postgres action after matching stop settings = %+v, %v; want noop", result.Actions[0], err)
+	}
+}
+
+func TestDiffMatchesNumericAndNamedStopSignals(t *testing.T) {
+	desired := &runtimepkg.DesiredWorkspace{Name: "shop-local", Resources: []*runtimepkg.DesiredResource{
+		{Key: "postgres", Enabled: true, RuntimeName: "devarch-shop-local-postgres", Spec: runtimepkg.ResourceSpec{Image: "postgres:16", StopSignal: "SIGINT"}},
+		{Key: "redis", Enabled: true, RuntimeName: "devarch-shop-local-redis", Spec: runtimepkg.ResourceSpec{Image: "redis:7", StopSignal: "TERM"}},
+	}}
+	running := runtimepkg.ResourceState{Running: true, Status: "running"}
+	// Podman reports the stop signal as a number.
+	snapshot := &runtimepkg.Snapshot{Workspace: runtimepkg.SnapshotWorkspace{Name: desired.Name}, Resources: []*runtimepkg.SnapshotResource{
+		{Key: "postgres", RuntimeName: "devarch-shop-local-postgres", State: running, Spec: runtimepkg.ResourceSpec{Image: "postgres:16", StopSignal: "2"}},
+		{Key: "redis", RuntimeName: "devarch-shop-local-redis", State: running, Spec: runtimepkg.ResourceSpec{Image: "redis:7", StopSignal: "15"}},
+	}}
+	result, err := planpkg.Diff(desired, snapshot)
+	if err != nil {
+		t.Fatalf("plan.Diff returned error: %v", err)
+	}
+	for _, action := range result.Actions {
+		if action.Kind != planpkg.ActionNoop {
+			t.Fatalf("%s action = %q %v, want noop", action.Target, action.Kind, action.Reasons)
+		}
+	}
+
+	snapshot.Resource("postgres").Spec.StopSignal = "15"
+	if result, err = planpkg.Diff(desired, snapshot); err != nil || result.Actions[0].Kind != planpkg.ActionModify {
+		t.Fatalf("postgres action with SIGTERM running = %+v, %v; want modify", result.Actions[0], err)
+	}
+}
+
+func TestDiffComparesUserns(t *testing.T) {
+	desired := &runtimepkg.DesiredWorkspace{Name: "shop-local", Resources: []*runtimepkg.DesiredResource{
+		{Key: "api", Enabled: true, RuntimeName: "devarch-shop-local-api", Spec: runtimepkg.ResourceSpec{Image: "node:22", Userns: "keep-id"}},
+	}}
+	snapshot := &runtimepkg.Snapshot{Workspace: runtimepkg.SnapshotWorkspace{Name: desired.Name}, Resources: []*runtimepkg.SnapshotResource{
+		{Key: "api", RuntimeName: "devarch-shop-local-api", State: runtimepkg.ResourceState{Running: true, Status: "running"}, Spec: runtimepkg.ResourceSpec{Image: "node:22"}},
+	}}
+	result, err := planpkg.Diff(desired, snapshot)
+	if err != nil {
+		t.Fatalf("plan.Diff returned error: %v", err)
+	}
+	if got, want := result.Actions[0].Kind, planpkg.ActionModify; got != want {
+		t.Fatalf("api action kind = %q, want %q", got, want)
+	}
+	if got, want := result.Actions[0].Reasons, []string{"user namespace changed"}; !bytes.Equal(marshalJSON(t, got), marshalJSON(t, want)) {
+		t.Fatalf("api reasons = %v, want %v", got, want)
+	}
+
+	// Dropping userns from the manifest is a change too.
+	snapshot.Resources[0].Spec.Userns, desired.Resources[0].Spec.Userns = "keep-id", ""
+	if result, err = planpkg.Diff(desired, snapshot); err != nil || result.Actions[0].Kind != planpkg.ActionModify {
+		t.Fatalf("api action after dropping userns = %+v, %v; want modify", result.Actions[0], err)
+	}
+	snapshot.Resources[0].Spec.Userns = ""
+	if result, err = planpkg.Diff(desired, snapshot); err != nil || result.Actions[0].Kind != planpkg.ActionNoop {
+		t.Fatalf("api action with matching userns = %+v, %v; want noop", result.Actions[0], err)
+	}
+}
+
+func TestDiffComparesMemoryBySize(t *testing.T) {
+	desired := &runtimepkg.DesiredWorkspace{Name: "shop-local", Resources: []*runtimepkg.DesiredResource{
+		{Key: "api", Enabled: true, RuntimeName: "devarch-shop-local-api", Spec: runtimepkg.ResourceSpec{Image: "node:22", Memory: "512m"}},
+	}}
+	snapshot := &runtimepkg.Snapshot{Workspace: runtimepkg.SnapshotWorkspace{Name: desired.Name}, Resources: []*runtimepkg.SnapshotResource{
+		{Key: "api", RuntimeName: "devarch-shop-local-api", State: runtimepkg.ResourceState{Running: true, Status: "running"}, Spec: runtimepkg.ResourceSpec{Image: "node:22", Memory: "536870912"}},
+	}}
+	result, err := planpkg.Diff(desired, snapshot)
+	if err != nil || result.Actions[0].Kind != planpkg.ActionNoop {
+		t.Fatalf("api action with the same limit in bytes = %+v, %v; want noop", result.Actions[0], err)
+	}
+	desired.Resources[0].Spec.Memory = "1g"
+	if result, err = planpkg.Diff(desired, snapshot); err != nil || result.Actions[0].Kind != planpkg.ActionModify {
+		t.Fatalf("api action after raising memory = %+v, %v; want modify", result.Actions[0], err)
+	}
+	if got, want := result.Actions[0].Reasons, []string{"memory limit changed"}; !bytes.Equal(marshalJSON(t, got), marshalJSON(t, want)) {
+		t.Fatalf("api reasons = %v, want %v", got, want)
+	}
+}
+
+func TestChangedOnlySkipsRestarts(t *testing.T) {
+	result := &planpkg.Result{Workspace: "shop-local", Actions: []planpkg.Action{
+		{Scope: planpkg.ScopeResource, Target: "api", Kind: planpkg.ActionModify, Reasons: []string{"image changed"}},
+		{Scope: planpkg.ScopeResource, Target: "redis", Kind: planpkg.ActionRestart, Reasons: []string{"resource exists but is not running"}},
+	}}
+	narrowed := planpkg.ChangedOnly(result)
+	if got := narrowed.Actions[0].Kind; got != planpkg.ActionModify {
+		t.Fatalf("api kind = %q, want modify", got)
+	}
+	if got := narrowed.Actions[1]; got.Kind != planpkg.ActionNoop || len(got.Reasons) != 2 {
+		t.Fatalf("redis action = %#v, want noop with skip reason", got)
+	}
+	if got := result.Actions[1].Kind; got != planpkg.ActionRestart {
+		t.Fatalf("original redis kind = %q, want restart", got)
+	}
+}
+
+func TestImageUpdatesOnlyRecreatesOutdatedResources(t *testing.T) {
+	result := &planpkg.Result{Workspace: "shop-local", Actions: []planpkg.Action{
+		{Scope: planpkg.ScopeWorkspace, Target: "network", Kind: planpkg.ActionAdd},
+		{Scope: planpkg.ScopeResource, Target: "api", Kind: planpkg.ActionNoop},
+		{Scope: planpkg.ScopeResource, Target: "redis", Kind: planpkg.ActionRestart, Reasons: []string{"resource exists but is not running"}},
+	}}
+	narrowed := planpkg.ImageUpdates(result, map[string]bool{"api": true})
+	if got := narrowed.Actions[1]; got.Kind != planpkg.ActionModify || len(got.Reasons) != 1 || got.Reasons[0] != "image digest changed" {
+		t.Fatalf("api action = %#v, want modify for the new digest", got)
+	}
+	for _, got := range []planpkg.Action{narrowed.Actions[0], narrowed.Actions[2]} {
+		if got.Kind != planpkg.ActionNoop {
+			t.Fatalf("%s action = %#v, want noop", got.Target, got)
+		}
+	}
+	if got := result.Actions[2].Kind; got != planpkg.ActionRestart {
+		t.Fatalf("original redis kind = %q, want restart", got)
+	}
+}
+
 func loadDesiredWorkspace(t *testing.T, name string) *runtimepkg.DesiredWorkspace {
 	t.Helper()
 	manifestPath := filepath.Join(repoRoot(t), "examples", "workspaces", name, "devarch.workspace.yaml")
@@ -33,6 +33,22 @@ func resourceNoopReasons() []string {
 	return []string{"resource runtime configuration matches desired state"}
 }
 
+func initCompletedNoopReasons() []string {
+	return []string{"init resource already ran to completion"}
+}
+
+func changedOnlySkipReason() string {
+	return "restart skipped: only changed resources are applied"
+}
+
+func imageDigestChangedReason() string {
+	return "image digest changed"
+}
+
+func imageUpdateSkipReason() string {
+	return "skipped: only outdated images are updated"
+}
+
 func resourceRestartReasons(running bool, health string) []string {
 	reasons := make([]string, 0, 2)
 	if !running {
@@ -71,6 +87,14 @@ func modifyReasons(fields []string) []string {
 			messages = append(messages, "port bindings changed")
 		case "projectSource":
 			messages = append(messages, "project source handling changed")
+		case "stopSignal":
+			messages = append(messages, "stop signal changed")
+		case "stopTimeout":
+			messages = append(messages, "stop timeout changed")
+		case "userns":
+			messages = append(messages, "user namespace changed")
+		case "memory":
+			messages = append(messages, "memory limit changed")
 		case "volumes":
 			messages = append(messages, "volumes changed")
 		case "workingDir":
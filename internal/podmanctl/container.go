@@ -22,7 +22,11 @@ type ContainerSpec struct {
 	Labels        map[string]string
 	Network       string
 	RestartPolicy string
+	StopSignal    string
+	StopTimeout   *int
 	Health        *workspace.Health
+	Userns        string
+	Memory        string
 }
 
 type PortSpec struct {
@@ -38,10 +42,20 @@ type VolumeSpec struct {
 	ReadOnly bool
 	Kind     string
 	Type     string
+	Options  []string
 }
 
 func BuildRunArgs(spec ContainerSpec) []string {
-	args := []string{"run", "--detach", "--replace"}
+	return buildContainerArgs([]string{"run", "--detach", "--replace"}, spec)
+}
+
+// BuildTaskArgs runs spec in the foreground and removes the container once it
+// exits, for one-off commands such as migrations.
+func BuildTaskArgs(spec ContainerSpec) []string {
+	return buildContainerArgs([]string{"run", "--rm"}, spec)
+}
+
+func buildContainerArgs(args []string, spec ContainerSpec) []string {
 	if spec.Name != "" {
 		args = append(args, "--name", spec.Name)
 	}
@@ -73,6 +87,18 @@ func BuildRunArgs(spec ContainerSpec) []string {
 	if spec.RestartPolicy != "" {
 		args = append(args, "--restart", spec.RestartPolicy)
 	}
+	if spec.Userns != "" {
+		args = append(args, "--userns", spec.Userns)
+	}
+	if spec.Memory != "" {
+		args = append(args, "--memory", spec.Memory)
+	}
+	if spec.StopSignal != "" {
+		args = append(args, "--stop-signal", spec.StopSignal)
+	}
+	if spec.StopTimeout != nil {
+		args = append(args, "--stop-timeout", strconv.Itoa(*spec.StopTimeout))
+	}
 	appendHealthArgs(&args, spec.Health)
 	if spec.Image != "" {
 		args = append(args, spec.Image)
@@ -102,13 +128,62 @@ func RemoveContainer(ctx context.Context, runner Runner, name string) error {
 	return fmt.Errorf("podman rm %q: %w", name, err)
 }
 
-func RestartContainer(ctx context.Context, runner Runner, name string) error {
-	if _, err := Podman(ctx, runner, "restart", name); err != nil {
+// RestartContainer restarts name. A nil timeout keeps the container's own
+// stop timeout.
+func RestartContainer(ctx context.Context, runner Runner, name string, timeout *int) error {
+	args := []string{"restart"}
+	if timeout != nil {
+		args = append(args, "--time", strconv.Itoa(*timeout))
+	}
+	if _, err := Podman(ctx, runner, append(args, name)...); err != nil {
 		return fmt.Errorf("podman restart %q: %w", name, err)
 	}
 	return nil
 }
 
+// StopContainer stops a container without removing it, so StartContainer can
+// bring it back with its filesystem intact.
+func StopContainer(ctx context.Context, runner Runner, name string, timeout *int) error {
+	args := []string{"stop"}
+	if timeout != nil {
+		args = append(args, "--time", strconv.Itoa(*timeout))
+	}
+	if _, err := Podman(ctx, runner, append(args, name)...); err != nil {
+		return fmt.Errorf("podman stop %q: %w", name, err)
+	}
+	return nil
+}
+
+func StartContainer(ctx context.Context, runner Runner, name string) error {
+	if _, err := Podman(ctx, runner, "start", name); err != nil {
+		return fmt.Errorf("podman start %q: %w", name, err)
+	}
+	return nil
+}
+
+// PauseContainer freezes every process in name until UnpauseContainer.
+func PauseContainer(ctx context.Context, runner Runner, name string) error {
+	if _, err := Podman(ctx, runner, "pause", name); err != nil {
+		return fmt.Errorf("podman pause %q: %w", name, err)
+	}
+	return nil
+}
+
+func UnpauseContainer(ctx context.Context, runner Runner, name string) error {
+	if _, err := Podman(ctx, runner, "unpause", name); err != nil {
+		return fmt.Errorf("podman unpause %q: %w", name, err)
+	}
+	return nil
+}
+
+// KillContainer sends signal to the container's main process.
+func KillContainer(ctx context.Context, runner Runner, name, signal string) error {
+	if _, err := Podman(ctx, runner, "kill", "--signal", signal, name); err != nil {
+		return fmt.Errorf("podman kill %q: %w", name, err)
+	}
+	return nil
+}
+
 func sortedEnvKeys(values map[string]workspace.EnvValue) []string {
 	keys := make([]string, 0, len(values))
 	for key := range values {
@@ -145,8 +220,13 @@ func volumeValue(volume VolumeSpec) string {
 	if volume.Source != "" {
 		parts = []string{volume.Source, volume.Target}
 	}
+	var options []string
 	if volume.ReadOnly {
-		parts = append(parts, "ro")
+		options = append(options, "ro")
+	}
+	options = append(options, volume.Options...)
+	if len(options) > 0 {
+		parts = append(parts, strings.Join(options, ","))
 	}
 	return strings.Join(parts, ":")
 }
@@ -21,13 +21,15 @@ func TestBuildRunArgsFullSpecDeterministic(t *testing.T) {
 			"ALPHA": workspace.NumberEnvValue("1"),
 		},
 		Ports: []PortSpec{{Container: 80, Published: 8080, Protocol: "tcp"}, {Container: 443, Published: 8443, HostIP: "127.0.0.1"}},
-		Volumes: []VolumeSpec{{Source: "/z", Target: "/z", ReadOnly: true}, {Source: "/a", Target: "/a"}},
+		Volumes: []VolumeSpec{{Source: "/z", Target: "/z", ReadOnly: true, Options: []string{"Z", "U"}}, {Source: "/a", Target: "/a"}},
 		Labels: map[string]string{"z": "last", "a": "first"},
 		Network: "dev-net",
 		RestartPolicy: "unless-stopped",
+		Userns: "keep-id",
+		Memory: "512m",
 		Health: &workspace.Health{Test: workspace.StringList{"curl", "-f", "http://localhost"}, Interval: "10s", Timeout: "2s", Retries: 3, StartPeriod: "5s"},
 	}
-	want := []string{"run", "--detach", "--replace", "--name", "dev-web", "--workdir", "/app", "--entrypoint", "/entrypoint.sh", "--env", "ALPHA=1", "--env", "ZED=last", "--publish", "127.0.0.1:8443:443/tcp", "--publish", "8080:80/tcp", "--volume", "/a:/a", "--volume", "/z:/z:ro", "--label", "a=first", "--label", "z=last", "--network", "dev-net", "--restart", "unless-stopped", "--health-cmd", "curl -f http://localhost", "--health-interval", "10s", "--health-timeout", "2s", "--health-retries", "3", "--health-start-period", "5s", "nginx:alpine", "nginx", "-g", "daemon off;"}
+	want := []string{"run", "--detach", "--replace", "--name", "dev-web", "--workdir", "/app", "--entrypoint", "/entrypoint.sh", "--env", "ALPHA=1", "--env", "ZED=last", "--publish", "127.0.0.1:8443:443/tcp", "--publish", "8080:80/tcp", "--volume", "/a:/a", "--volume", "/z:/z:ro,Z,U", "--label", "a=first", "--label", "z=last", "--network", "dev-net", "--restart", "unless-stopped", "--userns", "keep-id", "--memory", "512m", "--health-cmd", "curl -f http://localhost", "--health-interval", "10s", "--health-timeout", "2s", "--health-retries", "3", "--health-start-period", "5s", "nginx:alpine", "nginx", "-g", "daemon off;"}
 	if got := BuildRunArgs(spec); !reflect.DeepEqual(got, want) {
 		t.Fatalf("BuildRunArgs = %#v, want %#v", got, want)
 	}
@@ -67,7 +69,7 @@ func TestRemoveContainerMissingDoesNotFail(t *testing.T) {
 
 func TestRestartContainer(t *testing.T) {
 	runner := &fakeRunner{}
-	if err := RestartContainer(context.Background(), runner, "dev"); err != nil {
+	if err := RestartContainer(context.Background(), runner, "dev", nil); err != nil {
 		t.Fatalf("RestartContainer returned error: %v", err)
 	}
 	want := []call{{command: "podman", args: []string{"restart", "dev"}}}
@@ -76,9 +78,27 @@ func TestRestartContainer(t *testing.T) {
 	}
 }
 
+func TestStopSettingsFlowToRunAndRestartArgs(t *testing.T) {
+	timeout := 30
+	args := BuildRunArgs(ContainerSpec{Name: "db", Image: "postgres:16", RestartPolicy: "unless-stopped", StopSignal: "SIGINT", StopTimeout: &timeout})
+	want := []string{"run", "--detach", "--replace", "--name", "db", "--restart", "unless-stopped", "--stop-signal", "SIGINT", "--stop-timeout", "30", "postgres:16"}
+	if !reflect.DeepEqual(args, want) {
+		t.Fatalf("BuildRunArgs = %#v, want %#v", args, want)
+	}
+
+	runner := &fakeRunner{}
+	if err := RestartContainer(context.Background(), runner, "db", &timeout); err != nil {
+		t.Fatalf("RestartContainer returned error: %v", err)
+	}
+	wantCalls := []call{{command: "podman", args: []string{"restart", "--time", "30", "db"}}}
+	if !reflect.DeepEqual(runner.calls, wantCalls) {
+		t.Fatalf("calls = %#v, want %#v", runner.calls, wantCalls)
+	}
+}
+
 func TestContainerErrorPropagation(t *testing.T) {
 	runner := &fakeRunner{errs: []error{errors.New("daemon down")}}
-	if err := RestartContainer(context.Background(), runner, "dev"); err == nil {
+	if err := RestartContainer(context.Background(), runner, "dev", nil); err == nil {
 		t.Fatal("expected error")
 	}
 }
@@ -0,0 +1,373 @@
+package projectscan
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IgnoreFilename holds ignore and include rules for ScanAll, read from the
+// scanned root.
+const IgnoreFilename = ".devarchignore"
+
+// DefaultConcurrency and DefaultProjectTimeout apply when ScanOptions leaves
+// them zero.
+const (
+	DefaultConcurrency    = 4
+	DefaultProjectTimeout = 10 * time.Second
+)
+
+// DefaultMaxDepth bounds how many directory levels below the root ScanAll
+// looks for projects when neither options nor a depth rule say otherwise.
+const DefaultMaxDepth = 3
+
+// defaultIgnores are skipped unless an include rule brings them back.
+var defaultIgnores = []string{".git", "node_modules", "vendor"}
+
+// projectMarkers are the files that make a directory a project for ScanAll.
+var projectMarkers = []string{"artisan", "composer.json", "package.json", "go.mod", "wp-config.php", "compose.yaml", "compose.yml", "docker-compose.yml", "docker-compose.yaml"}
+
+// Rule is one ignore, include, or depth rule applied by ScanAll. Source is
+// "default", the ignore file, or "option".
+type Rule struct {
+	Pattern string `json:"pattern"`
+	Action  string `json:"action"`
+	Depth   int    `json:"depth,omitempty"`
+	Source  string `json:"source"`
+}
+
+// ScanOptions adds include and exclude globs and a maximum depth on top of
+// the root's ignore file. They are applied after the file, so they win.
+// Concurrency bounds how many projects are scanned at once, and a project
+// that takes longer than ProjectTimeout is reported with a scan-timeout
+// diagnostic instead of its details. Language, Framework, and HasFrontend
+// keep only matching projects; archived projects are left out unless
+// Archived is set.
+type ScanOptions struct {
+	Include        []string
+	Exclude        []string
+	MaxDepth       int
+	Concurrency    int
+	ProjectTimeout time.Duration
+	Language       string
+	Framework      string
+	HasFrontend    *bool
+	Archived       bool
+}
+
+// Skipped records a directory ScanAll did not descend into and the rule
+// that excluded it.
+type Skipped struct {
+	Path string `json:"path"`
+	Rule string `json:"rule"`
+}
+
+// Inventory is the result of scanning every project below a root.
+type Inventory struct {
+	Root     string    `json:"root"`
+	MaxDepth int       `json:"maxDepth"`
+	Rules    []Rule    `json:"rules"`
+	Projects []*Result `json:"projects"`
+	Skipped  []Skipped `json:"skipped,omitempty"`
+	// Hidden counts scanned projects left out by the filters.
+	Hidden  int   `json:"hidden,omitempty"`
+	TotalMs int64 `json:"totalMs"`
+}
+
+// ScanAll finds project directories below root and scans each one. A
+// directory holding a known project marker, root included, is scanned and
+// not descended into further. Rules are matched against the slash-separated
+// path relative to root, or against the directory name when the pattern has
+// no slash, and the last matching rule wins, as in .gitignore.
+func ScanAll(root string, options ScanOptions) (*Inventory, error) {
+	cleanRoot, err := filepath.Abs(filepath.Clean(root))
+	if err != nil {
+		return nil, fmt.Errorf("scan projects %s: %w", root, err)
+	}
+	if info, err := os.Stat(cleanRoot); err != nil {
+		return nil, fmt.Errorf("scan projects %s: %w", cleanRoot, err)
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("scan projects %s: not a directory", cleanRoot)
+	}
+	if options.MaxDepth < 0 || options.Concurrency < 0 || options.ProjectTimeout < 0 {
+		return nil, fmt.Errorf("scan projects %s: depth, concurrency, and timeout must not be negative", cleanRoot)
+	}
+	started := time.Now()
+
+	rules, err := scanRules(cleanRoot, options)
+	if err != nil {
+		return nil, err
+	}
+	inventory := &Inventory{Root: cleanRoot, MaxDepth: options.MaxDepth, Rules: rules, Projects: []*Result{}}
+	if inventory.MaxDepth == 0 {
+		inventory.MaxDepth = DefaultMaxDepth
+	}
+
+	var dirs []string
+	if isProjectDir(cleanRoot) {
+		dirs = append(dirs, cleanRoot)
+	} else if err := filepath.WalkDir(cleanRoot, func(current string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		relative, _ := filepath.Rel(cleanRoot, current)
+		relative = filepath.ToSlash(relative)
+		if relative == "." {
+			return nil
+		}
+		if rule, ignored := ignoredBy(rules, relative); ignored {
+			inventory.Skipped = append(inventory.Skipped, Skipped{Path: relative, Rule: rule})
+			return filepath.SkipDir
+		}
+		if isProjectDir(current) {
+			dirs = append(dirs, current)
+			return filepath.SkipDir
+		}
+		if strings.Count(relative, "/")+1 >= depthLimit(rules, relative, inventory.MaxDepth) {
+			return filepath.SkipDir
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("scan projects %s: %w", cleanRoot, err)
+	}
+	projects, err := scanProjects(dirs, options)
+	if err != nil {
+		return nil, err
+	}
+	for _, project := range projects {
+		if options.matches(project) {
+			inventory.Projects = append(inventory.Projects, project)
+		} else {
+			inventory.Hidden++
+		}
+	}
+	inventory.TotalMs = time.Since(started).Milliseconds()
+	return inventory, nil
+}
+
+// scanProjects scans dirs with a bounded worker pool and returns the results
+// in dirs order. Scan does not take a context, so a project that times out
+// keeps its goroutine until the filesystem returns; only the result is
+// dropped.
+func scanProjects(dirs []string, options ScanOptions) ([]*Result, error) {
+	workers := options.Concurrency
+	if workers == 0 {
+		workers = DefaultConcurrency
+	}
+	timeout := options.ProjectTimeout
+	if timeout == 0 {
+		timeout = DefaultProjectTimeout
+	}
+	sort.Strings(dirs)
+	results := make([]*Result, len(dirs))
+	errs := make([]error, len(dirs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < workers && worker < len(dirs); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				results[index], errs[index] = scanWithTimeout(dirs[index], timeout)
+			}
+		}()
+	}
+	for index := range dirs {
+		jobs <- index
+	}
+	close(jobs)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+func scanWithTimeout(dir string, timeout time.Duration) (*Result, error) {
+	type outcome struct {
+		result *Result
+		err    error
+	}
+	started := time.Now()
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := Scan(dir)
+		done <- outcome{result, err}
+	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case scanned := <-done:
+		if scanned.result != nil {
+			scanned.result.ScanMs = time.Since(started).Milliseconds()
+		}
+		return scanned.result, scanned.err
+	case <-timer.C:
+		return &Result{
+			Name:        filepath.Base(dir),
+			Path:        dir,
+			ProjectType: "unknown",
+			ScanMs:      time.Since(started).Milliseconds(),
+			Diagnostics: []Diagnostic{{Severity: "warning", Code: "scan-timeout", Message: fmt.Sprintf("scan did not finish within %s", timeout)}},
+		}, nil
+	}
+}
+
+// matches reports whether project passes the filters. Language is compared
+// case-insensitively; Framework matches any word of the detected framework,
+// so "laravel" matches "Laravel ^11.0".
+func (options ScanOptions) matches(project *Result) bool {
+	if project.Archived && !options.Archived {
+		return false
+	}
+	if options.Language != "" && !strings.EqualFold(project.Language, options.Language) {
+		return false
+	}
+	if options.HasFrontend != nil && project.HasFrontend != *options.HasFrontend {
+		return false
+	}
+	if options.Framework == "" {
+		return true
+	}
+	for _, word := range strings.FieldsFunc(project.Framework, func(r rune) bool { return r == ' ' || r == ',' }) {
+		if strings.EqualFold(word, options.Framework) {
+			return true
+		}
+	}
+	return strings.EqualFold(project.Framework, options.Framework)
+}
+
+// scanRules returns the defaults, then the rules from the root's ignore
+// file, then the options, in the order they are matched.
+func scanRules(root string, options ScanOptions) ([]Rule, error) {
+	var rules []Rule
+	for _, pattern := range defaultIgnores {
+		rules = append(rules, Rule{Pattern: pattern, Action: "exclude", Source: "default"})
+	}
+	fileRules, err := readIgnoreFile(filepath.Join(root, IgnoreFilename))
+	if err != nil {
+		return nil, err
+	}
+	rules = append(rules, fileRules...)
+	for _, pattern := range options.Exclude {
+		rules = append(rules, Rule{Pattern: cleanPattern(pattern), Action: "exclude", Source: "option"})
+	}
+	for _, pattern := range options.Include {
+		rules = append(rules, Rule{Pattern: cleanPattern(pattern), Action: "include", Source: "option"})
+	}
+	for _, rule := range rules {
+		if _, err := path.Match(rule.Pattern, ""); err != nil {
+			return nil, fmt.Errorf("scan rule %q from %s: %w", rule.Pattern, rule.Source, err)
+		}
+	}
+	return rules, nil
+}
+
+// readIgnoreFile parses an ignore file: one glob per line, "!glob" to
+// include a path an earlier rule excluded, "depth N glob" to limit how deep
+// the scan goes below matching directories, and "#" comments.
+func readIgnoreFile(filename string) ([]Rule, error) {
+	file, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	var rules []Rule
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "" || strings.HasPrefix(text, "#"):
+		case strings.HasPrefix(text, "!"):
+			rules = append(rules, Rule{Pattern: cleanPattern(text[1:]), Action: "include", Source: IgnoreFilename})
+		case strings.HasPrefix(text, "depth "):
+			fields := strings.Fields(text)
+			depth, err := strconv.Atoi(fieldAt(fields, 1))
+			if len(fields) != 3 || err != nil || depth < 1 {
+				return nil, fmt.Errorf("%s:%d: want \"depth N glob\" with N of at least 1", filename, line)
+			}
+			rules = append(rules, Rule{Pattern: cleanPattern(fields[2]), Action: "depth", Depth: depth, Source: IgnoreFilename})
+		default:
+			rules = append(rules, Rule{Pattern: cleanPattern(text), Action: "exclude", Source: IgnoreFilename})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", filename, err)
+	}
+	return rules, nil
+}
+
+// ignoredBy reports whether relative is excluded and the pattern that
+// decided it.
+func ignoredBy(rules []Rule, relative string) (string, bool) {
+	ignored, pattern := false, ""
+	for _, rule := range rules {
+		if rule.Action == "depth" || !ruleMatches(rule.Pattern, relative) {
+			continue
+		}
+		ignored, pattern = rule.Action == "exclude", rule.Pattern
+	}
+	return pattern, ignored
+}
+
+// depthLimit returns the depth, counted from root, past which the scan does
+// not descend below relative. A depth rule counts from the directory it
+// matches, and the closest matching directory wins.
+func depthLimit(rules []Rule, relative string, maxDepth int) int {
+	limit := maxDepth
+	parts := strings.Split(relative, "/")
+	for level := 1; level <= len(parts); level++ {
+		ancestor := strings.Join(parts[:level], "/")
+		for _, rule := range rules {
+			if rule.Action == "depth" && ruleMatches(rule.Pattern, ancestor) {
+				limit = level + rule.Depth
+			}
+		}
+	}
+	return limit
+}
+
+func ruleMatches(pattern, relative string) bool {
+	if !strings.Contains(pattern, "/") {
+		relative = path.Base(relative)
+	}
+	matched, _ := path.Match(pattern, relative)
+	return matched
+}
+
+func cleanPattern(pattern string) string {
+	return strings.Trim(strings.TrimSpace(filepath.ToSlash(pattern)), "/")
+}
+
+func fieldAt(fields []string, index int) string {
+	if index < len(fields) {
+		return fields[index]
+	}
+	return ""
+}
+
+func isProjectDir(dir string) bool {
+	for _, marker := range projectMarkers {
+		if fileExists(filepath.Join(dir, marker)) {
+			return true
+		}
+	}
+	return false
+}
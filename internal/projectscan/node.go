@@ -0,0 +1,143 @@
+package projectscan
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// NodeLatestLTS is the newest Node.js LTS major. Open-ended engine ranges
+// resolve to it rather than to a current, short-lived major.
+const NodeLatestLTS = 24
+
+// nodeCodenames maps the LTS codenames .nvmrc accepts as lts/<codename>.
+var nodeCodenames = map[string]int{
+	"gallium":  16,
+	"hydrogen": 18,
+	"iron":     20,
+	"jod":      22,
+	"krypton":  24,
+}
+
+// NodeVersion returns the Node.js version a project declares and the file
+// it came from. A pin in .nvmrc or .node-version wins over the
+// package.json engines.node range, since it is what developers run.
+func NodeVersion(dir string) (string, string) {
+	for _, name := range []string{".nvmrc", ".node-version"} {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		if version := strings.TrimSpace(strings.SplitN(string(content), "\n", 2)[0]); version != "" {
+			return version, name
+		}
+	}
+	if engine := stringField(mapField(readJSON(filepath.Join(dir, "package.json")), "engines"), "node"); engine != "" {
+		return engine, "package.json"
+	}
+	return "", ""
+}
+
+// NodeMajor picks the Node.js major to run for a version or engine range:
+// the newest LTS major it allows, or else the newest major it allows at
+// all. It reports false when no major satisfies the constraint.
+func NodeMajor(constraint string) (int, bool) {
+	for major := NodeLatestLTS; major > 0; major -= 2 {
+		if NodeSatisfies(constraint, major) {
+			return major, true
+		}
+	}
+	for major := NodeLatestLTS + 1; major > 0; major-- {
+		if NodeSatisfies(constraint, major) {
+			return major, true
+		}
+	}
+	return 0, false
+}
+
+// NodeSatisfies reports whether some release of major satisfies a version
+// or npm-style range such as "20", "v20.11.1", "^18.17", ">=18 <22", or
+// "18 || 20". Aliases such as "lts/*" and "node" allow every major, and
+// lts/<codename> allows the major it names. Constraints it cannot parse
+// are treated as satisfied, so they never produce a false conflict.
+func NodeSatisfies(constraint string, major int) bool {
+	constraint = strings.ToLower(strings.TrimSpace(constraint))
+	switch constraint {
+	case "", "*", "node", "latest", "current", "stable", "lts", "lts/*":
+		return true
+	}
+	if codename, ok := strings.CutPrefix(constraint, "lts/"); ok {
+		named, known := nodeCodenames[codename]
+		return !known || named == major
+	}
+	for _, alternative := range strings.Split(constraint, "||") {
+		satisfied, ok := nodeRangeSatisfies(strings.Fields(alternative), major)
+		if !ok || satisfied {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeRangeSatisfies checks one space-separated set of comparators. The
+// second result is false when a comparator cannot be parsed.
+func nodeRangeSatisfies(comparators []string, major int) (bool, bool) {
+	if len(comparators) == 3 && comparators[1] == "-" {
+		low, _, okLow := nodeVersionMajor(comparators[0])
+		high, _, okHigh := nodeVersionMajor(comparators[2])
+		return (low < 0 || major >= low) && (high < 0 || major <= high), okLow && okHigh
+	}
+	for _, comparator := range comparators {
+		operator := strings.TrimRight(comparator, "v0123456789.xX*")
+		want, minorSet, ok := nodeVersionMajor(comparator[len(operator):])
+		if !ok {
+			return false, false
+		}
+		if want < 0 {
+			continue
+		}
+		var satisfied bool
+		switch operator {
+		case "", "=", "^", "~":
+			satisfied = major == want
+		case ">=":
+			satisfied = major >= want
+		case ">":
+			satisfied = major > want || (minorSet && major == want)
+		case "<=":
+			satisfied = major <= want
+		case "<":
+			satisfied = major < want || (minorSet && major == want)
+		default:
+			return false, false
+		}
+		if !satisfied {
+			return false, true
+		}
+	}
+	return true, true
+}
+
+// nodeVersionMajor parses the major of a version such as "v20.1.x". A
+// wildcard major is returned as -1. minorSet reports whether the version
+// names a non-zero minor or patch, which matters for > and <.
+func nodeVersionMajor(version string) (int, bool, bool) {
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if parts[0] == "x" || parts[0] == "X" || parts[0] == "*" {
+		return -1, false, true
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil || major < 0 {
+		return 0, false, false
+	}
+	minorSet := false
+	for _, part := range parts[1:] {
+		switch part {
+		case "", "0", "x", "X", "*":
+		default:
+			minorSet = true
+		}
+	}
+	return major, minorSet, true
+}
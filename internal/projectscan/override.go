@@ -0,0 +1,64 @@
+package projectscan
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OverrideFilename holds hand-written corrections to a project's detected
+// fields. It lives in the project directory, so the corrections survive
+// every rescan.
+const OverrideFilename = "devarch.project.yaml"
+
+// Override replaces detected fields of a Result, which lists the replaced
+// ones in Overrides. Empty fields keep the detected value. Archived hides
+// the project from ScanAll unless the scan asks for archived projects.
+type Override struct {
+	Name        string `yaml:"name"`
+	ProjectType string `yaml:"projectType"`
+	Framework   string `yaml:"framework"`
+	Language    string `yaml:"language"`
+	HasFrontend *bool  `yaml:"hasFrontend"`
+	Archived    bool   `yaml:"archived"`
+}
+
+// applyOverride reads the project's override file, if any, and applies it to
+// result. A file that cannot be parsed is reported as a diagnostic and
+// otherwise ignored.
+func applyOverride(result *Result, dir string) {
+	filename := filepath.Join(dir, OverrideFilename)
+	content, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return
+	}
+	var override Override
+	if err == nil {
+		err = yaml.Unmarshal(content, &override)
+	}
+	if err != nil {
+		result.Diagnostics = append(result.Diagnostics, Diagnostic{
+			Severity: "warning",
+			Code:     "override-invalid",
+			Message:  fmt.Sprintf("ignoring %s: %v", filename, err),
+		})
+		return
+	}
+	set := func(field string, target *string, value string) {
+		if value != "" {
+			*target = value
+			result.Overrides = append(result.Overrides, field)
+		}
+	}
+	set("name", &result.Name, override.Name)
+	set("projectType", &result.ProjectType, override.ProjectType)
+	set("framework", &result.Framework, override.Framework)
+	set("language", &result.Language, override.Language)
+	if override.HasFrontend != nil {
+		result.HasFrontend = *override.HasFrontend
+		result.Overrides = append(result.Overrides, "hasFrontend")
+	}
+	result.Archived = override.Archived
+}
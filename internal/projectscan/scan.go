@@ -3,12 +3,14 @@ package projectscan
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
 
+	"github.com/prospect-ogujiuba/devarch/internal/workspace"
 	"gopkg.in/yaml.v3"
 )
 
@@ -23,11 +25,75 @@ type Diagnostic struct {
 // ComposeService captures the small structured compose slice exposed by the
 // scan command.
 type ComposeService struct {
-	Name        string   `json:"name"`
-	Image       string   `json:"image,omitempty"`
-	ServiceType string   `json:"serviceType,omitempty"`
-	Ports       []string `json:"ports,omitempty"`
-	DependsOn   []string `json:"dependsOn,omitempty"`
+	Name        string `json:"name"`
+	Image       string `json:"image,omitempty"`
+	ServiceType string `json:"serviceType,omitempty"`
+	// Ports are in short syntax, with long-syntax entries rendered to it;
+	// PortSpecs keeps every field of each, including mode and name.
+	Ports     []string      `json:"ports,omitempty"`
+	PortSpecs []ComposePort `json:"portSpecs,omitempty"`
+	// DependsOn names the services this one depends on; Dependencies adds
+	// the condition each waits for.
+	DependsOn    []string            `json:"dependsOn,omitempty"`
+	Dependencies []ComposeDependency `json:"dependencies,omitempty"`
+	// Volumes are source:target[:mode] mounts with host paths in canonical
+	// form, so compose files written on Windows read the same as others.
+	Volumes []string `json:"volumes,omitempty"`
+	// UnmodeledKeys lists service-level compose keys that a DevArch workspace
+	// resource cannot express, so configuration carried over from this
+	// service would silently lose them.
+	UnmodeledKeys []string `json:"unmodeledKeys,omitempty"`
+}
+
+// ComposePort is one compose port in long syntax. Target and Published are
+// strings so ranges such as 8000-8010 survive.
+type ComposePort struct {
+	Target      string `json:"target"`
+	Published   string `json:"published,omitempty"`
+	HostIP      string `json:"hostIP,omitempty"`
+	Protocol    string `json:"protocol,omitempty"`
+	Mode        string `json:"mode,omitempty"`
+	Name        string `json:"name,omitempty"`
+	AppProtocol string `json:"appProtocol,omitempty"`
+}
+
+// String renders the port in short syntax, which has no place for Mode,
+// Name, or AppProtocol.
+func (p ComposePort) String() string {
+	value := p.Target
+	if p.Published != "" {
+		value = p.Published + ":" + value
+	}
+	if p.HostIP != "" {
+		value = p.HostIP + ":" + value
+	}
+	if p.Protocol != "" {
+		value += "/" + p.Protocol
+	}
+	return value
+}
+
+// ComposeDependency is one depends_on entry. Condition is service_started,
+// service_healthy, or service_completed_successfully; the list form of
+// depends_on implies service_started.
+type ComposeDependency struct {
+	Service   string `json:"service"`
+	Condition string `json:"condition"`
+}
+
+// Script is a named command from package.json or composer.json.
+type Script struct {
+	Name    string `json:"name"`
+	Command string `json:"command"`
+	Source  string `json:"source"`
+}
+
+// Extension is an installed WordPress plugin or theme, read from its file
+// header.
+type Extension struct {
+	Slug    string `json:"slug"`
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
 }
 
 // Result is the transport-safe project scan shape used by the shared service
@@ -39,6 +105,8 @@ type Result struct {
 	Framework          string           `json:"framework,omitempty"`
 	Language           string           `json:"language,omitempty"`
 	PackageManager     string           `json:"packageManager,omitempty"`
+	NodeVersion        string           `json:"nodeVersion,omitempty"`
+	NodeVersionSource  string           `json:"nodeVersionSource,omitempty"`
 	Description        string           `json:"description,omitempty"`
 	Version            string           `json:"version,omitempty"`
 	EntryPoint         string           `json:"entryPoint,omitempty"`
@@ -48,7 +116,17 @@ type Result struct {
 	ServiceCount       int              `json:"serviceCount,omitempty"`
 	Services           []ComposeService `json:"services,omitempty"`
 	SuggestedTemplates []string         `json:"suggestedTemplates,omitempty"`
+	Dependencies       []string         `json:"dependencies,omitempty"`
+	Scripts            []Script         `json:"scripts,omitempty"`
+	Plugins            []Extension      `json:"plugins,omitempty"`
+	Themes             []Extension      `json:"themes,omitempty"`
+	Overrides          []string         `json:"overrides,omitempty"`
+	Archived           bool             `json:"archived,omitempty"`
+	GitBranch          string           `json:"gitBranch,omitempty"`
+	GitCommit          string           `json:"gitCommit,omitempty"`
 	Diagnostics        []Diagnostic     `json:"diagnostics,omitempty"`
+	// ScanMs is how long the scan took; only ScanAll records it.
+	ScanMs int64 `json:"scanMs,omitempty"`
 }
 
 type composeFile struct {
@@ -56,9 +134,34 @@ type composeFile struct {
 }
 
 type composeServiceDef struct {
-	Image     string      `yaml:"image"`
-	Ports     interface{} `yaml:"ports"`
-	DependsOn interface{} `yaml:"depends_on"`
+	Image     string                 `yaml:"image"`
+	Ports     interface{}            `yaml:"ports"`
+	DependsOn interface{}            `yaml:"depends_on"`
+	Volumes   []interface{}          `yaml:"volumes"`
+	Extra     map[string]interface{} `yaml:",inline"`
+}
+
+// resourceComposeKeys are compose service keys with a DevArch workspace
+// resource or template equivalent.
+var resourceComposeKeys = map[string]bool{
+	"build":             true,
+	"command":           true,
+	"container_name":    true,
+	"depends_on":        true,
+	"develop":           true,
+	"entrypoint":        true,
+	"environment":       true,
+	"env_file":          true,
+	"healthcheck":       true,
+	"image":             true,
+	"labels":            true,
+	"networks":          true,
+	"ports":             true,
+	"restart":           true,
+	"stop_grace_period": true,
+	"stop_signal":       true,
+	"volumes":           true,
+	"working_dir":       true,
 }
 
 // Scan inspects a project directory and returns a small structured summary plus
@@ -108,15 +211,44 @@ func Scan(path string) (*Result, error) {
 	if hasPackageJSON && result.ProjectType != "node" {
 		detectFrontend(result, cleanPath)
 	}
+	result.Scripts = scanScripts(cleanPath)
+	result.GitBranch, result.GitCommit = gitHead(cleanPath)
 	composeFiles, services, diagnostics := scanCompose(cleanPath)
 	result.ComposeFiles = composeFiles
 	result.Services = services
 	result.ServiceCount = len(services)
 	result.Diagnostics = append(result.Diagnostics, diagnostics...)
+	applyOverride(result, cleanPath)
 	result.SuggestedTemplates = suggestedTemplates(result)
 	return result, nil
 }
 
+// gitHead reads the checked-out branch, or the commit of a detached HEAD,
+// from .git/HEAD without running git. A .git file, as used by worktrees and
+// submodules, is followed to its gitdir.
+func gitHead(dir string) (string, string) {
+	gitDir := filepath.Join(dir, ".git")
+	if content, err := os.ReadFile(gitDir); err == nil {
+		target, ok := strings.CutPrefix(strings.TrimSpace(string(content)), "gitdir:")
+		if !ok {
+			return "", ""
+		}
+		gitDir = strings.TrimSpace(target)
+		if !filepath.IsAbs(gitDir) {
+			gitDir = filepath.Join(dir, gitDir)
+		}
+	}
+	content, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return "", ""
+	}
+	head := strings.TrimSpace(string(content))
+	if ref, ok := strings.CutPrefix(head, "ref:"); ok {
+		return strings.TrimPrefix(strings.TrimSpace(ref), "refs/heads/"), ""
+	}
+	return "", head
+}
+
 func scanLaravel(result *Result, dir string) {
 	result.ProjectType = "laravel"
 	result.Language = "php"
@@ -136,6 +268,7 @@ func scanLaravel(result *Result, dir string) {
 			result.Version = version
 		}
 	}
+	result.Dependencies = sortedKeys(mergeStringMaps(mapField(data, "require"), mapField(data, "require-dev")))
 	if fileExists(filepath.Join(dir, "package.json")) {
 		detectFrontend(result, dir)
 	}
@@ -155,6 +288,65 @@ func scanWordPress(result *Result, dir string) {
 			result.Version = string(matches[1])
 		}
 	}
+	result.Plugins = wordPressExtensions(filepath.Join(dir, "wp-content", "plugins"), "Plugin Name")
+	result.Themes = wordPressExtensions(filepath.Join(dir, "wp-content", "themes"), "Theme Name")
+}
+
+// wordPressExtensions lists the plugins or themes installed under dir. A
+// plugin is a PHP file directly in dir or in one of its subdirectories
+// whose header has a "Plugin Name:" line; a theme is a subdirectory whose
+// style.css has a "Theme Name:" line.
+func wordPressExtensions(dir, nameField string) []Extension {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var extensions []Extension
+	for _, entry := range entries {
+		var candidates []string
+		slug := entry.Name()
+		switch {
+		case entry.IsDir() && nameField == "Theme Name":
+			candidates = []string{filepath.Join(dir, slug, "style.css")}
+		case entry.IsDir():
+			candidates, _ = filepath.Glob(filepath.Join(dir, slug, "*.php"))
+		case strings.HasSuffix(slug, ".php") && nameField == "Plugin Name":
+			candidates = []string{filepath.Join(dir, slug)}
+			slug = strings.TrimSuffix(slug, ".php")
+		}
+		for _, candidate := range candidates {
+			header := wordPressHeader(candidate)
+			if header[nameField] == "" {
+				continue
+			}
+			extensions = append(extensions, Extension{Slug: slug, Name: header[nameField], Version: header["Version"]})
+			break
+		}
+	}
+	return extensions
+}
+
+// wordPressHeaderPattern matches "Field: value" lines in the comment block
+// WordPress reads plugin and theme metadata from.
+var wordPressHeaderPattern = regexp.MustCompile(`(?m)^[ \t/*#@]*(Plugin Name|Theme Name|Version):[ \t]*(.+?)[ \t]*(?:\*/)?[ \t]*$`)
+
+// wordPressHeader reads the header fields from the first 8 KiB of filename,
+// as WordPress does.
+func wordPressHeader(filename string) map[string]string {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+	buffer := make([]byte, 8192)
+	n, _ := io.ReadFull(file, buffer)
+	header := map[string]string{}
+	for _, match := range wordPressHeaderPattern.FindAllSubmatch(buffer[:n], -1) {
+		if _, seen := header[string(match[1])]; !seen {
+			header[string(match[1])] = string(match[2])
+		}
+	}
+	return header
 }
 
 func scanGo(result *Result, dir string) {
@@ -184,9 +376,13 @@ func scanGo(result *Result, dir string) {
 		case trimmed == ")":
 			inRequire = false
 		case strings.HasPrefix(trimmed, "require ") && !strings.Contains(trimmed, "("):
-			result.Framework = appendFramework(result.Framework, detectGoFramework(strings.Fields(strings.TrimPrefix(trimmed, "require "))))
-		case inRequire:
-			result.Framework = appendFramework(result.Framework, detectGoFramework(strings.Fields(trimmed)))
+			fields := strings.Fields(strings.TrimPrefix(trimmed, "require "))
+			result.Framework = appendFramework(result.Framework, detectGoFramework(fields))
+			result.Dependencies = append(result.Dependencies, fields[0])
+		case inRequire && trimmed != "" && !strings.HasPrefix(trimmed, "//"):
+			fields := strings.Fields(trimmed)
+			result.Framework = appendFramework(result.Framework, detectGoFramework(fields))
+			result.Dependencies = append(result.Dependencies, fields[0])
 		}
 	}
 	if matches, _ := filepath.Glob(filepath.Join(dir, "cmd", "*", "main.go")); len(matches) > 0 {
@@ -202,6 +398,7 @@ func scanNode(result *Result, dir string) {
 	result.ProjectType = "node"
 	result.Language = "javascript"
 	result.PackageManager = "npm"
+	result.NodeVersion, result.NodeVersionSource = NodeVersion(dir)
 
 	data := readJSON(filepath.Join(dir, "package.json"))
 	if data == nil {
@@ -223,9 +420,34 @@ func scanNode(result *Result, dir string) {
 	}
 
 	result.Framework = detectNodeFramework(data)
+	result.Dependencies = sortedKeys(mergeStringMaps(mapField(data, "dependencies"), mapField(data, "devDependencies")))
 	detectFrontend(result, dir)
 }
 
+// scanScripts lists the package.json scripts, then the composer.json
+// scripts, each sorted by name. A composer script given as a list of
+// commands is joined with "&&".
+func scanScripts(dir string) []Script {
+	var scripts []Script
+	for _, source := range []string{"package.json", "composer.json"} {
+		data := readJSON(filepath.Join(dir, source))
+		entries := mapField(data, "scripts")
+		for _, name := range sortedKeys(entries) {
+			var command string
+			switch value := entries[name].(type) {
+			case string:
+				command = value
+			case []any:
+				command = strings.Join(stringifyList(value), " && ")
+			default:
+				continue
+			}
+			scripts = append(scripts, Script{Name: name, Command: command, Source: source})
+		}
+	}
+	return scripts
+}
+
 func detectFrontend(result *Result, dir string) {
 	data := readJSON(filepath.Join(dir, "package.json"))
 	if data == nil {
@@ -287,17 +509,166 @@ func parseCompose(path string) ([]ComposeService, []Diagnostic) {
 	}
 	sort.Strings(keys)
 	services := make([]ComposeService, 0, len(keys))
+	var diagnostics []Diagnostic
 	for _, key := range keys {
 		service := compose.Services[key]
+		unmodeled := unmodeledComposeKeys(service.Extra)
 		services = append(services, ComposeService{
-			Name:        key,
-			Image:       strings.TrimSpace(service.Image),
-			ServiceType: detectServiceType(key, service.Image),
-			Ports:       stringifyList(service.Ports),
-			DependsOn:   stringifyList(service.DependsOn),
+			Name:          key,
+			Image:         strings.TrimSpace(service.Image),
+			ServiceType:   detectServiceType(key, service.Image),
+			Ports:         composePortStrings(service.Ports),
+			PortSpecs:     composePorts(service.Ports),
+			DependsOn:     stringifyList(service.DependsOn),
+			Dependencies:  composeDependencies(service.DependsOn),
+			Volumes:       composeVolumes(service.Volumes),
+			UnmodeledKeys: unmodeled,
 		})
+		if len(unmodeled) > 0 {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: "warning",
+				Code:     "compose-unmodeled-keys",
+				Message:  fmt.Sprintf("compose service %q uses keys with no workspace resource equivalent: %s", key, strings.Join(unmodeled, ", ")),
+			})
+		}
+	}
+	return services, diagnostics
+}
+
+// composePorts reads short and long port syntax.
+func composePorts(value any) []ComposePort {
+	items, _ := value.([]any)
+	var ports []ComposePort
+	for _, item := range items {
+		ports = append(ports, composePort(item))
 	}
-	return services, nil
+	return ports
+}
+
+// composePortStrings keeps short-syntax ports as written and renders
+// long-syntax ones to short syntax.
+func composePortStrings(value any) []string {
+	items, _ := value.([]any)
+	var ports []string
+	for _, item := range items {
+		if short, ok := item.(string); ok {
+			ports = append(ports, short)
+			continue
+		}
+		ports = append(ports, composePort(item).String())
+	}
+	return ports
+}
+
+func composePort(item any) ComposePort {
+	typed, ok := item.(map[string]any)
+	if !ok {
+		return parseShortPort(fmt.Sprint(item))
+	}
+	port := ComposePort{Target: composeScalar(typed["target"]), Published: composeScalar(typed["published"])}
+	port.HostIP, _ = typed["host_ip"].(string)
+	port.Protocol, _ = typed["protocol"].(string)
+	port.Mode, _ = typed["mode"].(string)
+	port.Name, _ = typed["name"].(string)
+	port.AppProtocol, _ = typed["app_protocol"].(string)
+	return port
+}
+
+// parseShortPort reads [host_ip:][published:]target[/protocol]. An IPv6
+// host IP is written in brackets.
+func parseShortPort(value string) ComposePort {
+	var port ComposePort
+	value, port.Protocol, _ = strings.Cut(value, "/")
+	if index := strings.LastIndex(value, ":"); index >= 0 {
+		value, port.Target = value[:index], value[index+1:]
+		if index := strings.LastIndex(value, ":"); index >= 0 && !strings.HasSuffix(value, "]") {
+			port.HostIP, port.Published = value[:index], value[index+1:]
+		} else {
+			port.Published = value
+		}
+	} else {
+		port.Target = value
+	}
+	return port
+}
+
+func composeScalar(value any) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// composeDependencies reads both forms of depends_on.
+func composeDependencies(value any) []ComposeDependency {
+	var dependencies []ComposeDependency
+	switch typed := value.(type) {
+	case []any:
+		for _, item := range typed {
+			dependencies = append(dependencies, ComposeDependency{Service: fmt.Sprint(item), Condition: "service_started"})
+		}
+	case map[string]any:
+		for _, service := range sortedKeys(typed) {
+			dependency := ComposeDependency{Service: service, Condition: "service_started"}
+			if options, ok := typed[service].(map[string]any); ok {
+				if condition, ok := options["condition"].(string); ok && condition != "" {
+					dependency.Condition = condition
+				}
+			}
+			dependencies = append(dependencies, dependency)
+		}
+	}
+	return dependencies
+}
+
+// composeVolumes reads short (src:dst[:mode]) and long volume syntax. A
+// Windows drive letter's colon is not taken as the separator.
+func composeVolumes(values []interface{}) []string {
+	var volumes []string
+	for _, value := range values {
+		var source, rest string
+		switch typed := value.(type) {
+		case string:
+			offset := 0
+			if workspace.IsWindowsDrivePath(typed) {
+				offset = 2
+			}
+			if index := strings.Index(typed[offset:], ":"); index >= 0 {
+				source, rest = typed[:offset+index], typed[offset+index+1:]
+			} else {
+				rest = typed
+			}
+		case map[string]interface{}:
+			source, _ = typed["source"].(string)
+			rest, _ = typed["target"].(string)
+			if readOnly, _ := typed["read_only"].(bool); readOnly {
+				rest += ":ro"
+			}
+		default:
+			continue
+		}
+		if source == "" {
+			volumes = append(volumes, rest)
+			continue
+		}
+		volumes = append(volumes, workspace.CanonicalHostPath(source)+":"+rest)
+	}
+	return volumes
+}
+
+func unmodeledComposeKeys(extra map[string]interface{}) []string {
+	keys := make([]string, 0, len(extra))
+	for key := range extra {
+		if resourceComposeKeys[key] || strings.HasPrefix(key, "x-") {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 func suggestedTemplates(result *Result) []string {
@@ -454,6 +825,18 @@ func stringifyList(value any) []string {
 	}
 }
 
+func sortedKeys(values map[string]any) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func mapField(data map[string]any, key string) map[string]any {
 	value, _ := data[key].(map[string]any)
 	return value
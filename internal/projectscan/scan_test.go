@@ -56,6 +56,125 @@ func TestScanNodeProjectDerivesTemplatesAndComposeServices(t *testing.T) {
 	}
 }
 
+func TestScanCanonicalizesWindowsComposeVolumes(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "compose.yml"), `services:
+  web:
+    image: nginx:alpine
+    volumes:
+      - C:\Users\ada\shop\html:/usr/share/nginx/html:ro
+      - .\nginx.conf:/etc/nginx/nginx.conf
+      - web-cache:/var/cache/nginx
+      - /tmp
+      - type: bind
+        source: d:\certs\
+        target: /certs
+        read_only: true
+`)
+	result, err := Scan(root)
+	if err != nil {
+		t.Fatalf("Scan returned error: %v", err)
+	}
+	want := []string{"C:/Users/ada/shop/html:/usr/share/nginx/html:ro", "./nginx.conf:/etc/nginx/nginx.conf", "web-cache:/var/cache/nginx", "/tmp", "D:/certs:/certs:ro"}
+	if got := result.Services[0].Volumes; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Volumes = %q, want %q", got, want)
+	}
+}
+
+func TestScanKeepsDependsOnConditionsAndLongPortSyntax(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "compose.yml"), `services:
+  api:
+    image: node:22
+    ports:
+      - "127.0.0.1:8443:443/tcp"
+      - 9229
+      - target: 3000
+        published: "8080"
+        host_ip: 0.0.0.0
+        protocol: tcp
+        mode: host
+        name: web
+        app_protocol: http
+    depends_on:
+      migrate:
+        condition: service_completed_successfully
+      db:
+        condition: service_healthy
+      cache: {}
+`)
+	result, err := Scan(root)
+	if err != nil {
+		t.Fatalf("Scan returned error: %v", err)
+	}
+	api := result.Services[0]
+	wantDependencies := []ComposeDependency{
+		{Service: "cache", Condition: "service_started"},
+		{Service: "db", Condition: "service_healthy"},
+		{Service: "migrate", Condition: "service_completed_successfully"},
+	}
+	if !reflect.DeepEqual(api.Dependencies, wantDependencies) || !reflect.DeepEqual(api.DependsOn, []string{"cache", "db", "migrate"}) {
+		t.Fatalf("Dependencies = %+v, DependsOn = %v", api.Dependencies, api.DependsOn)
+	}
+	wantPorts := []ComposePort{
+		{Target: "443", Published: "8443", HostIP: "127.0.0.1", Protocol: "tcp"},
+		{Target: "9229"},
+		{Target: "3000", Published: "8080", HostIP: "0.0.0.0", Protocol: "tcp", Mode: "host", Name: "web", AppProtocol: "http"},
+	}
+	if !reflect.DeepEqual(api.PortSpecs, wantPorts) {
+		t.Fatalf("PortSpecs = %+v, want %+v", api.PortSpecs, wantPorts)
+	}
+	if want := []string{"127.0.0.1:8443:443/tcp", "9229", "0.0.0.0:8080:3000/tcp"}; !reflect.DeepEqual(api.Ports, want) {
+		t.Fatalf("Ports = %v, want %v", api.Ports, want)
+	}
+	// Short syntax round-trips through ComposePort, less the long-only fields.
+	for i, short := range api.Ports {
+		port := wantPorts[i]
+		port.Mode, port.Name, port.AppProtocol = "", "", ""
+		if got := parseShortPort(short); got != port || got.String() != short {
+			t.Fatalf("parseShortPort(%q) = %+v, want %+v", short, got, port)
+		}
+	}
+}
+
+func TestScanFlagsUnmodeledComposeKeys(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "compose.yml"), `services:
+  db:
+    image: postgres:16
+    environment:
+      POSTGRES_DB: shop
+    shm_size: 256m
+    cap_add:
+      - SYS_PTRACE
+    x-devarch-note: ignored
+  cache:
+    image: redis:7
+    healthcheck:
+      test: ["CMD", "redis-cli", "ping"]
+`)
+
+	result, err := Scan(root)
+	if err != nil {
+		t.Fatalf("Scan returned error: %v", err)
+	}
+	if got := result.Services[0].UnmodeledKeys; got != nil {
+		t.Fatalf("cache UnmodeledKeys = %v, want none", got)
+	}
+	if got, want := result.Services[1].UnmodeledKeys, []string{"cap_add", "shm_size"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("db UnmodeledKeys = %v, want %v", got, want)
+	}
+	found := false
+	for _, diagnostic := range result.Diagnostics {
+		if diagnostic.Code == "compose-unmodeled-keys" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Diagnostics = %#v, want compose-unmodeled-keys", result.Diagnostics)
+	}
+}
+
 func TestScanLaravelProjectSuggestsLaravelTemplate(t *testing.T) {
 	root := t.TempDir()
 	writeFile(t, filepath.Join(root, "artisan"), "#!/usr/bin/env php\n")
@@ -88,3 +207,185 @@ func writeFile(t *testing.T, path, content string) {
 		t.Fatalf("os.WriteFile(%s): %v", path, err)
 	}
 }
+
+func TestScanAllAppliesIgnoreFileAndDepthRules(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "shop", "package.json"), `{"dependencies": {"express": "^4"}}`)
+	writeFile(t, filepath.Join(root, "shop", "node_modules", "left-pad", "package.json"), `{}`)
+	writeFile(t, filepath.Join(root, "archive", "old-shop", "package.json"), `{}`)
+	writeFile(t, filepath.Join(root, "archive", "keep", "go.mod"), "module keep\n")
+	writeFile(t, filepath.Join(root, "clients", "acme", "site", "composer.json"), `{}`)
+	writeFile(t, filepath.Join(root, IgnoreFilename), "# old checkouts\narchive/*\n!archive/keep\ndepth 1 clients\n")
+
+	inventory, err := ScanAll(root, ScanOptions{})
+	if err != nil {
+		t.Fatalf("ScanAll returned error: %v", err)
+	}
+	var names []string
+	for _, project := range inventory.Projects {
+		names = append(names, project.Name)
+	}
+	if want := []string{"keep", "shop"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("projects = %v, want %v", names, want)
+	}
+	if want := []Skipped{{Path: "archive/old-shop", Rule: "archive/*"}}; !reflect.DeepEqual(inventory.Skipped, want) {
+		t.Fatalf("skipped = %#v, want %#v", inventory.Skipped, want)
+	}
+	if got := len(inventory.Rules); got != 6 || inventory.Rules[5].Action != "depth" {
+		t.Fatalf("rules = %#v, want 3 defaults and 3 from %s", inventory.Rules, IgnoreFilename)
+	}
+
+	inventory, err = ScanAll(root, ScanOptions{Exclude: []string{"shop"}, MaxDepth: 4})
+	if err != nil {
+		t.Fatalf("ScanAll with options returned error: %v", err)
+	}
+	if len(inventory.Projects) != 1 || inventory.Projects[0].Name != "keep" {
+		t.Fatalf("projects = %#v, want only keep once shop is excluded", inventory.Projects)
+	}
+}
+
+func TestScanAllReadsGitHeadWithoutGit(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "api", "go.mod"), "module api\n")
+	writeFile(t, filepath.Join(root, "api", ".git", "HEAD"), "ref: refs/heads/feature/login\n")
+	writeFile(t, filepath.Join(root, "web", "package.json"), `{}`)
+	writeFile(t, filepath.Join(root, "web", ".git"), "gitdir: ../.worktrees/web\n")
+	writeFile(t, filepath.Join(root, ".worktrees", "web", "HEAD"), "3f2a9c1d\n")
+
+	inventory, err := ScanAll(root, ScanOptions{Concurrency: 2, Exclude: []string{".worktrees"}})
+	if err != nil {
+		t.Fatalf("ScanAll returned error: %v", err)
+	}
+	if len(inventory.Projects) != 2 {
+		t.Fatalf("projects = %#v, want api and web", inventory.Projects)
+	}
+	api, web := inventory.Projects[0], inventory.Projects[1]
+	if api.Name != "api" || api.GitBranch != "feature/login" || api.GitCommit != "" {
+		t.Fatalf("api = %#v, want branch feature/login", api)
+	}
+	if web.Name != "web" || web.GitBranch != "" || web.GitCommit != "3f2a9c1d" {
+		t.Fatalf("web = %#v, want detached commit 3f2a9c1d", web)
+	}
+	if _, err := ScanAll(root, ScanOptions{Concurrency: -1}); err == nil {
+		t.Fatal("ScanAll accepted a negative concurrency")
+	}
+}
+
+func TestScanAllAppliesOverridesAndFilters(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "shop", "package.json"), `{
+  "scripts": {"test": "vitest", "build": "vite build"},
+  "dependencies": {"react": "^18", "express": "^4"}
+}`)
+	writeFile(t, filepath.Join(root, "shop", OverrideFilename), "name: storefront\nframework: Remix\n")
+	writeFile(t, filepath.Join(root, "billing", "composer.json"), `{
+  "require": {"laravel/framework": "^11.0"},
+  "scripts": {"post-install-cmd": ["@php artisan key:generate", "@php artisan migrate"]}
+}`)
+	writeFile(t, filepath.Join(root, "billing", "artisan"), "")
+	writeFile(t, filepath.Join(root, "legacy", "go.mod"), "module legacy\n\nrequire (\n\tgithub.com/go-chi/chi/v5 v5.0.0\n)\n")
+	writeFile(t, filepath.Join(root, "legacy", OverrideFilename), "archived: true\n")
+
+	inventory, err := ScanAll(root, ScanOptions{})
+	if err != nil {
+		t.Fatalf("ScanAll returned error: %v", err)
+	}
+	if len(inventory.Projects) != 2 || inventory.Hidden != 1 {
+		t.Fatalf("projects = %#v, hidden = %d, want billing and storefront with legacy archived", inventory.Projects, inventory.Hidden)
+	}
+	billing, shop := inventory.Projects[0], inventory.Projects[1]
+	if want := []Script{{Name: "post-install-cmd", Command: "@php artisan key:generate && @php artisan migrate", Source: "composer.json"}}; !reflect.DeepEqual(billing.Scripts, want) {
+		t.Fatalf("billing scripts = %#v, want %#v", billing.Scripts, want)
+	}
+	if shop.Name != "storefront" || shop.Framework != "Remix" || !reflect.DeepEqual(shop.Overrides, []string{"name", "framework"}) {
+		t.Fatalf("shop = %#v, want name and framework from %s", shop, OverrideFilename)
+	}
+	if want := []string{"express", "react"}; !reflect.DeepEqual(shop.Dependencies, want) {
+		t.Fatalf("shop dependencies = %v, want %v", shop.Dependencies, want)
+	}
+	if len(shop.Scripts) != 2 || shop.Scripts[0].Name != "build" {
+		t.Fatalf("shop scripts = %#v, want build and test", shop.Scripts)
+	}
+
+	hasFrontend := true
+	inventory, err = ScanAll(root, ScanOptions{HasFrontend: &hasFrontend})
+	if err != nil {
+		t.Fatalf("ScanAll with frontend filter returned error: %v", err)
+	}
+	if len(inventory.Projects) != 1 || inventory.Projects[0].Name != "storefront" {
+		t.Fatalf("projects = %#v, want only storefront", inventory.Projects)
+	}
+	inventory, err = ScanAll(root, ScanOptions{Framework: "chi", Archived: true})
+	if err != nil {
+		t.Fatalf("ScanAll with framework filter returned error: %v", err)
+	}
+	if len(inventory.Projects) != 1 || inventory.Projects[0].Name != "legacy" || !reflect.DeepEqual(inventory.Projects[0].Dependencies, []string{"github.com/go-chi/chi/v5"}) {
+		t.Fatalf("projects = %#v, want archived legacy with its chi dependency", inventory.Projects)
+	}
+}
+
+func TestScanWordPressListsPluginsAndThemes(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "wp-includes", "version.php"), "<?php\n$wp_version = '6.5.2';\n")
+	writeFile(t, filepath.Join(root, "wp-content", "plugins", "hello.php"), "<?php\n/*\nPlugin Name: Hello Dolly\nVersion: 1.7.2\n*/\n")
+	writeFile(t, filepath.Join(root, "wp-content", "plugins", "akismet", "index.php"), "<?php\n// Silence is golden.\n")
+	writeFile(t, filepath.Join(root, "wp-content", "plugins", "akismet", "akismet.php"), "<?php\n/**\n * Plugin Name: Akismet Anti-spam\n * Version: 5.3\n */\n")
+	writeFile(t, filepath.Join(root, "wp-content", "plugins", "index.php"), "<?php\n")
+	writeFile(t, filepath.Join(root, "wp-content", "themes", "twentytwentyfour", "style.css"), "/*\nTheme Name: Twenty Twenty-Four\nVersion: 1.1\n*/\n")
+
+	result, err := Scan(root)
+	if err != nil {
+		t.Fatalf("Scan returned error: %v", err)
+	}
+	if result.ProjectType != "wordpress" || result.Version != "6.5.2" {
+		t.Fatalf("result = %#v, want WordPress 6.5.2", result)
+	}
+	wantPlugins := []Extension{{Slug: "akismet", Name: "Akismet Anti-spam", Version: "5.3"}, {Slug: "hello", Name: "Hello Dolly", Version: "1.7.2"}}
+	if !reflect.DeepEqual(result.Plugins, wantPlugins) {
+		t.Fatalf("Plugins = %#v, want %#v", result.Plugins, wantPlugins)
+	}
+	if want := []Extension{{Slug: "twentytwentyfour", Name: "Twenty Twenty-Four", Version: "1.1"}}; !reflect.DeepEqual(result.Themes, want) {
+		t.Fatalf("Themes = %#v, want %#v", result.Themes, want)
+	}
+}
+
+func TestScanNodeReadsVersionAndPicksMajor(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "package.json"), `{"name": "shop-api", "engines": {"node": ">=18 <21"}}`)
+	result, err := Scan(root)
+	if err != nil {
+		t.Fatalf("Scan returned error: %v", err)
+	}
+	if result.NodeVersion != ">=18 <21" || result.NodeVersionSource != "package.json" {
+		t.Fatalf("node version = %q from %q, want engines.node", result.NodeVersion, result.NodeVersionSource)
+	}
+	writeFile(t, filepath.Join(root, ".nvmrc"), "v18.19.0\n")
+	if version, source := NodeVersion(root); version != "v18.19.0" || source != ".nvmrc" {
+		t.Fatalf("NodeVersion = %q from %q, want the .nvmrc pin", version, source)
+	}
+
+	for _, tc := range []struct {
+		constraint string
+		major      int
+		ok         bool
+	}{
+		{constraint: ">=18 <21", major: 20, ok: true},
+		{constraint: "^18.17", major: 18, ok: true},
+		{constraint: "v23.1.0", major: 23, ok: true},
+		{constraint: "16 || 20.x", major: 20, ok: true},
+		{constraint: ">=18", major: NodeLatestLTS, ok: true},
+		{constraint: "18.0.0 - 20", major: 20, ok: true},
+		{constraint: "lts/hydrogen", major: 18, ok: true},
+		{constraint: "lts/*", major: NodeLatestLTS, ok: true},
+		{constraint: "<19.0.0", major: 18, ok: true},
+		{constraint: ">99", ok: false},
+	} {
+		major, ok := NodeMajor(tc.constraint)
+		if major != tc.major || ok != tc.ok {
+			t.Errorf("NodeMajor(%q) = %d, %v; want %d, %v", tc.constraint, major, ok, tc.major, tc.ok)
+		}
+	}
+	if NodeSatisfies(">=20", 18) || !NodeSatisfies("not a range", 18) {
+		t.Fatal("NodeSatisfies should reject majors below the range and accept unparseable constraints")
+	}
+}
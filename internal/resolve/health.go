@@ -0,0 +1,29 @@
+package resolve
+
+import (
+	"fmt"
+	"strings"
+)
+
+// expandHealthShorthand replaces a tcp or http health shorthand with the
+// generated test command. The command tries the tools common in slim images
+// in turn, so it works without knowing what the image ships.
+func expandHealthShorthand(key string, health *Health) (*Health, error) {
+	if health == nil || (health.TCP == 0 && health.HTTP == "") {
+		return health, nil
+	}
+	if len(health.Test) > 0 || (health.TCP != 0 && health.HTTP != "") {
+		return nil, fmt.Errorf("resource %s health: set only one of test, tcp, and http", key)
+	}
+
+	expanded := cloneHealth(health)
+	if health.TCP != 0 {
+		expanded.Test = StringList{"CMD-SHELL", fmt.Sprintf("nc -z 127.0.0.1 %d || bash -c 'exec 3<>/dev/tcp/127.0.0.1/%d'", health.TCP, health.TCP)}
+	} else {
+		url := strings.ReplaceAll(health.HTTP, "'", "%27")
+		expanded.Test = StringList{"CMD-SHELL", fmt.Sprintf("wget -q -O /dev/null '%s' || curl -fsS -o /dev/null '%s'", url, url)}
+	}
+	expanded.TCP = 0
+	expanded.HTTP = ""
+	return expanded, nil
+}
@@ -1,6 +1,9 @@
 package resolve
 
-import "github.com/prospect-ogujiuba/devarch/internal/workspace"
+import (
+	"github.com/prospect-ogujiuba/devarch/internal/catalog"
+	"github.com/prospect-ogujiuba/devarch/internal/workspace"
+)
 
 // Graph is the deterministic effective graph shared by resolve, contracts, and
 // runtime planning. It intentionally omits runtime snapshot, apply state, and
@@ -19,6 +22,7 @@ type Workspace struct {
 	Description    string                       `json:"description,omitempty"`
 	Runtime        workspace.RuntimePreferences `json:"runtime,omitempty"`
 	Policies       workspace.Policies           `json:"policies,omitempty"`
+	Variables      map[string]string            `json:"variables,omitempty"`
 	CatalogSources []string                     `json:"catalogSources,omitempty"`
 
 	ManifestPath string `json:"-"`
@@ -34,6 +38,7 @@ type Resource struct {
 	Source    *SourceRef          `json:"source,omitempty"`
 	Runtime   *Runtime            `json:"runtime,omitempty"`
 	Env       map[string]EnvValue `json:"env,omitempty"`
+	EnvSchema map[string]EnvVar   `json:"envSchema,omitempty"`
 	Ports     []Port              `json:"ports,omitempty"`
 	Volumes   []Volume            `json:"volumes,omitempty"`
 	DependsOn []string            `json:"dependsOn,omitempty"`
@@ -43,6 +48,18 @@ type Resource struct {
 	Domains   []string            `json:"domains,omitempty"`
 	Develop   map[string]any      `json:"develop,omitempty"`
 	Overrides map[string]any      `json:"overrides,omitempty"`
+	Replicas  int                 `json:"replicas,omitempty"`
+
+	LoadBalancer *LoadBalancer `json:"loadBalancer,omitempty"`
+	Hooks        *Hooks        `json:"hooks,omitempty"`
+	Inspector    *Inspector    `json:"inspector,omitempty"`
+	Init         bool          `json:"init,omitempty"`
+	Userns       string        `json:"userns,omitempty"`
+	Memory       string        `json:"memory,omitempty"`
+	// StopSignal and StopTimeout are the resource's own overrides of the
+	// template runtime values.
+	StopSignal  string `json:"stopSignal,omitempty"`
+	StopTimeout *int   `json:"stopTimeout,omitempty"`
 }
 
 type TemplateRef struct {
@@ -66,6 +83,10 @@ type Runtime struct {
 	Command    StringList `json:"command,omitempty"`
 	Entrypoint StringList `json:"entrypoint,omitempty"`
 	WorkingDir string     `json:"workingDir,omitempty"`
+	// StopSignal and StopTimeout tune graceful shutdown. StopTimeout is in
+	// seconds.
+	StopSignal  string `json:"stopSignal,omitempty"`
+	StopTimeout *int   `json:"stopTimeout,omitempty"`
 }
 
 type Build struct {
@@ -82,6 +103,8 @@ type StringList = workspace.StringList
 
 type EnvValue = workspace.EnvValue
 
+type EnvVar = catalog.TemplateEnvVar
+
 type Port = workspace.Port
 
 type Volume = workspace.Volume
@@ -92,6 +115,12 @@ type Export = workspace.Export
 
 type Health = workspace.Health
 
+type LoadBalancer = workspace.LoadBalancer
+
+type Inspector = workspace.Inspector
+
+type Hooks = workspace.Hooks
+
 func (g *Graph) Resource(key string) *Resource {
 	if g == nil {
 		return nil
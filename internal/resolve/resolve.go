@@ -2,6 +2,7 @@ package resolve
 
 import (
 	"fmt"
+	"maps"
 	"path/filepath"
 
 	"github.com/prospect-ogujiuba/devarch/internal/catalog"
@@ -20,6 +21,19 @@ func (e *MissingTemplateError) Error() string {
 	return fmt.Sprintf("resource %q references unknown template %q", e.ResourceKey, e.TemplateName)
 }
 
+// HiddenTemplateError reports a resource referencing a catalog template whose
+// visibility does not include the workspace's team.
+type HiddenTemplateError struct {
+	ResourceKey  string
+	TemplateName string
+	Visibility   string
+	Team         string
+}
+
+func (e *HiddenTemplateError) Error() string {
+	return fmt.Sprintf("resource %q references template %q, which team %q has not shared with this workspace (visibility %s)", e.ResourceKey, e.TemplateName, e.Team, e.Visibility)
+}
+
 // Resolve resolves a loaded workspace against a deterministic catalog index.
 func Resolve(ws *workspace.Workspace, index *catalog.Index) (*Graph, error) {
 	if ws == nil {
@@ -33,6 +47,7 @@ func Resolve(ws *workspace.Workspace, index *catalog.Index) (*Graph, error) {
 			Description:    ws.Metadata.Description,
 			Runtime:        ws.Runtime,
 			Policies:       ws.Policies,
+			Variables:      cloneStringMap(ws.Variables),
 			CatalogSources: append([]string(nil), ws.Catalog.Sources...),
 			ManifestPath:   ws.ManifestPath,
 			ManifestDir:    ws.ManifestDir,
@@ -45,6 +60,9 @@ func Resolve(ws *workspace.Workspace, index *catalog.Index) (*Graph, error) {
 		if err != nil {
 			return nil, err
 		}
+		if err := substituteVariables(resource, ws.Variables); err != nil {
+			return nil, err
+		}
 		graph.Resources = append(graph.Resources, resource)
 	}
 
@@ -66,6 +84,25 @@ func buildResource(ws *workspace.Workspace, index *catalog.Index, key string, re
 		Domains:   normalizeStringSlice(resource.Domains),
 		Develop:   cloneRawMap(resource.Develop),
 		Overrides: cloneRawMap(resource.Overrides),
+		Replicas:  resource.Replicas,
+		Hooks:     resource.Hooks.Clone(),
+		Init:      resource.Init,
+		Userns:    resource.Userns,
+		Memory:    resource.Memory,
+
+		StopSignal: resource.StopSignal,
+	}
+	if resource.StopTimeout != nil {
+		timeout := *resource.StopTimeout
+		resolved.StopTimeout = &timeout
+	}
+	if resource.LoadBalancer != nil {
+		balancer := *resource.LoadBalancer
+		resolved.LoadBalancer = &balancer
+	}
+	if resource.Inspector != nil {
+		inspector := *resource.Inspector
+		resolved.Inspector = &inspector
 	}
 
 	if resource.Source != nil {
@@ -82,8 +119,12 @@ func buildResource(ws *workspace.Workspace, index *catalog.Index, key string, re
 		resolved.Volumes = mergeVolumes(nil, resolved.Volumes)
 		resolved.Imports = mergeImports(nil, resolved.Imports)
 		resolved.Exports = mergeExports(nil, resolved.Exports)
-		resolved.Health = selectHealth(nil, resolved.Health)
 		resolved.Develop = selectRawMap(nil, resolved.Develop)
+		health, err := expandHealthShorthand(key, selectHealth(nil, resolved.Health))
+		if err != nil {
+			return nil, err
+		}
+		resolved.Health = health
 		return resolved, nil
 	}
 
@@ -91,6 +132,9 @@ func buildResource(ws *workspace.Workspace, index *catalog.Index, key string, re
 	if !ok {
 		return nil, &MissingTemplateError{ResourceKey: key, TemplateName: resource.Template}
 	}
+	if !template.Metadata.VisibleTo(ws.Metadata.Team) {
+		return nil, &HiddenTemplateError{ResourceKey: key, TemplateName: resource.Template, Visibility: template.Metadata.Visibility, Team: template.Metadata.Team}
+	}
 
 	resolved.Template = &TemplateRef{
 		Name:         template.Metadata.Name,
@@ -113,12 +157,16 @@ func buildResource(ws *workspace.Workspace, index *catalog.Index, key string, re
 
 	resolved.Runtime = templateRuntime
 	resolved.Env = mergeEnv(templateEnv, resource.Env)
+	resolved.EnvSchema = maps.Clone(template.Spec.EnvSchema)
 	resolved.Ports = mergePorts(convertPorts(template.Spec.Ports), resource.Ports)
 	resolved.Volumes = mergeVolumes(convertVolumes(template.Spec.Volumes), resource.Volumes)
 	resolved.Imports = mergeImports(convertImports(template.Spec.Imports), resource.Imports)
 	resolved.Exports = mergeExports(convertExports(template.Spec.Exports), resource.Exports)
-	resolved.Health = selectHealth(templateHealth, resource.Health)
 	resolved.Develop = selectRawMap(template.Spec.Develop, resource.Develop)
+	resolved.Health, err = expandHealthShorthand(key, selectHealth(templateHealth, resource.Health))
+	if err != nil {
+		return nil, err
+	}
 
 	return resolved, nil
 }
@@ -185,6 +233,7 @@ func convertVolumes(volumes []catalog.TemplateVolume) []Volume {
 			Target:   volumes[i].Target,
 			ReadOnly: volumes[i].ReadOnly,
 			Kind:     volumes[i].Kind,
+			Options:  append([]string(nil), volumes[i].Options...),
 		}
 	}
 	return converted
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"slices"
 	"testing"
@@ -48,6 +49,140 @@ resources:
 	}
 }
 
+func TestResolveRejectsTemplatesHiddenFromTheWorkspaceTeam(t *testing.T) {
+	catalogRoot := t.TempDir()
+	writeResolveWorkspaceFixture(t, filepath.Join(catalogRoot, "ledger", catalog.TemplateFilename), `apiVersion: devarch.io/alpha1
+kind: Template
+metadata:
+  name: ledger
+  team: payments
+  visibility: shared
+  sharedWith: [billing]
+spec:
+  runtime:
+    image: ghcr.io/payments/ledger:1
+`)
+	index := loadCatalogIndex(t, []string{catalogRoot})
+	for team, visible := range map[string]bool{"payments": true, "billing": true, "search": false, "": false} {
+		manifest := "apiVersion: devarch.io/alpha1\nkind: Workspace\nmetadata:\n  name: ledger-local\n"
+		if team != "" {
+			manifest += "  team: " + team + "\n"
+		}
+		manifest += "resources:\n  ledger:\n    template: ledger\n"
+		ws, err := workspacepkg.Load(writeResolveWorkspaceFixture(t, filepath.Join(t.TempDir(), "devarch.workspace.yaml"), manifest))
+		if err != nil {
+			t.Fatalf("workspace.Load returned error: %v", err)
+		}
+		_, err = Resolve(ws, index)
+		var hiddenErr *HiddenTemplateError
+		if visible && err != nil {
+			t.Fatalf("team %q: Resolve returned error: %v", team, err)
+		}
+		if !visible && !errors.As(err, &hiddenErr) {
+			t.Fatalf("team %q: Resolve error = %v, want HiddenTemplateError", team, err)
+		}
+	}
+}
+
+func TestResolveSubstitutesWorkspaceVariables(t *testing.T) {
+	catalogRoot := filepath.ToSlash(filepath.Join(repoRoot(t), "catalog", "builtin"))
+	manifestPath := writeResolveWorkspaceFixture(t, filepath.Join(t.TempDir(), "devarch.workspace.yaml"), `apiVersion: devarch.io/alpha1
+kind: Workspace
+metadata:
+  name: vars-local
+catalog:
+  sources:
+    - `+catalogRoot+`
+variables:
+  DB_NAME: shop
+  DOMAIN: shop.test
+resources:
+  postgres:
+    template: postgres
+    env:
+      POSTGRES_DB: ${var.DB_NAME}
+      DATABASE_URL: postgres://devarch@postgres/${var.DB_NAME}
+      SHELL_STYLE: ${HOME}
+    domains:
+      - db.${var.DOMAIN}
+`)
+	ws, err := workspacepkg.Load(manifestPath)
+	if err != nil {
+		t.Fatalf("workspace.Load(%s) returned error: %v", manifestPath, err)
+	}
+	graph, err := Resolve(ws, loadCatalogIndex(t, ws.ResolvedCatalogSources()))
+	if err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	postgres := graph.Resource("postgres")
+	for key, want := range map[string]string{"POSTGRES_DB": "shop", "DATABASE_URL": "postgres://devarch@postgres/shop", "SHELL_STYLE": "${HOME}"} {
+		if got := postgres.Env[key].Text(); got != want {
+			t.Fatalf("postgres.Env[%s] = %q, want %q", key, got, want)
+		}
+	}
+	if got, want := postgres.Domains, []string{"db.shop.test"}; !slices.Equal(got, want) {
+		t.Fatalf("postgres.Domains = %v, want %v", got, want)
+	}
+	if got := ws.Resources["postgres"].Env["POSTGRES_DB"].Text(); got != "${var.DB_NAME}" {
+		t.Fatalf("workspace env mutated: POSTGRES_DB = %q", got)
+	}
+
+	ws.Resources["postgres"].Env["POSTGRES_USER"] = workspacepkg.StringEnvValue("${var.DB_USER}")
+	_, err = Resolve(ws, loadCatalogIndex(t, ws.ResolvedCatalogSources()))
+	var undefined *UndefinedVariableError
+	if !errors.As(err, &undefined) {
+		t.Fatalf("expected UndefinedVariableError, got %T (%v)", err, err)
+	}
+	if undefined.ResourceKey != "postgres" || undefined.Name != "DB_USER" {
+		t.Fatalf("undefined = %+v, want postgres/DB_USER", undefined)
+	}
+}
+
+func TestResolveExpandsTCPAndHTTPHealthShorthands(t *testing.T) {
+	catalogRoot := filepath.ToSlash(filepath.Join(repoRoot(t), "catalog", "builtin"))
+	manifestPath := writeResolveWorkspaceFixture(t, filepath.Join(t.TempDir(), "devarch.workspace.yaml"), `apiVersion: devarch.io/alpha1
+kind: Workspace
+metadata:
+  name: wait-local
+catalog:
+  sources:
+    - `+catalogRoot+`
+resources:
+  postgres:
+    template: postgres
+    health:
+      tcp: 5432
+      interval: 5s
+  api:
+    template: node-api
+    health:
+      http: http://localhost:3000/healthz
+`)
+	ws, err := workspacepkg.Load(manifestPath)
+	if err != nil {
+		t.Fatalf("workspace.Load(%s) returned error: %v", manifestPath, err)
+	}
+	graph, err := Resolve(ws, loadCatalogIndex(t, ws.ResolvedCatalogSources()))
+	if err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	postgres := graph.Resource("postgres").Health
+	if got, want := []string(postgres.Test), []string{"CMD-SHELL", "nc -z 127.0.0.1 5432 || bash -c 'exec 3<>/dev/tcp/127.0.0.1/5432'"}; !slices.Equal(got, want) {
+		t.Fatalf("postgres health test = %v, want %v", got, want)
+	}
+	if postgres.TCP != 0 || postgres.Interval != "5s" {
+		t.Fatalf("postgres health = %+v, want shorthand cleared and interval kept", postgres)
+	}
+	if got, want := []string(graph.Resource("api").Health.Test), []string{"CMD-SHELL", "wget -q -O /dev/null 'http://localhost:3000/healthz' || curl -fsS -o /dev/null 'http://localhost:3000/healthz'"}; !slices.Equal(got, want) {
+		t.Fatalf("api health test = %v, want %v", got, want)
+	}
+
+	ws.Resources["api"].Health.Test = workspacepkg.StringList{"CMD", "true"}
+	if _, err := Resolve(ws, loadCatalogIndex(t, ws.ResolvedCatalogSources())); err == nil {
+		t.Fatal("expected error for health with both test and http, got nil")
+	}
+}
+
 func TestBuildMergesTemplateDefaultsWithWorkspaceOverrides(t *testing.T) {
 	ws, index := loadExampleGraphInputs(t, "shop-local")
 
@@ -154,7 +289,7 @@ resources:
 	if got, want := api.Volumes, []Volume{
 		{Source: "workspace.logs", Target: "/workspace/logs", Kind: "data"},
 		{Source: "workspace.node-modules", Target: "/workspace/node_modules", ReadOnly: true, Kind: "cache"},
-	}; !slices.Equal(got, want) {
+	}; !reflect.DeepEqual(got, want) {
 		t.Fatalf("api.Volumes = %#v, want %#v", got, want)
 	}
 	if api.Health == nil {
@@ -9,11 +9,13 @@ import (
 )
 
 type runtimeDocument struct {
-	Image      string               `yaml:"image"`
-	Build      *buildDocument       `yaml:"build,omitempty"`
-	Command    workspace.StringList `yaml:"command,omitempty"`
-	Entrypoint workspace.StringList `yaml:"entrypoint,omitempty"`
-	WorkingDir string               `yaml:"workingDir,omitempty"`
+	Image       string               `yaml:"image"`
+	Build       *buildDocument       `yaml:"build,omitempty"`
+	Command     workspace.StringList `yaml:"command,omitempty"`
+	Entrypoint  workspace.StringList `yaml:"entrypoint,omitempty"`
+	WorkingDir  string               `yaml:"workingDir,omitempty"`
+	StopSignal  string               `yaml:"stopSignal,omitempty"`
+	StopTimeout *int                 `yaml:"stopTimeout,omitempty"`
 }
 
 type buildDocument struct {
@@ -39,10 +41,12 @@ func decodeRuntime(raw map[string]any, templatePath string) (*Runtime, error) {
 	}
 
 	runtime := &Runtime{
-		Image:      document.Image,
-		Command:    cloneStringList(document.Command),
-		Entrypoint: cloneStringList(document.Entrypoint),
-		WorkingDir: document.WorkingDir,
+		Image:       document.Image,
+		Command:     cloneStringList(document.Command),
+		Entrypoint:  cloneStringList(document.Entrypoint),
+		WorkingDir:  document.WorkingDir,
+		StopSignal:  document.StopSignal,
+		StopTimeout: document.StopTimeout,
 	}
 	if document.Build != nil {
 		runtime.Build = &Build{
@@ -0,0 +1,100 @@
+package resolve
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/prospect-ogujiuba/devarch/internal/workspace"
+)
+
+const variablePrefix = "var."
+
+// UndefinedVariableError reports a ${var.NAME} placeholder that names no
+// workspace variable.
+type UndefinedVariableError struct {
+	ResourceKey string
+	Field       string
+	Name        string
+}
+
+func (e *UndefinedVariableError) Error() string {
+	return fmt.Sprintf("resource %q %s references undefined variable %q", e.ResourceKey, e.Field, e.Name)
+}
+
+// substituteVariables expands ${var.NAME} placeholders in the string env
+// values, image, command, entrypoint, and domains of a resolved resource.
+// Other placeholder namespaces, such as the ${resource.host} tokens used by
+// exports, are left for later stages.
+func substituteVariables(resource *Resource, variables map[string]string) error {
+	keys := make([]string, 0, len(resource.Env))
+	for key := range resource.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := resource.Env[key]
+		if value.Kind() != workspace.EnvValueString {
+			continue
+		}
+		expanded, err := expandVariables(resource.Key, "env "+key, value.Text(), variables)
+		if err != nil {
+			return err
+		}
+		resource.Env[key] = workspace.StringEnvValue(expanded)
+	}
+
+	if resource.Runtime != nil {
+		image, err := expandVariables(resource.Key, "image", resource.Runtime.Image, variables)
+		if err != nil {
+			return err
+		}
+		resource.Runtime.Image = image
+		if err := expandList(resource.Key, "command", resource.Runtime.Command, variables); err != nil {
+			return err
+		}
+		if err := expandList(resource.Key, "entrypoint", resource.Runtime.Entrypoint, variables); err != nil {
+			return err
+		}
+	}
+	return expandList(resource.Key, "domains", resource.Domains, variables)
+}
+
+func expandList(resourceKey, field string, values []string, variables map[string]string) error {
+	for index, value := range values {
+		expanded, err := expandVariables(resourceKey, field, value, variables)
+		if err != nil {
+			return err
+		}
+		values[index] = expanded
+	}
+	return nil
+}
+
+func expandVariables(resourceKey, field, value string, variables map[string]string) (string, error) {
+	if !strings.Contains(value, "${"+variablePrefix) {
+		return value, nil
+	}
+	var builder strings.Builder
+	for {
+		start := strings.Index(value, "${"+variablePrefix)
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(value[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+		name := value[start+2+len(variablePrefix) : end]
+		replacement, ok := variables[name]
+		if !ok {
+			return "", &UndefinedVariableError{ResourceKey: resourceKey, Field: field, Name: name}
+		}
+		builder.WriteString(value[:start])
+		builder.WriteString(replacement)
+		value = value[end+1:]
+	}
+	builder.WriteString(value)
+	return builder.String(), nil
+}
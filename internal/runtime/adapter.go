@@ -34,6 +34,12 @@ type AppliedResource struct {
 	Spec        ResourceSpec `json:"spec"`
 }
 
+// RestartRequest tunes a resource restart. A nil Timeout keeps the stop
+// timeout configured on the container.
+type RestartRequest struct {
+	Timeout *int `json:"timeout,omitempty"`
+}
+
 type LogsRequest struct {
 	Tail   int        `json:"tail,omitempty"`
 	Follow bool       `json:"follow,omitempty"`
@@ -70,7 +76,7 @@ type Adapter interface {
 	RemoveNetwork(ctx context.Context, network *DesiredNetwork) error
 	ApplyResource(ctx context.Context, request ApplyResourceRequest) error
 	RemoveResource(ctx context.Context, resource ResourceRef) error
-	RestartResource(ctx context.Context, resource ResourceRef) error
+	RestartResource(ctx context.Context, resource ResourceRef, request RestartRequest) error
 	StreamLogs(ctx context.Context, resource ResourceRef, request LogsRequest, consume LogsConsumer) error
 	Exec(ctx context.Context, resource ResourceRef, request ExecRequest) (*ExecResult, error)
 }
@@ -0,0 +1,98 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/prospect-ogujiuba/devarch/internal/workspace"
+)
+
+// UnmanagedContainer is a container DevArch does not manage, with the
+// settings that adoption carries over into a workspace resource.
+type UnmanagedContainer struct {
+	ID             string                        `json:"id"`
+	Name           string                        `json:"name"`
+	Image          string                        `json:"image"`
+	Status         string                        `json:"status,omitempty"`
+	ComposeProject string                        `json:"composeProject,omitempty"`
+	Command        []string                      `json:"command,omitempty"`
+	Env            map[string]workspace.EnvValue `json:"env,omitempty"`
+	Ports          []PortSpec                    `json:"ports,omitempty"`
+	Volumes        []VolumeSpec                  `json:"volumes,omitempty"`
+}
+
+// ContainerAdopter is implemented by adapters that can list and inspect
+// containers outside any workspace, so they can be described as resources.
+type ContainerAdopter interface {
+	UnmanagedContainers(ctx context.Context) ([]UnmanagedContainer, error)
+	InspectUnmanagedContainer(ctx context.Context, name string) (*UnmanagedContainer, error)
+}
+
+// UnmanagedContainersFromInspect decodes container inspect output, skipping
+// containers that carry the DevArch managed-by label. When image inspect
+// output is given, env entries equal to the image's own defaults are
+// dropped, since the image sets them again anyway. Named volumes keep their
+// volume name as the source.
+func UnmanagedContainersFromInspect(containerInspectJSON, imageInspectJSON []byte) ([]UnmanagedContainer, error) {
+	if len(containerInspectJSON) == 0 {
+		return nil, nil
+	}
+	var documents []containerInspectDocument
+	if err := json.Unmarshal(containerInspectJSON, &documents); err != nil {
+		return nil, fmt.Errorf("decode container inspect: %w", err)
+	}
+	imageEnv := map[string]bool{}
+	if len(imageInspectJSON) > 0 {
+		var images []struct {
+			Config struct {
+				Env []string `json:"Env"`
+			} `json:"Config"`
+		}
+		if err := json.Unmarshal(imageInspectJSON, &images); err != nil {
+			return nil, fmt.Errorf("decode image inspect: %w", err)
+		}
+		for _, image := range images {
+			for _, entry := range image.Config.Env {
+				imageEnv[entry] = true
+			}
+		}
+	}
+
+	containers := make([]UnmanagedContainer, 0, len(documents))
+	for _, document := range documents {
+		if document.Config.Labels[LabelManagedBy] == ManagedByValue {
+			continue
+		}
+		var env []string
+		for _, entry := range document.Config.Env {
+			if !imageEnv[entry] {
+				env = append(env, entry)
+			}
+		}
+		volumes := volumesFromInspect(document.Mounts)
+		for i := range document.Mounts {
+			if document.Mounts[i].Type == "volume" && document.Mounts[i].Name != "" {
+				for j := range volumes {
+					if volumes[j].Target == document.Mounts[i].Destination {
+						volumes[j].Source = document.Mounts[i].Name
+					}
+				}
+			}
+		}
+		containers = append(containers, UnmanagedContainer{
+			ID:             document.ID,
+			Name:           trimContainerName(document.Name),
+			Image:          document.Config.Image,
+			Status:         document.State.Status,
+			ComposeProject: document.Config.Labels[LabelComposeProject],
+			Command:        append([]string(nil), document.Config.Cmd...),
+			Env:            envFromInspect(env),
+			Ports:          portsFromInspect(document.NetworkSettings.Ports),
+			Volumes:        volumes,
+		})
+	}
+	sort.Slice(containers, func(i, j int) bool { return containers[i].Name < containers[j].Name })
+	return containers, nil
+}
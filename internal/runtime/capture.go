@@ -0,0 +1,66 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// CaptureImage runs tcpdump for traffic captures. It is started as a helper
+// container joined to the target container's network namespace, so the
+// target image does not need tcpdump.
+const CaptureImage = "docker.io/nicolaka/netshoot:latest"
+
+// CaptureTimeoutExitCode is the exit code of timeout(1) when the capture ran
+// for its full duration, which is the normal way a capture ends.
+const CaptureTimeoutExitCode = 124
+
+// TrafficCapturer is implemented by adapters that can record a resource's
+// network traffic to a pcap file on the host.
+type TrafficCapturer interface {
+	CaptureTraffic(ctx context.Context, resource ResourceRef, request CaptureRequest) error
+}
+
+// CaptureRequest describes one capture. Directory is a host directory mounted
+// into the helper container; the pcap is written there as File.
+type CaptureRequest struct {
+	Duration   time.Duration
+	MaxPackets int
+	Filter     string
+	Directory  string
+	File       string
+}
+
+// CaptureArgs builds the `run` arguments shared by the podman and docker
+// adapters: a throwaway helper in the target's network namespace with the
+// capabilities tcpdump needs, stopped by timeout(1) after the duration.
+// tcpdump keeps root so it can write into the mounted directory.
+func CaptureArgs(resource ResourceRef, request CaptureRequest) ([]string, error) {
+	if resource.RuntimeName == "" {
+		return nil, fmt.Errorf("capture traffic: runtime name is required")
+	}
+	if request.Directory == "" || request.File == "" {
+		return nil, fmt.Errorf("capture traffic: output directory and file are required")
+	}
+	seconds := int(request.Duration / time.Second)
+	if seconds <= 0 {
+		return nil, fmt.Errorf("capture traffic: duration must be at least one second")
+	}
+	args := []string{
+		"run", "--rm",
+		"--network", "container:" + resource.RuntimeName,
+		"--cap-add", "NET_ADMIN", "--cap-add", "NET_RAW",
+		"--volume", request.Directory + ":/capture:Z",
+		CaptureImage,
+		"timeout", strconv.Itoa(seconds),
+		"tcpdump", "-i", "any", "-U", "-Z", "root", "-w", "/capture/" + request.File,
+	}
+	if request.MaxPackets > 0 {
+		args = append(args, "-c", strconv.Itoa(request.MaxPackets))
+	}
+	if request.Filter != "" {
+		args = append(args, request.Filter)
+	}
+	return args, nil
+}
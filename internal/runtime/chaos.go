@@ -0,0 +1,64 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChaosInjector is implemented by adapters that can disturb a running
+// resource for resilience testing: freeze it, signal it, or degrade its
+// network for a bounded time.
+type ChaosInjector interface {
+	PauseResource(ctx context.Context, resource ResourceRef) error
+	UnpauseResource(ctx context.Context, resource ResourceRef) error
+	KillResource(ctx context.Context, resource ResourceRef, signal string) error
+	DegradeNetwork(ctx context.Context, resource ResourceRef, fault NetworkFault) error
+}
+
+// NetworkFault is a netem setting applied to a resource's eth0 for Duration.
+// Loss is a percentage.
+type NetworkFault struct {
+	Duration time.Duration
+	Latency  time.Duration
+	Jitter   time.Duration
+	Loss     float64
+}
+
+// NetworkFaultArgs builds the `run` arguments shared by adapters: a helper in
+// the target's network namespace that installs a netem qdisc, sleeps for the
+// duration, and removes the qdisc again. The removal is trapped on exit and on
+// INT or TERM, so stopping the helper early does not leave the fault behind.
+// The helper blocks until the fault is lifted.
+func NetworkFaultArgs(resource ResourceRef, fault NetworkFault) ([]string, error) {
+	if resource.RuntimeName == "" {
+		return nil, fmt.Errorf("degrade network: runtime name is required")
+	}
+	seconds := int(fault.Duration / time.Second)
+	if seconds <= 0 {
+		return nil, fmt.Errorf("degrade network: duration must be at least one second")
+	}
+	var netem []string
+	if fault.Latency > 0 {
+		netem = append(netem, "delay", fmt.Sprintf("%dms", fault.Latency.Milliseconds()))
+		if fault.Jitter > 0 {
+			netem = append(netem, fmt.Sprintf("%dms", fault.Jitter.Milliseconds()))
+		}
+	}
+	if fault.Loss > 0 {
+		netem = append(netem, "loss", strconv.FormatFloat(fault.Loss, 'f', -1, 64)+"%")
+	}
+	if len(netem) == 0 {
+		return nil, fmt.Errorf("degrade network: latency or loss is required")
+	}
+	script := fmt.Sprintf("trap 'tc qdisc del dev eth0 root' EXIT INT TERM; tc qdisc replace dev eth0 root netem %s && sleep %d & wait", strings.Join(netem, " "), seconds)
+	return []string{
+		"run", "--rm",
+		"--network", "container:" + resource.RuntimeName,
+		"--cap-add", "NET_ADMIN",
+		CaptureImage,
+		"sh", "-c", script,
+	}, nil
+}
@@ -0,0 +1,97 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// LabelComposeProject is the label docker compose and podman-compose put on
+// the containers and networks of a compose project.
+const LabelComposeProject = "com.docker.compose.project"
+
+// NameConflict is a container or network holding a runtime name a workspace
+// wants, without being managed by that workspace. Applying over it would
+// replace someone else's container.
+type NameConflict struct {
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	Resource string `json:"resource,omitempty"`
+	Owner    string `json:"owner"`
+}
+
+// NameConflictFinder is implemented by adapters that can look up runtime
+// names outside the workspace's own labels.
+type NameConflictFinder interface {
+	FindNameConflicts(ctx context.Context, desired *DesiredWorkspace) ([]NameConflict, error)
+}
+
+// NameConflicts compares inspect output for containers and the network that
+// carry the desired runtime names against the workspace labels, and reports
+// the ones owned by something else.
+func NameConflicts(desired *DesiredWorkspace, containerInspectJSON, networkInspectJSON []byte) ([]NameConflict, error) {
+	if desired == nil {
+		return nil, fmt.Errorf("name conflicts: nil desired workspace")
+	}
+	resourceByName := make(map[string]string, len(desired.Resources))
+	for _, resource := range desired.Resources {
+		if resource != nil && resource.Enabled {
+			resourceByName[resource.RuntimeName] = resource.Key
+		}
+	}
+
+	var conflicts []NameConflict
+	if len(containerInspectJSON) > 0 {
+		var containers []containerInspectDocument
+		if err := json.Unmarshal(containerInspectJSON, &containers); err != nil {
+			return nil, fmt.Errorf("name conflicts: decode container inspect: %w", err)
+		}
+		for _, container := range containers {
+			name := trimContainerName(container.Name)
+			key, ok := resourceByName[name]
+			if !ok {
+				continue
+			}
+			if owner := nameOwner(container.Config.Labels, desired.Name); owner != "" {
+				conflicts = append(conflicts, NameConflict{Kind: "container", Name: name, Resource: key, Owner: owner})
+			}
+		}
+	}
+	if len(networkInspectJSON) > 0 && desired.Network != nil {
+		var networks []networkInspectDocument
+		if err := json.Unmarshal(networkInspectJSON, &networks); err != nil {
+			return nil, fmt.Errorf("name conflicts: decode network inspect: %w", err)
+		}
+		for _, network := range networks {
+			if network.Name != desired.Network.Name {
+				continue
+			}
+			if owner := nameOwner(network.Labels, desired.Name); owner != "" {
+				conflicts = append(conflicts, NameConflict{Kind: "network", Name: network.Name, Owner: owner})
+			}
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		if conflicts[i].Kind != conflicts[j].Kind {
+			return conflicts[i].Kind < conflicts[j].Kind
+		}
+		return conflicts[i].Name < conflicts[j].Name
+	})
+	return conflicts, nil
+}
+
+// nameOwner describes who owns an object with the given labels, or returns
+// "" when it belongs to workspaceName.
+func nameOwner(labels map[string]string, workspaceName string) string {
+	if labels[LabelManagedBy] == ManagedByValue {
+		if labels[LabelWorkspace] == workspaceName {
+			return ""
+		}
+		return fmt.Sprintf("devarch workspace %q", labels[LabelWorkspace])
+	}
+	if project := labels[LabelComposeProject]; project != "" {
+		return fmt.Sprintf("compose project %q", project)
+	}
+	return "unmanaged"
+}
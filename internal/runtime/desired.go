@@ -2,11 +2,14 @@ package runtime
 
 import (
 	"fmt"
+	"path"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/prospect-ogujiuba/devarch/internal/contracts"
+	"github.com/prospect-ogujiuba/devarch/internal/projectscan"
 	"github.com/prospect-ogujiuba/devarch/internal/resolve"
 	"github.com/prospect-ogujiuba/devarch/internal/workspace"
 )
@@ -32,6 +35,9 @@ func BuildDesiredWorkspace(graph *resolve.Graph, result *contracts.Result) (*Des
 
 	if graph.Workspace.Runtime.IsolatedNetwork {
 		networkName := WorkspaceNetworkName(desired.Name, desired.NamingStrategy)
+		if prefix := graph.Workspace.Runtime.NetworkPrefix; prefix != "" {
+			networkName = fmt.Sprintf("%s-%s-net", prefix, desired.Name)
+		}
 		desired.Network = &DesiredNetwork{
 			Name:   networkName,
 			Labels: WorkspaceLabels(desired.Name),
@@ -49,10 +55,11 @@ func BuildDesiredWorkspace(graph *resolve.Graph, result *contracts.Result) (*Des
 			Enabled:      resource.Enabled,
 			LogicalHost:  resource.Host,
 			RuntimeName:  ResourceRuntimeName(desired.Name, resource.Key, desired.NamingStrategy),
-			DeclaredEnv:  cloneEnvMap(resource.Env),
+			DeclaredEnv:  envWithDefaults(resource.Env, injectedEnv[resource.Key], resource.EnvSchema),
 			InjectedEnv:  cloneEnvMap(injectedEnv[resource.Key]),
 			DependsOn:    cloneStringSlice(resource.DependsOn),
 			Domains:      cloneStringSlice(resource.Domains),
+			Hooks:        resource.Hooks.Clone(),
 			Diagnostics:  nil,
 			TemplateName: "",
 		}
@@ -76,26 +83,63 @@ func BuildDesiredWorkspace(graph *resolve.Graph, result *contracts.Result) (*Des
 		item.OverrideLabels = overrideLabels
 		item.Diagnostics = append(item.Diagnostics, diagnostics...)
 
+		image, diagnostics := imageWithOverrides(desired.Name, resource.Key, imageFromResolve(resource.Runtime), resource.Overrides)
+		item.Diagnostics = append(item.Diagnostics, diagnostics...)
+		item.Diagnostics = append(item.Diagnostics, nodeEngineDiagnostics(desired.Name, resource.Key, item.Source, image)...)
+		image = MirroredImage(image, graph.Workspace.Runtime.RegistryMirrors)
+
+		command, diagnostics := commandWithOverrides(desired.Name, resource.Key, commandFromResolve(resource.Runtime), resource.Overrides)
+		item.Diagnostics = append(item.Diagnostics, diagnostics...)
+
+		item.Diagnostics = append(item.Diagnostics, envSchemaDiagnostics(desired.Name, resource.Key, mergeEnv(item.InjectedEnv, item.DeclaredEnv), resource.EnvSchema)...)
+
+		item.Diagnostics = append(item.Diagnostics, hostPathDiagnostics(desired.Name, resource.Key, desired.ManifestDir, resource.Volumes)...)
+
 		watchRules, diagnostics := extractWatchRules(desired.Name, desired.ManifestDir, item.Source, resource.Key, resource.Develop)
 		item.Diagnostics = append(item.Diagnostics, diagnostics...)
 
 		item.Spec = ResourceSpec{
-			Image:         imageFromResolve(resource.Runtime),
+			Image:         image,
 			Build:         buildFromResolve(resource.Runtime),
-			Command:       commandFromResolve(resource.Runtime),
+			Command:       command,
 			Entrypoint:    entrypointFromResolve(resource.Runtime),
 			WorkingDir:    workingDirFromResolve(resource.Runtime),
 			Env:           mergeEnv(item.InjectedEnv, item.DeclaredEnv),
 			Ports:         portsFromResolve(resource.Ports),
-			Volumes:       volumesFromResolve(resource.Volumes),
+			Volumes:       volumesFromResolve(resource.Volumes, desired.ManifestDir),
 			Health:        cloneHealth(resource.Health),
+			StopSignal:    stopSignalFromResolve(resource),
+			StopTimeout:   stopTimeoutFromResolve(resource),
 			ProjectSource: projectSourceFromResolve(item.Source, resource.Runtime, watchRules),
 			DevelopWatch:  watchRules,
 			Labels:        mergeLabels(ResourceLabels(desired.Name, resource.Key, resource.Host, networkName(desired)), item.OverrideLabels),
+			Init:          resource.Init,
+			Userns:        resource.Userns,
+			Memory:        resource.Memory,
 		}
 
+		if resource.Replicas > 1 || resource.LoadBalancer != nil {
+			replicas := expandReplicas(desired, item, max(resource.Replicas, 1))
+			desired.Resources = append(desired.Resources, replicas...)
+			if resource.LoadBalancer != nil {
+				balancer := loadBalancerResource(desired, item, replicas, *resource.LoadBalancer)
+				desired.Resources = append(desired.Resources, balancer)
+				if resource.Inspector != nil {
+					desired.Resources = append(desired.Resources, inspectorResource(desired, balancer, *resource.Inspector))
+				}
+			}
+			continue
+		}
 		desired.Resources = append(desired.Resources, item)
+		if resource.Inspector != nil {
+			desired.Resources = append(desired.Resources, inspectorResource(desired, item, *resource.Inspector))
+		}
+	}
+	if err := expandReplicaDependencies(desired); err != nil {
+		return nil, err
 	}
+	replicaPortDiagnostics(desired)
+	desired.Diagnostics = append(desired.Diagnostics, quotaDiagnostics(desired, graph.Workspace.Policies)...)
 
 	return desired, nil
 }
@@ -108,6 +152,8 @@ func normalizedProvider(provider string) string {
 		return ProviderDocker
 	case ProviderPodman:
 		return ProviderPodman
+	case ProviderNerdctl:
+		return ProviderNerdctl
 	default:
 		return strings.ToLower(strings.TrimSpace(provider))
 	}
@@ -179,6 +225,22 @@ func extractLabels(workspaceName, resourceKey string, overrides map[string]any)
 	}
 	sort.Strings(keys)
 	for _, key := range keys {
+		if key == "image" || key == "imageTag" || key == "command" {
+			continue
+		}
+		if key == "autoUpdate" {
+			policy, ok := overrides[key].(string)
+			if !ok || !validAutoUpdatePolicy(policy) {
+				diagnostics = append(diagnostics, UnsupportedFieldDiagnostic(workspaceName, resourceKey, "invalid-auto-update", fmt.Sprintf("resource %q overrides.autoUpdate must be registry, local, disabled, or managed", resourceKey)))
+				continue
+			}
+			if policy == AutoUpdateManaged {
+				labels[LabelManagedUpdate] = policy
+				continue
+			}
+			labels[LabelAutoUpdate] = policy
+			continue
+		}
 		if key != "labels" {
 			diagnostics = append(diagnostics, UnsupportedFieldDiagnostic(workspaceName, resourceKey, "unsupported-override", fmt.Sprintf("resource %q override %q is unsupported", resourceKey, key)))
 			continue
@@ -201,6 +263,128 @@ func extractLabels(workspaceName, resourceKey string, overrides map[string]any)
 	return labels, diagnostics
 }
 
+// AutoUpdateManaged is the overrides.autoUpdate policy that leaves updates
+// to DevArch instead of podman auto-update.
+const AutoUpdateManaged = "managed"
+
+// validAutoUpdatePolicy reports whether policy is one podman auto-update
+// understands, or AutoUpdateManaged.
+func validAutoUpdatePolicy(policy string) bool {
+	switch policy {
+	case "registry", "local", "disabled", AutoUpdateManaged:
+		return true
+	default:
+		return false
+	}
+}
+
+// imageWithOverrides applies overrides.image (a full image reference) and then
+// overrides.imageTag (a tag swapped onto that reference) to the template image,
+// so two resources built from one template can run different versions.
+func imageWithOverrides(workspaceName, resourceKey, image string, overrides map[string]any) (string, []Diagnostic) {
+	var diagnostics []Diagnostic
+	if raw, ok := overrides["image"]; ok {
+		value, ok := raw.(string)
+		if !ok || strings.TrimSpace(value) == "" {
+			diagnostics = append(diagnostics, UnsupportedFieldDiagnostic(workspaceName, resourceKey, "invalid-image-override", fmt.Sprintf("resource %q overrides.image must be a non-empty string", resourceKey)))
+		} else {
+			image = strings.TrimSpace(value)
+		}
+	}
+	if raw, ok := overrides["imageTag"]; ok {
+		value, ok := raw.(string)
+		switch {
+		case !ok || !validImageTag(value):
+			diagnostics = append(diagnostics, UnsupportedFieldDiagnostic(workspaceName, resourceKey, "invalid-image-override", fmt.Sprintf("resource %q overrides.imageTag must be a valid image tag", resourceKey)))
+		case image == "":
+			diagnostics = append(diagnostics, UnsupportedFieldDiagnostic(workspaceName, resourceKey, "invalid-image-override", fmt.Sprintf("resource %q overrides.imageTag requires an image", resourceKey)))
+		default:
+			image = imageRepository(image) + ":" + value
+		}
+	}
+	return image, diagnostics
+}
+
+// nodeEngineDiagnostics warns when a node image runs a project whose
+// .nvmrc, .node-version, or engines.node rules out the image's major. Tags
+// without a leading major, such as "lts-alpine", are not checked.
+func nodeEngineDiagnostics(workspaceName, resourceKey string, source *SourceRef, image string) []Diagnostic {
+	if source == nil || source.Type != "project" || source.ResolvedPath == "" {
+		return nil
+	}
+	repository := imageRepository(image)
+	tag, ok := strings.CutPrefix(strings.SplitN(image, "@", 2)[0], repository+":")
+	if !ok || path.Base(repository) != "node" {
+		return nil
+	}
+	major, err := strconv.Atoi(strings.SplitN(strings.SplitN(tag, "-", 2)[0], ".", 2)[0])
+	if err != nil {
+		return nil
+	}
+	constraint, file := projectscan.NodeVersion(source.ResolvedPath)
+	if constraint == "" || projectscan.NodeSatisfies(constraint, major) {
+		return nil
+	}
+	return []Diagnostic{{
+		Severity:  SeverityWarning,
+		Code:      "node-engine-mismatch",
+		Workspace: workspaceName,
+		Resource:  resourceKey,
+		Message:   fmt.Sprintf("resource %q runs %s but %s declares node %q", resourceKey, image, file, constraint),
+	}}
+}
+
+// commandWithOverrides replaces the template command with overrides.command,
+// so a worker resource can run the same template as its app with another
+// process, such as a queue worker.
+func commandWithOverrides(workspaceName, resourceKey string, command []string, overrides map[string]any) ([]string, []Diagnostic) {
+	raw, ok := overrides["command"]
+	if !ok {
+		return command, nil
+	}
+	items, ok := raw.([]any)
+	replaced := make([]string, 0, len(items))
+	for _, item := range items {
+		value, isString := item.(string)
+		if !isString {
+			ok = false
+			break
+		}
+		replaced = append(replaced, value)
+	}
+	if !ok || len(replaced) == 0 {
+		return command, []Diagnostic{UnsupportedFieldDiagnostic(workspaceName, resourceKey, "invalid-command-override", fmt.Sprintf("resource %q overrides.command must be a non-empty list of strings", resourceKey))}
+	}
+	return replaced, nil
+}
+
+// imageRepository strips any tag and digest from an image reference. A colon
+// before the last slash belongs to a registry host port, not a tag.
+func imageRepository(image string) string {
+	if index := strings.Index(image, "@"); index >= 0 {
+		image = image[:index]
+	}
+	if index := strings.LastIndex(image, ":"); index > strings.LastIndex(image, "/") {
+		image = image[:index]
+	}
+	return image
+}
+
+func validImageTag(tag string) bool {
+	if tag == "" || len(tag) > 128 {
+		return false
+	}
+	for index, char := range tag {
+		switch {
+		case char >= 'a' && char <= 'z', char >= 'A' && char <= 'Z', char >= '0' && char <= '9', char == '_':
+		case (char == '.' || char == '-') && index > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 func extractWatchRules(workspaceName, manifestDir string, source *SourceRef, resourceKey string, develop map[string]any) ([]WatchRule, []Diagnostic) {
 	if len(develop) == 0 {
 		return nil, nil
@@ -307,6 +491,22 @@ func workingDirFromResolve(runtime *resolve.Runtime) string {
 	return runtime.WorkingDir
 }
 
+// stopSignalFromResolve prefers the resource's own stopSignal over the
+// template's runtime.stopSignal, as stopTimeoutFromResolve does.
+func stopSignalFromResolve(resource *resolve.Resource) string {
+	if resource.StopSignal != "" || resource.Runtime == nil {
+		return resource.StopSignal
+	}
+	return resource.Runtime.StopSignal
+}
+
+func stopTimeoutFromResolve(resource *resolve.Resource) *int {
+	if resource.StopTimeout != nil || resource.Runtime == nil {
+		return cloneIntPtr(resource.StopTimeout)
+	}
+	return cloneIntPtr(resource.Runtime.StopTimeout)
+}
+
 func portsFromResolve(ports []resolve.Port) []PortSpec {
 	if len(ports) == 0 {
 		return nil
@@ -323,17 +523,18 @@ func portsFromResolve(ports []resolve.Port) []PortSpec {
 	return converted
 }
 
-func volumesFromResolve(volumes []resolve.Volume) []VolumeSpec {
+func volumesFromResolve(volumes []resolve.Volume, manifestDir string) []VolumeSpec {
 	if len(volumes) == 0 {
 		return nil
 	}
 	converted := make([]VolumeSpec, len(volumes))
 	for i := range volumes {
 		converted[i] = VolumeSpec{
-			Source:   volumes[i].Source,
+			Source:   ExpandHostRoots(volumes[i].Source, manifestDir),
 			Target:   volumes[i].Target,
 			ReadOnly: volumes[i].ReadOnly,
 			Kind:     volumes[i].Kind,
+			Options:  append([]string(nil), volumes[i].Options...),
 		}
 	}
 	return converted
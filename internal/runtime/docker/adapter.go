@@ -2,6 +2,7 @@ package docker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os/exec"
 	"strconv"
@@ -78,10 +79,36 @@ func (a *Adapter) RemoveResource(ctx context.Context, resource runtimepkg.Resour
 	return unsupported("remove-resource")
 }
 
-func (a *Adapter) RestartResource(ctx context.Context, resource runtimepkg.ResourceRef) error {
+func (a *Adapter) RestartResource(ctx context.Context, resource runtimepkg.ResourceRef, request runtimepkg.RestartRequest) error {
 	return unsupported("restart-resource")
 }
 
+// StopResource stops a container without removing it, so StartResource can
+// resume it.
+func (a *Adapter) StopResource(ctx context.Context, resource runtimepkg.ResourceRef, timeout *int) error {
+	if resource.RuntimeName == "" {
+		return fmt.Errorf("docker stop-resource: runtime name is required")
+	}
+	args := []string{"stop"}
+	if timeout != nil {
+		args = append(args, "--time", strconv.Itoa(*timeout))
+	}
+	if _, err := a.runner.Run(ctx, "docker", append(args, resource.RuntimeName)...); err != nil {
+		return fmt.Errorf("docker stop %q: %w", resource.RuntimeName, err)
+	}
+	return nil
+}
+
+func (a *Adapter) StartResource(ctx context.Context, resource runtimepkg.ResourceRef) error {
+	if resource.RuntimeName == "" {
+		return fmt.Errorf("docker start-resource: runtime name is required")
+	}
+	if _, err := a.runner.Run(ctx, "docker", "start", resource.RuntimeName); err != nil {
+		return fmt.Errorf("docker start %q: %w", resource.RuntimeName, err)
+	}
+	return nil
+}
+
 func (a *Adapter) StreamLogs(ctx context.Context, resource runtimepkg.ResourceRef, request runtimepkg.LogsRequest, consume runtimepkg.LogsConsumer) error {
 	if consume == nil {
 		return fmt.Errorf("docker logs: nil consumer")
@@ -109,6 +136,68 @@ func (a *Adapter) StreamLogs(ctx context.Context, resource runtimepkg.ResourceRe
 	return nil
 }
 
+func (a *Adapter) ResourceStats(ctx context.Context, resources []runtimepkg.ResourceRef) ([]runtimepkg.ResourceStats, error) {
+	if len(resources) == 0 {
+		return nil, nil
+	}
+	args := []string{"stats", "--no-stream", "--format", runtimepkg.StatsFormat}
+	for _, resource := range resources {
+		args = append(args, resource.RuntimeName)
+	}
+	output, err := a.runner.Run(ctx, "docker", args...)
+	if err != nil {
+		return nil, err
+	}
+	return runtimepkg.ParseStatsOutput(output, resources)
+}
+
+func (a *Adapter) TopResource(ctx context.Context, resource runtimepkg.ResourceRef) (*runtimepkg.ProcessList, error) {
+	if resource.RuntimeName == "" {
+		return nil, fmt.Errorf("docker top: runtime name is required")
+	}
+	output, err := a.runner.Run(ctx, "docker", "top", resource.RuntimeName)
+	if err != nil {
+		return nil, err
+	}
+	return runtimepkg.ParseTopOutput(resource, output), nil
+}
+
+func (a *Adapter) SaveImages(ctx context.Context, images []string, path string) error {
+	if len(images) == 0 {
+		return fmt.Errorf("docker save-images: at least one image is required")
+	}
+	if path == "" {
+		return fmt.Errorf("docker save-images: archive path is required")
+	}
+	args := append([]string{"save", "-o", path}, images...)
+	_, err := a.runner.Run(ctx, "docker", args...)
+	return err
+}
+
+func (a *Adapter) CaptureTraffic(ctx context.Context, resource runtimepkg.ResourceRef, request runtimepkg.CaptureRequest) error {
+	args, err := runtimepkg.CaptureArgs(resource, request)
+	if err != nil {
+		return err
+	}
+	_, err = a.runner.Run(ctx, "docker", args...)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == runtimepkg.CaptureTimeoutExitCode {
+		return nil
+	}
+	return err
+}
+
+func (a *Adapter) LoadImages(ctx context.Context, path string) ([]string, error) {
+	if path == "" {
+		return nil, fmt.Errorf("docker load-images: archive path is required")
+	}
+	output, err := a.runner.Run(ctx, "docker", "load", "-i", path)
+	if err != nil {
+		return nil, err
+	}
+	return runtimepkg.ParseLoadedImages(output), nil
+}
+
 func (a *Adapter) Exec(ctx context.Context, resource runtimepkg.ResourceRef, request runtimepkg.ExecRequest) (*runtimepkg.ExecResult, error) {
 	if request.Interactive || request.TTY {
 		return nil, unsupported("exec-interactive")
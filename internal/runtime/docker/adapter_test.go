@@ -88,6 +88,41 @@ func TestDockerAdapterContractInspectLogsAndExec(t *testing.T) {
 	}
 }
 
+func TestDockerAdapterTopResourceKeepsCommandColumn(t *testing.T) {
+	runner := &fakeRunner{responses: map[string]fakeResponse{
+		"docker top devarch-shop-local-api": {
+			stdout: []byte("UID    PID    PPID   C   STIME   TTY   TIME       CMD\nroot   4121   4100   0   12:00   ?     00:00:01   node server.js --port 3000\n"),
+		},
+	}}
+	adapter := New(runner)
+	processes, err := adapter.TopResource(context.Background(), runtimepkg.ResourceRef{Workspace: "shop-local", Key: "api", RuntimeName: "devarch-shop-local-api"})
+	if err != nil {
+		t.Fatalf("TopResource returned error: %v", err)
+	}
+	if got, want := processes.Titles, []string{"UID", "PID", "PPID", "C", "STIME", "TTY", "TIME", "CMD"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Titles = %v, want %v", got, want)
+	}
+	if got, want := processes.Processes, [][]string{{"root", "4121", "4100", "0", "12:00", "?", "00:00:01", "node server.js --port 3000"}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Processes = %v, want %v", got, want)
+	}
+}
+
+func TestDockerAdapterStopsAndStartsResources(t *testing.T) {
+	runner := &fakeRunner{responses: map[string]fakeResponse{
+		"docker stop --time 30 devarch-shop-local-postgres": {},
+		"docker start devarch-shop-local-postgres":          {},
+	}}
+	var adapter runtimepkg.ResourceLifecycle = New(runner)
+	ref := runtimepkg.ResourceRef{Workspace: "shop-local", Key: "postgres", RuntimeName: "devarch-shop-local-postgres"}
+	timeout := 30
+	if err := adapter.StopResource(context.Background(), ref, &timeout); err != nil {
+		t.Fatalf("StopResource returned error: %v", err)
+	}
+	if err := adapter.StartResource(context.Background(), ref); err != nil {
+		t.Fatalf("StartResource returned error: %v", err)
+	}
+}
+
 type fakeRunner struct {
 	responses map[string]fakeResponse
 }
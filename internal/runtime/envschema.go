@@ -0,0 +1,75 @@
+package runtime
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/prospect-ogujiuba/devarch/internal/resolve"
+	"github.com/prospect-ogujiuba/devarch/internal/workspace"
+)
+
+// envWithDefaults copies the declared env of a resource and adds the schema
+// defaults for vars that neither it nor a contract import sets, so a
+// default never shadows an injected value.
+func envWithDefaults(declared, injected map[string]workspace.EnvValue, schema map[string]resolve.EnvVar) map[string]workspace.EnvValue {
+	env := cloneEnvMap(declared)
+	for _, key := range slices.Sorted(maps.Keys(schema)) {
+		if schema[key].Default == "" {
+			continue
+		}
+		if _, ok := env[key]; ok {
+			continue
+		}
+		if _, ok := injected[key]; ok {
+			continue
+		}
+		if env == nil {
+			env = map[string]workspace.EnvValue{}
+		}
+		env[key] = workspace.StringEnvValue(schema[key].Default)
+	}
+	return env
+}
+
+// envSchemaDiagnostics checks the effective env of a resource against its
+// template's env schema. A missing required var or a value that does not
+// match its pattern blocks apply, so the resource fails at plan time rather
+// than when the container crashes. Secret references and values that still
+// hold placeholders are not pattern-checked.
+func envSchemaDiagnostics(workspaceName, resourceKey string, env map[string]workspace.EnvValue, schema map[string]resolve.EnvVar) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, key := range slices.Sorted(maps.Keys(schema)) {
+		envVar := schema[key]
+		value, ok := env[key]
+		if !ok || (value.Kind() == workspace.EnvValueString && value.Text() == "") {
+			if envVar.Required {
+				message := fmt.Sprintf("resource %q: %s is required", resourceKey, key)
+				if envVar.Description != "" {
+					message += " (" + envVar.Description + ")"
+				}
+				diagnostics = append(diagnostics, envSchemaDiagnostic(workspaceName, resourceKey, key, "env-required", message))
+			}
+			continue
+		}
+		if value.Kind() == workspace.EnvValueSecretRef || strings.Contains(value.Text(), "${") {
+			continue
+		}
+		if !envVar.Match(value.Text()) {
+			diagnostics = append(diagnostics, envSchemaDiagnostic(workspaceName, resourceKey, key, "env-invalid", fmt.Sprintf("resource %q: %s does not match pattern %q", resourceKey, key, envVar.Pattern)))
+		}
+	}
+	return diagnostics
+}
+
+func envSchemaDiagnostic(workspaceName, resourceKey, envKey, code, message string) Diagnostic {
+	return Diagnostic{
+		Severity:  SeverityError,
+		Code:      code,
+		Workspace: workspaceName,
+		Resource:  resourceKey,
+		EnvKey:    envKey,
+		Message:   message,
+	}
+}
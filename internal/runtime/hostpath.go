@@ -0,0 +1,165 @@
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/prospect-ogujiuba/devarch/internal/resolve"
+	"github.com/prospect-ogujiuba/devarch/internal/workspace"
+)
+
+// Host path roots a volume source may start with, so a manifest mounts the
+// same files on every machine. PROJECT_ROOT is the manifest's directory.
+const (
+	ProjectRootVar = "${PROJECT_ROOT}"
+	HomeVar        = "${HOME}"
+)
+
+// MachineBindSource rewrites a bind source for provider on a host running
+// platform, a GOOS value. Only podman on Windows needs it: the podman machine
+// there is a WSL2 VM that mounts drives under /mnt/<drive> (C:\src\app
+// becomes /mnt/c/src/app), and the podman client passes -v sources through
+// as-is, so the drive colon would otherwise split the mount spec. Docker
+// Desktop and nerdctl take the Windows path as written, and on macOS the
+// podman machine mounts the home directory at the same path, so every other
+// combination, POSIX path, and named volume is returned unchanged.
+func MachineBindSource(provider, platform, source string) string {
+	if provider != ProviderPodman || platform != "windows" || !workspace.IsWindowsDrivePath(source) {
+		return source
+	}
+	drive := source[0] | 0x20
+	rest := strings.TrimRight(strings.ReplaceAll(source[3:], "\\", "/"), "/")
+	if rest == "" {
+		return "/mnt/" + string(drive)
+	}
+	return "/mnt/" + string(drive) + "/" + rest
+}
+
+// HostBindSource undoes MachineBindSource for a source the runtime reports,
+// so /mnt/c/src/app from podman on Windows compares equal to the C:/src/app
+// a manifest stores. Other sources are returned unchanged.
+func HostBindSource(provider, platform, source string) string {
+	if provider != ProviderPodman || platform != "windows" {
+		return source
+	}
+	rest, ok := strings.CutPrefix(source, "/mnt/")
+	if !ok || rest == "" || rest[0] < 'a' || rest[0] > 'z' || (len(rest) > 1 && rest[1] != '/') {
+		return source
+	}
+	return workspace.CanonicalHostPath(strings.ToUpper(rest[:1]) + ":/" + strings.TrimPrefix(rest[1:], "/"))
+}
+
+// ExpandHostRoots replaces a leading ${PROJECT_ROOT} or ${HOME} in a volume
+// source with the manifest directory or the user's home directory. A root
+// that cannot be resolved is left in place, so the runtime rejects the
+// mount instead of binding the wrong directory.
+func ExpandHostRoots(source, manifestDir string) string {
+	if rest, ok := strings.CutPrefix(source, ProjectRootVar); ok && manifestDir != "" {
+		return manifestDir + rest
+	}
+	if rest, ok := strings.CutPrefix(source, HomeVar); ok {
+		if home, err := os.UserHomeDir(); err == nil && home != "" {
+			return home + rest
+		}
+	}
+	return source
+}
+
+// PortableHostPath rewrites an absolute host path under the manifest
+// directory or the home directory to start with ${PROJECT_ROOT} or ${HOME}.
+// It reports false for paths under neither.
+func PortableHostPath(source, manifestDir string) (string, bool) {
+	if rest, ok := cutPathPrefix(source, manifestDir); ok {
+		return ProjectRootVar + rest, true
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		if rest, ok := cutPathPrefix(source, home); ok {
+			return HomeVar + rest, true
+		}
+	}
+	return source, false
+}
+
+func cutPathPrefix(path, root string) (string, bool) {
+	if root == "" || root == "/" || !filepath.IsAbs(root) {
+		return "", false
+	}
+	root = filepath.Clean(root)
+	if path == root {
+		return "", true
+	}
+	rest, ok := strings.CutPrefix(path, root+string(filepath.Separator))
+	if !ok {
+		return "", false
+	}
+	return "/" + filepath.ToSlash(rest), true
+}
+
+// hostPathDiagnostics warns about volume sources written as absolute host
+// paths, which break when the manifest is used on another machine. The
+// message suggests the ${PROJECT_ROOT} or ${HOME} form when one applies.
+func hostPathDiagnostics(workspaceName, resourceKey, manifestDir string, volumes []resolve.Volume) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, volume := range volumes {
+		if !isAbsoluteHostPath(volume.Source) {
+			continue
+		}
+		message := fmt.Sprintf("resource %q mounts absolute host path %s at %s, which other machines may not have", resourceKey, volume.Source, volume.Target)
+		if portable, ok := PortableHostPath(volume.Source, manifestDir); ok {
+			message += "; use " + portable
+		} else {
+			message += "; use a path under " + ProjectRootVar + " or " + HomeVar
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity:  SeverityWarning,
+			Code:      "host-path-absolute",
+			Workspace: workspaceName,
+			Resource:  resourceKey,
+			Message:   message,
+		})
+	}
+	return diagnostics
+}
+
+// SELinuxEnforcing reports whether SELinux enforces its policy on this host,
+// where a container is denied a bind mount whose source was not relabeled.
+func SELinuxEnforcing() bool {
+	data, err := os.ReadFile("/sys/fs/selinux/enforce")
+	return err == nil && strings.TrimSpace(string(data)) == "1"
+}
+
+// SELinuxLabelDiagnostics warns about bind mounts of enabled resources that
+// set neither the z nor the Z option. Callers add them only on an enforcing
+// SELinux host, since elsewhere the options do nothing.
+func SELinuxLabelDiagnostics(desired *DesiredWorkspace) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, resource := range desired.Resources {
+		if resource == nil || !resource.Enabled {
+			continue
+		}
+		for _, volume := range resource.Spec.Volumes {
+			if !isBindSource(volume.Source) || slices.Contains(volume.Options, "z") || slices.Contains(volume.Options, "Z") {
+				continue
+			}
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity:  SeverityWarning,
+				Code:      "bind-mount-unlabeled",
+				Workspace: desired.Name,
+				Resource:  resource.Key,
+				Message:   fmt.Sprintf("resource %q bind mounts %s without a z or Z option; SELinux will deny the container access", resource.Key, volume.Source),
+			})
+		}
+	}
+	return diagnostics
+}
+
+func isBindSource(source string) bool {
+	return isAbsoluteHostPath(source) || strings.HasPrefix(source, ".") || strings.HasPrefix(source, "~")
+}
+
+func isAbsoluteHostPath(source string) bool {
+	return strings.HasPrefix(source, "/") || workspace.IsWindowsDrivePath(source)
+}
@@ -0,0 +1,58 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+)
+
+// ImageArchiver is implemented by adapters that can move images through a
+// tar archive, so a workspace can be recreated on a host without registry
+// access.
+type ImageArchiver interface {
+	SaveImages(ctx context.Context, images []string, path string) error
+	LoadImages(ctx context.Context, path string) ([]string, error)
+}
+
+// ImagePuller is implemented by adapters that can pull an image ahead of
+// creating a container, so apply can time the pull on its own. Pulled is
+// false when the image was already present.
+type ImagePuller interface {
+	PullImage(ctx context.Context, image string) (pulled bool, err error)
+}
+
+// ImageRefresher is implemented by adapters that can pull the newest copy
+// of an image, whether or not one is present, and report the ID it
+// resolves to. A managed image update compares that ID with the image ID
+// of the running container.
+type ImageRefresher interface {
+	RefreshImage(ctx context.Context, image string) (id string, err error)
+}
+
+// SameImageID reports whether two image IDs name the same image, ignoring
+// the sha256: prefix one runtime prints and another leaves out.
+func SameImageID(a, b string) bool {
+	return a != "" && strings.TrimPrefix(a, "sha256:") == strings.TrimPrefix(b, "sha256:")
+}
+
+// ParseLoadedImages extracts image references from `docker load` and
+// `podman load` output. Podman may list several images on one
+// "Loaded image(s):" line separated by commas.
+func ParseLoadedImages(output []byte) []string {
+	var images []string
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		var list string
+		for _, prefix := range []string{"Loaded image(s):", "Loaded image:", "Loaded image ID:"} {
+			if rest, ok := strings.CutPrefix(line, prefix); ok {
+				list = rest
+				break
+			}
+		}
+		for _, image := range strings.Split(list, ",") {
+			if image = strings.TrimSpace(image); image != "" {
+				images = append(images, image)
+			}
+		}
+	}
+	return images
+}
@@ -15,13 +15,17 @@ type containerInspectDocument struct {
 	ID           string `json:"Id"`
 	Name         string `json:"Name"`
 	RestartCount int    `json:"RestartCount"`
+	Image        string `json:"Image"`
 	Config       struct {
-		Image       string            `json:"Image"`
-		Env         []string          `json:"Env"`
-		Cmd         []string          `json:"Cmd"`
-		Entrypoint  []string          `json:"Entrypoint"`
-		WorkingDir  string            `json:"WorkingDir"`
-		Labels      map[string]string `json:"Labels"`
+		Image      string            `json:"Image"`
+		Env        []string          `json:"Env"`
+		Cmd        []string          `json:"Cmd"`
+		Entrypoint []string          `json:"Entrypoint"`
+		WorkingDir string            `json:"WorkingDir"`
+		Labels     map[string]string `json:"Labels"`
+		// StopSignal is a name such as SIGINT, or a number from older podman.
+		StopSignal  json.RawMessage `json:"StopSignal"`
+		StopTimeout *int            `json:"StopTimeout"`
 		Healthcheck *struct {
 			Test        []string `json:"Test"`
 			Interval    int64    `json:"Interval"`
@@ -41,6 +45,10 @@ type containerInspectDocument struct {
 			Status string `json:"Status"`
 		} `json:"Health"`
 	} `json:"State"`
+	HostConfig struct {
+		UsernsMode string `json:"UsernsMode"`
+		Memory     int64  `json:"Memory"`
+	} `json:"HostConfig"`
 	NetworkSettings struct {
 		Ports    map[string][]portBinding           `json:"Ports"`
 		Networks map[string]networkEndpointSettings `json:"Networks"`
@@ -59,6 +67,7 @@ type networkEndpointSettings struct {
 
 type mountDocument struct {
 	Type        string `json:"Type"`
+	Name        string `json:"Name"`
 	Source      string `json:"Source"`
 	Destination string `json:"Destination"`
 	RW          bool   `json:"RW"`
@@ -128,6 +137,7 @@ func NormalizeInspectSnapshot(provider string, desired *DesiredWorkspace, contai
 			RuntimeName: trimContainerName(doc.Name),
 			LogicalHost: logicalHost,
 			ID:          doc.ID,
+			ImageID:     doc.Image,
 			State: ResourceState{
 				Status:       doc.State.Status,
 				Running:      doc.State.Running,
@@ -148,6 +158,11 @@ func NormalizeInspectSnapshot(provider string, desired *DesiredWorkspace, contai
 				Volumes:    volumesFromInspect(doc.Mounts),
 				Health:     healthFromInspect(doc.Config.Healthcheck),
 				Labels:     labels,
+
+				StopSignal:  stopSignalFromInspect(doc.Config.StopSignal),
+				StopTimeout: cloneIntPtr(doc.Config.StopTimeout),
+				Userns:      usernsFromInspect(doc.HostConfig.UsernsMode),
+				Memory:      memoryFromInspect(doc.HostConfig.Memory),
 			},
 		})
 	}
@@ -218,6 +233,63 @@ func portsFromInspect(values map[string][]portBinding) []PortSpec {
 	return ports
 }
 
+func stopSignalFromInspect(value json.RawMessage) string {
+	var name string
+	if json.Unmarshal(value, &name) == nil {
+		return CanonicalStopSignal(name)
+	}
+	var number int
+	if json.Unmarshal(value, &number) == nil && number > 0 {
+		return CanonicalStopSignal(strconv.Itoa(number))
+	}
+	return ""
+}
+
+// signalNames are the Linux signal numbers containers run with.
+var signalNames = []string{
+	1: "HUP", 2: "INT", 3: "QUIT", 4: "ILL", 5: "TRAP", 6: "ABRT", 7: "BUS", 8: "FPE",
+	9: "KILL", 10: "USR1", 11: "SEGV", 12: "USR2", 13: "PIPE", 14: "ALRM", 15: "TERM", 16: "STKFLT",
+	17: "CHLD", 18: "CONT", 19: "STOP", 20: "TSTP", 21: "TTIN", 22: "TTOU", 23: "URG", 24: "XCPU",
+	25: "XFSZ", 26: "VTALRM", 27: "PROF", 28: "WINCH", 29: "IO", 30: "PWR", 31: "SYS",
+}
+
+// CanonicalStopSignal spells a stop signal as its SIG-prefixed name, so a
+// manifest's TERM or SIGTERM matches the 15 podman reports. Numbers without
+// a name, such as real-time signals, are kept as written.
+func CanonicalStopSignal(signal string) string {
+	signal = strings.ToUpper(strings.TrimSpace(signal))
+	if number, err := strconv.Atoi(signal); err == nil {
+		if number > 0 && number < len(signalNames) {
+			return "SIG" + signalNames[number]
+		}
+		return signal
+	}
+	if signal == "" || strings.HasPrefix(signal, "SIG") {
+		return signal
+	}
+	return "SIG" + signal
+}
+
+// memoryFromInspect reports a memory limit in bytes; zero means none.
+func memoryFromInspect(bytes int64) string {
+	if bytes <= 0 {
+		return ""
+	}
+	return strconv.FormatInt(bytes, 10)
+}
+
+// usernsFromInspect reduces a reported user namespace mode to the values a
+// manifest can set. Runtimes report the default as "", "private", or "host",
+// and podman may append options, as in keep-id:uid=1000.
+func usernsFromInspect(mode string) string {
+	mode, _, _ = strings.Cut(mode, ":")
+	switch mode {
+	case "", "private", "host":
+		return ""
+	}
+	return mode
+}
+
 func volumesFromInspect(values []mountDocument) []VolumeSpec {
 	if len(values) == 0 {
 		return nil
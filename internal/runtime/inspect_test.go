@@ -1,8 +1,11 @@
 package runtime_test
 
 import (
+	"os"
 	"path/filepath"
+	"reflect"
 	stdruntime "runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -165,6 +168,585 @@ func TestNormalizeInspectSnapshotPreservesPublishedPortsAndHealth(t *testing.T)
 	}
 }
 
+func TestBuildDesiredWorkspaceAppliesImageOverrides(t *testing.T) {
+	graph := &resolvepkg.Graph{
+		Workspace: resolvepkg.Workspace{Name: "shop-local"},
+		Resources: []*resolvepkg.Resource{
+			{Key: "postgres", Enabled: true, Host: "postgres", Runtime: &resolvepkg.Runtime{Image: "docker.io/library/postgres:16@sha256:abc"}, Overrides: map[string]any{"imageTag": "17-alpine"}},
+			{Key: "cache", Enabled: true, Host: "cache", Runtime: &resolvepkg.Runtime{Image: "redis:7"}, Overrides: map[string]any{"image": "registry.local:5000/valkey", "imageTag": "8"}},
+			{Key: "broken", Enabled: true, Host: "broken", Runtime: &resolvepkg.Runtime{Image: "redis:7"}, Overrides: map[string]any{"imageTag": "-bad"}},
+		},
+	}
+	desired, err := runtimepkg.BuildDesiredWorkspace(graph, nil)
+	if err != nil {
+		t.Fatalf("BuildDesiredWorkspace returned error: %v", err)
+	}
+	if got, want := desired.Resource("postgres").Spec.Image, "docker.io/library/postgres:17-alpine"; got != want {
+		t.Fatalf("postgres image = %q, want %q", got, want)
+	}
+	if got, want := desired.Resource("cache").Spec.Image, "registry.local:5000/valkey:8"; got != want {
+		t.Fatalf("cache image = %q, want %q", got, want)
+	}
+	broken := desired.Resource("broken")
+	if got, want := broken.Spec.Image, "redis:7"; got != want {
+		t.Fatalf("broken image = %q, want %q", got, want)
+	}
+	if len(broken.Diagnostics) != 1 || broken.Diagnostics[0].Code != "invalid-image-override" {
+		t.Fatalf("broken diagnostics = %#v, want one invalid-image-override", broken.Diagnostics)
+	}
+	if len(desired.Resource("postgres").Diagnostics) != 0 {
+		t.Fatalf("postgres diagnostics = %#v, want none", desired.Resource("postgres").Diagnostics)
+	}
+}
+
+func TestBuildDesiredWorkspaceAppliesCommandOverride(t *testing.T) {
+	graph := &resolvepkg.Graph{
+		Workspace: resolvepkg.Workspace{Name: "shop-local"},
+		Resources: []*resolvepkg.Resource{
+			{Key: "app-queue", Enabled: true, Host: "app-queue", Runtime: &resolvepkg.Runtime{Image: "php:8.3-cli", Command: []string{"php", "artisan", "serve"}}, Overrides: map[string]any{"command": []any{"php", "artisan", "queue:work"}}},
+			{Key: "broken", Enabled: true, Host: "broken", Runtime: &resolvepkg.Runtime{Image: "php:8.3-cli", Command: []string{"php"}}, Overrides: map[string]any{"command": "php artisan"}},
+		},
+	}
+	desired, err := runtimepkg.BuildDesiredWorkspace(graph, nil)
+	if err != nil {
+		t.Fatalf("BuildDesiredWorkspace returned error: %v", err)
+	}
+	if got, want := desired.Resource("app-queue").Spec.Command, []string{"php", "artisan", "queue:work"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("queue command = %v, want %v", got, want)
+	}
+	broken := desired.Resource("broken")
+	if !reflect.DeepEqual(broken.Spec.Command, []string{"php"}) || len(broken.Diagnostics) != 1 || broken.Diagnostics[0].Code != "invalid-command-override" {
+		t.Fatalf("broken = %#v, want template command and one invalid-command-override", broken)
+	}
+}
+
+func TestBuildDesiredWorkspaceWarnsOnNodeEngineMismatch(t *testing.T) {
+	project := t.TempDir()
+	if err := os.WriteFile(filepath.Join(project, ".nvmrc"), []byte("20\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	source := &resolvepkg.SourceRef{Type: "project", Path: ".", ResolvedPath: project}
+	graph := &resolvepkg.Graph{
+		Workspace: resolvepkg.Workspace{Name: "shop-local"},
+		Resources: []*resolvepkg.Resource{
+			{Key: "api", Enabled: true, Host: "api", Source: source, Runtime: &resolvepkg.Runtime{Image: "node:22-alpine"}},
+			{Key: "pinned", Enabled: true, Host: "pinned", Source: source, Runtime: &resolvepkg.Runtime{Image: "node:22-alpine"}, Overrides: map[string]any{"imageTag": "20-alpine"}},
+			{Key: "lts", Enabled: true, Host: "lts", Source: source, Runtime: &resolvepkg.Runtime{Image: "node:lts-alpine"}},
+		},
+	}
+	desired, err := runtimepkg.BuildDesiredWorkspace(graph, nil)
+	if err != nil {
+		t.Fatalf("BuildDesiredWorkspace returned error: %v", err)
+	}
+	api := desired.Resource("api")
+	if len(api.Diagnostics) != 1 || api.Diagnostics[0].Code != "node-engine-mismatch" || api.Diagnostics[0].Severity != runtimepkg.SeverityWarning {
+		t.Fatalf("api diagnostics = %#v, want one node-engine-mismatch warning", api.Diagnostics)
+	}
+	for _, key := range []string{"pinned", "lts"} {
+		if diagnostics := desired.Resource(key).Diagnostics; len(diagnostics) != 0 {
+			t.Fatalf("%s diagnostics = %#v, want none", key, diagnostics)
+		}
+	}
+}
+
+func TestBuildDesiredWorkspaceMapsAutoUpdateOverride(t *testing.T) {
+	graph := &resolvepkg.Graph{
+		Workspace: resolvepkg.Workspace{Name: "shop-local"},
+		Resources: []*resolvepkg.Resource{
+			{Key: "api", Enabled: true, Host: "api", Runtime: &resolvepkg.Runtime{Image: "node:22"}, Overrides: map[string]any{"autoUpdate": "registry"}},
+			{Key: "broken", Enabled: true, Host: "broken", Runtime: &resolvepkg.Runtime{Image: "node:22"}, Overrides: map[string]any{"autoUpdate": "always"}},
+			{Key: "web", Enabled: true, Host: "web", Runtime: &resolvepkg.Runtime{Image: "nginx:alpine"}, Overrides: map[string]any{"autoUpdate": "managed"}},
+		},
+	}
+	desired, err := runtimepkg.BuildDesiredWorkspace(graph, nil)
+	if err != nil {
+		t.Fatalf("BuildDesiredWorkspace returned error: %v", err)
+	}
+	if got := desired.Resource("api").Spec.Labels[runtimepkg.LabelAutoUpdate]; got != "registry" {
+		t.Fatalf("auto-update label = %q, want registry", got)
+	}
+	web := desired.Resource("web").Spec.Labels
+	if _, ok := web[runtimepkg.LabelAutoUpdate]; ok || web[runtimepkg.LabelManagedUpdate] != "managed" {
+		t.Fatalf("managed labels = %#v, want only %s", web, runtimepkg.LabelManagedUpdate)
+	}
+	broken := desired.Resource("broken")
+	if _, ok := broken.Spec.Labels[runtimepkg.LabelAutoUpdate]; ok {
+		t.Fatal("invalid policy should not set the auto-update label")
+	}
+	if len(broken.Diagnostics) != 1 || broken.Diagnostics[0].Code != "invalid-auto-update" {
+		t.Fatalf("broken diagnostics = %#v, want one invalid-auto-update", broken.Diagnostics)
+	}
+}
+
+func TestBuildDesiredWorkspaceExpandsReplicas(t *testing.T) {
+	graph := &resolvepkg.Graph{
+		Workspace: resolvepkg.Workspace{Name: "shop-local"},
+		Resources: []*resolvepkg.Resource{
+			{Key: "api", Enabled: true, Host: "api", Replicas: 3, Runtime: &resolvepkg.Runtime{Image: "node:22"}, Ports: []resolvepkg.Port{{Host: 8080, Container: 3000}, {Container: 9229}}},
+			{Key: "web", Enabled: true, Host: "web", DependsOn: []string{"api"}, Runtime: &resolvepkg.Runtime{Image: "nginx:alpine"}},
+		},
+	}
+	desired, err := runtimepkg.BuildDesiredWorkspace(graph, nil)
+	if err != nil {
+		t.Fatalf("BuildDesiredWorkspace returned error: %v", err)
+	}
+	if desired.Resource("api") != nil {
+		t.Fatalf("scaled resource should only appear as replicas")
+	}
+	for index, want := range []int{8080, 8081, 8082} {
+		key := runtimepkg.ReplicaKey("api", index+1)
+		replica := desired.Resource(key)
+		if replica == nil {
+			t.Fatalf("missing replica %q", key)
+		}
+		if replica.ReplicaOf != "api" || replica.LogicalHost != "api" {
+			t.Fatalf("replica %q = replicaOf %q host %q, want api/api", key, replica.ReplicaOf, replica.LogicalHost)
+		}
+		if got := replica.RuntimeName; got != "devarch-shop-local-"+key {
+			t.Fatalf("replica %q runtime name = %q", key, got)
+		}
+		if got := replica.Spec.Ports[0].Published; got != want {
+			t.Fatalf("replica %q published port = %d, want %d", key, got, want)
+		}
+		if got := replica.Spec.Ports[1].Published; got != 0 {
+			t.Fatalf("replica %q unpublished port = %d, want 0", key, got)
+		}
+		if got := replica.Spec.Labels[runtimepkg.LabelResource]; got != key {
+			t.Fatalf("replica %q resource label = %q", key, got)
+		}
+	}
+	if got, want := strings.Join(desired.Resource("web").DependsOn, ","), "api-1,api-2,api-3"; got != want {
+		t.Fatalf("web dependsOn = %q, want %q", got, want)
+	}
+
+	graph.Resources = append(graph.Resources, &resolvepkg.Resource{Key: "api-2", Enabled: true, Host: "api-2", Runtime: &resolvepkg.Runtime{Image: "node:22"}})
+	if _, err := runtimepkg.BuildDesiredWorkspace(graph, nil); err == nil {
+		t.Fatal("expected replica key collision to fail")
+	}
+}
+
+func TestBuildDesiredWorkspaceFlagsReplicaPortCollisions(t *testing.T) {
+	graph := &resolvepkg.Graph{
+		Workspace: resolvepkg.Workspace{Name: "shop-local"},
+		Resources: []*resolvepkg.Resource{
+			{Key: "api", Enabled: true, Host: "api", Replicas: 3, Runtime: &resolvepkg.Runtime{Image: "node:22"}, Ports: []resolvepkg.Port{{Host: 8080, Container: 3000}}},
+			{Key: "web", Enabled: true, Host: "web", Runtime: &resolvepkg.Runtime{Image: "nginx:alpine"}, Ports: []resolvepkg.Port{{Host: 8081, Container: 80}}},
+		},
+	}
+	desired, err := runtimepkg.BuildDesiredWorkspace(graph, nil)
+	if err != nil {
+		t.Fatalf("BuildDesiredWorkspace returned error: %v", err)
+	}
+	replica := desired.Resource("api-2")
+	if len(replica.Diagnostics) != 1 || replica.Diagnostics[0].Code != "replica-port-conflict" || !strings.Contains(replica.Diagnostics[0].Message, `"web"`) {
+		t.Fatalf("api-2 diagnostics = %#v, want one replica-port-conflict naming web", replica.Diagnostics)
+	}
+	if len(desired.Resource("web").Diagnostics) != 0 || len(desired.Resource("api-3").Diagnostics) != 0 {
+		t.Fatal("only the colliding replica should carry the diagnostic")
+	}
+	if !desired.Blocked() {
+		t.Fatal("a replica port collision should block apply")
+	}
+
+	graph.Resources = graph.Resources[:1]
+	graph.Workspace.Policies = workspacepkg.Policies{HostPorts: &workspacepkg.PortRange{From: 8080, To: 8081}}
+	desired, err = runtimepkg.BuildDesiredWorkspace(graph, nil)
+	if err != nil {
+		t.Fatalf("BuildDesiredWorkspace returned error: %v", err)
+	}
+	if len(desired.Diagnostics) != 1 || desired.Diagnostics[0].Code != "quota-exceeded" || !strings.Contains(desired.Diagnostics[0].Message, `"api-3" publishes host port 8082`) {
+		t.Fatalf("diagnostics = %#v, want api-3 outside policies.hostPorts", desired.Diagnostics)
+	}
+}
+
+func TestBuildDesiredWorkspaceGeneratesLoadBalancerForReplicas(t *testing.T) {
+	graph := &resolvepkg.Graph{
+		Workspace: resolvepkg.Workspace{Name: "shop-local", Runtime: workspacepkg.RuntimePreferences{IsolatedNetwork: true}},
+		Resources: []*resolvepkg.Resource{
+			{Key: "api", Enabled: true, Host: "api", Replicas: 2, LoadBalancer: &resolvepkg.LoadBalancer{Port: 3000, Host: 8080}, Runtime: &resolvepkg.Runtime{Image: "node:22"}},
+			{Key: "web", Enabled: true, Host: "web", DependsOn: []string{"api"}, Runtime: &resolvepkg.Runtime{Image: "nginx:alpine"}},
+		},
+	}
+	desired, err := runtimepkg.BuildDesiredWorkspace(graph, nil)
+	if err != nil {
+		t.Fatalf("BuildDesiredWorkspace returned error: %v", err)
+	}
+	balancer := desired.Resource("api")
+	if balancer == nil {
+		t.Fatal("expected generated load balancer under the resource key")
+	}
+	if balancer.Spec.Image != runtimepkg.LoadBalancerImage || len(balancer.Diagnostics) != 0 {
+		t.Fatalf("balancer = image %q diagnostics %#v", balancer.Spec.Image, balancer.Diagnostics)
+	}
+	if got := balancer.Spec.Ports; len(got) != 1 || got[0].Container != 80 || got[0].Published != 8080 {
+		t.Fatalf("balancer ports = %#v, want 8080->80", got)
+	}
+	script := strings.Join(balancer.Spec.Command, " ")
+	for _, upstream := range []string{"server devarch-shop-local-api-1:3000;", "server devarch-shop-local-api-2:3000;"} {
+		if !strings.Contains(script, upstream) {
+			t.Fatalf("balancer command %q missing %q", script, upstream)
+		}
+	}
+	if got, want := strings.Join(balancer.DependsOn, ","), "api-1,api-2"; got != want {
+		t.Fatalf("balancer dependsOn = %q, want %q", got, want)
+	}
+	if got, want := strings.Join(desired.Resource("web").DependsOn, ","), "api"; got != want {
+		t.Fatalf("web dependsOn = %q, want %q", got, want)
+	}
+
+	graph.Resources[0].Replicas = 3
+	scaled, err := runtimepkg.BuildDesiredWorkspace(graph, nil)
+	if err != nil {
+		t.Fatalf("BuildDesiredWorkspace returned error: %v", err)
+	}
+	if !strings.Contains(strings.Join(scaled.Resource("api").Spec.Command, " "), "server devarch-shop-local-api-3:3000;") {
+		t.Fatal("expected scaled balancer to include the third replica")
+	}
+
+	graph.Workspace.Runtime.IsolatedNetwork = false
+	unnetworked, err := runtimepkg.BuildDesiredWorkspace(graph, nil)
+	if err != nil {
+		t.Fatalf("BuildDesiredWorkspace returned error: %v", err)
+	}
+	if diagnostics := unnetworked.Resource("api").Diagnostics; len(diagnostics) != 1 || diagnostics[0].Code != "load-balancer-network" {
+		t.Fatalf("diagnostics = %#v, want load-balancer-network", diagnostics)
+	}
+}
+
+func TestBuildDesiredWorkspaceFrontsResourceWithInspector(t *testing.T) {
+	graph := &resolvepkg.Graph{
+		Workspace: resolvepkg.Workspace{Name: "shop-local", Runtime: workspacepkg.RuntimePreferences{IsolatedNetwork: true}},
+		Resources: []*resolvepkg.Resource{
+			{Key: "api", Enabled: true, Host: "api", Inspector: &resolvepkg.Inspector{Port: 3000}, Runtime: &resolvepkg.Runtime{Image: "node:22"}, Ports: []resolvepkg.Port{{Host: 8080, Container: 3000}, {Host: 9229, Container: 9229}}},
+		},
+	}
+	desired, err := runtimepkg.BuildDesiredWorkspace(graph, nil)
+	if err != nil {
+		t.Fatalf("BuildDesiredWorkspace returned error: %v", err)
+	}
+	api := desired.Resource("api")
+	if got := api.Spec.Ports; len(got) != 1 || got[0].Container != 9229 {
+		t.Fatalf("api ports = %#v, want only the debugger port left", got)
+	}
+	inspector := desired.Resource(runtimepkg.InspectorKey("api"))
+	if inspector == nil {
+		t.Fatal("expected generated inspector resource")
+	}
+	if inspector.Spec.Image != runtimepkg.InspectorImage || len(inspector.Diagnostics) != 0 {
+		t.Fatalf("inspector = image %q diagnostics %#v", inspector.Spec.Image, inspector.Diagnostics)
+	}
+	if got := inspector.Spec.Ports; len(got) != 1 || got[0].Published != 8080 {
+		t.Fatalf("inspector ports = %#v, want host port 8080", got)
+	}
+	if command := strings.Join(inspector.Spec.Command, " "); !strings.Contains(command, "--mode reverse:http://devarch-shop-local-api:3000") {
+		t.Fatalf("inspector command = %q", command)
+	}
+	if got := strings.Join(inspector.DependsOn, ","); got != "api" {
+		t.Fatalf("inspector dependsOn = %q, want api", got)
+	}
+
+	graph.Workspace.Runtime.IsolatedNetwork = false
+	unnetworked, err := runtimepkg.BuildDesiredWorkspace(graph, nil)
+	if err != nil {
+		t.Fatalf("BuildDesiredWorkspace returned error: %v", err)
+	}
+	if diagnostics := unnetworked.Resource("api-inspector").Diagnostics; len(diagnostics) != 1 || diagnostics[0].Code != "inspector-network" {
+		t.Fatalf("diagnostics = %#v, want inspector-network", diagnostics)
+	}
+}
+
+func TestBuildDesiredWorkspaceEnforcesPolicyQuotas(t *testing.T) {
+	graph := &resolvepkg.Graph{
+		Workspace: resolvepkg.Workspace{Name: "shop-local", Policies: workspacepkg.Policies{MaxResources: 2, MaxPublishedPorts: 3}},
+		Resources: []*resolvepkg.Resource{
+			{Key: "api", Enabled: true, Host: "api", Replicas: 2, Runtime: &resolvepkg.Runtime{Image: "node:22"}, Ports: []resolvepkg.Port{{Host: 8080, Container: 3000}}},
+			{Key: "worker", Enabled: false, Host: "worker", Runtime: &resolvepkg.Runtime{Image: "node:22"}},
+		},
+	}
+	desired, err := runtimepkg.BuildDesiredWorkspace(graph, nil)
+	if err != nil {
+		t.Fatalf("BuildDesiredWorkspace returned error: %v", err)
+	}
+	if desired.Blocked() {
+		t.Fatalf("workspace within quota should not block: %#v", desired.Diagnostics)
+	}
+
+	graph.Resources[0].Replicas = 4
+	desired, err = runtimepkg.BuildDesiredWorkspace(graph, nil)
+	if err != nil {
+		t.Fatalf("BuildDesiredWorkspace returned error: %v", err)
+	}
+	if len(desired.Diagnostics) != 2 || desired.Diagnostics[0].Code != "quota-exceeded" || desired.Diagnostics[1].Code != "quota-exceeded" {
+		t.Fatalf("diagnostics = %#v, want two quota-exceeded", desired.Diagnostics)
+	}
+	if !desired.Blocked() {
+		t.Fatal("expected quota overrun to block apply")
+	}
+}
+
+func TestBuildDesiredWorkspaceEnforcesMaxTotalMemory(t *testing.T) {
+	graph := &resolvepkg.Graph{
+		Workspace: resolvepkg.Workspace{Name: "shop-local", Policies: workspacepkg.Policies{MaxTotalMemory: "1g"}},
+		Resources: []*resolvepkg.Resource{
+			{Key: "api", Enabled: true, Host: "api", Replicas: 2, Memory: "256m", Runtime: &resolvepkg.Runtime{Image: "node:22"}},
+			{Key: "db", Enabled: true, Host: "db", Memory: "512m", Runtime: &resolvepkg.Runtime{Image: "postgres:16"}},
+		},
+	}
+	desired, err := runtimepkg.BuildDesiredWorkspace(graph, nil)
+	if err != nil {
+		t.Fatalf("BuildDesiredWorkspace returned error: %v", err)
+	}
+	if desired.Blocked() {
+		t.Fatalf("workspace within memory quota should not block: %#v", desired.Diagnostics)
+	}
+	if got := desired.Resource("db").Spec.Memory; got != "512m" {
+		t.Fatalf("db memory = %q, want 512m", got)
+	}
+
+	graph.Resources[1].Memory = "1g"
+	graph.Resources = append(graph.Resources, &resolvepkg.Resource{Key: "cache", Enabled: true, Host: "cache", Runtime: &resolvepkg.Runtime{Image: "redis:7"}})
+	desired, err = runtimepkg.BuildDesiredWorkspace(graph, nil)
+	if err != nil {
+		t.Fatalf("BuildDesiredWorkspace returned error: %v", err)
+	}
+	var messages []string
+	for _, diagnostic := range desired.Diagnostics {
+		if diagnostic.Code != "quota-exceeded" {
+			t.Fatalf("diagnostic = %#v, want quota-exceeded", diagnostic)
+		}
+		messages = append(messages, diagnostic.Message)
+	}
+	if len(messages) != 2 || !strings.Contains(messages[0], `"cache" sets no memory limit`) || !strings.Contains(messages[1], "exceeding policies.maxTotalMemory 1g") {
+		t.Fatalf("messages = %q, want the unlimited cache and the overrun", messages)
+	}
+	if !desired.Blocked() {
+		t.Fatal("expected memory overrun to block apply")
+	}
+}
+
+func TestBuildDesiredWorkspaceChecksTemplateEnvSchema(t *testing.T) {
+	schema := map[string]resolvepkg.EnvVar{
+		"DB_PASSWORD":  {Required: true, Description: "database password"},
+		"DATABASE_URL": {Required: true},
+		"LOG_LEVEL":    {Default: "info", Pattern: "debug|info|warn"},
+		"PORT":         {Pattern: "[0-9]+"},
+	}
+	graph := &resolvepkg.Graph{
+		Workspace: resolvepkg.Workspace{Name: "shop-local"},
+		Resources: []*resolvepkg.Resource{{
+			Key: "api", Enabled: true, Host: "api", Runtime: &resolvepkg.Runtime{Image: "node:22"}, EnvSchema: schema,
+			Env: map[string]workspacepkg.EnvValue{"PORT": workspacepkg.StringEnvValue("http"), "DATABASE_URL": workspacepkg.StringEnvValue("")},
+		}},
+	}
+	desired, err := runtimepkg.BuildDesiredWorkspace(graph, nil)
+	if err != nil {
+		t.Fatalf("BuildDesiredWorkspace returned error: %v", err)
+	}
+	api := desired.Resource("api")
+	var got []string
+	for _, diagnostic := range api.Diagnostics {
+		got = append(got, diagnostic.Code+":"+diagnostic.EnvKey)
+	}
+	if want := []string{"env-required:DATABASE_URL", "env-required:DB_PASSWORD", "env-invalid:PORT"}; !reflect.DeepEqual(got, want) || !api.Blocked() {
+		t.Fatalf("diagnostics = %v, want %v and a blocked resource", got, want)
+	}
+	if !strings.Contains(api.Diagnostics[1].Message, "DB_PASSWORD is required (database password)") {
+		t.Fatalf("message = %q", api.Diagnostics[1].Message)
+	}
+	if got := api.Spec.Env["LOG_LEVEL"].Text(); got != "info" {
+		t.Fatalf("LOG_LEVEL = %q, want the schema default", got)
+	}
+
+	graph.Resources[0].Env = map[string]workspacepkg.EnvValue{
+		"PORT":         workspacepkg.StringEnvValue("3000"),
+		"DATABASE_URL": workspacepkg.StringEnvValue("postgres://${resource.host}/app"),
+		"DB_PASSWORD":  workspacepkg.StringEnvValue("secret"),
+		"LOG_LEVEL":    workspacepkg.StringEnvValue("debug"),
+	}
+	desired, err = runtimepkg.BuildDesiredWorkspace(graph, nil)
+	if err != nil {
+		t.Fatalf("BuildDesiredWorkspace returned error: %v", err)
+	}
+	if diagnostics := desired.Resource("api").Diagnostics; len(diagnostics) != 0 {
+		t.Fatalf("diagnostics = %#v, want none", diagnostics)
+	}
+}
+
+func TestBuildDesiredWorkspaceResolvesPortableHostPaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	graph := &resolvepkg.Graph{
+		Workspace: resolvepkg.Workspace{Name: "shop-local", ManifestDir: "/srv/shop"},
+		Resources: []*resolvepkg.Resource{{
+			Key: "web", Enabled: true, Host: "web", Runtime: &resolvepkg.Runtime{Image: "nginx:alpine"},
+			Volumes: []resolvepkg.Volume{
+				{Source: "${PROJECT_ROOT}/nginx.conf", Target: "/etc/nginx/nginx.conf"},
+				{Source: "${HOME}/certs", Target: "/certs"},
+				{Source: "/srv/shop/html", Target: "/usr/share/nginx/html"},
+				{Source: "/opt/shared", Target: "/shared"},
+				{Source: "web-cache", Target: "/var/cache/nginx"},
+			},
+		}},
+	}
+	desired, err := runtimepkg.BuildDesiredWorkspace(graph, nil)
+	if err != nil {
+		t.Fatalf("BuildDesiredWorkspace returned error: %v", err)
+	}
+	web := desired.Resource("web")
+	var sources []string
+	for _, volume := range web.Spec.Volumes {
+		sources = append(sources, volume.Source)
+	}
+	if want := []string{"/srv/shop/nginx.conf", home + "/certs", "/srv/shop/html", "/opt/shared", "web-cache"}; !reflect.DeepEqual(sources, want) {
+		t.Fatalf("volume sources = %v, want %v", sources, want)
+	}
+	if len(web.Diagnostics) != 2 || web.Diagnostics[0].Code != "host-path-absolute" || web.Blocked() {
+		t.Fatalf("diagnostics = %#v, want two non-blocking host-path-absolute warnings", web.Diagnostics)
+	}
+	if !strings.Contains(web.Diagnostics[0].Message, "use ${PROJECT_ROOT}/html") || !strings.Contains(web.Diagnostics[1].Message, "path under ${PROJECT_ROOT} or ${HOME}") {
+		t.Fatalf("messages = %q, %q", web.Diagnostics[0].Message, web.Diagnostics[1].Message)
+	}
+}
+
+func TestSELinuxLabelDiagnosticsFlagsUnlabeledBindMounts(t *testing.T) {
+	desired := &runtimepkg.DesiredWorkspace{Name: "shop-local", Resources: []*runtimepkg.DesiredResource{
+		{Key: "web", Enabled: true, Spec: runtimepkg.ResourceSpec{Volumes: []runtimepkg.VolumeSpec{
+			{Source: "/srv/shop/html", Target: "/usr/share/nginx/html"},
+			{Source: "/srv/shop/nginx.conf", Target: "/etc/nginx/nginx.conf", Options: []string{"Z"}},
+			{Source: "web-cache", Target: "/var/cache/nginx"},
+		}}},
+		{Key: "worker", Enabled: false, Spec: runtimepkg.ResourceSpec{Volumes: []runtimepkg.VolumeSpec{{Source: "./jobs", Target: "/jobs"}}}},
+	}}
+	diagnostics := runtimepkg.SELinuxLabelDiagnostics(desired)
+	if len(diagnostics) != 1 || diagnostics[0].Code != "bind-mount-unlabeled" || diagnostics[0].Severity != runtimepkg.SeverityWarning || !strings.Contains(diagnostics[0].Message, "/srv/shop/html") {
+		t.Fatalf("diagnostics = %#v, want one warning for /srv/shop/html", diagnostics)
+	}
+}
+
+func TestMirroredImageRewritesConfiguredRegistries(t *testing.T) {
+	mirrors := map[string]string{"docker.io": "localhost:5000", "ghcr.io": "cache.local/ghcr"}
+	cases := map[string]string{
+		"postgres:16":                     "localhost:5000/library/postgres:16",
+		"docker.io/bitnami/redis:7":       "localhost:5000/bitnami/redis:7",
+		"index.docker.io/nginx":           "localhost:5000/library/nginx",
+		"ghcr.io/acme/api@sha256:abc":     "cache.local/ghcr/acme/api@sha256:abc",
+		"quay.io/prometheus/prometheus:3": "quay.io/prometheus/prometheus:3",
+		"localhost/devarch-api:dev":       "localhost/devarch-api:dev",
+	}
+	for image, want := range cases {
+		if got := runtimepkg.MirroredImage(image, mirrors); got != want {
+			t.Fatalf("MirroredImage(%q) = %q, want %q", image, got, want)
+		}
+	}
+	if got := runtimepkg.MirroredImage("postgres:16", nil); got != "postgres:16" {
+		t.Fatalf("MirroredImage without mirrors = %q, want unchanged", got)
+	}
+}
+
+func TestMachineBindSourceTranslatesWindowsDrivesForPodmanOnly(t *testing.T) {
+	cases := map[string]string{
+		`C:\Users\ada\shop\nginx.conf`: "/mnt/c/Users/ada/shop/nginx.conf",
+		"d:/src/app/":                  "/mnt/d/src/app",
+		`E:\`:                          "/mnt/e",
+		"/home/ada/shop":               "/home/ada/shop",
+		"./nginx.conf":                 "./nginx.conf",
+		"pgdata":                       "pgdata",
+	}
+	for source, want := range cases {
+		if got := runtimepkg.MachineBindSource(runtimepkg.ProviderPodman, "windows", source); got != want {
+			t.Fatalf("MachineBindSource(podman, windows, %q) = %q, want %q", source, got, want)
+		}
+	}
+	// Docker Desktop and nerdctl take the Windows path as written, and podman
+	// off Windows has no drive mounts to translate to.
+	for _, target := range []struct{ provider, platform string }{
+		{runtimepkg.ProviderDocker, "windows"},
+		{runtimepkg.ProviderNerdctl, "windows"},
+		{runtimepkg.ProviderPodman, "linux"},
+		{runtimepkg.ProviderPodman, "darwin"},
+	} {
+		if got := runtimepkg.MachineBindSource(target.provider, target.platform, "C:/Users/ada/shop"); got != "C:/Users/ada/shop" {
+			t.Fatalf("MachineBindSource(%s, %s) = %q, want the path unchanged", target.provider, target.platform, got)
+		}
+	}
+	for source, want := range map[string]string{
+		"/mnt/c/Users/ada/shop/nginx.conf": "C:/Users/ada/shop/nginx.conf",
+		"/mnt/e":                           "E:/",
+		"/mnt/data/shop":                   "/mnt/data/shop",
+		"/var/lib/containers":              "/var/lib/containers",
+	} {
+		if got := runtimepkg.HostBindSource(runtimepkg.ProviderPodman, "windows", source); got != want {
+			t.Fatalf("HostBindSource(podman, windows, %q) = %q, want %q", source, got, want)
+		}
+	}
+	if got := runtimepkg.HostBindSource(runtimepkg.ProviderPodman, "linux", "/mnt/c/src"); got != "/mnt/c/src" {
+		t.Fatalf("HostBindSource on linux = %q, want unchanged", got)
+	}
+}
+
+func TestBuildDesiredWorkspaceKeepsWindowsSourcesForDocker(t *testing.T) {
+	graph := &resolvepkg.Graph{
+		Workspace: resolvepkg.Workspace{Name: "shop-local", ManifestDir: "C:/Users/ada/shop", Runtime: workspacepkg.RuntimePreferences{Provider: runtimepkg.ProviderDocker}},
+		Resources: []*resolvepkg.Resource{{
+			Key: "web", Enabled: true, Host: "web", Runtime: &resolvepkg.Runtime{Image: "nginx:alpine"},
+			Volumes: []resolvepkg.Volume{
+				{Source: "${PROJECT_ROOT}/nginx.conf", Target: "/etc/nginx/nginx.conf"},
+				{Source: "D:/certs", Target: "/certs"},
+			},
+		}},
+	}
+	desired, err := runtimepkg.BuildDesiredWorkspace(graph, nil)
+	if err != nil {
+		t.Fatalf("BuildDesiredWorkspace returned error: %v", err)
+	}
+	var sources []string
+	for _, volume := range desired.Resource("web").Spec.Volumes {
+		sources = append(sources, volume.Source)
+	}
+	if want := []string{"C:/Users/ada/shop/nginx.conf", "D:/certs"}; !reflect.DeepEqual(sources, want) {
+		t.Fatalf("docker volume sources = %v, want the Windows paths Docker Desktop mounts", sources)
+	}
+}
+
+func TestResourceStopSettingsOverrideTemplateAndRoundTripInspect(t *testing.T) {
+	templateTimeout, resourceTimeout := 10, 45
+	graph := &resolvepkg.Graph{
+		Workspace: resolvepkg.Workspace{Name: "shop-local"},
+		Resources: []*resolvepkg.Resource{
+			{Key: "postgres", Enabled: true, Host: "postgres", Runtime: &resolvepkg.Runtime{Image: "postgres:16", StopSignal: "SIGTERM", StopTimeout: &templateTimeout}, StopSignal: "SIGINT", StopTimeout: &resourceTimeout},
+			{Key: "redis", Enabled: true, Host: "redis", Runtime: &resolvepkg.Runtime{Image: "redis:7", StopSignal: "SIGTERM"}},
+		},
+	}
+	desired, err := runtimepkg.BuildDesiredWorkspace(graph, nil)
+	if err != nil {
+		t.Fatalf("BuildDesiredWorkspace returned error: %v", err)
+	}
+	postgres, redis := desired.Resource("postgres").Spec, desired.Resource("redis").Spec
+	if postgres.StopSignal != "SIGINT" || postgres.StopTimeout == nil || *postgres.StopTimeout != 45 {
+		t.Fatalf("postgres stop = %q, %v; want the resource's SIGINT and 45", postgres.StopSignal, postgres.StopTimeout)
+	}
+	if redis.StopSignal != "SIGTERM" || redis.StopTimeout != nil {
+		t.Fatalf("redis stop = %q, %v; want the template's SIGTERM", redis.StopSignal, redis.StopTimeout)
+	}
+
+	snapshot, err := runtimepkg.NormalizeInspectSnapshot(runtimepkg.ProviderPodman, desired, []byte(`[
+  {"Id": "a", "Name": "devarch-shop-local-postgres", "Config": {"Image": "postgres:16", "StopSignal": "SIGINT", "StopTimeout": 45, "Labels": {"devarch.workspace": "shop-local", "devarch.resource": "postgres"}}, "HostConfig": {"UsernsMode": "keep-id:uid=1000"}},
+  {"Id": "b", "Name": "devarch-shop-local-redis", "Config": {"Image": "redis:7", "StopSignal": 15, "Labels": {"devarch.workspace": "shop-local", "devarch.resource": "redis"}}, "HostConfig": {"UsernsMode": "private"}}
+]`), nil)
+	if err != nil {
+		t.Fatalf("NormalizeInspectSnapshot returned error: %v", err)
+	}
+	if spec := snapshot.Resource("postgres").Spec; spec.StopSignal != "SIGINT" || spec.StopTimeout == nil || *spec.StopTimeout != 45 {
+		t.Fatalf("inspected postgres stop = %q, %v", spec.StopSignal, spec.StopTimeout)
+	}
+	if got := snapshot.Resource("redis").Spec.StopSignal; got != "SIGTERM" {
+		t.Fatalf("numeric stop signal = %q, want SIGTERM", got)
+	}
+	if postgres, redis := snapshot.Resource("postgres").Spec.Userns, snapshot.Resource("redis").Spec.Userns; postgres != "keep-id" || redis != "" {
+		t.Fatalf("inspected userns = %q, %q; want keep-id and the default", postgres, redis)
+	}
+}
+
 func loadDesiredWorkspace(t *testing.T, name string) *runtimepkg.DesiredWorkspace {
 	t.Helper()
 	manifestPath := filepath.Join(repoRoot(t), "examples", "workspaces", name, "devarch.workspace.yaml")
@@ -200,3 +782,30 @@ func repoRoot(t *testing.T) string {
 	}
 	return filepath.Clean(filepath.Join(filepath.Dir(file), "..", ".."))
 }
+
+func TestNetworkFaultArgsTrapsQdiscRemoval(t *testing.T) {
+	ref := runtimepkg.ResourceRef{Workspace: "shop-local", Key: "api", RuntimeName: "devarch-shop-local-api"}
+	args, err := runtimepkg.NetworkFaultArgs(ref, runtimepkg.NetworkFault{Duration: time.Minute, Loss: 5})
+	if err != nil {
+		t.Fatalf("NetworkFaultArgs returned error: %v", err)
+	}
+	script := args[len(args)-1]
+	if !strings.HasPrefix(script, "trap 'tc qdisc del dev eth0 root' EXIT INT TERM;") {
+		t.Fatalf("script = %q, want the qdisc removal trapped on exit and signals", script)
+	}
+	if !strings.Contains(script, "netem loss 5% && sleep 60 & wait") {
+		t.Fatalf("script = %q, want the fault held for the duration", script)
+	}
+}
+
+func TestLoadTestArgsMountOnlyTheScriptAndSummary(t *testing.T) {
+	args, err := runtimepkg.LoadTestArgs(runtimepkg.LoadTestRequest{Network: "devarch-shop-local", Directory: "/tmp/devarch-loadtest-1", Script: "script.js", Summary: "summary.json", Env: map[string]string{"TARGET": "http://api:3000"}})
+	if err != nil {
+		t.Fatalf("LoadTestArgs returned error: %v", err)
+	}
+	got := strings.Join(args, " ")
+	want := "run --rm --network devarch-shop-local --volume /tmp/devarch-loadtest-1/script.js:/loadtest/script.js:ro,Z --volume /tmp/devarch-loadtest-1/summary.json:/loadtest/summary.json:Z --env TARGET=http://api:3000 " + runtimepkg.LoadTestImage
+	if !strings.HasPrefix(got, want) {
+		t.Fatalf("LoadTestArgs = %q, want prefix %q", got, want)
+	}
+}
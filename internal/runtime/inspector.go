@@ -0,0 +1,68 @@
+package runtime
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/prospect-ogujiuba/devarch/internal/workspace"
+)
+
+// InspectorImage runs the generated HTTP inspector. mitmdump prints one line
+// per request and response, which is what the request listing parses from
+// the inspector's logs.
+const InspectorImage = "docker.io/mitmproxy/mitmproxy:10.4"
+
+// inspectorListenPort is the port mitmdump listens on inside its container.
+const inspectorListenPort = 8080
+
+// InspectorKey names the inspector resource generated for resourceKey.
+func InspectorKey(resourceKey string) string {
+	return resourceKey + "-inspector"
+}
+
+// inspectorResource generates a reverse proxy that records the HTTP traffic
+// sent to target's inspector port. Without an explicit host port the proxy
+// takes over the host port target publishes for that container port, so
+// clients on the host keep their URL and go through the proxy instead.
+// Services inside the workspace network still reach target directly unless
+// they are pointed at the inspector's host. With no host port at all the
+// proxy is only reachable inside the network.
+func inspectorResource(desired *DesiredWorkspace, target *DesiredResource, inspector workspace.Inspector) *DesiredResource {
+	key := InspectorKey(target.Key)
+	published := inspector.Host
+	if published == 0 {
+		for i, port := range target.Spec.Ports {
+			if port.Container == inspector.Port && port.Published > 0 {
+				published = port.Published
+				target.Spec.Ports = append(target.Spec.Ports[:i:i], target.Spec.Ports[i+1:]...)
+				break
+			}
+		}
+	}
+
+	resource := &DesiredResource{
+		Key:         key,
+		Enabled:     target.Enabled,
+		LogicalHost: key,
+		RuntimeName: ResourceRuntimeName(desired.Name, key, desired.NamingStrategy),
+		DependsOn:   []string{target.Key},
+		Spec: ResourceSpec{
+			Image: InspectorImage,
+			Command: []string{
+				"mitmdump",
+				"--mode", fmt.Sprintf("reverse:http://%s:%d", target.RuntimeName, inspector.Port),
+				"--listen-port", strconv.Itoa(inspectorListenPort),
+				"--set", "keep_host_header=true",
+				"--flow-detail", "1",
+			},
+			Labels: ResourceLabels(desired.Name, key, key, networkName(desired)),
+		},
+	}
+	if published > 0 {
+		resource.Spec.Ports = []PortSpec{{Container: inspectorListenPort, Published: published}}
+	}
+	if desired.Network == nil {
+		resource.Diagnostics = append(resource.Diagnostics, UnsupportedFieldDiagnostic(desired.Name, target.Key, "inspector-network", fmt.Sprintf("resource %q inspector requires runtime.isolatedNetwork so the proxy reaches it by name", target.Key)))
+	}
+	return resource
+}
@@ -0,0 +1,11 @@
+package runtime
+
+import "context"
+
+// ResourceLifecycle is implemented by adapters that can stop a resource
+// without removing it and start it again later. Timeout is the graceful stop
+// window in seconds; nil keeps the container's own stop timeout.
+type ResourceLifecycle interface {
+	StopResource(ctx context.Context, resource ResourceRef, timeout *int) error
+	StartResource(ctx context.Context, resource ResourceRef) error
+}
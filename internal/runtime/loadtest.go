@@ -0,0 +1,58 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+// LoadTestImage runs load test scripts.
+const LoadTestImage = "docker.io/grafana/k6:latest"
+
+// LoadTestThresholdExitCode is k6's exit code when the run finished but a
+// threshold in the script failed.
+const LoadTestThresholdExitCode = 99
+
+// LoadTester is implemented by adapters that can run a k6 script in a
+// throwaway container on the workspace network. A non-zero exit from k6 is
+// reported in the result rather than as an error.
+type LoadTester interface {
+	RunLoadTest(ctx context.Context, request LoadTestRequest) (*ExecResult, error)
+}
+
+// LoadTestRequest describes one k6 run. Directory is a private host directory
+// that holds Script and Summary, k6's --summary-export JSON. The two files are
+// mounted on their own, so the directory can stay closed to other users and
+// only Summary has to be writable by k6's user.
+type LoadTestRequest struct {
+	Network   string
+	Directory string
+	Script    string
+	Summary   string
+	Env       map[string]string
+}
+
+// LoadTestArgs builds the `run` arguments shared by adapters.
+func LoadTestArgs(request LoadTestRequest) ([]string, error) {
+	if request.Network == "" {
+		return nil, fmt.Errorf("load test: network is required")
+	}
+	if request.Directory == "" || request.Script == "" || request.Summary == "" {
+		return nil, fmt.Errorf("load test: directory, script, and summary are required")
+	}
+	args := []string{
+		"run", "--rm", "--network", request.Network,
+		"--volume", filepath.Join(request.Directory, request.Script) + ":/loadtest/" + request.Script + ":ro,Z",
+		"--volume", filepath.Join(request.Directory, request.Summary) + ":/loadtest/" + request.Summary + ":Z",
+	}
+	keys := make([]string, 0, len(request.Env))
+	for key := range request.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		args = append(args, "--env", key+"="+request.Env[key])
+	}
+	return append(args, LoadTestImage, "run", "--no-color", "--summary-export", "/loadtest/"+request.Summary, "/loadtest/"+request.Script), nil
+}
@@ -66,7 +66,7 @@ func (f *fakeAdapter) RemoveResource(context.Context, runtimepkg.ResourceRef) er
 	return errors.New("not implemented")
 }
 
-func (f *fakeAdapter) RestartResource(context.Context, runtimepkg.ResourceRef) error {
+func (f *fakeAdapter) RestartResource(context.Context, runtimepkg.ResourceRef, runtimepkg.RestartRequest) error {
 	return errors.New("not implemented")
 }
 
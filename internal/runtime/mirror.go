@@ -0,0 +1,44 @@
+package runtime
+
+import "strings"
+
+const defaultRegistry = "docker.io"
+
+// MirroredImage rewrites image to pull through the mirror configured for its
+// registry. Short Docker Hub references such as "postgres:16" are expanded to
+// their library path first so a docker.io mirror receives "library/postgres".
+// Images without a matching mirror are returned unchanged.
+func MirroredImage(image string, mirrors map[string]string) string {
+	if image == "" || len(mirrors) == 0 {
+		return image
+	}
+	registry, path := splitImageRegistry(image)
+	mirror, ok := mirrors[registry]
+	if !ok && registry == defaultRegistry {
+		mirror, ok = mirrors["index.docker.io"]
+	}
+	if !ok || mirror == "" {
+		return image
+	}
+	return mirror + "/" + path
+}
+
+// splitImageRegistry separates the registry host from the repository path. The
+// first path component is a registry only when it looks like a host name.
+func splitImageRegistry(image string) (string, string) {
+	first, rest, found := strings.Cut(image, "/")
+	if found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		registry := strings.ToLower(first)
+		if registry == "index.docker.io" {
+			registry = defaultRegistry
+		}
+		if registry == defaultRegistry && !strings.Contains(rest, "/") {
+			rest = "library/" + rest
+		}
+		return registry, rest
+	}
+	if !found {
+		return defaultRegistry, "library/" + image
+	}
+	return defaultRegistry, image
+}
@@ -8,9 +8,10 @@ import (
 )
 
 const (
-	ProviderAuto   = "auto"
-	ProviderDocker = "docker"
-	ProviderPodman = "podman"
+	ProviderAuto    = "auto"
+	ProviderDocker  = "docker"
+	ProviderPodman  = "podman"
+	ProviderNerdctl = "nerdctl"
 
 	SeverityWarning = "warning"
 	SeverityError   = "error"
@@ -42,6 +43,7 @@ type DesiredResource struct {
 	Enabled        bool                          `json:"enabled"`
 	LogicalHost    string                        `json:"logicalHost"`
 	RuntimeName    string                        `json:"runtimeName"`
+	ReplicaOf      string                        `json:"replicaOf,omitempty"`
 	TemplateName   string                        `json:"templateName,omitempty"`
 	Source         *SourceRef                    `json:"source,omitempty"`
 	DeclaredEnv    map[string]workspace.EnvValue `json:"declaredEnv,omitempty"`
@@ -49,6 +51,7 @@ type DesiredResource struct {
 	DependsOn      []string                      `json:"dependsOn,omitempty"`
 	Domains        []string                      `json:"domains,omitempty"`
 	OverrideLabels map[string]string             `json:"overrideLabels,omitempty"`
+	Hooks          *workspace.Hooks              `json:"hooks,omitempty"`
 	Diagnostics    []Diagnostic                  `json:"diagnostics,omitempty"`
 	Spec           ResourceSpec                  `json:"spec"`
 }
@@ -70,9 +73,17 @@ type ResourceSpec struct {
 	Ports         []PortSpec                    `json:"ports,omitempty"`
 	Volumes       []VolumeSpec                  `json:"volumes,omitempty"`
 	Health        *workspace.Health             `json:"health,omitempty"`
+	StopSignal    string                        `json:"stopSignal,omitempty"`
+	StopTimeout   *int                          `json:"stopTimeout,omitempty"`
 	ProjectSource *ProjectSource                `json:"projectSource,omitempty"`
 	DevelopWatch  []WatchRule                   `json:"developWatch,omitempty"`
 	Labels        map[string]string             `json:"labels,omitempty"`
+	// Init resources run once to completion instead of being kept running.
+	Init bool `json:"init,omitempty"`
+	// Userns is the user namespace mode the container is created with.
+	Userns string `json:"userns,omitempty"`
+	// Memory is the container memory limit; inspect reports it in bytes.
+	Memory string `json:"memory,omitempty"`
 }
 
 type BuildSpec struct {
@@ -97,11 +108,12 @@ type PortSpec struct {
 }
 
 type VolumeSpec struct {
-	Source   string `json:"source,omitempty"`
-	Target   string `json:"target"`
-	ReadOnly bool   `json:"readOnly,omitempty"`
-	Kind     string `json:"kind,omitempty"`
-	Type     string `json:"type,omitempty"`
+	Source   string   `json:"source,omitempty"`
+	Target   string   `json:"target"`
+	ReadOnly bool     `json:"readOnly,omitempty"`
+	Kind     string   `json:"kind,omitempty"`
+	Type     string   `json:"type,omitempty"`
+	Options  []string `json:"options,omitempty"`
 }
 
 type WatchRule struct {
@@ -147,6 +159,7 @@ type SnapshotResource struct {
 	RuntimeName string        `json:"runtimeName"`
 	LogicalHost string        `json:"logicalHost,omitempty"`
 	ID          string        `json:"id,omitempty"`
+	ImageID     string        `json:"imageId,omitempty"`
 	State       ResourceState `json:"state,omitempty"`
 	Spec        ResourceSpec  `json:"spec"`
 }
@@ -322,6 +335,14 @@ func cloneHealth(health *workspace.Health) *workspace.Health {
 	return &cloned
 }
 
+func cloneIntPtr(value *int) *int {
+	if value == nil {
+		return nil
+	}
+	cloned := *value
+	return &cloned
+}
+
 func clonePorts(values []PortSpec) []PortSpec {
 	if len(values) == 0 {
 		return nil
@@ -333,7 +354,11 @@ func cloneVolumes(values []VolumeSpec) []VolumeSpec {
 	if len(values) == 0 {
 		return nil
 	}
-	return append([]VolumeSpec(nil), values...)
+	cloned := append([]VolumeSpec(nil), values...)
+	for i := range cloned {
+		cloned[i].Options = cloneStringSlice(cloned[i].Options)
+	}
+	return cloned
 }
 
 func cloneWatchRules(values []WatchRule) []WatchRule {
@@ -371,8 +396,13 @@ func (s ResourceSpec) Clone() ResourceSpec {
 		Ports:         clonePorts(s.Ports),
 		Volumes:       cloneVolumes(s.Volumes),
 		Health:        cloneHealth(s.Health),
+		StopSignal:    s.StopSignal,
+		StopTimeout:   cloneIntPtr(s.StopTimeout),
 		ProjectSource: cloneProjectSource(s.ProjectSource),
 		DevelopWatch:  cloneWatchRules(s.DevelopWatch),
 		Labels:        cloneStringMap(s.Labels),
+		Init:          s.Init,
+		Userns:        s.Userns,
+		Memory:        s.Memory,
 	}
 }
@@ -11,6 +11,12 @@ const (
 	LabelHostAlias = "devarch.host"
 	LabelNetwork   = "devarch.network"
 
+	// LabelAutoUpdate is podman's own auto-update policy label.
+	LabelAutoUpdate = "io.containers.autoupdate"
+	// LabelManagedUpdate marks a resource whose image DevArch itself
+	// updates in the workspace's maintenance windows.
+	LabelManagedUpdate = "devarch.auto-update"
+
 	ManagedByValue = "devarch"
 )
 
@@ -0,0 +1,217 @@
+package nerdctl
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+)
+
+// Adapter drives nerdctl, the docker-compatible CLI for containerd used by
+// Rancher Desktop and Lima. Its inspect output uses the docker format, so
+// snapshots share the docker normalization.
+type Adapter struct {
+	runner runtimepkg.CommandRunner
+}
+
+func New(runner runtimepkg.CommandRunner) *Adapter {
+	if runner == nil {
+		runner = execRunner{}
+	}
+	return &Adapter{runner: runner}
+}
+
+func (a *Adapter) Provider() string {
+	return runtimepkg.ProviderNerdctl
+}
+
+func (a *Adapter) Capabilities() runtimepkg.AdapterCapabilities {
+	return runtimepkg.AdapterCapabilities{Inspect: true, Logs: true, Exec: true}
+}
+
+func (a *Adapter) InspectWorkspace(ctx context.Context, desired *runtimepkg.DesiredWorkspace) (*runtimepkg.Snapshot, error) {
+	if desired == nil {
+		return nil, fmt.Errorf("nerdctl inspect workspace: nil desired workspace")
+	}
+	args := []string{"ps", "-aq", "--filter", fmt.Sprintf("label=%s=%s", runtimepkg.LabelWorkspace, desired.Name), "--filter", fmt.Sprintf("label=%s=%s", runtimepkg.LabelManagedBy, runtimepkg.ManagedByValue)}
+	idsOutput, err := a.runner.Run(ctx, "nerdctl", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var inspectOutput []byte
+	ids := parseLines(idsOutput)
+	if len(ids) > 0 {
+		inspectOutput, err = a.runner.Run(ctx, "nerdctl", append([]string{"inspect"}, ids...)...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var networkOutput []byte
+	if desired.Network != nil {
+		networkOutput, err = a.runner.Run(ctx, "nerdctl", "network", "inspect", desired.Network.Name)
+		if err != nil && !isNotFoundError(err) {
+			return nil, err
+		}
+		if isNotFoundError(err) {
+			networkOutput = nil
+		}
+	}
+
+	return runtimepkg.NormalizeInspectSnapshot(runtimepkg.ProviderNerdctl, desired, inspectOutput, networkOutput)
+}
+
+func (a *Adapter) EnsureNetwork(ctx context.Context, network *runtimepkg.DesiredNetwork) error {
+	return unsupported("ensure-network")
+}
+
+func (a *Adapter) RemoveNetwork(ctx context.Context, network *runtimepkg.DesiredNetwork) error {
+	return unsupported("remove-network")
+}
+
+func (a *Adapter) ApplyResource(ctx context.Context, request runtimepkg.ApplyResourceRequest) error {
+	return unsupported("apply-resource")
+}
+
+func (a *Adapter) RemoveResource(ctx context.Context, resource runtimepkg.ResourceRef) error {
+	return unsupported("remove-resource")
+}
+
+func (a *Adapter) RestartResource(ctx context.Context, resource runtimepkg.ResourceRef, request runtimepkg.RestartRequest) error {
+	return unsupported("restart-resource")
+}
+
+func (a *Adapter) StreamLogs(ctx context.Context, resource runtimepkg.ResourceRef, request runtimepkg.LogsRequest, consume runtimepkg.LogsConsumer) error {
+	if consume == nil {
+		return fmt.Errorf("nerdctl logs: nil consumer")
+	}
+	args := []string{"logs", "--timestamps"}
+	if request.Tail > 0 {
+		args = append(args, "--tail", strconv.Itoa(request.Tail))
+	}
+	if request.Since != nil {
+		args = append(args, "--since", request.Since.Format(timeLayout()))
+	}
+	if request.Follow {
+		args = append(args, "--follow")
+	}
+	args = append(args, resource.RuntimeName)
+	output, err := a.runner.Run(ctx, "nerdctl", args...)
+	if err != nil {
+		return err
+	}
+	for _, chunk := range runtimepkg.ParseLogOutput("combined", output) {
+		if err := consume(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *Adapter) ResourceStats(ctx context.Context, resources []runtimepkg.ResourceRef) ([]runtimepkg.ResourceStats, error) {
+	if len(resources) == 0 {
+		return nil, nil
+	}
+	args := []string{"stats", "--no-stream", "--format", runtimepkg.StatsFormat}
+	for _, resource := range resources {
+		args = append(args, resource.RuntimeName)
+	}
+	output, err := a.runner.Run(ctx, "nerdctl", args...)
+	if err != nil {
+		return nil, err
+	}
+	return runtimepkg.ParseStatsOutput(output, resources)
+}
+
+func (a *Adapter) TopResource(ctx context.Context, resource runtimepkg.ResourceRef) (*runtimepkg.ProcessList, error) {
+	if resource.RuntimeName == "" {
+		return nil, fmt.Errorf("nerdctl top: runtime name is required")
+	}
+	output, err := a.runner.Run(ctx, "nerdctl", "top", resource.RuntimeName)
+	if err != nil {
+		return nil, err
+	}
+	return runtimepkg.ParseTopOutput(resource, output), nil
+}
+
+func (a *Adapter) SaveImages(ctx context.Context, images []string, path string) error {
+	if len(images) == 0 {
+		return fmt.Errorf("nerdctl save-images: at least one image is required")
+	}
+	if path == "" {
+		return fmt.Errorf("nerdctl save-images: archive path is required")
+	}
+	args := append([]string{"save", "-o", path}, images...)
+	_, err := a.runner.Run(ctx, "nerdctl", args...)
+	return err
+}
+
+func (a *Adapter) LoadImages(ctx context.Context, path string) ([]string, error) {
+	if path == "" {
+		return nil, fmt.Errorf("nerdctl load-images: archive path is required")
+	}
+	output, err := a.runner.Run(ctx, "nerdctl", "load", "-i", path)
+	if err != nil {
+		return nil, err
+	}
+	return runtimepkg.ParseLoadedImages(output), nil
+}
+
+func (a *Adapter) Exec(ctx context.Context, resource runtimepkg.ResourceRef, request runtimepkg.ExecRequest) (*runtimepkg.ExecResult, error) {
+	if request.Interactive || request.TTY {
+		return nil, unsupported("exec-interactive")
+	}
+	args := append([]string{"exec", resource.RuntimeName}, request.Command...)
+	output, err := a.runner.Run(ctx, "nerdctl", args...)
+	if err != nil {
+		return nil, err
+	}
+	return &runtimepkg.ExecResult{ExitCode: 0, Stdout: string(output)}, nil
+}
+
+type execRunner struct{}
+
+func (execRunner) Run(ctx context.Context, command string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, command, args...)
+	return cmd.CombinedOutput()
+}
+
+func parseLines(output []byte) []string {
+	text := strings.TrimSpace(string(output))
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(text, "\n")
+	parsed := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		parsed = append(parsed, line)
+	}
+	if len(parsed) == 0 {
+		return nil
+	}
+	return parsed
+}
+
+func isNotFoundError(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "no such network") || strings.Contains(message, "not found") || strings.Contains(message, "no network found")
+}
+
+func unsupported(operation string) error {
+	return &runtimepkg.UnsupportedOperationError{Provider: runtimepkg.ProviderNerdctl, Operation: operation, Reason: "apply mutations are unsupported by this runtime adapter"}
+}
+
+func timeLayout() string {
+	return "2006-01-02T15:04:05Z07:00"
+}
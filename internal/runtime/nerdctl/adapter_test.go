@@ -0,0 +1,91 @@
+package nerdctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
+)
+
+func TestNerdctlAdapterInspectsDockerCompatibleOutput(t *testing.T) {
+	runner := &fakeRunner{responses: map[string]fakeResponse{
+		"nerdctl ps -aq --filter label=devarch.workspace=shop-local --filter label=devarch.managed-by=devarch": {
+			stdout: []byte("4c1f2b\n"),
+		},
+		"nerdctl inspect 4c1f2b": {
+			stdout: []byte(`[
+  {
+    "Id": "4c1f2b",
+    "Name": "devarch-shop-local-api",
+    "Config": {
+      "Image": "docker.io/library/node:22-alpine",
+      "Labels": {
+        "devarch.managed-by": "devarch",
+        "devarch.workspace": "shop-local",
+        "devarch.resource": "api",
+        "devarch.network": "devarch-shop-local-net"
+      }
+    },
+    "State": {"Status": "running", "Running": true},
+    "NetworkSettings": {"Ports": {"3000/tcp": [{"HostIp": "0.0.0.0", "HostPort": "8200"}]}}
+  }
+]`),
+		},
+		"nerdctl network inspect devarch-shop-local-net": {
+			err: fmt.Errorf("exit status 1: no network found matching: devarch-shop-local-net"),
+		},
+		"nerdctl exec devarch-shop-local-api node --version": {
+			stdout: []byte("v22.1.0\n"),
+		},
+	}}
+	adapter := New(runner)
+	if got := adapter.Provider(); got != runtimepkg.ProviderNerdctl {
+		t.Fatalf("Provider() = %q, want nerdctl", got)
+	}
+	desired := &runtimepkg.DesiredWorkspace{
+		Name:           "shop-local",
+		NamingStrategy: runtimepkg.NamingStrategyWorkspaceResource,
+		Network:        &runtimepkg.DesiredNetwork{Name: "devarch-shop-local-net"},
+		Resources:      []*runtimepkg.DesiredResource{{Key: "api", RuntimeName: "devarch-shop-local-api"}},
+	}
+	snapshot, err := adapter.InspectWorkspace(context.Background(), desired)
+	if err != nil {
+		t.Fatalf("InspectWorkspace returned error: %v", err)
+	}
+	if snapshot.Resource("api") == nil || snapshot.Resource("api").Spec.Ports[0].Published != 8200 {
+		t.Fatalf("snapshot resources = %#v, want api publishing 8200", snapshot.Resources)
+	}
+
+	result, err := adapter.Exec(context.Background(), runtimepkg.ResourceRef{Workspace: "shop-local", Key: "api", RuntimeName: "devarch-shop-local-api"}, runtimepkg.ExecRequest{Command: []string{"node", "--version"}})
+	if err != nil {
+		t.Fatalf("Exec returned error: %v", err)
+	}
+	if got, want := result.Stdout, "v22.1.0\n"; got != want {
+		t.Fatalf("Exec stdout = %q, want %q", got, want)
+	}
+	var unsupportedErr *runtimepkg.UnsupportedOperationError
+	if err := adapter.ApplyResource(context.Background(), runtimepkg.ApplyResourceRequest{}); !errors.As(err, &unsupportedErr) {
+		t.Fatalf("ApplyResource error = %v, want UnsupportedOperationError", err)
+	}
+}
+
+type fakeRunner struct {
+	responses map[string]fakeResponse
+}
+
+type fakeResponse struct {
+	stdout []byte
+	err    error
+}
+
+func (f *fakeRunner) Run(_ context.Context, command string, args ...string) ([]byte, error) {
+	key := strings.TrimSpace(command + " " + strings.Join(args, " "))
+	response, ok := f.responses[key]
+	if !ok {
+		return nil, fmt.Errorf("unexpected command %q", key)
+	}
+	return response.stdout, response.err
+}
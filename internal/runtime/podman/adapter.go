@@ -2,7 +2,10 @@ package podman
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os/exec"
+	goruntime "runtime"
 	"strconv"
 	"strings"
 
@@ -14,6 +17,10 @@ type Adapter struct {
 	runner podmanctl.Runner
 }
 
+// hostPlatform decides whether bind sources are translated for a podman
+// machine; see runtimepkg.MachineBindSource.
+var hostPlatform = goruntime.GOOS
+
 func New(runner podmanctl.Runner) *Adapter {
 	if runner == nil {
 		runner = podmanctl.ExecRunner{}
@@ -59,7 +66,107 @@ func (a *Adapter) InspectWorkspace(ctx context.Context, desired *runtimepkg.Desi
 		}
 	}
 
-	return runtimepkg.NormalizeInspectSnapshot(runtimepkg.ProviderPodman, desired, inspectOutput, networkOutput)
+	snapshot, err := runtimepkg.NormalizeInspectSnapshot(runtimepkg.ProviderPodman, desired, inspectOutput, networkOutput)
+	if err != nil {
+		return nil, err
+	}
+	for _, resource := range snapshot.Resources {
+		for i := range resource.Spec.Volumes {
+			resource.Spec.Volumes[i].Source = runtimepkg.HostBindSource(runtimepkg.ProviderPodman, hostPlatform, resource.Spec.Volumes[i].Source)
+		}
+	}
+	return snapshot, nil
+}
+
+// FindNameConflicts inspects every container and the network whose name the
+// workspace wants, whatever their labels, so apply does not run --replace
+// over a container it does not own.
+func (a *Adapter) FindNameConflicts(ctx context.Context, desired *runtimepkg.DesiredWorkspace) ([]runtimepkg.NameConflict, error) {
+	if desired == nil {
+		return nil, fmt.Errorf("podman find name conflicts: nil desired workspace")
+	}
+	namesOutput, err := a.runner.Run(ctx, "podman", "ps", "-a", "--format", "{{.Names}}")
+	if err != nil {
+		return nil, err
+	}
+	wanted := make(map[string]bool, len(desired.Resources))
+	for _, resource := range desired.Resources {
+		if resource != nil && resource.Enabled {
+			wanted[resource.RuntimeName] = true
+		}
+	}
+	var names []string
+	for _, name := range parseLines(namesOutput) {
+		if wanted[name] {
+			names = append(names, name)
+		}
+	}
+
+	var inspectOutput []byte
+	if len(names) > 0 {
+		inspectOutput, err = a.runner.Run(ctx, "podman", append([]string{"container", "inspect"}, names...)...)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var networkOutput []byte
+	if desired.Network != nil {
+		networkOutput, err = a.runner.Run(ctx, "podman", "network", "inspect", desired.Network.Name)
+		if err != nil && !isNotFoundError(err) {
+			return nil, err
+		}
+		if isNotFoundError(err) {
+			networkOutput = nil
+		}
+	}
+	return runtimepkg.NameConflicts(desired, inspectOutput, networkOutput)
+}
+
+// UnmanagedContainers lists running containers without the DevArch
+// managed-by label.
+func (a *Adapter) UnmanagedContainers(ctx context.Context) ([]runtimepkg.UnmanagedContainer, error) {
+	idsOutput, err := a.runner.Run(ctx, "podman", "ps", "-q", "--filter", "status=running")
+	if err != nil {
+		return nil, err
+	}
+	ids := parseLines(idsOutput)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	inspectOutput, err := a.runner.Run(ctx, "podman", append([]string{"container", "inspect"}, ids...)...)
+	if err != nil {
+		return nil, err
+	}
+	return runtimepkg.UnmanagedContainersFromInspect(inspectOutput, nil)
+}
+
+// InspectUnmanagedContainer describes one container for adoption, leaving
+// out env the image sets by default. When the image cannot be inspected the
+// env is reported in full.
+func (a *Adapter) InspectUnmanagedContainer(ctx context.Context, name string) (*runtimepkg.UnmanagedContainer, error) {
+	if name == "" {
+		return nil, fmt.Errorf("podman inspect container: name is required")
+	}
+	inspectOutput, err := a.runner.Run(ctx, "podman", "container", "inspect", name)
+	if err != nil {
+		return nil, fmt.Errorf("podman container inspect %q: %w", name, err)
+	}
+	containers, err := runtimepkg.UnmanagedContainersFromInspect(inspectOutput, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("container %q is already managed by devarch", name)
+	}
+	imageOutput, err := a.runner.Run(ctx, "podman", "image", "inspect", containers[0].Image)
+	if err != nil {
+		return &containers[0], nil
+	}
+	containers, err = runtimepkg.UnmanagedContainersFromInspect(inspectOutput, imageOutput)
+	if err != nil {
+		return nil, err
+	}
+	return &containers[0], nil
 }
 
 func (a *Adapter) EnsureNetwork(ctx context.Context, network *runtimepkg.DesiredNetwork) error {
@@ -91,11 +198,57 @@ func (a *Adapter) RemoveResource(ctx context.Context, resource runtimepkg.Resour
 	return podmanctl.RemoveContainer(ctx, a.runner, resource.RuntimeName)
 }
 
-func (a *Adapter) RestartResource(ctx context.Context, resource runtimepkg.ResourceRef) error {
+func (a *Adapter) RestartResource(ctx context.Context, resource runtimepkg.ResourceRef, request runtimepkg.RestartRequest) error {
 	if resource.RuntimeName == "" {
 		return fmt.Errorf("podman restart-resource: runtime name is required")
 	}
-	return podmanctl.RestartContainer(ctx, a.runner, resource.RuntimeName)
+	return podmanctl.RestartContainer(ctx, a.runner, resource.RuntimeName, request.Timeout)
+}
+
+func (a *Adapter) StopResource(ctx context.Context, resource runtimepkg.ResourceRef, timeout *int) error {
+	if resource.RuntimeName == "" {
+		return fmt.Errorf("podman stop-resource: runtime name is required")
+	}
+	return podmanctl.StopContainer(ctx, a.runner, resource.RuntimeName, timeout)
+}
+
+func (a *Adapter) StartResource(ctx context.Context, resource runtimepkg.ResourceRef) error {
+	if resource.RuntimeName == "" {
+		return fmt.Errorf("podman start-resource: runtime name is required")
+	}
+	return podmanctl.StartContainer(ctx, a.runner, resource.RuntimeName)
+}
+
+func (a *Adapter) PauseResource(ctx context.Context, resource runtimepkg.ResourceRef) error {
+	if resource.RuntimeName == "" {
+		return fmt.Errorf("podman pause-resource: runtime name is required")
+	}
+	return podmanctl.PauseContainer(ctx, a.runner, resource.RuntimeName)
+}
+
+func (a *Adapter) UnpauseResource(ctx context.Context, resource runtimepkg.ResourceRef) error {
+	if resource.RuntimeName == "" {
+		return fmt.Errorf("podman unpause-resource: runtime name is required")
+	}
+	return podmanctl.UnpauseContainer(ctx, a.runner, resource.RuntimeName)
+}
+
+func (a *Adapter) KillResource(ctx context.Context, resource runtimepkg.ResourceRef, signal string) error {
+	if resource.RuntimeName == "" {
+		return fmt.Errorf("podman kill-resource: runtime name is required")
+	}
+	return podmanctl.KillContainer(ctx, a.runner, resource.RuntimeName, signal)
+}
+
+// DegradeNetwork holds a netem qdisc on the resource's interface for the
+// fault duration, then removes it.
+func (a *Adapter) DegradeNetwork(ctx context.Context, resource runtimepkg.ResourceRef, fault runtimepkg.NetworkFault) error {
+	args, err := runtimepkg.NetworkFaultArgs(resource, fault)
+	if err != nil {
+		return err
+	}
+	_, err = a.runner.Run(ctx, "podman", args...)
+	return err
 }
 
 func (a *Adapter) StreamLogs(ctx context.Context, resource runtimepkg.ResourceRef, request runtimepkg.LogsRequest, consume runtimepkg.LogsConsumer) error {
@@ -125,6 +278,87 @@ func (a *Adapter) StreamLogs(ctx context.Context, resource runtimepkg.ResourceRe
 	return nil
 }
 
+func (a *Adapter) ResourceStats(ctx context.Context, resources []runtimepkg.ResourceRef) ([]runtimepkg.ResourceStats, error) {
+	if len(resources) == 0 {
+		return nil, nil
+	}
+	args := []string{"stats", "--no-stream", "--format", runtimepkg.StatsFormat}
+	for _, resource := range resources {
+		args = append(args, resource.RuntimeName)
+	}
+	output, err := a.runner.Run(ctx, "podman", args...)
+	if err != nil {
+		return nil, err
+	}
+	return runtimepkg.ParseStatsOutput(output, resources)
+}
+
+func (a *Adapter) TopResource(ctx context.Context, resource runtimepkg.ResourceRef) (*runtimepkg.ProcessList, error) {
+	if resource.RuntimeName == "" {
+		return nil, fmt.Errorf("podman top: runtime name is required")
+	}
+	output, err := a.runner.Run(ctx, "podman", "top", resource.RuntimeName)
+	if err != nil {
+		return nil, err
+	}
+	return runtimepkg.ParseTopOutput(resource, output), nil
+}
+
+func (a *Adapter) SaveImages(ctx context.Context, images []string, path string) error {
+	if len(images) == 0 {
+		return fmt.Errorf("podman save-images: at least one image is required")
+	}
+	if path == "" {
+		return fmt.Errorf("podman save-images: archive path is required")
+	}
+	args := append([]string{"save", "-m", "-o", path}, images...)
+	_, err := a.runner.Run(ctx, "podman", args...)
+	return err
+}
+
+// PullImage pulls image unless podman already has it.
+func (a *Adapter) PullImage(ctx context.Context, image string) (bool, error) {
+	if image == "" {
+		return false, fmt.Errorf("podman pull-image: image is required")
+	}
+	if _, err := a.runner.Run(ctx, "podman", "image", "exists", image); err == nil {
+		return false, nil
+	}
+	if _, err := a.runner.Run(ctx, "podman", "pull", "--quiet", image); err != nil {
+		return false, fmt.Errorf("podman pull %q: %w", image, err)
+	}
+	return true, nil
+}
+
+// RefreshImage pulls image even when podman already has it, so a newer
+// digest behind the same tag is fetched, and returns the resulting image
+// ID, which podman pull --quiet prints.
+func (a *Adapter) RefreshImage(ctx context.Context, image string) (string, error) {
+	if image == "" {
+		return "", fmt.Errorf("podman refresh-image: image is required")
+	}
+	output, err := a.runner.Run(ctx, "podman", "pull", "--quiet", image)
+	if err != nil {
+		return "", fmt.Errorf("podman pull %q: %w", image, err)
+	}
+	lines := parseLines(output)
+	if len(lines) == 0 {
+		return "", fmt.Errorf("podman pull %q: no image ID in output", image)
+	}
+	return lines[len(lines)-1], nil
+}
+
+func (a *Adapter) LoadImages(ctx context.Context, path string) ([]string, error) {
+	if path == "" {
+		return nil, fmt.Errorf("podman load-images: archive path is required")
+	}
+	output, err := a.runner.Run(ctx, "podman", "load", "-i", path)
+	if err != nil {
+		return nil, err
+	}
+	return runtimepkg.ParseLoadedImages(output), nil
+}
+
 func (a *Adapter) Exec(ctx context.Context, resource runtimepkg.ResourceRef, request runtimepkg.ExecRequest) (*runtimepkg.ExecResult, error) {
 	if request.Interactive || request.TTY {
 		return nil, unsupported("exec-interactive")
@@ -137,6 +371,69 @@ func (a *Adapter) Exec(ctx context.Context, resource runtimepkg.ResourceRef, req
 	return &runtimepkg.ExecResult{ExitCode: 0, Stdout: string(output)}, nil
 }
 
+// RunTask runs command in a throwaway container built from the resource spec.
+// Ports, health checks, the restart policy, and devarch labels are dropped so
+// the task neither clashes with the running resource nor shows up in
+// workspace inspection. Exit status 125 is podman's own failure and stays an
+// error.
+func (a *Adapter) RunTask(ctx context.Context, request runtimepkg.ApplyResourceRequest, command []string) (*runtimepkg.ExecResult, error) {
+	if len(command) == 0 {
+		return nil, fmt.Errorf("podman run-task: command is required")
+	}
+	spec, err := containerSpecFromRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	spec.Name = ""
+	spec.Command = append([]string(nil), command...)
+	spec.Ports = nil
+	spec.Labels = nil
+	spec.RestartPolicy = ""
+	spec.Health = nil
+	output, err := a.runner.Run(ctx, "podman", podmanctl.BuildTaskArgs(spec)...)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() != 125 {
+		return &runtimepkg.ExecResult{ExitCode: exitErr.ExitCode(), Stdout: string(output)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &runtimepkg.ExecResult{ExitCode: 0, Stdout: string(output)}, nil
+}
+
+// CaptureTraffic runs tcpdump from a helper container in the resource's
+// network namespace until the duration or packet limit is reached.
+func (a *Adapter) CaptureTraffic(ctx context.Context, resource runtimepkg.ResourceRef, request runtimepkg.CaptureRequest) error {
+	args, err := runtimepkg.CaptureArgs(resource, request)
+	if err != nil {
+		return err
+	}
+	_, err = a.runner.Run(ctx, "podman", args...)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == runtimepkg.CaptureTimeoutExitCode {
+		return nil
+	}
+	return err
+}
+
+// RunLoadTest runs k6 on the workspace network. Exit status 125 is podman's
+// own failure and stays an error.
+func (a *Adapter) RunLoadTest(ctx context.Context, request runtimepkg.LoadTestRequest) (*runtimepkg.ExecResult, error) {
+	args, err := runtimepkg.LoadTestArgs(request)
+	if err != nil {
+		return nil, err
+	}
+	output, err := a.runner.Run(ctx, "podman", args...)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() != 125 {
+		return &runtimepkg.ExecResult{ExitCode: exitErr.ExitCode(), Stdout: string(output)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &runtimepkg.ExecResult{ExitCode: 0, Stdout: string(output)}, nil
+}
+
 func containerSpecFromRequest(request runtimepkg.ApplyResourceRequest) (podmanctl.ContainerSpec, error) {
 	resource := request.Resource
 	if resource.RuntimeName == "" {
@@ -162,7 +459,14 @@ func containerSpecFromRequest(request runtimepkg.ApplyResourceRequest) (podmanct
 		Labels:        cloneStringMap(resource.Spec.Labels),
 		Network:       request.NetworkName,
 		RestartPolicy: "unless-stopped",
+		StopSignal:    resource.Spec.StopSignal,
+		StopTimeout:   resource.Spec.StopTimeout,
 		Health:        resource.Spec.Health,
+		Userns:        resource.Spec.Userns,
+		Memory:        resource.Spec.Memory,
+	}
+	if resource.Spec.Init {
+		spec.RestartPolicy = ""
 	}
 	if spec.Labels == nil {
 		spec.Labels = map[string]string{}
@@ -183,7 +487,8 @@ func containerSpecFromRequest(request runtimepkg.ApplyResourceRequest) (podmanct
 		spec.Ports = append(spec.Ports, podmanctl.PortSpec{Container: port.Container, Published: port.Published, Protocol: port.Protocol, HostIP: port.HostIP})
 	}
 	for _, volume := range resource.Spec.Volumes {
-		spec.Volumes = append(spec.Volumes, podmanctl.VolumeSpec{Source: volume.Source, Target: volume.Target, ReadOnly: volume.ReadOnly, Kind: volume.Kind, Type: volume.Type})
+		source := runtimepkg.MachineBindSource(runtimepkg.ProviderPodman, hostPlatform, volume.Source)
+		spec.Volumes = append(spec.Volumes, podmanctl.VolumeSpec{Source: source, Target: volume.Target, ReadOnly: volume.ReadOnly, Kind: volume.Kind, Type: volume.Type, Options: volume.Options})
 	}
 	return spec, nil
 }
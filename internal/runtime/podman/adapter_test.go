@@ -3,9 +3,11 @@ package podman
 import (
 	"context"
 	"fmt"
+	"os/exec"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	runtimepkg "github.com/prospect-ogujiuba/devarch/internal/runtime"
 	"github.com/prospect-ogujiuba/devarch/internal/workspace"
@@ -131,11 +133,197 @@ func TestPodmanAdapterMutationsCallPodmanctl(t *testing.T) {
 	if err := adapter.RemoveResource(context.Background(), ref); err != nil {
 		t.Fatalf("RemoveResource returned error: %v", err)
 	}
-	if err := adapter.RestartResource(context.Background(), ref); err != nil {
+	if err := adapter.RestartResource(context.Background(), ref, runtimepkg.RestartRequest{}); err != nil {
 		t.Fatalf("RestartResource returned error: %v", err)
 	}
 }
 
+func TestContainerSpecTranslatesWindowsBindSourcesForPodmanMachine(t *testing.T) {
+	defer func(platform string) { hostPlatform = platform }(hostPlatform)
+	request := runtimepkg.ApplyResourceRequest{Resource: runtimepkg.AppliedResource{
+		RuntimeName: "devarch-shop-local-web",
+		Spec: runtimepkg.ResourceSpec{Image: "nginx:alpine", Volumes: []runtimepkg.VolumeSpec{
+			{Source: "C:/Users/ada/shop/nginx.conf", Target: "/etc/nginx/nginx.conf", ReadOnly: true},
+			{Source: "web-cache", Target: "/var/cache/nginx"},
+		}},
+	}}
+	for platform, want := range map[string]string{"windows": "/mnt/c/Users/ada/shop/nginx.conf", "linux": "C:/Users/ada/shop/nginx.conf"} {
+		hostPlatform = platform
+		spec, err := containerSpecFromRequest(request)
+		if err != nil {
+			t.Fatalf("containerSpecFromRequest returned error: %v", err)
+		}
+		if spec.Volumes[0].Source != want || spec.Volumes[1].Source != "web-cache" {
+			t.Fatalf("%s volume sources = %q, %q; want %q and the named volume", platform, spec.Volumes[0].Source, spec.Volumes[1].Source, want)
+		}
+	}
+}
+
+func TestPodmanAdapterStopsAndStartsResources(t *testing.T) {
+	runner := &fakeRunner{responses: map[string]fakeResponse{
+		"podman stop --time 5 devarch-shop-local-api": {},
+		"podman start devarch-shop-local-api":         {},
+	}}
+	adapter := New(runner)
+	var lifecycle runtimepkg.ResourceLifecycle = adapter
+	ref := runtimepkg.ResourceRef{RuntimeName: "devarch-shop-local-api"}
+	timeout := 5
+	if err := lifecycle.StopResource(context.Background(), ref, &timeout); err != nil {
+		t.Fatalf("StopResource returned error: %v", err)
+	}
+	if err := lifecycle.StartResource(context.Background(), ref); err != nil {
+		t.Fatalf("StartResource returned error: %v", err)
+	}
+	if err := lifecycle.StartResource(context.Background(), runtimepkg.ResourceRef{}); err == nil || !strings.Contains(err.Error(), "runtime name is required") {
+		t.Fatalf("StartResource missing runtime name error = %v", err)
+	}
+}
+
+func TestPodmanAdapterRunTaskUsesThrowawayContainer(t *testing.T) {
+	exitErr := exec.Command("sh", "-c", "exit 3").Run()
+	runner := &fakeRunner{responses: map[string]fakeResponse{
+		"podman run --rm --env APP_ENV=local --network devarch-shop-local-net node:22 npm run migrate": {stdout: []byte("migrated\n")},
+		"podman run --rm --env APP_ENV=local --network devarch-shop-local-net node:22 npm run seed":    {stdout: []byte("seed failed\n"), err: exitErr},
+	}}
+	adapter := New(runner)
+	request := runtimepkg.ApplyResourceRequest{
+		Workspace:   "shop-local",
+		NetworkName: "devarch-shop-local-net",
+		Resource: runtimepkg.AppliedResource{
+			Key:         "api",
+			LogicalHost: "api",
+			RuntimeName: "devarch-shop-local-api",
+			Spec: runtimepkg.ResourceSpec{
+				Image:   "node:22",
+				Command: []string{"npm", "start"},
+				Env:     map[string]workspace.EnvValue{"APP_ENV": workspace.StringEnvValue("local")},
+				Ports:   []runtimepkg.PortSpec{{Published: 3000, Container: 3000}},
+				Health:  &workspace.Health{Test: []string{"curl", "-f", "http://localhost:3000"}},
+			},
+		},
+	}
+	result, err := adapter.RunTask(context.Background(), request, []string{"npm", "run", "migrate"})
+	if err != nil || result.ExitCode != 0 || result.Stdout != "migrated\n" {
+		t.Fatalf("RunTask migrate = %#v, %v", result, err)
+	}
+	result, err = adapter.RunTask(context.Background(), request, []string{"npm", "run", "seed"})
+	if err != nil || result.ExitCode != 3 {
+		t.Fatalf("RunTask seed = %#v, %v; want exit code 3", result, err)
+	}
+}
+
+func TestPodmanAdapterCapturesTrafficFromHelperContainer(t *testing.T) {
+	timedOut := exec.Command("sh", "-c", "exit 124").Run()
+	runner := &fakeRunner{responses: map[string]fakeResponse{
+		"podman run --rm --network container:devarch-shop-local-api --cap-add NET_ADMIN --cap-add NET_RAW --volume /tmp/capture:/capture:Z " + runtimepkg.CaptureImage + " timeout 20 tcpdump -i any -U -Z root -w /capture/capture.pcap -c 100 port 5432": {err: timedOut},
+	}}
+	adapter := New(runner)
+	ref := runtimepkg.ResourceRef{Workspace: "shop-local", Key: "api", RuntimeName: "devarch-shop-local-api"}
+	request := runtimepkg.CaptureRequest{Duration: 20 * time.Second, MaxPackets: 100, Filter: "port 5432", Directory: "/tmp/capture", File: "capture.pcap"}
+	if err := adapter.CaptureTraffic(context.Background(), ref, request); err != nil {
+		t.Fatalf("CaptureTraffic returned error: %v", err)
+	}
+	request.Duration = 0
+	if err := adapter.CaptureTraffic(context.Background(), ref, request); err == nil || !strings.Contains(err.Error(), "duration") {
+		t.Fatalf("CaptureTraffic zero duration error = %v", err)
+	}
+}
+
+func TestPodmanAdapterChaosActions(t *testing.T) {
+	runner := &fakeRunner{responses: map[string]fakeResponse{
+		"podman pause devarch-shop-local-api":              {},
+		"podman unpause devarch-shop-local-api":            {},
+		"podman kill --signal TERM devarch-shop-local-api": {},
+		"podman run --rm --network container:devarch-shop-local-api --cap-add NET_ADMIN " + runtimepkg.CaptureImage + " sh -c trap 'tc qdisc del dev eth0 root' EXIT INT TERM; tc qdisc replace dev eth0 root netem delay 300ms 50ms loss 2.5% && sleep 90 & wait": {},
+	}}
+	adapter := New(runner)
+	ref := runtimepkg.ResourceRef{Workspace: "shop-local", Key: "api", RuntimeName: "devarch-shop-local-api"}
+	if err := adapter.PauseResource(context.Background(), ref); err != nil {
+		t.Fatalf("PauseResource returned error: %v", err)
+	}
+	if err := adapter.UnpauseResource(context.Background(), ref); err != nil {
+		t.Fatalf("UnpauseResource returned error: %v", err)
+	}
+	if err := adapter.KillResource(context.Background(), ref, "TERM"); err != nil {
+		t.Fatalf("KillResource returned error: %v", err)
+	}
+	fault := runtimepkg.NetworkFault{Duration: 90 * time.Second, Latency: 300 * time.Millisecond, Jitter: 50 * time.Millisecond, Loss: 2.5}
+	if err := adapter.DegradeNetwork(context.Background(), ref, fault); err != nil {
+		t.Fatalf("DegradeNetwork returned error: %v", err)
+	}
+	if err := adapter.DegradeNetwork(context.Background(), ref, runtimepkg.NetworkFault{Duration: time.Minute}); err == nil || !strings.Contains(err.Error(), "latency or loss") {
+		t.Fatalf("DegradeNetwork without a fault error = %v", err)
+	}
+}
+
+func TestPodmanAdapterFindsForeignOwnersOfRuntimeNames(t *testing.T) {
+	runner := &fakeRunner{responses: map[string]fakeResponse{
+		"podman ps -a --format {{.Names}}": {
+			stdout: []byte("devarch-shop-local-api\ndevarch-shop-local-web\nunrelated\n"),
+		},
+		"podman container inspect devarch-shop-local-api devarch-shop-local-web": {
+			stdout: []byte(`[
+  {"Name": "devarch-shop-local-api", "Config": {"Labels": {"devarch.managed-by": "devarch", "devarch.workspace": "shop-local"}}},
+  {"Name": "devarch-shop-local-web", "Config": {"Labels": {"com.docker.compose.project": "shop"}}}
+]`),
+		},
+		"podman network inspect devarch-shop-local-net": {
+			stdout: []byte(`[{"Name":"devarch-shop-local-net","Labels":{"devarch.managed-by":"devarch","devarch.workspace":"other"}}]`),
+		},
+	}}
+	desired := &runtimepkg.DesiredWorkspace{
+		Name:    "shop-local",
+		Network: &runtimepkg.DesiredNetwork{Name: "devarch-shop-local-net"},
+		Resources: []*runtimepkg.DesiredResource{
+			{Key: "api", Enabled: true, RuntimeName: "devarch-shop-local-api"},
+			{Key: "redis", Enabled: true, RuntimeName: "devarch-shop-local-redis"},
+			{Key: "web", Enabled: true, RuntimeName: "devarch-shop-local-web"},
+		},
+	}
+	conflicts, err := New(runner).FindNameConflicts(context.Background(), desired)
+	if err != nil {
+		t.Fatalf("FindNameConflicts returned error: %v", err)
+	}
+	want := []runtimepkg.NameConflict{
+		{Kind: "container", Name: "devarch-shop-local-web", Resource: "web", Owner: `compose project "shop"`},
+		{Kind: "network", Name: "devarch-shop-local-net", Owner: `devarch workspace "other"`},
+	}
+	if !reflect.DeepEqual(conflicts, want) {
+		t.Fatalf("FindNameConflicts = %#v, want %#v", conflicts, want)
+	}
+}
+
+func TestPodmanAdapterInspectsUnmanagedContainerForAdoption(t *testing.T) {
+	runner := &fakeRunner{responses: map[string]fakeResponse{
+		"podman container inspect legacy_db": {
+			stdout: []byte(`[{
+  "Id": "abc",
+  "Name": "legacy_db",
+  "Config": {"Image": "postgres:15", "Env": ["PATH=/usr/bin", "POSTGRES_DB=shop"], "Cmd": ["postgres"], "Labels": {"com.docker.compose.project": "legacy"}},
+  "State": {"Status": "running", "Running": true},
+  "NetworkSettings": {"Ports": {"5432/tcp": [{"HostIp": "", "HostPort": "15432"}]}},
+  "Mounts": [{"Type": "volume", "Name": "legacy-data", "Source": "/var/lib/containers/storage/volumes/legacy-data/_data", "Destination": "/var/lib/postgresql/data", "RW": true}]
+}]`),
+		},
+		"podman image inspect postgres:15": {
+			stdout: []byte(`[{"Config": {"Env": ["PATH=/usr/bin"]}}]`),
+		},
+	}}
+	container, err := New(runner).InspectUnmanagedContainer(context.Background(), "legacy_db")
+	if err != nil {
+		t.Fatalf("InspectUnmanagedContainer returned error: %v", err)
+	}
+	if got, want := container.Env, map[string]workspace.EnvValue{"POSTGRES_DB": workspace.StringEnvValue("shop")}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Env = %#v, want %#v", got, want)
+	}
+	if container.ComposeProject != "legacy" || len(container.Volumes) != 1 || container.Volumes[0].Source != "legacy-data" {
+		t.Fatalf("container = %#v", container)
+	}
+	if len(container.Ports) != 1 || container.Ports[0].Published != 15432 {
+		t.Fatalf("Ports = %#v", container.Ports)
+	}
+}
+
 func TestPodmanAdapterMutationValidation(t *testing.T) {
 	adapter := New(&fakeRunner{responses: map[string]fakeResponse{}})
 	if err := adapter.ApplyResource(context.Background(), runtimepkg.ApplyResourceRequest{}); err == nil || !strings.Contains(err.Error(), "runtime name is required") {
@@ -150,6 +338,64 @@ func TestPodmanAdapterMutationValidation(t *testing.T) {
 	}
 }
 
+func TestPodmanAdapterResourceStatsParsesSamples(t *testing.T) {
+	runner := &fakeRunner{responses: map[string]fakeResponse{
+		"podman stats --no-stream --format " + runtimepkg.StatsFormat + " devarch-shop-local-api devarch-shop-local-postgres": {
+			stdout: []byte("devarch-shop-local-api\t1.50%\t64MiB / 2GiB\t3.12%\t1.2kB / 800B\t0B / 4.1MB\t7\ndevarch-shop-local-postgres\t0.25%\t128MB / 2GB\t6.40%\t-- / --\t-- / --\t12\n"),
+		},
+	}}
+	adapter := New(runner)
+	refs := []runtimepkg.ResourceRef{
+		{Workspace: "shop-local", Key: "api", RuntimeName: "devarch-shop-local-api"},
+		{Workspace: "shop-local", Key: "postgres", RuntimeName: "devarch-shop-local-postgres"},
+	}
+	stats, err := adapter.ResourceStats(context.Background(), refs)
+	if err != nil {
+		t.Fatalf("ResourceStats returned error: %v", err)
+	}
+	want := []runtimepkg.ResourceStats{
+		{Key: "api", RuntimeName: "devarch-shop-local-api", CPUPercent: 1.5, MemoryBytes: 64 << 20, MemoryLimitBytes: 2 << 30, MemoryPercent: 3.12, NetInputBytes: 1200, NetOutputBytes: 800, BlockOutputBytes: 4100000, PIDs: 7},
+		{Key: "postgres", RuntimeName: "devarch-shop-local-postgres", CPUPercent: 0.25, MemoryBytes: 128000000, MemoryLimitBytes: 2000000000, MemoryPercent: 6.4, PIDs: 12},
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Fatalf("ResourceStats = %#v, want %#v", stats, want)
+	}
+}
+
+func TestPodmanAdapterSavesAndLoadsImageArchives(t *testing.T) {
+	runner := &fakeRunner{responses: map[string]fakeResponse{
+		"podman save -m -o /tmp/shop.tar docker.io/library/postgres:16 docker.io/library/redis:7": {},
+		"podman load -i /tmp/shop.tar": {
+			stdout: []byte("Getting image source signatures\nLoaded image(s): docker.io/library/postgres:16,docker.io/library/redis:7\n"),
+		},
+	}}
+	adapter := New(runner)
+	if err := adapter.SaveImages(context.Background(), []string{"docker.io/library/postgres:16", "docker.io/library/redis:7"}, "/tmp/shop.tar"); err != nil {
+		t.Fatalf("SaveImages returned error: %v", err)
+	}
+	images, err := adapter.LoadImages(context.Background(), "/tmp/shop.tar")
+	if err != nil {
+		t.Fatalf("LoadImages returned error: %v", err)
+	}
+	want := []string{"docker.io/library/postgres:16", "docker.io/library/redis:7"}
+	if !reflect.DeepEqual(images, want) {
+		t.Fatalf("LoadImages = %#v, want %#v", images, want)
+	}
+}
+
+func TestPodmanAdapterRefreshImageReturnsPulledID(t *testing.T) {
+	runner := &fakeRunner{responses: map[string]fakeResponse{
+		"podman pull --quiet docker.io/library/postgres:16": {stdout: []byte("3f0a7c\n")},
+	}}
+	id, err := New(runner).RefreshImage(context.Background(), "docker.io/library/postgres:16")
+	if err != nil {
+		t.Fatalf("RefreshImage returned error: %v", err)
+	}
+	if id != "3f0a7c" {
+		t.Fatalf("RefreshImage = %q, want 3f0a7c", id)
+	}
+}
+
 type fakeRunner struct {
 	responses map[string]fakeResponse
 }
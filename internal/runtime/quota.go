@@ -0,0 +1,64 @@
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/prospect-ogujiuba/devarch/internal/workspace"
+)
+
+// quotaDiagnostics checks the expanded desired state against the workspace
+// policy limits. Exceeding a limit is a blocking diagnostic, so plan still
+// reports the overrun while apply refuses to act on it.
+func quotaDiagnostics(desired *DesiredWorkspace, policies workspace.Policies) []Diagnostic {
+	if policies.MaxResources <= 0 && policies.MaxPublishedPorts <= 0 && policies.HostPorts == nil && policies.MaxTotalMemory == "" {
+		return nil
+	}
+	// Load has already validated the sizes, so a parse error means no cap.
+	memoryLimit, _ := workspace.ParseMemory(policies.MaxTotalMemory)
+	var diagnostics []Diagnostic
+	resources, ports := 0, 0
+	var memory int64
+	for _, resource := range desired.Resources {
+		if resource == nil || !resource.Enabled {
+			continue
+		}
+		resources++
+		// Every declared port is published; ones without a host port get a
+		// random one, which still occupies the host.
+		ports += len(resource.Spec.Ports)
+		if memoryLimit > 0 {
+			if resource.Spec.Memory == "" {
+				diagnostics = append(diagnostics, quotaDiagnostic(desired.Name, fmt.Sprintf("resource %q sets no memory limit, which policies.maxTotalMemory requires", resource.Key)))
+			} else if size, err := workspace.ParseMemory(resource.Spec.Memory); err == nil {
+				memory += size
+			}
+		}
+		if policies.HostPorts == nil {
+			continue
+		}
+		for _, port := range resource.Spec.Ports {
+			if port.Published != 0 && !policies.HostPorts.Contains(port.Published) {
+				diagnostics = append(diagnostics, quotaDiagnostic(desired.Name, fmt.Sprintf("resource %q publishes host port %d, outside policies.hostPorts %d-%d", resource.Key, port.Published, policies.HostPorts.From, policies.HostPorts.To)))
+			}
+		}
+	}
+	if policies.MaxResources > 0 && resources > policies.MaxResources {
+		diagnostics = append(diagnostics, quotaDiagnostic(desired.Name, fmt.Sprintf("workspace %q enables %d resources, exceeding policies.maxResources %d", desired.Name, resources, policies.MaxResources)))
+	}
+	if policies.MaxPublishedPorts > 0 && ports > policies.MaxPublishedPorts {
+		diagnostics = append(diagnostics, quotaDiagnostic(desired.Name, fmt.Sprintf("workspace %q publishes %d host ports, exceeding policies.maxPublishedPorts %d", desired.Name, ports, policies.MaxPublishedPorts)))
+	}
+	if memoryLimit > 0 && memory > memoryLimit {
+		diagnostics = append(diagnostics, quotaDiagnostic(desired.Name, fmt.Sprintf("workspace %q limits resources to %d bytes of memory in total, exceeding policies.maxTotalMemory %s", desired.Name, memory, policies.MaxTotalMemory)))
+	}
+	return diagnostics
+}
+
+func quotaDiagnostic(workspaceName, message string) Diagnostic {
+	return Diagnostic{
+		Severity:  SeverityError,
+		Code:      "quota-exceeded",
+		Workspace: workspaceName,
+		Message:   message,
+	}
+}
@@ -0,0 +1,162 @@
+package runtime
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prospect-ogujiuba/devarch/internal/workspace"
+)
+
+// LoadBalancerImage serves the generated load balancer resource.
+const LoadBalancerImage = "docker.io/library/nginx:alpine"
+
+// ReplicaKey names the n-th (1-based) replica of a scaled resource.
+func ReplicaKey(resourceKey string, index int) string {
+	return fmt.Sprintf("%s-%d", resourceKey, index)
+}
+
+// expandReplicas turns one desired resource into count identical copies keyed
+// <key>-1..<key>-N. Copies share the logical host and spec; published host
+// ports are offset by the replica index so each copy binds its own port.
+// Scaling down needs no bookkeeping: replicas that drop out of the desired
+// state are labeled resources the plan removes like any other.
+func expandReplicas(desired *DesiredWorkspace, item *DesiredResource, count int) []*DesiredResource {
+	replicas := make([]*DesiredResource, 0, count)
+	for index := 1; index <= count; index++ {
+		key := ReplicaKey(item.Key, index)
+		replica := &DesiredResource{
+			Key:            key,
+			Enabled:        item.Enabled,
+			LogicalHost:    item.LogicalHost,
+			RuntimeName:    ResourceRuntimeName(desired.Name, key, desired.NamingStrategy),
+			ReplicaOf:      item.Key,
+			TemplateName:   item.TemplateName,
+			Source:         item.Source,
+			DeclaredEnv:    cloneEnvMap(item.DeclaredEnv),
+			InjectedEnv:    cloneEnvMap(item.InjectedEnv),
+			DependsOn:      cloneStringSlice(item.DependsOn),
+			Domains:        cloneStringSlice(item.Domains),
+			OverrideLabels: cloneStringMap(item.OverrideLabels),
+			Hooks:          item.Hooks.Clone(),
+			Diagnostics:    append([]Diagnostic(nil), item.Diagnostics...),
+			Spec:           item.Spec.Clone(),
+		}
+		for i := range replica.Spec.Ports {
+			if replica.Spec.Ports[i].Published > 0 {
+				replica.Spec.Ports[i].Published += index - 1
+			}
+		}
+		replica.Spec.Labels = mergeLabels(ResourceLabels(desired.Name, key, item.LogicalHost, networkName(desired)), replica.OverrideLabels)
+		replicas = append(replicas, replica)
+	}
+	return replicas
+}
+
+// expandReplicaDependencies points dependsOn entries that name a scaled
+// resource at each of its replicas, unless a load balancer holds that key,
+// and rejects replica keys that collide with a declared resource.
+func expandReplicaDependencies(desired *DesiredWorkspace) error {
+	replicasOf := make(map[string][]string)
+	seen := make(map[string]bool, len(desired.Resources))
+	for _, resource := range desired.Resources {
+		if seen[resource.Key] {
+			return fmt.Errorf("build desired workspace: resource key %q collides with a replica key", resource.Key)
+		}
+		seen[resource.Key] = true
+		if resource.ReplicaOf != "" {
+			replicasOf[resource.ReplicaOf] = append(replicasOf[resource.ReplicaOf], resource.Key)
+		}
+	}
+	if len(replicasOf) == 0 {
+		return nil
+	}
+	for _, resource := range desired.Resources {
+		if len(resource.DependsOn) == 0 {
+			continue
+		}
+		expanded := make([]string, 0, len(resource.DependsOn))
+		for _, dependency := range resource.DependsOn {
+			if keys, ok := replicasOf[dependency]; ok && !seen[dependency] {
+				expanded = append(expanded, keys...)
+				continue
+			}
+			expanded = append(expanded, dependency)
+		}
+		resource.DependsOn = expanded
+	}
+	return nil
+}
+
+// replicaPortDiagnostics blocks replicas whose offset host port is already
+// published by another enabled resource, such as api-2 landing on the 8081
+// a sibling resource declares. The diagnostic goes on the replica, since
+// shifting ports is what introduced the clash.
+func replicaPortDiagnostics(desired *DesiredWorkspace) {
+	type binding struct {
+		port     int
+		protocol string
+	}
+	owners := map[binding]*DesiredResource{}
+	for _, resource := range desired.Resources {
+		if resource == nil || !resource.Enabled {
+			continue
+		}
+		for _, port := range resource.Spec.Ports {
+			if port.Published == 0 {
+				continue
+			}
+			key := binding{port: port.Published, protocol: strings.ToLower(port.Protocol)}
+			if key.protocol == "" {
+				key.protocol = "tcp"
+			}
+			owner, taken := owners[key]
+			if !taken {
+				owners[key] = resource
+				continue
+			}
+			if owner.Key == resource.Key || (owner.ReplicaOf == "" && resource.ReplicaOf == "") {
+				continue
+			}
+			replica, other := resource, owner
+			if replica.ReplicaOf == "" {
+				replica, other = owner, resource
+			}
+			replica.Diagnostics = append(replica.Diagnostics, UnsupportedFieldDiagnostic(desired.Name, replica.Key, "replica-port-conflict", fmt.Sprintf("replica %q publishes host port %d, which resource %q also publishes; move the base port or the other resource's port", replica.Key, port.Published, other.Key)))
+		}
+	}
+}
+
+// loadBalancerResource generates an nginx resource under the scaled resource's
+// own key, so dependents and the logical host reach the balancer rather than a
+// single copy. The upstream list is rebuilt from the replicas every time the
+// desired state is built, so a changed replica count shows up as a modified
+// command and the balancer is recreated on the next apply. Replicas are
+// addressed by runtime name, which only resolves on the workspace network.
+func loadBalancerResource(desired *DesiredWorkspace, item *DesiredResource, replicas []*DesiredResource, balancer workspace.LoadBalancer) *DesiredResource {
+	upstreams := make([]string, 0, len(replicas))
+	dependsOn := make([]string, 0, len(replicas))
+	for _, replica := range replicas {
+		upstreams = append(upstreams, fmt.Sprintf("server %s:%d;", replica.RuntimeName, balancer.Port))
+		dependsOn = append(dependsOn, replica.Key)
+	}
+	config := fmt.Sprintf("upstream replicas { %s } server { listen 80; location / { proxy_pass http://replicas; proxy_set_header Host $host; } }", strings.Join(upstreams, " "))
+
+	resource := &DesiredResource{
+		Key:         item.Key,
+		Enabled:     item.Enabled,
+		LogicalHost: item.LogicalHost,
+		RuntimeName: ResourceRuntimeName(desired.Name, item.Key, desired.NamingStrategy),
+		DependsOn:   dependsOn,
+		Domains:     cloneStringSlice(item.Domains),
+		Spec: ResourceSpec{
+			Image:   LoadBalancerImage,
+			Command: []string{"sh", "-c", fmt.Sprintf("printf '%%s\\n' '%s' > /etc/nginx/conf.d/default.conf && exec nginx -g 'daemon off;'", config)},
+			Ports:   []PortSpec{{Container: 80, Published: balancer.Host}},
+			Labels:  ResourceLabels(desired.Name, item.Key, item.LogicalHost, networkName(desired)),
+		},
+	}
+	if desired.Network == nil {
+		resource.Diagnostics = append(resource.Diagnostics, UnsupportedFieldDiagnostic(desired.Name, item.Key, "load-balancer-network", fmt.Sprintf("resource %q loadBalancer requires runtime.isolatedNetwork so replicas resolve by name", item.Key)))
+	}
+	return resource
+}
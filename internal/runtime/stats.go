@@ -0,0 +1,139 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// StatsFormat is the Go template passed to `docker stats` and `podman stats`.
+// Both CLIs accept the same field names, so one parser covers both providers.
+const StatsFormat = "{{.Name}}\t{{.CPUPerc}}\t{{.MemUsage}}\t{{.MemPerc}}\t{{.NetIO}}\t{{.BlockIO}}\t{{.PIDs}}"
+
+// ResourceStats is one point-in-time usage sample for a managed container.
+type ResourceStats struct {
+	Key              string  `json:"key"`
+	RuntimeName      string  `json:"runtimeName"`
+	CPUPercent       float64 `json:"cpuPercent"`
+	MemoryBytes      uint64  `json:"memoryBytes"`
+	MemoryLimitBytes uint64  `json:"memoryLimitBytes,omitempty"`
+	MemoryPercent    float64 `json:"memoryPercent"`
+	NetInputBytes    uint64  `json:"netInputBytes,omitempty"`
+	NetOutputBytes   uint64  `json:"netOutputBytes,omitempty"`
+	BlockInputBytes  uint64  `json:"blockInputBytes,omitempty"`
+	BlockOutputBytes uint64  `json:"blockOutputBytes,omitempty"`
+	PIDs             int     `json:"pids,omitempty"`
+}
+
+// StatsReader is implemented by adapters that can sample container usage. It
+// is optional so adapters without a stats surface keep satisfying Adapter.
+type StatsReader interface {
+	ResourceStats(ctx context.Context, resources []ResourceRef) ([]ResourceStats, error)
+}
+
+// ParseStatsOutput parses `stats --no-stream --format StatsFormat` output and
+// assigns resource keys by runtime name.
+func ParseStatsOutput(output []byte, resources []ResourceRef) ([]ResourceStats, error) {
+	keys := make(map[string]string, len(resources))
+	for _, resource := range resources {
+		keys[resource.RuntimeName] = resource.Key
+	}
+
+	stats := make([]ResourceStats, 0, len(resources))
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("parse stats line %q: expected 7 fields, got %d", line, len(fields))
+		}
+		name := strings.TrimPrefix(strings.TrimSpace(fields[0]), "/")
+		sample := ResourceStats{Key: keys[name], RuntimeName: name}
+		var err error
+		if sample.CPUPercent, err = parsePercent(fields[1]); err != nil {
+			return nil, fmt.Errorf("parse stats cpu for %s: %w", name, err)
+		}
+		if sample.MemoryBytes, sample.MemoryLimitBytes, err = parseBytePair(fields[2]); err != nil {
+			return nil, fmt.Errorf("parse stats memory for %s: %w", name, err)
+		}
+		if sample.MemoryPercent, err = parsePercent(fields[3]); err != nil {
+			return nil, fmt.Errorf("parse stats memory percent for %s: %w", name, err)
+		}
+		if sample.NetInputBytes, sample.NetOutputBytes, err = parseBytePair(fields[4]); err != nil {
+			return nil, fmt.Errorf("parse stats network io for %s: %w", name, err)
+		}
+		if sample.BlockInputBytes, sample.BlockOutputBytes, err = parseBytePair(fields[5]); err != nil {
+			return nil, fmt.Errorf("parse stats block io for %s: %w", name, err)
+		}
+		if pids := strings.TrimSpace(fields[6]); pids != "" && pids != "--" {
+			if sample.PIDs, err = strconv.Atoi(pids); err != nil {
+				return nil, fmt.Errorf("parse stats pids for %s: %w", name, err)
+			}
+		}
+		stats = append(stats, sample)
+	}
+	return stats, nil
+}
+
+func parsePercent(value string) (float64, error) {
+	value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "%"))
+	if value == "" || value == "--" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+func parseBytePair(value string) (uint64, uint64, error) {
+	left, right, _ := strings.Cut(value, "/")
+	first, err := ParseByteSize(left)
+	if err != nil {
+		return 0, 0, err
+	}
+	second, err := ParseByteSize(right)
+	if err != nil {
+		return 0, 0, err
+	}
+	return first, second, nil
+}
+
+// ParseByteSize parses human-readable sizes printed by docker and podman, for
+// example "512B", "1.5MiB", "2GB" or "1kB".
+func ParseByteSize(value string) (uint64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "--" {
+		return 0, nil
+	}
+	index := len(value)
+	for index > 0 && !isSizeDigit(value[index-1]) {
+		index--
+	}
+	number, err := strconv.ParseFloat(strings.TrimSpace(value[:index]), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", value)
+	}
+	multiplier, ok := byteUnits[strings.ToLower(strings.TrimSpace(value[index:]))]
+	if !ok {
+		return 0, fmt.Errorf("invalid size unit in %q", value)
+	}
+	return uint64(math.Round(number * multiplier)), nil
+}
+
+func isSizeDigit(char byte) bool {
+	return (char >= '0' && char <= '9') || char == '.'
+}
+
+var byteUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+}
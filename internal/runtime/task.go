@@ -0,0 +1,11 @@
+package runtime
+
+import "context"
+
+// TaskRunner is implemented by adapters that can run a resource's image and
+// environment as a one-off container with another command, removing the
+// container when it exits. A non-zero exit from the command is reported in
+// the result rather than as an error.
+type TaskRunner interface {
+	RunTask(ctx context.Context, request ApplyResourceRequest, command []string) (*ExecResult, error)
+}
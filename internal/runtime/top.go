@@ -0,0 +1,47 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+)
+
+// ProcessList is the parsed `top` table for one running container.
+type ProcessList struct {
+	Key         string     `json:"key"`
+	RuntimeName string     `json:"runtimeName"`
+	Titles      []string   `json:"titles"`
+	Processes   [][]string `json:"processes"`
+}
+
+// ProcessLister is implemented by adapters that can list container processes.
+type ProcessLister interface {
+	TopResource(ctx context.Context, resource ResourceRef) (*ProcessList, error)
+}
+
+// ParseTopOutput parses the whitespace-aligned table printed by `docker top`
+// and `podman top`. The last column keeps embedded spaces so full command
+// lines survive.
+func ParseTopOutput(resource ResourceRef, output []byte) *ProcessList {
+	list := &ProcessList{Key: resource.Key, RuntimeName: resource.RuntimeName, Processes: [][]string{}}
+	lines := strings.Split(strings.TrimRight(string(output), "\n"), "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) == "" {
+		return list
+	}
+	list.Titles = strings.Fields(lines[0])
+	for _, line := range lines[1:] {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		list.Processes = append(list.Processes, splitTopRow(line, len(list.Titles)))
+	}
+	return list
+}
+
+func splitTopRow(line string, columns int) []string {
+	fields := strings.Fields(line)
+	if columns <= 0 || len(fields) <= columns {
+		return fields
+	}
+	row := append([]string(nil), fields[:columns-1]...)
+	return append(row, strings.Join(fields[columns-1:], " "))
+}
@@ -14,6 +14,8 @@ const (
 	ManifestFilename    = "devarch.workspace.yaml"
 	WorkspaceSchemaFile = "workspace.schema.json"
 	TemplateSchemaFile  = "template.schema.json"
+	TeamFilename        = "devarch.team.yaml"
+	TeamSchemaFile      = "team.schema.json"
 )
 
 // ValidationError captures one schema validation failure.
@@ -60,6 +62,11 @@ func ValidateTemplateBytes(data []byte) error {
 	return validateDocument(TemplateSchemaFile, data)
 }
 
+// ValidateTeamBytes validates a team document.
+func ValidateTeamBytes(data []byte) error {
+	return validateDocument(TeamSchemaFile, data)
+}
+
 func validateDocument(schemaName string, data []byte) error {
 	document, err := decodeDocument(data)
 	if err != nil {
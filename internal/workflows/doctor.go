@@ -1,11 +1,20 @@
 package workflows
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 )
 
+// lowMemoryThreshold is the available memory below which doctor warns that
+// starting more containers is likely to push the host into swap or OOM kills.
+const lowMemoryThreshold = 1 << 30
+
 // DoctorReport reports local development health.
 type DoctorReport struct {
 	Status WorkflowStatus `json:"status"`
@@ -16,6 +25,8 @@ type DoctorOptions struct {
 	WorkspaceRoots []string
 	CatalogRoots   []string
 	RootDir        string
+	// MeminfoPath overrides /proc/meminfo for the host memory check.
+	MeminfoPath string
 }
 
 func Doctor(ctx context.Context, runner Runner, opts DoctorOptions) (*DoctorReport, error) {
@@ -29,6 +40,9 @@ func Doctor(ctx context.Context, runner Runner, opts DoctorOptions) (*DoctorRepo
 		readableRoots("catalog-roots", "Catalog roots", opts.CatalogRoots),
 		packageDiscovery(ctx, runner, opts.RootDir),
 	}
+	if check, ok := hostMemory(opts.MeminfoPath); ok {
+		checks = append(checks, check)
+	}
 	return &DoctorReport{Status: ReportStatus(checks), Checks: checks}, nil
 }
 
@@ -66,6 +80,65 @@ func packageDiscovery(ctx context.Context, runner Runner, rootDir string) CheckR
 	return CheckResult{ID: "go.discovery", Name: "Go package discovery", Status: StatusFail, Message: "root package discovery failed", Diagnostics: []Diagnostic{{ID: "go.discovery.failed", Severity: StatusFail, Message: result.StderrSummary, Detail: result.Error}}}
 }
 
+// hostMemory reports available memory from /proc/meminfo. Hosts without it,
+// such as macOS, skip the check rather than reporting it unavailable.
+func hostMemory(path string) (CheckResult, bool) {
+	available, total, ok := readMeminfo(path)
+	if !ok {
+		return CheckResult{}, false
+	}
+	message := fmt.Sprintf("%s available of %s", formatBytes(available), formatBytes(total))
+	if available < lowMemoryThreshold {
+		return CheckResult{ID: "host.memory", Name: "Host memory", Status: StatusWarn, Message: message, Diagnostics: []Diagnostic{{ID: "host.memory.low", Severity: StatusWarn, Message: "stop unused workspaces before applying more resources", Fields: map[string]any{"availableBytes": available, "totalBytes": total}}}}, true
+	}
+	return CheckResult{ID: "host.memory", Name: "Host memory", Status: StatusPass, Message: message}, true
+}
+
+// AvailableMemory returns MemAvailable in bytes from path, or /proc/meminfo
+// when path is empty. ok is false on hosts without it.
+func AvailableMemory(path string) (available uint64, ok bool) {
+	available, _, ok = readMeminfo(path)
+	return available, ok
+}
+
+func readMeminfo(path string) (available, total uint64, ok bool) {
+	if path == "" {
+		path = "/proc/meminfo"
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, 0, false
+	}
+	return parseMeminfo(data)
+}
+
+// parseMeminfo returns MemAvailable and MemTotal in bytes.
+func parseMeminfo(data []byte) (available, total uint64, ok bool) {
+	var haveAvailable, haveTotal bool
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "MemAvailable:":
+			available, haveAvailable = value*1024, true
+		case "MemTotal:":
+			total, haveTotal = value*1024, true
+		}
+	}
+	return available, total, haveAvailable && haveTotal
+}
+
+func formatBytes(value uint64) string {
+	return fmt.Sprintf("%.1f GiB", float64(value)/(1<<30))
+}
+
 func firstNonEmpty(values ...string) string {
 	for _, value := range values {
 		if value != "" {
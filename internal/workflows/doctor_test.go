@@ -3,6 +3,8 @@ package workflows
 import (
 	"context"
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -25,8 +27,12 @@ func TestDoctorReportsFailingPodman(t *testing.T) {
 }
 
 func TestDoctorPassesWithReadableRoots(t *testing.T) {
+	meminfo := filepath.Join(t.TempDir(), "meminfo")
+	if err := os.WriteFile(meminfo, []byte("MemTotal:        8000000 kB\nMemFree:         4000000 kB\nMemAvailable:    6000000 kB\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
 	runner := &FakeRunner{Results: []CommandResult{{Status: StatusPass, StdoutSummary: "podman version 5"}, {Status: StatusPass}, {Status: StatusPass}}}
-	report, err := Doctor(context.Background(), runner, DoctorOptions{WorkspaceRoots: []string{"."}, CatalogRoots: []string{"."}})
+	report, err := Doctor(context.Background(), runner, DoctorOptions{WorkspaceRoots: []string{"."}, CatalogRoots: []string{"."}, MeminfoPath: meminfo})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -34,3 +40,22 @@ func TestDoctorPassesWithReadableRoots(t *testing.T) {
 		t.Fatalf("status = %s, want pass", report.Status)
 	}
 }
+
+func TestDoctorWarnsOnLowHostMemory(t *testing.T) {
+	meminfo := filepath.Join(t.TempDir(), "meminfo")
+	if err := os.WriteFile(meminfo, []byte("MemTotal:        8000000 kB\nMemFree:          100000 kB\nMemAvailable:     512000 kB\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runner := &FakeRunner{Results: []CommandResult{{Status: StatusPass, StdoutSummary: "podman version 5"}, {Status: StatusPass}, {Status: StatusPass}}}
+	report, err := Doctor(context.Background(), runner, DoctorOptions{WorkspaceRoots: []string{"."}, CatalogRoots: []string{"."}, MeminfoPath: meminfo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Status != StatusWarn {
+		t.Fatalf("status = %s, want warn", report.Status)
+	}
+	check := report.Checks[len(report.Checks)-1]
+	if check.ID != "host.memory" || check.Status != StatusWarn || check.Message != "0.5 GiB available of 7.6 GiB" {
+		t.Fatalf("host memory check = %#v", check)
+	}
+}
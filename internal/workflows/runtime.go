@@ -26,5 +26,11 @@ func RuntimeStatus(ctx context.Context, runner Runner) *RuntimeStatusReport {
 	} else {
 		checks = append(checks, CheckResult{ID: "runtime.docker.compat", Name: "Docker compatibility", Status: StatusUnavailable, Message: "docker not available; podman remains canonical"})
 	}
+	nerdctl := runner.Run(ctx, "nerdctl", "--version")
+	if nerdctl.Status == StatusPass {
+		checks = append(checks, CheckResult{ID: "runtime.nerdctl", Name: "nerdctl runtime", Status: StatusPass, Message: firstNonEmpty(nerdctl.StdoutSummary, "nerdctl available") + "; inspect, logs, and exec only"})
+	} else {
+		checks = append(checks, CheckResult{ID: "runtime.nerdctl", Name: "nerdctl runtime", Status: StatusUnavailable, Message: "nerdctl not available"})
+	}
 	return &RuntimeStatusReport{Status: ReportStatus(checks), Checks: checks}
 }
@@ -14,4 +14,7 @@ func TestRuntimeStatusPodmanFirst(t *testing.T) {
 	if report.Checks[1].Status != StatusUnavailable {
 		t.Fatalf("docker should be compatibility only: %#v", report.Checks[1])
 	}
+	if report.Checks[2].ID != "runtime.nerdctl" || report.Checks[2].Status != StatusUnavailable {
+		t.Fatalf("nerdctl check = %#v, want unavailable", report.Checks[2])
+	}
 }
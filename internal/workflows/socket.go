@@ -1,6 +1,11 @@
 package workflows
 
-import "context"
+import (
+	"context"
+	goruntime "runtime"
+	"strings"
+	"time"
+)
 
 // SocketStatus describes Podman socket state.
 type SocketStatusReport struct {
@@ -36,3 +41,144 @@ func socketCommand(ctx context.Context, runner Runner, action string) (*CommandR
 	result := runner.Run(ctx, "systemctl", "--user", action, "podman.socket")
 	return &result, nil
 }
+
+// socketRetryAttempts and socketRetryInterval bound how long SocketDiagnose
+// waits for the client connection after a repair.
+const socketRetryAttempts = 3
+
+var socketRetryInterval = time.Second
+
+// socketPlatform selects between the systemd socket unit and podman machine
+// checks in SocketDiagnose.
+var socketPlatform = goruntime.GOOS
+
+// SocketDiagnosisReport explains why the Podman socket is unusable and what
+// to run about it. Repairs lists the commands run when repair was requested.
+type SocketDiagnosisReport struct {
+	Status      WorkflowStatus  `json:"status"`
+	Checks      []CheckResult   `json:"checks"`
+	Suggestions []string        `json:"suggestions,omitempty"`
+	Repairs     []CommandResult `json:"repairs,omitempty"`
+}
+
+// SocketDiagnose checks how the podman client reaches the runtime and
+// whether it can connect. On Linux that is the podman.socket systemd user
+// unit; a unit systemd reports active while the connection fails is treated
+// as stale. On macOS and Windows the client talks to a podman machine VM,
+// so the default machine is checked instead. With repair, it starts (or,
+// when stale, restarts) the unit or machine and retries the connection a few
+// times before reporting; without it, the commands are only suggested.
+func SocketDiagnose(ctx context.Context, runner Runner, repair bool) *SocketDiagnosisReport {
+	if runner == nil {
+		runner = ExecRunner{}
+	}
+	report := &SocketDiagnosisReport{}
+	var fix []string
+	var stale bool
+	var connection CommandResult
+	if socketPlatform == "darwin" || socketPlatform == "windows" {
+		fix = diagnoseMachine(ctx, runner, report)
+		connection = podmanConnection(ctx, runner)
+	} else {
+		var active bool
+		fix, active = diagnoseSocketUnit(ctx, runner, report)
+		connection = podmanConnection(ctx, runner)
+		stale = active && connection.Status != StatusPass
+		if stale {
+			fix = []string{"systemctl", "--user", "restart", "podman.socket"}
+		}
+	}
+
+	if connection.Status != StatusPass && fix != nil {
+		if repair {
+			report.Repairs = append(report.Repairs, runner.Run(ctx, fix[0], fix[1:]...))
+			for attempt := 0; attempt < socketRetryAttempts && connection.Status != StatusPass; attempt++ {
+				if attempt > 0 && !sleepContext(ctx, socketRetryInterval) {
+					break
+				}
+				connection = podmanConnection(ctx, runner)
+			}
+		} else {
+			report.Suggestions = append(report.Suggestions, strings.Join(fix, " "))
+		}
+	}
+	if connection.Status != StatusPass && len(report.Suggestions) == 0 && len(report.Repairs) == 0 {
+		// Nothing to start: the default connection likely points elsewhere.
+		report.Suggestions = append(report.Suggestions, "podman system connection list")
+	}
+
+	if connection.Status == StatusPass {
+		report.Checks = append(report.Checks, CheckResult{ID: "podman.socket.connection", Name: "Client connection", Status: StatusPass, Message: firstNonEmpty(connection.StdoutSummary, "connected")})
+	} else {
+		message := "podman client cannot reach the socket"
+		if stale {
+			message = "socket is active but not answering; it is likely stale"
+		}
+		report.Checks = append(report.Checks, CheckResult{ID: "podman.socket.connection", Name: "Client connection", Status: StatusFail, Message: message, Diagnostics: []Diagnostic{{ID: "podman.socket.unreachable", Severity: StatusFail, Message: connection.StderrSummary, Detail: connection.Error}}})
+	}
+	report.Status = ReportStatus(report.Checks)
+	return report
+}
+
+// diagnoseSocketUnit adds the systemd user unit checks to report and returns
+// the command that would bring the socket up, or nil when systemctl --user
+// is unusable, along with whether the unit is active.
+func diagnoseSocketUnit(ctx context.Context, runner Runner, report *SocketDiagnosisReport) ([]string, bool) {
+	enabled := runner.Run(ctx, "systemctl", "--user", "is-enabled", "podman.socket")
+	active := runner.Run(ctx, "systemctl", "--user", "is-active", "podman.socket")
+
+	userBus := enabled.Status == StatusPass || enabled.StdoutSummary != ""
+	if !userBus {
+		report.Checks = append(report.Checks, CheckResult{ID: "podman.socket.unit", Name: "Socket unit", Status: StatusWarn, Message: "systemd user manager unavailable", Diagnostics: []Diagnostic{{ID: "podman.socket.user-bus", Severity: StatusWarn, Message: firstNonEmpty(enabled.StderrSummary, "systemctl --user failed"), Detail: enabled.Error}}})
+		report.Suggestions = append(report.Suggestions, "loginctl enable-linger $USER", "podman system service --time=0 &")
+	} else if enabled.Status == StatusPass {
+		report.Checks = append(report.Checks, CheckResult{ID: "podman.socket.unit", Name: "Socket unit", Status: StatusPass, Message: "podman.socket " + firstNonEmpty(enabled.StdoutSummary, "enabled")})
+	} else {
+		report.Checks = append(report.Checks, CheckResult{ID: "podman.socket.unit", Name: "Socket unit", Status: StatusWarn, Message: "podman.socket " + enabled.StdoutSummary + "; it will not start at login"})
+		report.Suggestions = append(report.Suggestions, "systemctl --user enable podman.socket")
+	}
+
+	if active.Status == StatusPass {
+		report.Checks = append(report.Checks, CheckResult{ID: "podman.socket.active", Name: "Socket active", Status: StatusPass, Message: "podman.socket active"})
+	} else {
+		report.Checks = append(report.Checks, CheckResult{ID: "podman.socket.active", Name: "Socket active", Status: StatusWarn, Message: "podman.socket " + firstNonEmpty(active.StdoutSummary, "not active")})
+	}
+	if !userBus {
+		return nil, false
+	}
+	return []string{"systemctl", "--user", "start", "podman.socket"}, active.Status == StatusPass
+}
+
+// diagnoseMachine adds the podman machine check to report and returns the
+// command that would bring the machine up, or nil when there is no machine
+// to start.
+func diagnoseMachine(ctx context.Context, runner Runner, report *SocketDiagnosisReport) []string {
+	machine := runner.Run(ctx, "podman", "machine", "inspect", "--format", "{{.State}}")
+	switch {
+	case machine.Status != StatusPass:
+		report.Checks = append(report.Checks, CheckResult{ID: "podman.machine", Name: "Podman machine", Status: StatusFail, Message: "no podman machine", Diagnostics: []Diagnostic{{ID: "podman.machine.missing", Severity: StatusFail, Message: machine.StderrSummary, Detail: machine.Error}}})
+		report.Suggestions = append(report.Suggestions, "podman machine init", "podman machine start")
+		return nil
+	case machine.StdoutSummary == "running":
+		report.Checks = append(report.Checks, CheckResult{ID: "podman.machine", Name: "Podman machine", Status: StatusPass, Message: "podman machine running"})
+		return nil
+	default:
+		report.Checks = append(report.Checks, CheckResult{ID: "podman.machine", Name: "Podman machine", Status: StatusWarn, Message: "podman machine " + firstNonEmpty(machine.StdoutSummary, "not running")})
+		return []string{"podman", "machine", "start"}
+	}
+}
+
+func podmanConnection(ctx context.Context, runner Runner) CommandResult {
+	return runner.Run(ctx, "podman", "--remote", "info", "--format", "{{.Host.RemoteSocket.Path}}")
+}
+
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -2,6 +2,7 @@ package workflows
 
 import (
 	"context"
+	"strings"
 	"testing"
 )
 
@@ -28,3 +29,60 @@ func TestSocketStartStopUseSystemdUserBoundary(t *testing.T) {
 		t.Fatalf("stop call = %#v", runner.Calls[1])
 	}
 }
+
+func TestSocketDiagnoseRestartsStaleSocketOnRepair(t *testing.T) {
+	socketPlatform, socketRetryInterval = "linux", 0
+	runner := &FakeRunner{Results: []CommandResult{
+		{Status: StatusPass, StdoutSummary: "enabled"},
+		{Status: StatusPass, StdoutSummary: "active"},
+		{Status: StatusFail, ExitCode: 125, StderrSummary: "connection refused"},
+		{Status: StatusPass},
+		{Status: StatusFail, ExitCode: 125, StderrSummary: "connection refused"},
+		{Status: StatusPass, StdoutSummary: "/run/user/1000/podman/podman.sock"},
+	}}
+	report := SocketDiagnose(context.Background(), runner, true)
+	if report.Status != StatusPass {
+		t.Fatalf("status = %s, want pass after repair: %#v", report.Status, report.Checks)
+	}
+	if len(report.Repairs) != 1 || report.Repairs[0].Args[1] != "restart" {
+		t.Fatalf("repairs = %#v, want one restart", report.Repairs)
+	}
+	if len(runner.Calls) != 6 {
+		t.Fatalf("calls = %d, want connection retried twice", len(runner.Calls))
+	}
+}
+
+func TestSocketDiagnoseSuggestsStartWithoutRepair(t *testing.T) {
+	socketPlatform = "linux"
+	runner := &FakeRunner{Results: []CommandResult{
+		{Status: StatusFail, ExitCode: 1, StdoutSummary: "disabled"},
+		{Status: StatusFail, ExitCode: 3, StdoutSummary: "inactive"},
+		{Status: StatusFail, ExitCode: 125, StderrSummary: "no such file or directory"},
+	}}
+	report := SocketDiagnose(context.Background(), runner, false)
+	if report.Status != StatusFail || len(report.Repairs) != 0 {
+		t.Fatalf("report = %#v, want fail without repairs", report)
+	}
+	want := []string{"systemctl --user enable podman.socket", "systemctl --user start podman.socket"}
+	if strings.Join(report.Suggestions, "|") != strings.Join(want, "|") {
+		t.Fatalf("suggestions = %v, want %v", report.Suggestions, want)
+	}
+}
+
+func TestSocketDiagnoseStartsPodmanMachineOffLinux(t *testing.T) {
+	socketPlatform, socketRetryInterval = "darwin", 0
+	defer func() { socketPlatform = "linux" }()
+	runner := &FakeRunner{Results: []CommandResult{
+		{Status: StatusPass, StdoutSummary: "stopped"},
+		{Status: StatusFail, ExitCode: 125, StderrSummary: "cannot connect to Podman"},
+		{Status: StatusPass},
+		{Status: StatusPass, StdoutSummary: "/var/folders/podman/podman-machine-default-api.sock"},
+	}}
+	report := SocketDiagnose(context.Background(), runner, true)
+	if runner.Calls[0].Args[1] != "inspect" || len(report.Repairs) != 1 || strings.Join(report.Repairs[0].Args, " ") != "machine start" {
+		t.Fatalf("calls = %#v, repairs = %#v; want podman machine start", runner.Calls, report.Repairs)
+	}
+	if report.Checks[0].Status != StatusWarn || report.Checks[1].Status != StatusPass {
+		t.Fatalf("checks = %#v, want stopped machine and working connection", report.Checks)
+	}
+}
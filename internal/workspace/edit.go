@@ -0,0 +1,223 @@
+package workspace
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SetExpires rewrites metadata.expires in a manifest file in place. Only the
+// one line is replaced, or inserted under metadata.name, so comments and
+// formatting elsewhere in the file are left alone.
+func SetExpires(manifestPath string, expires time.Time) error {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return fmt.Errorf("read workspace manifest %s: %w", manifestPath, err)
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return fmt.Errorf("decode workspace manifest %s: %w", manifestPath, err)
+	}
+	metadata := mappingValue(&document, "metadata")
+	if metadata == nil || metadata.Kind != yaml.MappingNode || metadata.Style&yaml.FlowStyle != 0 {
+		return fmt.Errorf("workspace manifest %s: metadata must be a block mapping", manifestPath)
+	}
+
+	lines := strings.Split(string(data), "\n")
+	value := fmt.Sprintf("%q", expires.Format(time.DateOnly))
+	if key, current := mappingEntry(metadata, "expires"); current != nil {
+		index := current.Line - 1
+		if current.Line != key.Line || index >= len(lines) {
+			return fmt.Errorf("workspace manifest %s: metadata.expires must be a single-line value", manifestPath)
+		}
+		rest := lines[index][current.Column-1:]
+		lines[index] = lines[index][:current.Column-1] + value + rest[scalarEnd(rest, current.Style):]
+	} else {
+		name, _ := mappingEntry(metadata, "name")
+		if name == nil {
+			return fmt.Errorf("workspace manifest %s: metadata.name is missing", manifestPath)
+		}
+		line := strings.Repeat(" ", name.Column-1) + "expires: " + value
+		lines = append(lines[:name.Line], append([]string{line}, lines[name.Line:]...)...)
+	}
+
+	info, err := os.Stat(manifestPath)
+	if err != nil {
+		return fmt.Errorf("stat workspace manifest %s: %w", manifestPath, err)
+	}
+	if err := os.WriteFile(manifestPath, []byte(strings.Join(lines, "\n")), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("write workspace manifest %s: %w", manifestPath, err)
+	}
+	return nil
+}
+
+// AddResource appends a resource entry to the resources mapping of a manifest
+// file. The entry goes after the last existing resource, at its indentation,
+// and the rest of the file is left as written.
+func AddResource(manifestPath, key string, resource Resource) error {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return fmt.Errorf("read workspace manifest %s: %w", manifestPath, err)
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return fmt.Errorf("decode workspace manifest %s: %w", manifestPath, err)
+	}
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		return fmt.Errorf("workspace manifest %s: empty document", manifestPath)
+	}
+	root := document.Content[0]
+	resourcesKey, resources := mappingEntry(root, "resources")
+	if resources == nil || resources.Kind != yaml.MappingNode || resources.Style&yaml.FlowStyle != 0 || len(resources.Content) == 0 {
+		return fmt.Errorf("workspace manifest %s: resources must be a non-empty block mapping", manifestPath)
+	}
+	if existing, _ := mappingEntry(resources, key); existing != nil {
+		return fmt.Errorf("workspace manifest %s: resource %q already exists", manifestPath, key)
+	}
+
+	indent := strings.Repeat(" ", resources.Content[0].Column-1)
+	return insertBlock(manifestPath, strings.Split(string(data), "\n"), resourcesKey.Line, indent, map[string]Resource{key: resource}, root)
+}
+
+// SetResourceHealth adds a health block to one resource of a manifest file.
+// The block goes after the resource's last field, at its indentation, and the
+// rest of the file is left as written. A resource that already sets health
+// is refused, so a hand-written check is never replaced.
+func SetResourceHealth(manifestPath, key string, health Health) error {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return fmt.Errorf("read workspace manifest %s: %w", manifestPath, err)
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return fmt.Errorf("decode workspace manifest %s: %w", manifestPath, err)
+	}
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		return fmt.Errorf("workspace manifest %s: empty document", manifestPath)
+	}
+	root := document.Content[0]
+	_, resources := mappingEntry(root, "resources")
+	if resources == nil || resources.Kind != yaml.MappingNode || resources.Style&yaml.FlowStyle != 0 {
+		return fmt.Errorf("workspace manifest %s: resources must be a block mapping", manifestPath)
+	}
+	resourceKey, resource := mappingEntry(resources, key)
+	if resource == nil {
+		return fmt.Errorf("workspace manifest %s: resource %q not found", manifestPath, key)
+	}
+	if resource.Kind != yaml.MappingNode || resource.Style&yaml.FlowStyle != 0 || len(resource.Content) == 0 {
+		return fmt.Errorf("workspace manifest %s: resource %q must be a non-empty block mapping", manifestPath, key)
+	}
+	if existing, _ := mappingEntry(resource, "health"); existing != nil {
+		return fmt.Errorf("workspace manifest %s: resource %q already sets health", manifestPath, key)
+	}
+
+	indent := strings.Repeat(" ", resource.Content[0].Column-1)
+	return insertBlock(manifestPath, strings.Split(string(data), "\n"), resourceKey.Line, indent, map[string]Health{"health": health}, resources, root)
+}
+
+// insertBlock encodes value as YAML at indent and writes it into the manifest
+// lines ahead of the first key of mappings that starts below line after, or at
+// the end of the file. Blank and comment lines just above that point stay with
+// what follows, so the block lands right after the last field it extends.
+func insertBlock(manifestPath string, lines []string, after int, indent string, value any, mappings ...*yaml.Node) error {
+	var encoded bytes.Buffer
+	encoder := yaml.NewEncoder(&encoded)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("encode workspace manifest %s: %w", manifestPath, err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("encode workspace manifest %s: %w", manifestPath, err)
+	}
+	var block []string
+	for _, line := range strings.Split(strings.TrimRight(encoded.String(), "\n"), "\n") {
+		block = append(block, indent+line)
+	}
+
+	insert := len(lines)
+	if insert > 0 && lines[insert-1] == "" {
+		insert--
+	}
+	for _, mapping := range mappings {
+		for i := 0; i+1 < len(mapping.Content); i += 2 {
+			if line := mapping.Content[i].Line; line > after && line-1 < insert {
+				insert = line - 1
+			}
+		}
+	}
+	for insert > 0 {
+		trimmed := strings.TrimSpace(lines[insert-1])
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			break
+		}
+		insert--
+	}
+	lines = append(lines[:insert], append(block, lines[insert:]...)...)
+
+	info, err := os.Stat(manifestPath)
+	if err != nil {
+		return fmt.Errorf("stat workspace manifest %s: %w", manifestPath, err)
+	}
+	if err := os.WriteFile(manifestPath, []byte(strings.Join(lines, "\n")), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("write workspace manifest %s: %w", manifestPath, err)
+	}
+	return nil
+}
+
+// scalarEnd returns where a single-line scalar written at the start of text
+// ends, so whatever follows it on the line, such as a comment, can be kept.
+func scalarEnd(text string, style yaml.Style) int {
+	switch {
+	case style&yaml.DoubleQuotedStyle != 0:
+		for i := 1; i < len(text); i++ {
+			switch text[i] {
+			case '\\':
+				i++
+			case '"':
+				return i + 1
+			}
+		}
+	case style&yaml.SingleQuotedStyle != 0:
+		for i := 1; i < len(text); i++ {
+			if text[i] != '\'' {
+				continue
+			}
+			if i+1 < len(text) && text[i+1] == '\'' {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	default:
+		end := len(text)
+		if comment := strings.Index(text, " #"); comment >= 0 {
+			end = comment
+		}
+		return len(strings.TrimRight(text[:end], " \t"))
+	}
+	return len(text)
+}
+
+func mappingValue(document *yaml.Node, key string) *yaml.Node {
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		return nil
+	}
+	_, value := mappingEntry(document.Content[0], key)
+	return value
+}
+
+func mappingEntry(mapping *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if mapping.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+	return nil, nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/prospect-ogujiuba/devarch/internal/spec"
 	"gopkg.in/yaml.v3"
@@ -87,11 +88,57 @@ func resolveManifestPath(path string) (string, error) {
 }
 
 func validateSemantics(ws *Workspace) error {
+	if _, _, err := ws.Metadata.ExpiresAt(); err != nil {
+		return &SemanticError{Field: "metadata.expires", Message: "must be a YYYY-MM-DD date"}
+	}
+	if ports := ws.Policies.HostPorts; ports != nil && ports.From > ports.To {
+		return &SemanticError{Field: "policies.hostPorts", Message: "from must not be above to"}
+	}
+	if limit := ws.Policies.MaxTotalMemory; limit != "" {
+		if _, err := ParseMemory(limit); err != nil {
+			return &SemanticError{Field: "policies.maxTotalMemory", Message: err.Error()}
+		}
+	}
+	for i, window := range ws.Metadata.Maintenance {
+		var err error
+		if window.Recurring() {
+			_, _, err = window.Clock()
+		} else {
+			_, _, err = window.Times()
+		}
+		if err != nil {
+			return &SemanticError{Field: fmt.Sprintf("metadata.maintenance[%d]", i), Message: err.Error()}
+		}
+	}
+	for i, schedule := range ws.Schedules {
+		if _, err := schedule.Clock(); err != nil {
+			return &SemanticError{Field: fmt.Sprintf("schedules[%d]", i), Message: err.Error()}
+		}
+	}
 	for resourceKey, resource := range ws.Resources {
-		if resource == nil || resource.Source == nil {
+		if resource == nil {
 			continue
 		}
-		if resource.Source.Type != "raw-compose" {
+		if resource.Memory != "" {
+			if _, err := ParseMemory(resource.Memory); err != nil {
+				return &SemanticError{Field: fmt.Sprintf("resources.%s.memory", resourceKey), Message: err.Error()}
+			}
+		}
+		for i, volume := range resource.Volumes {
+			if slices.Contains(volume.Options, "z") && slices.Contains(volume.Options, "Z") {
+				return &SemanticError{
+					Field:   fmt.Sprintf("resources.%s.volumes[%d].options", resourceKey, i),
+					Message: "must not set both z and Z",
+				}
+			}
+		}
+		if resource.Inspector != nil && resource.Replicas > 1 && resource.LoadBalancer == nil {
+			return &SemanticError{
+				Field:   fmt.Sprintf("resources.%s.inspector", resourceKey),
+				Message: "requires loadBalancer when replicas is greater than 1",
+			}
+		}
+		if resource.Source == nil || resource.Source.Type != "raw-compose" {
 			continue
 		}
 		if resource.Source.Service == "" {
@@ -1,10 +1,13 @@
 package workspace
 
 import (
+	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoadAcceptsManifestFileOrDirectory(t *testing.T) {
@@ -90,6 +93,266 @@ resources:
 	}
 }
 
+func TestLoadReadsOwnershipAndRejectsInvalidExpiry(t *testing.T) {
+	manifestPath := writeWorkspaceFixture(t, filepath.Join(t.TempDir(), "devarch.workspace.yaml"), `apiVersion: devarch.io/alpha1
+kind: Workspace
+metadata:
+  name: shared
+  owner: Ada
+  team: payments
+  contact: "#payments-dev"
+  expires: "2026-05-01"
+resources:
+  api:
+    template: node-api
+`)
+	ws, err := Load(manifestPath)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	expires, ok, err := ws.Metadata.ExpiresAt()
+	if err != nil || !ok || expires.Format("2006-01-02") != "2026-05-01" {
+		t.Fatalf("ExpiresAt = %v, %t, %v; want 2026-05-01", expires, ok, err)
+	}
+	if ws.Metadata.Owner != "Ada" || ws.Metadata.Team != "payments" || ws.Metadata.Contact != "#payments-dev" {
+		t.Fatalf("metadata = %+v", ws.Metadata)
+	}
+	if !ws.Runtime.IsolatedNetwork {
+		t.Fatal("a team workspace should get an isolated network")
+	}
+
+	invalidPath := writeWorkspaceFixture(t, filepath.Join(t.TempDir(), "devarch.workspace.yaml"), `apiVersion: devarch.io/alpha1
+kind: Workspace
+metadata:
+  name: shared
+  expires: "2026-13-01"
+resources:
+  api:
+    template: node-api
+`)
+	if _, err := Load(invalidPath); err == nil || !strings.Contains(err.Error(), "metadata.expires") {
+		t.Fatalf("expected metadata.expires error, got %v", err)
+	}
+}
+
+func TestLoadValidatesMaintenanceWindows(t *testing.T) {
+	manifestPath := writeWorkspaceFixture(t, filepath.Join(t.TempDir(), "devarch.workspace.yaml"), `apiVersion: devarch.io/alpha1
+kind: Workspace
+metadata:
+  name: shared
+  maintenance:
+    - summary: Postgres upgrade
+      start: "2026-11-02T09:00:00Z"
+      end: "2026-11-02T11:00:00Z"
+    - summary: Nightly stop
+      days: [mon, tue, wed, thu, fri]
+      start: "19:00"
+      end: "08:00"
+resources:
+  api:
+    template: node-api
+`)
+	ws, err := Load(manifestPath)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if len(ws.Metadata.Maintenance) != 2 || ws.Metadata.Maintenance[0].Recurring() || !ws.Metadata.Maintenance[1].Recurring() {
+		t.Fatalf("maintenance = %+v", ws.Metadata.Maintenance)
+	}
+	start, end, err := ws.Metadata.Maintenance[1].Clock()
+	if err != nil || start != 19*time.Hour || end != 32*time.Hour {
+		t.Fatalf("Clock = %v, %v, %v; want 19h, 32h", start, end, err)
+	}
+
+	invalidPath := writeWorkspaceFixture(t, filepath.Join(t.TempDir(), "devarch.workspace.yaml"), `apiVersion: devarch.io/alpha1
+kind: Workspace
+metadata:
+  name: shared
+  maintenance:
+    - summary: Postgres upgrade
+      start: "2026-11-02T11:00:00Z"
+      end: "2026-11-02T09:00:00Z"
+resources:
+  api:
+    template: node-api
+`)
+	if _, err := Load(invalidPath); err == nil || !strings.Contains(err.Error(), "metadata.maintenance[0]") {
+		t.Fatalf("expected metadata.maintenance[0] error, got %v", err)
+	}
+}
+
+func TestLoadReadsVolumeOptions(t *testing.T) {
+	manifestPath := writeWorkspaceFixture(t, filepath.Join(t.TempDir(), "devarch.workspace.yaml"), `apiVersion: devarch.io/alpha1
+kind: Workspace
+metadata:
+  name: shared
+resources:
+  api:
+    template: node-api
+    volumes:
+      - source: ./src
+        target: /app
+        options: [Z, U]
+`)
+	ws, err := Load(manifestPath)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if got := ws.Resources["api"].Volumes[0].Options; !slices.Equal(got, []string{"Z", "U"}) {
+		t.Fatalf("options = %v, want [Z U]", got)
+	}
+
+	for _, options := range []string{"[z, Z]", "[rw]"} {
+		invalidPath := writeWorkspaceFixture(t, filepath.Join(t.TempDir(), "devarch.workspace.yaml"), `apiVersion: devarch.io/alpha1
+kind: Workspace
+metadata:
+  name: shared
+resources:
+  api:
+    template: node-api
+    volumes:
+      - source: ./src
+        target: /app
+        options: `+options+`
+`)
+		if _, err := Load(invalidPath); err == nil {
+			t.Fatalf("Load accepted options %s", options)
+		}
+	}
+}
+
+func TestSetExpiresReplacesOrInsertsOnlyTheExpiryLine(t *testing.T) {
+	manifestPath := writeWorkspaceFixture(t, filepath.Join(t.TempDir(), "devarch.workspace.yaml"), `apiVersion: devarch.io/alpha1
+kind: Workspace
+metadata:
+  name: shared # keep me
+resources:
+  api:
+    template: node-api
+`)
+	if err := SetExpires(manifestPath, time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("SetExpires returned error: %v", err)
+	}
+	if err := SetExpires(manifestPath, time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("second SetExpires returned error: %v", err)
+	}
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		t.Fatalf("os.ReadFile returned error: %v", err)
+	}
+	if got := string(data); !strings.Contains(got, "  name: shared # keep me\n  expires: \"2026-06-01\"\nresources:") {
+		t.Fatalf("manifest =\n%s", got)
+	}
+	ws, err := Load(manifestPath)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if ws.Metadata.Expires != "2026-06-01" {
+		t.Fatalf("Expires = %q, want 2026-06-01", ws.Metadata.Expires)
+	}
+}
+
+func TestSetExpiresKeepsTheExpiryLineComment(t *testing.T) {
+	for _, current := range []string{`2026-05-01`, `"2026-05-01"`, `'2026-05-01'`} {
+		manifestPath := writeWorkspaceFixture(t, filepath.Join(t.TempDir(), "devarch.workspace.yaml"), `apiVersion: devarch.io/alpha1
+kind: Workspace
+metadata:
+  name: shared
+  expires: `+current+`   # demo ends here
+resources:
+  api:
+    template: node-api
+`)
+		if err := SetExpires(manifestPath, time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+			t.Fatalf("SetExpires returned error: %v", err)
+		}
+		data, err := os.ReadFile(manifestPath)
+		if err != nil {
+			t.Fatalf("os.ReadFile returned error: %v", err)
+		}
+		if got := string(data); !strings.Contains(got, "  expires: \"2026-06-01\"   # demo ends here\nresources:") {
+			t.Fatalf("manifest from %s =\n%s", current, got)
+		}
+	}
+}
+
+func TestAddResourceAppendsAfterTheLastResource(t *testing.T) {
+	manifestPath := writeWorkspaceFixture(t, filepath.Join(t.TempDir(), "devarch.workspace.yaml"), `apiVersion: devarch.io/alpha1
+kind: Workspace
+metadata:
+  name: shared
+resources:
+  api:
+    template: node-api # keep me
+
+# policies for the shared host
+policies:
+  maxResources: 4
+`)
+	resource := Resource{Template: "postgres", Ports: []Port{{Host: 5432, Container: 5432}}}
+	if err := AddResource(manifestPath, "legacy-db", resource); err != nil {
+		t.Fatalf("AddResource returned error: %v", err)
+	}
+	if err := AddResource(manifestPath, "api", resource); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("duplicate AddResource error = %v", err)
+	}
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		t.Fatalf("os.ReadFile returned error: %v", err)
+	}
+	want := "    template: node-api # keep me\n  legacy-db:\n    template: postgres\n    ports:\n      - host: 5432\n        container: 5432\n\n# policies"
+	if got := string(data); !strings.Contains(got, want) {
+		t.Fatalf("manifest =\n%s", got)
+	}
+	ws, err := Load(manifestPath)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if ws.Resources["legacy-db"].Template != "postgres" {
+		t.Fatalf("legacy-db = %#v", ws.Resources["legacy-db"])
+	}
+}
+
+func TestSetResourceHealthAppendsToTheResource(t *testing.T) {
+	manifestPath := writeWorkspaceFixture(t, filepath.Join(t.TempDir(), "devarch.workspace.yaml"), `apiVersion: devarch.io/alpha1
+kind: Workspace
+metadata:
+  name: shared
+resources:
+  db:
+    template: postgres
+    env:
+      POSTGRES_DB: shop
+  # the API
+  api:
+    template: node-api
+    health:
+      tcp: 3000
+`)
+	health := Health{Test: StringList{"CMD-SHELL", "pg_isready -h localhost"}, Interval: "10s", Retries: 5}
+	if err := SetResourceHealth(manifestPath, "db", health); err != nil {
+		t.Fatalf("SetResourceHealth returned error: %v", err)
+	}
+	if err := SetResourceHealth(manifestPath, "api", health); err == nil || !strings.Contains(err.Error(), "already sets health") {
+		t.Fatalf("SetResourceHealth(api) error = %v", err)
+	}
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		t.Fatalf("os.ReadFile returned error: %v", err)
+	}
+	want := "      POSTGRES_DB: shop\n    health:\n      test:\n        - CMD-SHELL\n        - pg_isready -h localhost\n      interval: 10s\n      retries: 5\n  # the API\n"
+	if got := string(data); !strings.Contains(got, want) {
+		t.Fatalf("manifest =\n%s", got)
+	}
+	ws, err := Load(manifestPath)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if got := ws.Resources["db"].Health; got == nil || got.Interval != "10s" {
+		t.Fatalf("db health = %#v", got)
+	}
+}
+
 func repoRoot(t *testing.T) string {
 	t.Helper()
 
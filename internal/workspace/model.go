@@ -3,9 +3,11 @@ package workspace
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -20,6 +22,8 @@ type Workspace struct {
 	Runtime    RuntimePreferences   `yaml:"runtime,omitempty" json:"runtime,omitempty"`
 	Catalog    Catalog              `yaml:"catalog,omitempty" json:"catalog,omitempty"`
 	Policies   Policies             `yaml:"policies,omitempty" json:"policies,omitempty"`
+	Schedules  []Schedule           `yaml:"schedules,omitempty" json:"schedules,omitempty"`
+	Variables  map[string]string    `yaml:"variables,omitempty" json:"variables,omitempty"`
 	Secrets    map[string]any       `yaml:"secrets,omitempty" json:"secrets,omitempty"`
 	Profiles   map[string]any       `yaml:"profiles,omitempty" json:"profiles,omitempty"`
 	Resources  map[string]*Resource `yaml:"resources" json:"resources"`
@@ -32,12 +36,138 @@ type Metadata struct {
 	Name        string `yaml:"name" json:"name"`
 	DisplayName string `yaml:"displayName,omitempty" json:"displayName,omitempty"`
 	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	// Notes is free-form markdown, such as a runbook, kept with the manifest.
+	Notes string `yaml:"notes,omitempty" json:"notes,omitempty"`
+	// Owner, Team, and Contact say who to ask before touching a workspace
+	// on a shared host. Expires is an optional YYYY-MM-DD date.
+	Owner   string `yaml:"owner,omitempty" json:"owner,omitempty"`
+	Team    string `yaml:"team,omitempty" json:"team,omitempty"`
+	Contact string `yaml:"contact,omitempty" json:"contact,omitempty"`
+	Expires string `yaml:"expires,omitempty" json:"expires,omitempty"`
+	// Maintenance announces planned disruptions, such as nightly stops run
+	// from cron. DevArch publishes them but does not act on them.
+	Maintenance []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+}
+
+// MaintenanceWindow is a planned disruption. A one-off window has RFC 3339
+// Start and End timestamps. A weekly window lists Days (mon..sun) and has
+// HH:MM Start and End local times; an End before Start runs into the next
+// day.
+type MaintenanceWindow struct {
+	Summary string   `yaml:"summary" json:"summary"`
+	Start   string   `yaml:"start" json:"start"`
+	End     string   `yaml:"end" json:"end"`
+	Days    []string `yaml:"days,omitempty" json:"days,omitempty"`
+}
+
+// Weekdays maps the Days names of a weekly maintenance window.
+var Weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Recurring reports whether the window repeats weekly.
+func (w MaintenanceWindow) Recurring() bool {
+	return len(w.Days) > 0
+}
+
+// Times parses a one-off window.
+func (w MaintenanceWindow) Times() (time.Time, time.Time, error) {
+	start, err := time.Parse(time.RFC3339, w.Start)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("start must be an RFC 3339 timestamp")
+	}
+	end, err := time.Parse(time.RFC3339, w.End)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("end must be an RFC 3339 timestamp")
+	}
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end must be after start")
+	}
+	return start, end, nil
+}
+
+// Clock parses a weekly window's start and end as offsets from midnight.
+// The end offset is past 24h when the window runs into the next day.
+func (w MaintenanceWindow) Clock() (time.Duration, time.Duration, error) {
+	start, err := time.Parse("15:04", w.Start)
+	if err != nil {
+		return 0, 0, fmt.Errorf("start must be an HH:MM time")
+	}
+	end, err := time.Parse("15:04", w.End)
+	if err != nil {
+		return 0, 0, fmt.Errorf("end must be an HH:MM time")
+	}
+	for _, day := range w.Days {
+		if _, ok := Weekdays[day]; !ok {
+			return 0, 0, fmt.Errorf("days must be mon, tue, wed, thu, fri, sat, or sun")
+		}
+	}
+	startOffset := time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute
+	endOffset := time.Duration(end.Hour())*time.Hour + time.Duration(end.Minute())*time.Minute
+	if endOffset <= startOffset {
+		endOffset += 24 * time.Hour
+	}
+	return startOffset, endOffset, nil
+}
+
+// Schedule stops or starts the whole workspace at an HH:MM local time on
+// the listed Days. DevArch has no scheduler: due schedules run when
+// workspace schedules --run is called, typically from cron. Disabled keeps
+// a schedule in the manifest without running it.
+type Schedule struct {
+	Action   string   `yaml:"action" json:"action"`
+	At       string   `yaml:"at" json:"at"`
+	Days     []string `yaml:"days" json:"days"`
+	Disabled bool     `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+}
+
+// Clock parses At as an offset from midnight and checks Action and Days.
+func (s Schedule) Clock() (time.Duration, error) {
+	if s.Action != "stop" && s.Action != "start" {
+		return 0, fmt.Errorf("action must be stop or start")
+	}
+	at, err := time.Parse("15:04", s.At)
+	if err != nil {
+		return 0, fmt.Errorf("at must be an HH:MM time")
+	}
+	if len(s.Days) == 0 {
+		return 0, fmt.Errorf("days must list at least one day")
+	}
+	for _, day := range s.Days {
+		if _, ok := Weekdays[day]; !ok {
+			return 0, fmt.Errorf("days must be mon, tue, wed, thu, fri, sat, or sun")
+		}
+	}
+	return time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute, nil
+}
+
+// ExpiresAt parses Expires. It reports false when no expiry is set.
+func (m Metadata) ExpiresAt() (time.Time, bool, error) {
+	if m.Expires == "" {
+		return time.Time{}, false, nil
+	}
+	expires, err := time.Parse(time.DateOnly, m.Expires)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return expires, true, nil
 }
 
 type RuntimePreferences struct {
 	Provider        string `yaml:"provider,omitempty" json:"provider,omitempty"`
 	IsolatedNetwork bool   `yaml:"isolatedNetwork,omitempty" json:"isolatedNetwork,omitempty"`
 	NamingStrategy  string `yaml:"namingStrategy,omitempty" json:"namingStrategy,omitempty"`
+	// NetworkPrefix replaces "devarch" in the isolated network's name.
+	NetworkPrefix string `yaml:"networkPrefix,omitempty" json:"networkPrefix,omitempty"`
+	// RegistryMirrors maps an upstream registry host (for example docker.io)
+	// to a pull-through cache that image references are rewritten to.
+	RegistryMirrors map[string]string `yaml:"registryMirrors,omitempty" json:"registryMirrors,omitempty"`
 }
 
 type Catalog struct {
@@ -48,6 +178,52 @@ type Catalog struct {
 type Policies struct {
 	AutoWire     bool   `yaml:"autoWire,omitempty" json:"autoWire,omitempty"`
 	SecretSource string `yaml:"secretSource,omitempty" json:"secretSource,omitempty"`
+	// MaxResources and MaxPublishedPorts cap the enabled resources (replicas
+	// counted individually) and published host ports. Zero means unlimited.
+	MaxResources      int `yaml:"maxResources,omitempty" json:"maxResources,omitempty"`
+	MaxPublishedPorts int `yaml:"maxPublishedPorts,omitempty" json:"maxPublishedPorts,omitempty"`
+	// HostPorts is the range fixed host ports must fall in.
+	HostPorts *PortRange `yaml:"hostPorts,omitempty" json:"hostPorts,omitempty"`
+	// MaxTotalMemory caps the sum of the enabled resources' memory limits,
+	// such as 8g. Every enabled resource must then set memory.
+	MaxTotalMemory string `yaml:"maxTotalMemory,omitempty" json:"maxTotalMemory,omitempty"`
+	// QueryResources lists the resources workspace query may run SQL
+	// against. Empty allows every postgres and mysql resource.
+	QueryResources []string `yaml:"queryResources,omitempty" json:"queryResources,omitempty"`
+}
+
+// ParseMemory converts a memory size in the units container runtimes
+// accept, bytes or a b, k, m, or g suffix in powers of 1024, to bytes.
+func ParseMemory(value string) (int64, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	shift := 0
+	switch {
+	case strings.HasSuffix(value, "k"):
+		shift = 10
+	case strings.HasSuffix(value, "m"):
+		shift = 20
+	case strings.HasSuffix(value, "g"):
+		shift = 30
+	}
+	number, err := strconv.ParseInt(strings.TrimRight(value, "bkmg"), 10, 64)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("invalid memory size %q", value)
+	}
+	if number > math.MaxInt64>>shift {
+		return 0, fmt.Errorf("memory size %q is too large", value)
+	}
+	return number << shift, nil
+}
+
+// PortRange is an inclusive range of host ports.
+type PortRange struct {
+	From int `yaml:"from" json:"from"`
+	To   int `yaml:"to" json:"to"`
+}
+
+// Contains reports whether port is in the range.
+func (r PortRange) Contains(port int) bool {
+	return port >= r.From && port <= r.To
 }
 
 type Resource struct {
@@ -64,6 +240,76 @@ type Resource struct {
 	Domains   []string            `yaml:"domains,omitempty" json:"domains,omitempty"`
 	Develop   map[string]any      `yaml:"develop,omitempty" json:"develop,omitempty"`
 	Overrides map[string]any      `yaml:"overrides,omitempty" json:"overrides,omitempty"`
+	Replicas  int                 `yaml:"replicas,omitempty" json:"replicas,omitempty"`
+	// LoadBalancer fronts the replicas with a generated nginx resource that
+	// takes over the resource key.
+	LoadBalancer *LoadBalancer `yaml:"loadBalancer,omitempty" json:"loadBalancer,omitempty"`
+	Hooks        *Hooks        `yaml:"hooks,omitempty" json:"hooks,omitempty"`
+	// Inspector puts a generated recording proxy in front of one container
+	// port so HTTP traffic to the resource can be listed.
+	Inspector *Inspector `yaml:"inspector,omitempty" json:"inspector,omitempty"`
+	// Init marks a run-to-completion step, such as a schema migration, that
+	// apply waits on before starting other resources.
+	Init bool `yaml:"init,omitempty" json:"init,omitempty"`
+	// Userns sets the podman user namespace mode. keep-id maps the host user
+	// to the same uid in the container, so files written to bind mounts stay
+	// owned by the host user.
+	Userns string `yaml:"userns,omitempty" json:"userns,omitempty"`
+	// Memory is the container memory limit, such as 512m or 2g.
+	Memory string `yaml:"memory,omitempty" json:"memory,omitempty"`
+	// StopSignal and StopTimeout (seconds) override the template's
+	// runtime.stopSignal and runtime.stopTimeout.
+	StopSignal  string `yaml:"stopSignal,omitempty" json:"stopSignal,omitempty"`
+	StopTimeout *int   `yaml:"stopTimeout,omitempty" json:"stopTimeout,omitempty"`
+	Notes       string `yaml:"notes,omitempty" json:"notes,omitempty"`
+}
+
+// Hooks run around a resource's add or modify during apply. PreApply hooks
+// that exec into the container only run when the container already exists.
+type Hooks struct {
+	PreApply  []Hook `yaml:"preApply,omitempty" json:"preApply,omitempty"`
+	PostApply []Hook `yaml:"postApply,omitempty" json:"postApply,omitempty"`
+}
+
+// Hook is one command, either exec'd in the resource container or run in a
+// one-off container from the resource spec. OnFailure is "abort" (default)
+// or "continue".
+type Hook struct {
+	Exec      []string `yaml:"exec,omitempty" json:"exec,omitempty"`
+	Run       []string `yaml:"run,omitempty" json:"run,omitempty"`
+	OnFailure string   `yaml:"onFailure,omitempty" json:"onFailure,omitempty"`
+}
+
+func (h *Hooks) Clone() *Hooks {
+	if h == nil {
+		return nil
+	}
+	return &Hooks{PreApply: cloneHooks(h.PreApply), PostApply: cloneHooks(h.PostApply)}
+}
+
+func cloneHooks(hooks []Hook) []Hook {
+	if hooks == nil {
+		return nil
+	}
+	cloned := make([]Hook, len(hooks))
+	for i, hook := range hooks {
+		cloned[i] = Hook{Exec: append([]string(nil), hook.Exec...), Run: append([]string(nil), hook.Run...), OnFailure: hook.OnFailure}
+	}
+	return cloned
+}
+
+// LoadBalancer balances Port on every replica behind one published Host port.
+type LoadBalancer struct {
+	Port int `yaml:"port" json:"port"`
+	Host int `yaml:"host,omitempty" json:"host,omitempty"`
+}
+
+// Inspector proxies Port through a recording HTTP proxy. Host publishes the
+// proxy on that host port; without it the proxy takes over the host port the
+// resource already publishes for Port.
+type Inspector struct {
+	Port int `yaml:"port" json:"port"`
+	Host int `yaml:"host,omitempty" json:"host,omitempty"`
 }
 
 type Source struct {
@@ -80,11 +326,16 @@ type Port struct {
 	HostIP    string `yaml:"hostIP,omitempty" json:"hostIP,omitempty"`
 }
 
+// Volume mounts a named volume, or a host path when Source is a path.
+// Options are extra mount options: z or Z relabel the source for SELinux
+// (shared or private), U chowns it to the container user, and cached relaxes
+// consistency on macOS.
 type Volume struct {
-	Source   string `yaml:"source,omitempty" json:"source,omitempty"`
-	Target   string `yaml:"target" json:"target"`
-	ReadOnly bool   `yaml:"readOnly,omitempty" json:"readOnly,omitempty"`
-	Kind     string `yaml:"kind,omitempty" json:"kind,omitempty"`
+	Source   string   `yaml:"source,omitempty" json:"source,omitempty"`
+	Target   string   `yaml:"target" json:"target"`
+	ReadOnly bool     `yaml:"readOnly,omitempty" json:"readOnly,omitempty"`
+	Kind     string   `yaml:"kind,omitempty" json:"kind,omitempty"`
+	Options  []string `yaml:"options,omitempty" json:"options,omitempty"`
 }
 
 type Import struct {
@@ -132,6 +383,11 @@ type Health struct {
 	Timeout     string     `yaml:"timeout,omitempty" json:"timeout,omitempty"`
 	Retries     int        `yaml:"retries,omitempty" json:"retries,omitempty"`
 	StartPeriod string     `yaml:"startPeriod,omitempty" json:"startPeriod,omitempty"`
+	// TCP and HTTP are shorthands for images that ship no health check of
+	// their own. Resolve turns them into a generated Test: healthy once the
+	// container port accepts connections, or once the URL answers with 2xx.
+	TCP  int    `yaml:"tcp,omitempty" json:"tcp,omitempty"`
+	HTTP string `yaml:"http,omitempty" json:"http,omitempty"`
 }
 
 // StringList accepts either a scalar string or a string array and normalizes the
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"sort"
+	"strings"
 )
 
 // Normalize applies deterministic defaults and path resolution to a loaded
@@ -14,6 +15,11 @@ func Normalize(ws *Workspace) error {
 	}
 
 	ws.Catalog.Sources, ws.Catalog.ResolvedSources = normalizeCatalogSources(ws.ManifestDir, ws.Catalog.Sources)
+	ws.Runtime.RegistryMirrors = normalizeRegistryMirrors(ws.Runtime.RegistryMirrors)
+	// A team's workspace never shares the default network with other teams.
+	if ws.Metadata.Team != "" {
+		ws.Runtime.IsolatedNetwork = true
+	}
 	ws.Secrets = cloneRawMap(ws.Secrets)
 	ws.Profiles = cloneRawMap(ws.Profiles)
 
@@ -38,7 +44,7 @@ func Normalize(ws *Workspace) error {
 		resource.Health = cloneHealth(resource.Health)
 
 		if resource.Source != nil {
-			resource.Source.Path = normalizeDisplayPath(resource.Source.Path)
+			resource.Source.Path = normalizeDisplayPath(CanonicalHostPath(resource.Source.Path))
 			resource.Source.ResolvedPath = resolveManifestRelativePath(ws.ManifestDir, resource.Source.Path)
 		}
 	}
@@ -46,6 +52,28 @@ func Normalize(ws *Workspace) error {
 	return nil
 }
 
+func normalizeRegistryMirrors(mirrors map[string]string) map[string]string {
+	if len(mirrors) == 0 {
+		return nil
+	}
+	normalized := make(map[string]string, len(mirrors))
+	for upstream, mirror := range mirrors {
+		upstream = strings.ToLower(strings.TrimSpace(upstream))
+		mirror = strings.TrimSuffix(strings.TrimSpace(mirror), "/")
+		for _, scheme := range []string{"https://", "http://"} {
+			mirror = strings.TrimPrefix(mirror, scheme)
+		}
+		if upstream == "" || mirror == "" {
+			continue
+		}
+		normalized[upstream] = mirror
+	}
+	if len(normalized) == 0 {
+		return nil
+	}
+	return normalized
+}
+
 func normalizeCatalogSources(baseDir string, sources []string) ([]string, []string) {
 	if len(sources) == 0 {
 		return nil, nil
@@ -120,6 +148,9 @@ func normalizeVolumes(volumes []Volume) []Volume {
 
 	normalized := make([]Volume, len(volumes))
 	copy(normalized, volumes)
+	for i := range normalized {
+		normalized[i].Source = CanonicalHostPath(normalized[i].Source)
+	}
 	sort.Slice(normalized, func(i, j int) bool {
 		if normalized[i].Target != normalized[j].Target {
 			return normalized[i].Target < normalized[j].Target
@@ -204,6 +235,36 @@ func resolveManifestRelativePath(baseDir, rawPath string) string {
 	return filepath.Clean(filepath.Join(baseDir, rawPath))
 }
 
+// CanonicalHostPath rewrites a host path written on Windows into the form
+// manifests store: forward slashes, an upper-case drive letter, and no
+// trailing slash, so C:\src\app\ and c:/src/app name the same source, and
+// .\config is read as ./config on every host. Other paths and named volumes
+// are returned unchanged. The podman adapter translates drive paths again
+// for the podman machine on Windows; see runtime.MachineBindSource.
+func CanonicalHostPath(path string) string {
+	if IsWindowsDrivePath(path) {
+		rest := strings.TrimRight(strings.ReplaceAll(path[2:], "\\", "/"), "/")
+		if rest == "" {
+			rest = "/"
+		}
+		return strings.ToUpper(path[:1]) + ":" + rest
+	}
+	if strings.HasPrefix(path, ".\\") || strings.HasPrefix(path, "..\\") {
+		return strings.ReplaceAll(path, "\\", "/")
+	}
+	return path
+}
+
+// IsWindowsDrivePath reports whether path starts with a drive letter, as in
+// C:\src or d:/src.
+func IsWindowsDrivePath(path string) bool {
+	if len(path) < 3 || path[1] != ':' || (path[2] != '\\' && path[2] != '/') {
+		return false
+	}
+	drive := path[0] | 0x20
+	return drive >= 'a' && drive <= 'z'
+}
+
 func normalizeDisplayPath(path string) string {
 	if path == "" {
 		return ""
@@ -120,6 +120,23 @@ func TestLoadNormalizesExamples(t *testing.T) {
 	}
 }
 
+func TestCanonicalHostPathRewritesWindowsPaths(t *testing.T) {
+	cases := map[string]string{
+		`C:\Users\ada\shop\`:  "C:/Users/ada/shop",
+		"d:/src/app":          "D:/src/app",
+		`e:\`:                 "E:/",
+		`.\config\nginx.conf`: "./config/nginx.conf",
+		`..\shared`:           "../shared",
+		"/home/ada/shop":      "/home/ada/shop",
+		"pgdata":              "pgdata",
+	}
+	for path, want := range cases {
+		if got := CanonicalHostPath(path); got != want {
+			t.Fatalf("CanonicalHostPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
 func TestNormalizeCatalogSourcesDedupesAndSorts(t *testing.T) {
 	root := t.TempDir()
 	manifestPath := writeWorkspaceFixture(t, filepath.Join(root, "devarch.workspace.yaml"), `apiVersion: devarch.io/alpha1
@@ -0,0 +1,79 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/prospect-ogujiuba/devarch/internal/spec"
+	"gopkg.in/yaml.v3"
+)
+
+// Team holds the defaults for the workspaces whose metadata.team names it.
+// It is read from a devarch.team.yaml file in a workspace root.
+type Team struct {
+	APIVersion string       `yaml:"apiVersion" json:"apiVersion"`
+	Kind       string       `yaml:"kind" json:"kind"`
+	Metadata   TeamMetadata `yaml:"metadata" json:"metadata"`
+	Defaults   TeamDefaults `yaml:"defaults,omitempty" json:"defaults,omitempty"`
+
+	Path string `yaml:"-" json:"-"`
+}
+
+type TeamMetadata struct {
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
+// TeamDefaults fill the matching workspace settings when a workspace leaves
+// them unset. Registry mirrors are merged per upstream registry.
+type TeamDefaults struct {
+	NetworkPrefix   string            `yaml:"networkPrefix,omitempty" json:"networkPrefix,omitempty"`
+	HostPorts       *PortRange        `yaml:"hostPorts,omitempty" json:"hostPorts,omitempty"`
+	RegistryMirrors map[string]string `yaml:"registryMirrors,omitempty" json:"registryMirrors,omitempty"`
+}
+
+// LoadTeam reads and validates a team file.
+func LoadTeam(path string) (*Team, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read team %s: %w", path, err)
+	}
+	if err := spec.ValidateTeamBytes(data); err != nil {
+		return nil, fmt.Errorf("validate team %s: %w", path, err)
+	}
+	var team Team
+	if err := yaml.Unmarshal(data, &team); err != nil {
+		return nil, fmt.Errorf("decode team %s: %w", path, err)
+	}
+	team.Path = filepath.Clean(path)
+	if ports := team.Defaults.HostPorts; ports != nil && ports.From > ports.To {
+		return nil, fmt.Errorf("validate team %s: defaults.hostPorts: from must not be above to", path)
+	}
+	team.Defaults.RegistryMirrors = normalizeRegistryMirrors(team.Defaults.RegistryMirrors)
+	return &team, nil
+}
+
+// ApplyTeamDefaults fills the workspace settings that team has defaults for
+// and the workspace leaves unset.
+func (ws *Workspace) ApplyTeamDefaults(team *Team) {
+	if ws == nil || team == nil {
+		return
+	}
+	if ws.Runtime.NetworkPrefix == "" {
+		ws.Runtime.NetworkPrefix = team.Defaults.NetworkPrefix
+	}
+	if ws.Policies.HostPorts == nil && team.Defaults.HostPorts != nil {
+		ports := *team.Defaults.HostPorts
+		ws.Policies.HostPorts = &ports
+	}
+	for upstream, mirror := range team.Defaults.RegistryMirrors {
+		if _, ok := ws.Runtime.RegistryMirrors[upstream]; ok {
+			continue
+		}
+		if ws.Runtime.RegistryMirrors == nil {
+			ws.Runtime.RegistryMirrors = map[string]string{}
+		}
+		ws.Runtime.RegistryMirrors[upstream] = mirror
+	}
+}